@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/nats-io/nats.go"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+	"github.com/uptrace/bun/extra/bunslog"
+
+	"github.com/GoFFXI/GoFFXI/internal/admin"
+	"github.com/GoFFXI/GoFFXI/internal/config"
+	"github.com/GoFFXI/GoFFXI/internal/database"
+)
+
+// version information - to be set during build time
+var (
+	Version   = "dev"
+	BuildDate = "unknown"
+	GitCommit = "none"
+)
+
+// command is a single admin sub-command
+type command struct {
+	name        string
+	description string
+	run         func(ctx context.Context, service *admin.Service, args []string) error
+}
+
+//nolint:gochecknoglobals // static list of sub-commands
+var commands = []command{
+	{
+		name:        "transfer-character",
+		description: "move an offline character to another account",
+		run:         runTransferCharacter,
+	},
+	{
+		name:        "set-content-ids",
+		description: "set the number of content IDs (character slots) an account is entitled to",
+		run:         runSetContentIDs,
+	},
+	{
+		name:        "set-gm-level",
+		description: "set the GM (staff) level of an account, 0 for regular players",
+		run:         runSetGMLevel,
+	},
+	{
+		name:        "reload-version-policy",
+		description: "make running lobby-auth servers reload their xiloader version policy",
+		run:         runReloadVersionPolicy,
+	},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	var selected *command
+	for i := range commands {
+		if commands[i].name == os.Args[1] {
+			selected = &commands[i]
+			break
+		}
+	}
+
+	if selected == nil {
+		printUsage()
+		os.Exit(2)
+	}
+
+	// load .env file automatically
+	err := godotenv.Load()
+	if err != nil {
+		log.Println("no .env file found (continuing with system environment)")
+	}
+
+	// parse config from environment
+	cfg := config.ParseConfigFromEnv()
+
+	// detect the log level
+	logLevel := slog.LevelInfo
+	if err = logLevel.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		fmt.Fprintf(os.Stderr, "error: invalid log level: '%s'\n", cfg.LogLevel)
+		os.Exit(1)
+	}
+
+	// setup our logger
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: logLevel,
+	}))
+
+	// create a context
+	ctx := context.Background()
+
+	// create a database connection
+	db, err := createDBConnection(ctx, &cfg, logger)
+	if err != nil {
+		logger.Error("failed to create database connection", "error", err)
+		os.Exit(1)
+	}
+
+	// NATS is only used to notify running servers, so it is not fatal if it is unavailable
+	natsConn, err := nats.Connect(cfg.NATSURL, nats.Name(fmt.Sprintf("%sadmin", cfg.NATSClientPrefix)))
+	if err != nil {
+		logger.Warn("failed to connect to NATS, running servers will not be notified", "error", err)
+	} else {
+		defer natsConn.Close()
+	}
+
+	// some house-keeping
+	logger.Info("admin command started", "command", selected.name, "version", Version, "buildDate", BuildDate, "gitCommit", GitCommit)
+
+	service := admin.NewService(&cfg, db, natsConn, logger)
+	if err = selected.run(ctx, service, os.Args[2:]); err != nil {
+		logger.Error("admin command failed", "command", selected.name, "error", err)
+		if natsConn != nil {
+			natsConn.Close()
+		}
+
+		os.Exit(1)
+	}
+
+	if natsConn != nil {
+		_ = natsConn.Flush()
+	}
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\ncommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-24s %s\n", cmd.name, cmd.description)
+	}
+}
+
+func runTransferCharacter(ctx context.Context, service *admin.Service, args []string) error {
+	flags := flag.NewFlagSet("transfer-character", flag.ContinueOnError)
+	characterID := flags.Uint("character", 0, "ID of the character to transfer")
+	toAccountID := flags.Uint("to-account", 0, "ID of the destination account")
+	reason := flags.String("reason", "", "reason for the transfer (recorded in the audit log)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *characterID == 0 || *toAccountID == 0 {
+		return errors.New("both -character and -to-account are required")
+	}
+
+	//nolint:gosec // IDs are stored as 32-bit values
+	_, err := service.TransferCharacter(ctx, uint32(*characterID), uint32(*toAccountID), *reason)
+	return err
+}
+
+func runSetContentIDs(ctx context.Context, service *admin.Service, args []string) error {
+	flags := flag.NewFlagSet("set-content-ids", flag.ContinueOnError)
+	accountID := flags.Uint("account", 0, "ID of the account")
+	contentIDs := flags.Int("content-ids", -1, "number of content IDs the account is entitled to")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *accountID == 0 || *contentIDs < 0 {
+		return errors.New("both -account and -content-ids are required")
+	}
+
+	//nolint:gosec // IDs are stored as 32-bit values
+	return service.SetContentIDs(ctx, uint32(*accountID), *contentIDs)
+}
+
+func runSetGMLevel(ctx context.Context, service *admin.Service, args []string) error {
+	flags := flag.NewFlagSet("set-gm-level", flag.ContinueOnError)
+	accountID := flags.Uint("account", 0, "ID of the account")
+	gmLevel := flags.Uint("level", 0, "GM level to assign (0-255)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *accountID == 0 {
+		return errors.New("-account is required")
+	}
+
+	if *gmLevel > 255 {
+		return errors.New("-level must be between 0 and 255")
+	}
+
+	//nolint:gosec // IDs are stored as 32-bit values and the level is bounds checked above
+	return service.SetGMLevel(ctx, uint32(*accountID), uint8(*gmLevel))
+}
+
+func runReloadVersionPolicy(_ context.Context, service *admin.Service, args []string) error {
+	flags := flag.NewFlagSet("reload-version-policy", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	return service.ReloadVersionPolicy()
+}
+
+func createDBConnection(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*database.DBImpl, error) {
+	var db *bun.DB
+
+	sqldb, err := sql.Open("mysql", cfg.DBConnectionString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	// https://bun.uptrace.dev/guide/running-bun-in-production.html#running-bun-in-production
+	maxOpenConns := 4 * runtime.GOMAXPROCS(0)
+	sqldb.SetMaxOpenConns(maxOpenConns)
+	sqldb.SetMaxIdleConns(maxOpenConns)
+
+	db = bun.NewDB(sqldb, mysqldialect.New())
+
+	queryLogLevel := slog.LevelDebug
+	if cfg.DBQueryLogLevel == "info" {
+		queryLogLevel = slog.LevelInfo
+	}
+
+	db.AddQueryHook(bunslog.NewQueryHook(
+		bunslog.WithQueryLogLevel(queryLogLevel),
+		bunslog.WithSlowQueryLogLevel(slog.LevelWarn),
+		bunslog.WithErrorQueryLogLevel(slog.LevelError),
+		bunslog.WithSlowQueryThreshold(3*time.Second),
+		bunslog.WithLogger(logger.With("component", "database")),
+	))
+
+	if err = db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return database.NewDB(db), nil
+}
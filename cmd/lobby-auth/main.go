@@ -81,6 +81,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	// load the xiloader version policy
+	if err = authServer.ReloadVersionPolicy(); err != nil {
+		logger.Error("invalid xiloader version policy", "error", err)
+		os.Exit(1)
+	}
+
+	// reload the version policy on request
+	versionPolicySubscription, err := authServer.SubscribeVersionPolicyReload()
+	if err != nil {
+		logger.Error("failed to subscribe to version policy reloads", "error", err)
+		os.Exit(1)
+	}
+
+	//nolint:errcheck // subscription will be removed on shutdown
+	defer versionPolicySubscription.Unsubscribe()
+
 	//nolint:errcheck // socket will be closed on shutdown
 	defer authServer.Socket().Close()
 
@@ -92,6 +108,10 @@ func main() {
 	wg.Add(1)
 	go authServer.ProcessConnections(ctx, &wg, authServer.HandleConnection)
 
+	// start the expired session sweeper goroutine
+	wg.Add(1)
+	go authServer.SweepExpiredSessions(ctx, &wg)
+
 	// start accepting connections
 	wg.Add(1)
 	go authServer.AcceptConnections(ctx, &wg)
@@ -65,9 +65,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	dataServer := &data.DataServer{
-		TCPServer: baseServer,
-	}
+	dataServer := data.NewDataServer(baseServer)
 
 	// connect to NATS server
 	if err = dataServer.CreateNATSConnection(); err != nil {
@@ -92,6 +90,10 @@ func main() {
 	wg.Add(1)
 	go dataServer.ProcessConnections(ctx, &wg, dataServer.HandleConnection)
 
+	// start the login queue processor goroutine
+	wg.Add(1)
+	go dataServer.ProcessLoginQueue(ctx, &wg)
+
 	// start accepting connections
 	wg.Add(1)
 	go dataServer.AcceptConnections(ctx, &wg)
@@ -12,6 +12,7 @@ import (
 	"go.uber.org/automaxprocs/maxprocs"
 
 	"github.com/GoFFXI/GoFFXI/internal/config"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
 	"github.com/GoFFXI/GoFFXI/internal/servers/base/tcp"
 	"github.com/GoFFXI/GoFFXI/internal/servers/lobby/view"
 )
@@ -65,8 +66,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	// load the game data, which new characters' starting zones come from
+	gameData, err := gamedata.NewStore(cfg.MapGameDataPath)
+	if err != nil {
+		logger.Error("failed to load game data", "path", cfg.MapGameDataPath, "error", err)
+		os.Exit(1)
+	}
+
 	viewServer := &view.ViewServer{
 		TCPServer: baseServer,
+		GameData:  gameData,
 	}
 
 	// connect to NATS server
@@ -70,7 +70,15 @@ func main() {
 
 	// start processing packets
 	wg.Add(1)
-	go instanceWorker.StartProcessingPackets()
+	go instanceWorker.StartProcessingPackets(ctx, &wg)
+
+	// start persisting character state queued by the zones
+	wg.Add(1)
+	go instanceWorker.ProcessSaves(ctx, &wg)
+
+	// start reporting packet handler metrics
+	wg.Add(1)
+	go instanceWorker.ReportPacketMetrics(ctx, &wg)
 
 	// wait for shutdown signal
 	if err = instanceWorker.WaitForShutdown(cancelCtx, &wg); err != nil {
@@ -94,6 +94,14 @@ func main() {
 	wg.Add(1)
 	go mapRouterServer.DeliverPacketsToClients(ctx, &wg)
 
+	// keep the account sessions of connected characters alive
+	wg.Add(1)
+	go mapRouterServer.RefreshSessions(ctx, &wg)
+
+	// drop clients that stopped responding
+	wg.Add(1)
+	go mapRouterServer.DisconnectIdleSessions(ctx, &wg)
+
 	// wait for shutdown signal
 	if err = mapRouterServer.WaitForShutdown(cancelCtx, &wg); err != nil {
 		logger.Error("error during shutdown", "error", err)
@@ -5,7 +5,6 @@ go 1.25.3
 require github.com/caarlos0/env/v11 v11.3.1 // direct
 
 require (
-	github.com/davecgh/go-spew v1.1.1
 	github.com/go-sql-driver/mysql v1.9.3
 	github.com/joho/godotenv v1.5.1
 	github.com/nats-io/nats.go v1.48.0
@@ -13,6 +12,7 @@ require (
 	github.com/uptrace/bun v1.2.16
 	github.com/uptrace/bun/dialect/mysqldialect v1.2.16
 	github.com/uptrace/bun/extra/bunslog v1.2.16
+	github.com/yuin/gopher-lua v1.1.2
 	go.uber.org/automaxprocs v1.6.0
 	golang.org/x/crypto v0.48.0
 )
@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoFFXI/GoFFXI/internal/constants"
+	"github.com/GoFFXI/GoFFXI/internal/database"
+)
+
+var (
+	ErrAccountNotFound   = errors.New("account not found")
+	ErrInvalidContentIDs = fmt.Errorf("content IDs must be between 0 and %d", constants.MaxCharacterSlots)
+)
+
+// SetContentIDs grants an account a specific number of content IDs (character slots),
+// overriding the server-wide default.
+func (s *Service) SetContentIDs(ctx context.Context, accountID uint32, contentIDs int) error {
+	if contentIDs < 0 || contentIDs > constants.MaxCharacterSlots {
+		return ErrInvalidContentIDs
+	}
+
+	_, err := s.db.GetAccountByID(ctx, uint(accountID))
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return ErrAccountNotFound
+		}
+
+		return fmt.Errorf("failed to lookup account: %w", err)
+	}
+
+	//nolint:gosec // bounds checked above
+	if err = s.db.SetAccountContentIDs(ctx, accountID, uint8(contentIDs)); err != nil {
+		return fmt.Errorf("failed to update content IDs: %w", err)
+	}
+
+	s.logger.Info("account content IDs updated", "accountID", accountID, "contentIDs", contentIDs)
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+)
+
+// SetGMLevel changes the staff level of an account. Level 0 is a regular player.
+func (s *Service) SetGMLevel(ctx context.Context, accountID uint32, gmLevel uint8) error {
+	err := s.db.SetAccountGMLevel(ctx, accountID, gmLevel)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return ErrAccountNotFound
+		}
+
+		return fmt.Errorf("failed to update GM level: %w", err)
+	}
+
+	s.logger.Info("account GM level updated", "accountID", accountID, "gmLevel", gmLevel)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/GoFFXI/GoFFXI/internal/config"
+	"github.com/GoFFXI/GoFFXI/internal/database"
+)
+
+// lobbySessionKeyLength is the number of bytes of the session key that
+// xiloader echoes back to the lobby servers (and that they key NATS subjects on).
+const lobbySessionKeyLength = 16
+
+// Service implements the staff operations that change account and character
+// ownership outside of the normal client flow.
+type Service struct {
+	cfg      *config.Config
+	db       database.DB
+	natsConn *nats.Conn
+	logger   *slog.Logger
+}
+
+func NewService(cfg *config.Config, db database.DB, natsConn *nats.Conn, logger *slog.Logger) *Service {
+	return &Service{
+		cfg:      cfg,
+		db:       db,
+		natsConn: natsConn,
+		logger:   logger,
+	}
+}
+
+// invalidateSessions asks any lobby view/data server holding one of these sessions
+// to drop the connection so the client is forced through authentication again.
+func (s *Service) invalidateSessions(sessions []database.AccountSession) {
+	if s.natsConn == nil {
+		return
+	}
+
+	for _, session := range sessions {
+		if len(session.SessionKey) < lobbySessionKeyLength {
+			continue
+		}
+
+		sessionKey := hex.EncodeToString(session.SessionKey[:lobbySessionKeyLength])
+		for _, server := range []string{"view", "data"} {
+			subject := fmt.Sprintf("session.%s.%s.close", sessionKey, server)
+			if err := s.natsConn.Publish(subject, nil); err != nil {
+				s.logger.Warn("failed to publish session close", "accountID", session.AccountID, "subject", subject, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoFFXI/GoFFXI/internal/constants"
+	"github.com/GoFFXI/GoFFXI/internal/database"
+)
+
+var (
+	ErrCharacterDeleted            = errors.New("character has been deleted")
+	ErrCharacterOnline             = errors.New("a character of the account is online")
+	ErrDestinationAccountNotFound  = errors.New("destination account not found")
+	ErrDestinationAccountFull      = errors.New("destination account has no free content IDs")
+	ErrDestinationIsCurrentAccount = errors.New("character already belongs to the destination account")
+)
+
+// TransferCharacter moves a character to another account. No character of either
+// account may be in-game, and the destination account must have a free content ID. The
+// lobby sessions of both accounts are invalidated so the lobby re-reads the character lists.
+func (s *Service) TransferCharacter(ctx context.Context, characterID, toAccountID uint32, reason string) (database.CharacterTransfer, error) {
+	_, err := s.db.GetAccountByID(ctx, uint(toAccountID))
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return database.CharacterTransfer{}, ErrDestinationAccountNotFound
+		}
+
+		return database.CharacterTransfer{}, fmt.Errorf("failed to lookup destination account: %w", err)
+	}
+
+	var transfer database.CharacterTransfer
+	var staleSessions []database.AccountSession
+
+	err = s.db.RunInTx(ctx, func(ctx context.Context, tx database.Tx) error {
+		// the character and the sessions stay locked until the transfer commits, so no
+		// login can bind a session to a character in the meantime
+		character, err := tx.LockCharacter(ctx, characterID)
+		if err != nil {
+			return fmt.Errorf("failed to lookup character: %w", err)
+		}
+
+		if character.AccountID == 0 {
+			return ErrCharacterDeleted
+		}
+
+		if character.AccountID == toAccountID {
+			return ErrDestinationIsCurrentAccount
+		}
+
+		// each account has one session; one bound to a character means that character is
+		// in-game (or on its way there), and deleting the session would end its game
+		fromAccountID := character.AccountID
+		sessions, err := tx.LockAccountSessions(ctx, []uint32{fromAccountID, toAccountID})
+		if err != nil {
+			return fmt.Errorf("failed to lookup account sessions: %w", err)
+		}
+
+		for _, session := range sessions {
+			if session.CharacterID != 0 {
+				return fmt.Errorf("%w: character %d of account %d", ErrCharacterOnline, session.CharacterID, session.AccountID)
+			}
+		}
+
+		count, err := tx.CountCharactersByAccountID(ctx, toAccountID)
+		if err != nil {
+			return fmt.Errorf("failed to count destination characters: %w", err)
+		}
+
+		contentIDLimit, err := tx.GetAccountContentIDLimit(ctx, toAccountID, s.cfg.MaxContentIDsPerAccount)
+		if err != nil {
+			return fmt.Errorf("failed to lookup destination content IDs: %w", err)
+		}
+
+		if count >= min(contentIDLimit, constants.MaxCharacterSlots) {
+			return ErrDestinationAccountFull
+		}
+
+		// drop the lobby sessions of both accounts, they may have cached character lists
+		for _, session := range sessions {
+			if err = tx.DeleteAccountSessions(ctx, session.AccountID); err != nil {
+				return fmt.Errorf("failed to delete account sessions: %w", err)
+			}
+
+			staleSessions = append(staleSessions, session)
+		}
+
+		character.OriginalAccountID = fromAccountID
+		character.AccountID = toAccountID
+
+		if _, err = tx.UpdateCharacter(ctx, &character); err != nil {
+			return fmt.Errorf("failed to update character: %w", err)
+		}
+
+		transfer, err = tx.CreateCharacterTransfer(ctx, &database.CharacterTransfer{
+			CharacterID:   characterID,
+			FromAccountID: fromAccountID,
+			ToAccountID:   toAccountID,
+			Reason:        reason,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record character transfer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return database.CharacterTransfer{}, err
+	}
+
+	s.invalidateSessions(staleSessions)
+	s.logger.Info("character transferred", "characterID", characterID, "fromAccountID", transfer.FromAccountID, "toAccountID", toAccountID)
+
+	return transfer, nil
+}
@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+)
+
+// versionPolicyReloadSubject must match auth.SubjectVersionPolicyReload
+const versionPolicyReloadSubject = "lobby.auth.version-policy.reload"
+
+var ErrNATSUnavailable = errors.New("not connected to NATS")
+
+// ReloadVersionPolicy asks every running lobby-auth server to reload its xiloader version policy.
+func (s *Service) ReloadVersionPolicy() error {
+	if s.natsConn == nil {
+		return ErrNATSUnavailable
+	}
+
+	if err := s.natsConn.Publish(versionPolicyReloadSubject, nil); err != nil {
+		return fmt.Errorf("failed to publish version policy reload: %w", err)
+	}
+
+	s.logger.Info("requested xiloader version policy reload")
+
+	return nil
+}
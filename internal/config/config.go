@@ -23,8 +23,8 @@ type Config struct {
 	// ServerPort is the port the server will listen on
 	ServerPort int `env:"SERVER_PORT" default:"54231"`
 
-	// MapInstanceID is the ID of this map instance server
-	MapInstanceID uint16 `env:"MAP_INSTANCE_ID" default:"0"`
+	// MapInstanceZones is the list of zone IDs this map instance simulates (empty = all zones)
+	MapInstanceZones []uint16 `env:"MAP_INSTANCE_ZONES" default:""`
 
 	// MaxServerConnections is the maximum number of concurrent connections the server will accept
 	MaxServerConnections int `env:"MAX_SERVER_CONNECTIONS" default:"1000"`
@@ -35,6 +35,12 @@ type Config struct {
 	// ServerReadTimeoutSeconds is the number of seconds before a read from a client times out
 	ServerReadTimeoutSeconds int `env:"SERVER_READ_TIMEOUT_SECONDS" default:"1800"`
 
+	// SessionKeyTTLSeconds is how long a session key stays valid without being used
+	SessionKeyTTLSeconds int `env:"SESSION_KEY_TTL_SECONDS" default:"3600"`
+
+	// SessionSweepIntervalSeconds is how often expired sessions are removed from the database
+	SessionSweepIntervalSeconds int `env:"SESSION_SWEEP_INTERVAL_SECONDS" default:"60"`
+
 	// ServerTLSCertPath is the path to the TLS certificate for the server
 	ServerTLSCertPath string `env:"SERVER_TLS_CERT_PATH" default:""`
 
@@ -63,6 +69,20 @@ type Config struct {
 	// MaxContentIDsPerAccount is the maximum number of content IDs a player can have
 	MaxContentIDsPerAccount int `env:"MAX_CONTENT_IDS_PER_ACCOUNT" default:"3"`
 
+	// WorldPopulationCap is the maximum number of characters that can be online at once (0 = unlimited)
+	// Players selecting a character while the world is full wait in a FIFO queue; staff bypass it
+	WorldPopulationCap int `env:"WORLD_POPULATION_CAP" default:"0"`
+
+	// LoginQueueCheckIntervalSeconds is how often the login queue checks for free capacity
+	LoginQueueCheckIntervalSeconds int `env:"LOGIN_QUEUE_CHECK_INTERVAL_SECONDS" default:"5"`
+
+	// LoginQueueTimeoutSeconds is how long a player may wait in the login queue before being disconnected
+	LoginQueueTimeoutSeconds int `env:"LOGIN_QUEUE_TIMEOUT_SECONDS" default:"1800"`
+
+	// XILoaderVersionPolicyPath is the path to a JSON xiloader version policy (allow/deny ranges and messages)
+	// When set, it takes precedence over XILoaderVersion and XILoaderEnforceVersion
+	XILoaderVersionPolicyPath string `env:"XI_LOADER_VERSION_POLICY_PATH" default:""`
+
 	// XILoaderVersion is the version of the XI Loader to use
 	XILoaderVersion string `env:"XI_LOADER_VERSION" default:"2.0.0"`
 
@@ -140,6 +160,67 @@ type Config struct {
 
 	// FFXIResourcePath is the directory containing compress.dat/decompress.dat resources
 	FFXIResourcePath string `env:"FFXI_RES_PATH" default:"resources"`
+
+	// MapClientPacketRateLimit is the number of client packets per second a map instance accepts from one client (0 = unlimited)
+	MapClientPacketRateLimit int `env:"MAP_CLIENT_PACKET_RATE_LIMIT" default:"120"`
+
+	// MapClientPacketBurst is the number of client packets a client may send at once before being rate limited
+	MapClientPacketBurst int `env:"MAP_CLIENT_PACKET_BURST" default:"240"`
+
+	// MapZoneTickMilliseconds is the interval between two ticks of a zone's game loop
+	MapZoneTickMilliseconds int `env:"MAP_ZONE_TICK_MILLISECONDS" default:"400"`
+
+	// MapZoneEventQueueSize is the number of events (e.g. client packets) a zone buffers between ticks
+	MapZoneEventQueueSize int `env:"MAP_ZONE_EVENT_QUEUE_SIZE" default:"1024"`
+
+	// MapMaxMovementSpeed is the fastest a character may move, in yalms per second; faster position updates are rejected
+	MapMaxMovementSpeed float64 `env:"MAP_MAX_MOVEMENT_SPEED" default:"12"`
+
+	// MapViewDistance is how far, in yalms, characters can see other entities
+	MapViewDistance float64 `env:"MAP_VIEW_DISTANCE" default:"50"`
+
+	// MapVisibilityUpdatesPerTick is the number of entity spawn/update/despawn packets sent to a character per zone tick
+	MapVisibilityUpdatesPerTick int `env:"MAP_VISIBILITY_UPDATES_PER_TICK" default:"10"`
+
+	// MapAutosaveIntervalSeconds is how often a zone persists the state of the characters in it
+	MapAutosaveIntervalSeconds int `env:"MAP_AUTOSAVE_INTERVAL_SECONDS" default:"300"`
+
+	// MapLogoutSeconds is how long a character waits after /logout or /shutdown before leaving the game
+	MapLogoutSeconds int `env:"MAP_LOGOUT_SECONDS" default:"30"`
+
+	// MapEventTimeoutSeconds is how long an event (NPC dialog or cutscene) may wait on the client before it is ended (0 = never)
+	MapEventTimeoutSeconds int `env:"MAP_EVENT_TIMEOUT_SECONDS" default:"600"`
+
+	// MapClientTimeoutSeconds is how long the router waits for a packet from a client before dropping it
+	MapClientTimeoutSeconds int `env:"MAP_CLIENT_TIMEOUT_SECONDS" default:"60"`
+
+	// MapDisconnectLingerSeconds is how long the character of a client that stopped responding stays in the world
+	MapDisconnectLingerSeconds int `env:"MAP_DISCONNECT_LINGER_SECONDS" default:"15"`
+
+	// MapSayRange is how far, in yalms, say and emote messages are heard
+	MapSayRange float64 `env:"MAP_SAY_RANGE" default:"50"`
+
+	// MapShoutRange is how far, in yalms, shouts are heard
+	MapShoutRange float64 `env:"MAP_SHOUT_RANGE" default:"180"`
+
+	// MapYellZones is the list of zone IDs yells can be sent from, and are heard in (comma separated)
+	MapYellZones []uint16 `env:"MAP_YELL_ZONES" default:"230,231,232,234,235,236,238,239,240,241,243,244,245,246,50,26,256,257"`
+
+	// MapChatLogEnabled specifies whether chat messages are stored in the database for moderation
+	MapChatLogEnabled bool `env:"MAP_CHAT_LOG_ENABLED" default:"true"`
+
+	// MapGameDataPath is the directory holding the game data files (manifest.json, items.csv, zone_lines.csv, ...)
+	// lobby-view reads the starting zones of new characters from it as well
+	MapGameDataPath string `env:"MAP_GAME_DATA_PATH" default:"resources/gamedata"`
+
+	// MapScriptsPath is the directory holding the Lua scripts of the zones (zones/<zone name>/Zone.lua, ...)
+	MapScriptsPath string `env:"MAP_SCRIPTS_PATH" default:"resources/scripts"`
+
+	// MapScriptTimeoutMilliseconds is how long a call into the scripts may run before it is stopped
+	MapScriptTimeoutMilliseconds int `env:"MAP_SCRIPT_TIMEOUT_MILLISECONDS" default:"100"`
+
+	// MapPacketMetricsIntervalSeconds is how often a map instance logs its packet handler metrics (0 = never)
+	MapPacketMetricsIntervalSeconds int `env:"MAP_PACKET_METRICS_INTERVAL_SECONDS" default:"300"`
 }
 
 func ParseConfigFromEnv() Config {
@@ -5,6 +5,12 @@ const (
 	ResponsePacketTerminator = 0x46465849 // "IXFF"
 )
 
+// Lobby Limits
+const (
+	// MaxCharacterSlots is the protocol maximum of character slots the lobby can display
+	MaxCharacterSlots = 16
+)
+
 // Race IDs
 const (
 	RaceInvalid        uint16 = 0
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// AccountEntitlement holds per-account overrides granted by staff. Accounts without
+// a row fall back to the server-wide defaults from the config.
+type AccountEntitlement struct {
+	AccountID  uint32 `bun:"type:int unsigned,pk"`
+	ContentIDs uint8  `bun:"type:tinyint unsigned,notnull"`
+
+	CreatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+}
+
+func (m *AccountEntitlement) BeforeUpdate(_ context.Context, _ *bun.UpdateQuery) error {
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+type AccountEntitlementQueries interface {
+	GetAccountEntitlement(ctx context.Context, accountID uint32) (AccountEntitlement, error)
+	GetAccountContentIDLimit(ctx context.Context, accountID uint32, defaultLimit int) (int, error)
+	SetAccountContentIDs(ctx context.Context, accountID uint32, contentIDs uint8) error
+}
+
+func (q *queriesImpl) GetAccountEntitlement(ctx context.Context, accountID uint32) (AccountEntitlement, error) {
+	var entitlement AccountEntitlement
+
+	err := q.db.NewSelect().Model(&entitlement).Where("account_id = ?", accountID).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AccountEntitlement{}, ErrNotFound
+		}
+
+		return AccountEntitlement{}, err
+	}
+
+	return entitlement, nil
+}
+
+// GetAccountContentIDLimit returns the number of content IDs (character slots) the account
+// is entitled to, or defaultLimit if staff never granted the account a specific amount.
+func (q *queriesImpl) GetAccountContentIDLimit(ctx context.Context, accountID uint32, defaultLimit int) (int, error) {
+	entitlement, err := q.GetAccountEntitlement(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return defaultLimit, nil
+		}
+
+		return 0, err
+	}
+
+	return int(entitlement.ContentIDs), nil
+}
+
+func (q *queriesImpl) SetAccountContentIDs(ctx context.Context, accountID uint32, contentIDs uint8) error {
+	entitlement := &AccountEntitlement{
+		AccountID:  accountID,
+		ContentIDs: contentIDs,
+		UpdatedAt:  time.Now(),
+	}
+
+	_, err := q.db.NewInsert().Model(entitlement).
+		On("DUPLICATE KEY UPDATE").
+		Set("content_ids = VALUES(content_ids)").
+		Set("updated_at = VALUES(updated_at)").
+		Exec(ctx)
+
+	return err
+}
@@ -10,22 +10,46 @@ import (
 )
 
 const (
-	ConstraintAccountSessionsAccountIDUnique = "account_sessions_account_id_unique"
+	ConstraintAccountSessionsAccountIDUnique = "PRIMARY"
 	AccountSessionKeyLength                  = 20
 )
 
-var ErrAccountSessionNotUnique = errors.New("account session not unique")
+var (
+	ErrAccountSessionNotUnique   = errors.New("account session not unique")
+	ErrAccountSessionExpired     = errors.New("account session expired")
+	ErrAccountSessionIPMismatch  = errors.New("account session used from a different IP address")
+	ErrAccountSessionNotVerified = errors.New("account session could not be verified")
+)
 
 type AccountSession struct {
-	AccountID   uint32 `bun:"type:int unsigned,unique"`
-	CharacterID uint32 `bun:"type:int unsigned,notnull,pk"`
-	SessionKey  []byte `bun:"type:binary(20),notnull,unique"`
-	ClientIP    string `bun:"type:varchar(15),notnull"`
+	AccountID   uint32    `bun:"type:int unsigned,pk"`
+	CharacterID uint32    `bun:"type:int unsigned,notnull,default:0"`
+	SessionKey  []byte    `bun:"type:binary(20),notnull,unique"`
+	ClientIP    string    `bun:"type:varchar(15),notnull"`
+	ExpiresAt   time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
 
 	CreatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
 	UpdatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
 }
 
+// Verify makes sure the session is still valid and is being used from the IP address
+// that authenticated it.
+func (m *AccountSession) Verify(clientIP string, now time.Time) error {
+	if clientIP == "" || m.ClientIP == "" {
+		return ErrAccountSessionNotVerified
+	}
+
+	if !now.Before(m.ExpiresAt) {
+		return ErrAccountSessionExpired
+	}
+
+	if m.ClientIP != clientIP {
+		return ErrAccountSessionIPMismatch
+	}
+
+	return nil
+}
+
 func (m *AccountSession) BeforeUpdate(_ context.Context, _ *bun.UpdateQuery) error {
 	m.UpdatedAt = time.Now()
 	return nil
@@ -34,9 +58,15 @@ func (m *AccountSession) BeforeUpdate(_ context.Context, _ *bun.UpdateQuery) err
 type AccountSessionQueries interface {
 	GetAccountSessionBySessionKey(ctx context.Context, sessionKey []byte) (AccountSession, error)
 	GetAccountSessionByCharacterID(ctx context.Context, characterID uint32) (AccountSession, error)
+	LockAccountSessions(ctx context.Context, accountIDs []uint32) ([]AccountSession, error)
 	CreateAccountSession(ctx context.Context, accountSession *AccountSession) (AccountSession, error)
 	DeleteAccountSessions(ctx context.Context, accountID uint32) error
-	UpdateAccountSession(ctx context.Context, accountID, characterID uint32, clientIP string, sessionKey []byte) error
+	DeleteAccountSessionBySessionKey(ctx context.Context, sessionKey []byte) error
+	UpdateAccountSession(ctx context.Context, accountID, characterID uint32, clientIP string, sessionKey []byte, expiresAt time.Time) error
+	TouchAccountSession(ctx context.Context, accountID uint32, expiresAt time.Time) error
+	TouchAccountSessionsByCharacterIDs(ctx context.Context, characterIDs []uint32, expiresAt time.Time) error
+	DeleteExpiredAccountSessions(ctx context.Context, now time.Time) (int64, error)
+	CountOnlineCharacters(ctx context.Context) (int, error)
 }
 
 func (q *queriesImpl) GetAccountSessionBySessionKey(ctx context.Context, sessionKey []byte) (AccountSession, error) {
@@ -69,6 +99,19 @@ func (q *queriesImpl) GetAccountSessionByCharacterID(ctx context.Context, charac
 	return accountSession, nil
 }
 
+// LockAccountSessions returns the sessions of the accounts, locking them (and the
+// absence of the missing ones) until the end of the transaction.
+func (q *queriesImpl) LockAccountSessions(ctx context.Context, accountIDs []uint32) ([]AccountSession, error) {
+	var accountSessions []AccountSession
+
+	err := q.db.NewSelect().Model(&accountSessions).Where("account_id IN (?)", bun.In(accountIDs)).For("UPDATE").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountSessions, nil
+}
+
 func (q *queriesImpl) CreateAccountSession(ctx context.Context, accountSession *AccountSession) (AccountSession, error) {
 	accountSession.SessionKey = normalizeSessionKey(accountSession.SessionKey)
 
@@ -89,10 +132,17 @@ func (q *queriesImpl) DeleteAccountSessions(ctx context.Context, accountID uint3
 	return err
 }
 
-func (q *queriesImpl) UpdateAccountSession(ctx context.Context, accountID, characterID uint32, clientIP string, sessionKey []byte) error {
+// DeleteAccountSessionBySessionKey removes a session, leaving any newer session of the account alone.
+func (q *queriesImpl) DeleteAccountSessionBySessionKey(ctx context.Context, sessionKey []byte) error {
+	_, err := q.db.NewDelete().Model((*AccountSession)(nil)).Where("session_key = ?", normalizeSessionKey(sessionKey)).Exec(ctx)
+	return err
+}
+
+func (q *queriesImpl) UpdateAccountSession(ctx context.Context, accountID, characterID uint32, clientIP string, sessionKey []byte, expiresAt time.Time) error {
 	query := q.db.NewUpdate().Model((*AccountSession)(nil)).
 		Set("character_id = ?", characterID).
 		Set("client_ip = ?", clientIP).
+		Set("expires_at = ?", expiresAt).
 		Set("updated_at = ?", time.Now()).
 		Where("account_id = ?", accountID)
 
@@ -105,6 +155,54 @@ func (q *queriesImpl) UpdateAccountSession(ctx context.Context, accountID, chara
 	return err
 }
 
+// TouchAccountSession extends the expiry of an account's session.
+func (q *queriesImpl) TouchAccountSession(ctx context.Context, accountID uint32, expiresAt time.Time) error {
+	_, err := q.db.NewUpdate().Model((*AccountSession)(nil)).
+		Set("expires_at = ?", expiresAt).
+		Where("account_id = ?", accountID).
+		Exec(ctx)
+
+	return err
+}
+
+// TouchAccountSessionsByCharacterIDs extends the expiry of every session bound to one of the characters.
+func (q *queriesImpl) TouchAccountSessionsByCharacterIDs(ctx context.Context, characterIDs []uint32, expiresAt time.Time) error {
+	if len(characterIDs) == 0 {
+		return nil
+	}
+
+	_, err := q.db.NewUpdate().Model((*AccountSession)(nil)).
+		Set("expires_at = ?", expiresAt).
+		Where("character_id IN (?)", bun.In(characterIDs)).
+		Exec(ctx)
+
+	return err
+}
+
+// DeleteExpiredAccountSessions removes every session that expired before now.
+func (q *queriesImpl) DeleteExpiredAccountSessions(ctx context.Context, now time.Time) (int64, error) {
+	res, err := q.db.NewDelete().Model((*AccountSession)(nil)).Where("expires_at < ?", now).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// CountOnlineCharacters returns the number of sessions that have a character bound to them.
+func (q *queriesImpl) CountOnlineCharacters(ctx context.Context) (int, error) {
+	count, err := q.db.NewSelect().Model((*AccountSession)(nil)).Where("character_id > 0").Count(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func normalizeSessionKey(key []byte) []byte {
 	normalized := make([]byte, AccountSessionKeyLength)
 	if len(key) == 0 {
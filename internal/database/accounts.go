@@ -11,6 +11,9 @@ import (
 
 const (
 	ConstraintAccountsUsernameUnique = "accounts_username_unique"
+
+	// GMLevelNone is the GM level of a regular player account; anything above is staff
+	GMLevelNone uint8 = 0
 )
 
 var ErrAccountNameNotUnique = errors.New("account username not unique")
@@ -19,11 +22,17 @@ type Account struct {
 	ID       uint32 `bun:"id,pk,autoincrement,type:int unsigned"`
 	Username string `bun:"type:varchar(16),notnull,unique"`
 	Password string `bun:"type:varchar(64),notnull"`
+	GMLevel  uint8  `bun:"type:tinyint unsigned,notnull,default:0"`
 
 	CreatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
 	UpdatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
 }
 
+// IsStaff returns whether the account belongs to a GM or other staff member.
+func (m *Account) IsStaff() bool {
+	return m.GMLevel > GMLevelNone
+}
+
 func (m *Account) BeforeUpdate(_ context.Context, _ bun.Query) error {
 	m.UpdatedAt = time.Now()
 	return nil
@@ -34,6 +43,7 @@ type AccountQueries interface {
 	GetAccountByUsername(ctx context.Context, username string) (Account, error)
 	CreateAccount(ctx context.Context, account *Account) (Account, error)
 	UpdateAccount(ctx context.Context, account *Account) (Account, error)
+	SetAccountGMLevel(ctx context.Context, id uint32, gmLevel uint8) error
 	AccountExists(ctx context.Context, username string) (bool, error)
 }
 
@@ -89,6 +99,19 @@ func (q *queriesImpl) UpdateAccount(ctx context.Context, account *Account) (Acco
 	return *account, notFoundErrIfNoRowsAffected(res)
 }
 
+func (q *queriesImpl) SetAccountGMLevel(ctx context.Context, id uint32, gmLevel uint8) error {
+	res, err := q.db.NewUpdate().Model((*Account)(nil)).
+		Set("gm_level = ?", gmLevel).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return notFoundErrIfNoRowsAffected(res)
+}
+
 func (q *queriesImpl) AccountExists(ctx context.Context, username string) (bool, error) {
 	count, err := q.db.NewSelect().Model((*Account)(nil)).Where("username = ?", username).Count(ctx)
 	if err != nil {
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+)
+
+// CharacterContainer is the size of one of a character's containers. Containers without
+// a row have their default size.
+type CharacterContainer struct {
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	Container   uint8  `bun:"type:tinyint unsigned,pk"`
+	Size        uint8  `bun:"type:tinyint unsigned,notnull"`
+}
+
+type CharacterContainerQueries interface {
+	GetCharacterContainers(ctx context.Context, characterID uint32) ([]CharacterContainer, error)
+	SetCharacterContainerSize(ctx context.Context, characterID uint32, container, size uint8) error
+}
+
+func (q *queriesImpl) GetCharacterContainers(ctx context.Context, characterID uint32) ([]CharacterContainer, error) {
+	var containers []CharacterContainer
+
+	err := q.db.NewSelect().
+		Model(&containers).
+		Where("character_id = ?", characterID).
+		Order("container ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return containers, nil
+}
+
+func (q *queriesImpl) SetCharacterContainerSize(ctx context.Context, characterID uint32, container, size uint8) error {
+	_, err := q.db.NewInsert().
+		Model(&CharacterContainer{CharacterID: characterID, Container: container, Size: size}).
+		On("DUPLICATE KEY UPDATE").
+		Set("size = VALUES(size)").
+		Exec(ctx)
+
+	return err
+}
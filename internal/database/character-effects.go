@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+)
+
+// CharacterEffect is a status effect a character keeps across zoning and logging out.
+type CharacterEffect struct {
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+
+	// Slot orders the effects of a character the way they were applied
+	Slot     uint8  `bun:"type:tinyint unsigned,pk"`
+	EffectID uint16 `bun:"type:smallint unsigned,notnull"`
+	Power    int32  `bun:"type:int,notnull"`
+
+	// SourceID is the entity that applied the effect, or 0
+	SourceID uint32 `bun:"type:int unsigned,notnull"`
+
+	// Tick is the time between the effect's ticks in milliseconds, or 0
+	Tick uint32 `bun:"type:int unsigned,notnull"`
+
+	// Remaining is how long the effect still lasts in milliseconds, or 0 for an effect that never wears off
+	Remaining uint32 `bun:"type:int unsigned,notnull"`
+}
+
+type CharacterEffectQueries interface {
+	GetCharacterEffects(ctx context.Context, characterID uint32) ([]CharacterEffect, error)
+	InsertCharacterEffects(ctx context.Context, effects []CharacterEffect) error
+	DeleteCharacterEffects(ctx context.Context, characterID uint32) error
+}
+
+func (q *queriesImpl) GetCharacterEffects(ctx context.Context, characterID uint32) ([]CharacterEffect, error) {
+	var effects []CharacterEffect
+
+	err := q.db.NewSelect().
+		Model(&effects).
+		Where("character_id = ?", characterID).
+		Order("slot ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return effects, nil
+}
+
+func (q *queriesImpl) InsertCharacterEffects(ctx context.Context, effects []CharacterEffect) error {
+	if len(effects) == 0 {
+		return nil
+	}
+
+	_, err := q.db.NewInsert().Model(&effects).Exec(ctx)
+	return err
+}
+
+func (q *queriesImpl) DeleteCharacterEffects(ctx context.Context, characterID uint32) error {
+	_, err := q.db.NewDelete().
+		Model((*CharacterEffect)(nil)).
+		Where("character_id = ?", characterID).
+		Exec(ctx)
+
+	return err
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+)
+
+// CharacterEquipment is the item equipped in one of a character's equipment slots.
+type CharacterEquipment struct {
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	EquipSlot   uint8  `bun:"type:tinyint unsigned,pk"`
+
+	// Container and Slot locate the equipped item in the character's containers
+	Container uint8 `bun:"type:tinyint unsigned,notnull"`
+	Slot      uint8 `bun:"type:tinyint unsigned,notnull"`
+}
+
+type CharacterEquipmentQueries interface {
+	GetCharacterEquipment(ctx context.Context, characterID uint32) ([]CharacterEquipment, error)
+	SaveCharacterEquipment(ctx context.Context, equipment *CharacterEquipment) error
+	DeleteCharacterEquipment(ctx context.Context, characterID uint32, equipSlot uint8) error
+}
+
+func (q *queriesImpl) GetCharacterEquipment(ctx context.Context, characterID uint32) ([]CharacterEquipment, error) {
+	var equipment []CharacterEquipment
+
+	err := q.db.NewSelect().
+		Model(&equipment).
+		Where("character_id = ?", characterID).
+		Order("equip_slot ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return equipment, nil
+}
+
+func (q *queriesImpl) SaveCharacterEquipment(ctx context.Context, equipment *CharacterEquipment) error {
+	_, err := q.db.NewInsert().Model(equipment).
+		On("DUPLICATE KEY UPDATE").
+		Set("container = VALUES(container)").
+		Set("slot = VALUES(slot)").
+		Exec(ctx)
+
+	return err
+}
+
+func (q *queriesImpl) DeleteCharacterEquipment(ctx context.Context, characterID uint32, equipSlot uint8) error {
+	_, err := q.db.NewDelete().
+		Model((*CharacterEquipment)(nil)).
+		Where("character_id = ? AND equip_slot = ?", characterID, equipSlot).
+		Exec(ctx)
+
+	return err
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/uptrace/bun"
+)
+
+// CharacterExp holds the experience points a character has earned towards the next
+// level of every job.
+type CharacterExp struct {
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	WAR         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	MNK         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	WHM         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	BLM         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	RDM         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	THF         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	PLD         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	DRK         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	BST         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	BRD         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	RNG         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	SAM         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	NIN         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	DRG         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	SMN         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	BLU         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	COR         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	PUP         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	DNC         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	SCH         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	GEO         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	RUN         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+}
+
+// jobExp returns the experience points of every job, indexed by job ID (index 0 is no job).
+func (e *CharacterExp) jobExp() []*uint16 {
+	return []*uint16{
+		nil,    // JobNone (0)
+		&e.WAR, // JobWarrior (1)
+		&e.MNK, // JobMonk (2)
+		&e.WHM, // JobWhiteMage (3)
+		&e.BLM, // JobBlackMage (4)
+		&e.RDM, // JobRedMage (5)
+		&e.THF, // JobThief (6)
+		&e.PLD, // JobPaladin (7)
+		&e.DRK, // JobDarkKnight (8)
+		&e.BST, // JobBeastmaster (9)
+		&e.BRD, // JobBard (10)
+		&e.RNG, // JobRanger (11)
+		&e.SAM, // JobSamurai (12)
+		&e.NIN, // JobNinja (13)
+		&e.DRG, // JobDragoon (14)
+		&e.SMN, // JobSummoner (15)
+		&e.BLU, // JobBlueMage (16)
+		&e.COR, // JobCorsair (17)
+		&e.PUP, // JobPuppetmaster (18)
+		&e.DNC, // JobDancer (19)
+		&e.SCH, // JobScholar (20)
+		&e.GEO, // JobGeomancer (21)
+		&e.RUN, // JobRuneFencer (22)
+	}
+}
+
+// Exp returns the experience points of a job, or 0 for unknown jobs.
+func (e *CharacterExp) Exp(job uint8) uint16 {
+	exp := e.jobExp()
+	if int(job) >= len(exp) || exp[job] == nil {
+		return 0
+	}
+
+	return *exp[job]
+}
+
+// SetExp sets the experience points of a job, reporting whether the job exists.
+func (e *CharacterExp) SetExp(job uint8, points uint16) bool {
+	exp := e.jobExp()
+	if int(job) >= len(exp) || exp[job] == nil {
+		return false
+	}
+
+	*exp[job] = points
+	return true
+}
+
+type CharacterExpQueries interface {
+	GetCharacterExpByID(ctx context.Context, characterID uint32) (CharacterExp, error)
+	SaveCharacterExp(ctx context.Context, characterExp *CharacterExp) error
+	DeleteCharacterExp(ctx context.Context, characterID uint32) error
+}
+
+func (q *queriesImpl) GetCharacterExpByID(ctx context.Context, characterID uint32) (CharacterExp, error) {
+	var characterExp CharacterExp
+
+	err := q.db.NewSelect().Model(&characterExp).Where("character_id = ?", characterID).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return CharacterExp{}, ErrNotFound
+		}
+
+		return CharacterExp{}, err
+	}
+
+	return characterExp, nil
+}
+
+// SaveCharacterExp writes the experience points of every job, creating the row for
+// characters that never earned any.
+func (q *queriesImpl) SaveCharacterExp(ctx context.Context, characterExp *CharacterExp) error {
+	query := q.db.NewInsert().Model(characterExp).On("DUPLICATE KEY UPDATE")
+	for _, job := range []string{"war", "mnk", "whm", "blm", "rdm", "thf", "pld", "drk", "bst", "brd", "rng", "sam", "nin", "drg", "smn", "blu", "cor", "pup", "dnc", "sch", "geo", "run"} {
+		query = query.Set("? = VALUES(?)", bun.Ident(job), bun.Ident(job))
+	}
+
+	_, err := query.Exec(ctx)
+	return err
+}
+
+func (q *queriesImpl) DeleteCharacterExp(ctx context.Context, characterID uint32) error {
+	_, err := q.db.NewDelete().Model((*CharacterExp)(nil)).Where("character_id = ?", characterID).Exec(ctx)
+	return err
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+)
+
+// CharacterItem is an item in one of a character's containers.
+type CharacterItem struct {
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	Container   uint8  `bun:"type:tinyint unsigned,pk"`
+	Slot        uint8  `bun:"type:tinyint unsigned,pk"`
+	ItemID      uint16 `bun:"type:smallint unsigned,notnull"`
+	Quantity    uint32 `bun:"type:int unsigned,notnull,default:1"`
+
+	// Extra holds the item's extra data (signature, augments, charges, ...)
+	Extra []byte `bun:"type:varbinary(24)"`
+}
+
+type CharacterItemQueries interface {
+	GetCharacterItems(ctx context.Context, characterID uint32) ([]CharacterItem, error)
+	SaveCharacterItem(ctx context.Context, item *CharacterItem) error
+	DeleteCharacterItem(ctx context.Context, characterID uint32, container, slot uint8) error
+}
+
+func (q *queriesImpl) GetCharacterItems(ctx context.Context, characterID uint32) ([]CharacterItem, error) {
+	var items []CharacterItem
+
+	err := q.db.NewSelect().
+		Model(&items).
+		Where("character_id = ?", characterID).
+		Order("container ASC", "slot ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (q *queriesImpl) SaveCharacterItem(ctx context.Context, item *CharacterItem) error {
+	_, err := q.db.NewInsert().Model(item).
+		On("DUPLICATE KEY UPDATE").
+		Set("item_id = VALUES(item_id)").
+		Set("quantity = VALUES(quantity)").
+		Set("extra = VALUES(extra)").
+		Exec(ctx)
+
+	return err
+}
+
+func (q *queriesImpl) DeleteCharacterItem(ctx context.Context, characterID uint32, container, slot uint8) error {
+	_, err := q.db.NewDelete().
+		Model((*CharacterItem)(nil)).
+		Where("character_id = ? AND container = ? AND slot = ?", characterID, container, slot).
+		Exec(ctx)
+
+	return err
+}
@@ -34,6 +34,87 @@ type CharacterJobs struct {
 	SCH         uint8  `bun:"type:tinyint unsigned,notnull,default:0"`
 	GEO         uint8  `bun:"type:tinyint unsigned,notnull,default:0"`
 	RUN         uint8  `bun:"type:tinyint unsigned,notnull,default:0"`
+
+	// Unlocked holds a bit per job the character may switch to (bit N = job N); the
+	// default unlocks the six starting jobs
+	Unlocked uint32 `bun:"type:int unsigned,notnull,default:126"`
+
+	// SubJobUnlocked is whether the character has unlocked support jobs
+	SubJobUnlocked bool `bun:"type:boolean,notnull,default:false"`
+}
+
+// jobLevels returns the level of every job, indexed by job ID (index 0 is no job).
+func (j *CharacterJobs) jobLevels() []*uint8 {
+	return []*uint8{
+		nil,    // JobNone (0)
+		&j.WAR, // JobWarrior (1)
+		&j.MNK, // JobMonk (2)
+		&j.WHM, // JobWhiteMage (3)
+		&j.BLM, // JobBlackMage (4)
+		&j.RDM, // JobRedMage (5)
+		&j.THF, // JobThief (6)
+		&j.PLD, // JobPaladin (7)
+		&j.DRK, // JobDarkKnight (8)
+		&j.BST, // JobBeastmaster (9)
+		&j.BRD, // JobBard (10)
+		&j.RNG, // JobRanger (11)
+		&j.SAM, // JobSamurai (12)
+		&j.NIN, // JobNinja (13)
+		&j.DRG, // JobDragoon (14)
+		&j.SMN, // JobSummoner (15)
+		&j.BLU, // JobBlueMage (16)
+		&j.COR, // JobCorsair (17)
+		&j.PUP, // JobPuppetmaster (18)
+		&j.DNC, // JobDancer (19)
+		&j.SCH, // JobScholar (20)
+		&j.GEO, // JobGeomancer (21)
+		&j.RUN, // JobRuneFencer (22)
+	}
+}
+
+// Level returns the level of a job, or 0 for unknown jobs.
+func (j *CharacterJobs) Level(job uint8) uint8 {
+	levels := j.jobLevels()
+	if int(job) >= len(levels) || levels[job] == nil {
+		return 0
+	}
+
+	return *levels[job]
+}
+
+// SetLevel sets the level of a job, reporting whether the job exists.
+func (j *CharacterJobs) SetLevel(job, level uint8) bool {
+	levels := j.jobLevels()
+	if int(job) >= len(levels) || levels[job] == nil {
+		return false
+	}
+
+	*levels[job] = level
+	return true
+}
+
+// IsUnlocked reports whether the character may switch to a job.
+func (j *CharacterJobs) IsUnlocked(job uint8) bool {
+	levels := j.jobLevels()
+	if int(job) >= len(levels) || levels[job] == nil {
+		return false
+	}
+
+	return j.Unlocked&(1<<job) != 0
+}
+
+// Levels returns the level of every job, indexed by job ID (index 0 is always 0).
+func (j *CharacterJobs) Levels() []uint8 {
+	levels := j.jobLevels()
+
+	result := make([]uint8, len(levels))
+	for job, level := range levels {
+		if level != nil {
+			result[job] = *level
+		}
+	}
+
+	return result
 }
 
 type CharacterJobsQueries interface {
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+)
+
+// CharacterKeyItem is a key item a character holds.
+type CharacterKeyItem struct {
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	KeyItemID   uint16 `bun:"type:smallint unsigned,pk"`
+}
+
+type CharacterKeyItemQueries interface {
+	GetCharacterKeyItems(ctx context.Context, characterID uint32) ([]CharacterKeyItem, error)
+	AddCharacterKeyItem(ctx context.Context, characterID uint32, keyItemID uint16) error
+	DeleteCharacterKeyItem(ctx context.Context, characterID uint32, keyItemID uint16) error
+}
+
+func (q *queriesImpl) GetCharacterKeyItems(ctx context.Context, characterID uint32) ([]CharacterKeyItem, error) {
+	var keyItems []CharacterKeyItem
+
+	err := q.db.NewSelect().
+		Model(&keyItems).
+		Where("character_id = ?", characterID).
+		Order("key_item_id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyItems, nil
+}
+
+func (q *queriesImpl) AddCharacterKeyItem(ctx context.Context, characterID uint32, keyItemID uint16) error {
+	keyItem := &CharacterKeyItem{CharacterID: characterID, KeyItemID: keyItemID}
+
+	_, err := q.db.NewInsert().
+		Model(keyItem).
+		Ignore().
+		Exec(ctx)
+
+	return err
+}
+
+func (q *queriesImpl) DeleteCharacterKeyItem(ctx context.Context, characterID uint32, keyItemID uint16) error {
+	_, err := q.db.NewDelete().
+		Model((*CharacterKeyItem)(nil)).
+		Where("character_id = ?", characterID).
+		Where("key_item_id = ?", keyItemID).
+		Exec(ctx)
+
+	return err
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type CharacterTransfer struct {
+	ID            uint32    `bun:"type:int unsigned,pk,autoincrement"`
+	CharacterID   uint32    `bun:"type:int unsigned,notnull"`
+	FromAccountID uint32    `bun:"type:int unsigned,notnull"`
+	ToAccountID   uint32    `bun:"type:int unsigned,notnull"`
+	Reason        string    `bun:"type:varchar(512),notnull"`
+	CreatedAt     time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+}
+
+type CharacterTransferQueries interface {
+	GetCharacterTransfersByCharacterID(ctx context.Context, characterID uint32) ([]CharacterTransfer, error)
+	CreateCharacterTransfer(ctx context.Context, transfer *CharacterTransfer) (CharacterTransfer, error)
+}
+
+func (q *queriesImpl) GetCharacterTransfersByCharacterID(ctx context.Context, characterID uint32) ([]CharacterTransfer, error) {
+	var transfers []CharacterTransfer
+
+	err := q.db.NewSelect().Model(&transfers).Where("character_id = ?", characterID).Order("id ASC").Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return transfers, nil
+		}
+
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+func (q *queriesImpl) CreateCharacterTransfer(ctx context.Context, transfer *CharacterTransfer) (CharacterTransfer, error) {
+	_, err := q.db.NewInsert().Model(transfer).Exec(ctx)
+	if err != nil {
+		return CharacterTransfer{}, err
+	}
+
+	return *transfer, nil
+}
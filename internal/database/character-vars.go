@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+)
+
+// CharacterVar is a named value scripts keep on a character (quest progress, ...).
+// Variables that are not stored are 0.
+type CharacterVar struct {
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	Name        string `bun:"type:varchar(64),pk"`
+	Value       int32  `bun:"type:int,notnull"`
+}
+
+type CharacterVarQueries interface {
+	GetCharacterVars(ctx context.Context, characterID uint32) ([]CharacterVar, error)
+	SetCharacterVar(ctx context.Context, characterVar *CharacterVar) error
+}
+
+func (q *queriesImpl) GetCharacterVars(ctx context.Context, characterID uint32) ([]CharacterVar, error) {
+	var vars []CharacterVar
+
+	err := q.db.NewSelect().
+		Model(&vars).
+		Where("character_id = ?", characterID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
+
+// SetCharacterVar stores a variable of a character; setting it to 0 deletes it.
+func (q *queriesImpl) SetCharacterVar(ctx context.Context, characterVar *CharacterVar) error {
+	if characterVar.Value == 0 {
+		_, err := q.db.NewDelete().
+			Model((*CharacterVar)(nil)).
+			Where("character_id = ?", characterVar.CharacterID).
+			Where("name = ?", characterVar.Name).
+			Exec(ctx)
+
+		return err
+	}
+
+	_, err := q.db.NewInsert().
+		Model(characterVar).
+		On("DUPLICATE KEY UPDATE").
+		Set("value = VALUES(value)").
+		Exec(ctx)
+
+	return err
+}
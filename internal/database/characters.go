@@ -26,7 +26,24 @@ type Character struct {
 	PosY float32 `bun:"type:float,notnull,default:0.000"`
 	PosZ float32 `bun:"type:float,notnull,default:0.000"`
 
+	// PosRot is the direction the character is facing (0-255, a full turn)
+	PosRot uint8 `bun:"type:tinyint unsigned,notnull,default:0"`
+
+	// the home point the character returns to when defeated
+	HomeZone uint16  `bun:"type:smallint unsigned,notnull,default:0"`
+	HomeX    float32 `bun:"type:float,notnull,default:0.000"`
+	HomeY    float32 `bun:"type:float,notnull,default:0.000"`
+	HomeZ    float32 `bun:"type:float,notnull,default:0.000"`
+	HomeRot  uint8   `bun:"type:tinyint unsigned,notnull,default:0"`
+
+	// InMogHouse is set while the character is in its mog house in PosZone
+	InMogHouse bool `bun:"type:boolean,notnull,default:false"`
+
+	// ZonesVisited is a bitmap of the zones the character has entered (bit N = zone N)
+	ZonesVisited []byte `bun:"type:varbinary(48)"`
+
 	Jobs  *CharacterJobs  `bun:"rel:has-one,join:id=character_id"`
+	Exp   *CharacterExp   `bun:"rel:has-one,join:id=character_id"`
 	Stats *CharacterStats `bun:"rel:has-one,join:id=character_id"`
 	Looks *CharacterLooks `bun:"rel:has-one,join:id=character_id"`
 }
@@ -36,47 +53,22 @@ func (c *Character) GetMainJobLevel() uint8 {
 		return 0
 	}
 
-	// Create array where index = job constant, value = job level
-	jobLevels := []uint8{
-		0,          // JobNone (0)
-		c.Jobs.WAR, // JobWarrior (1)
-		c.Jobs.MNK, // JobMonk (2)
-		c.Jobs.WHM, // JobWhiteMage (3)
-		c.Jobs.BLM, // JobBlackMage (4)
-		c.Jobs.RDM, // JobRedMage (5)
-		c.Jobs.THF, // JobThief (6)
-		c.Jobs.PLD, // JobPaladin (7)
-		c.Jobs.DRK, // JobDarkKnight (8)
-		c.Jobs.BST, // JobBeastmaster (9)
-		c.Jobs.BRD, // JobBard (10)
-		c.Jobs.RNG, // JobRanger (11)
-		c.Jobs.SAM, // JobSamurai (12)
-		c.Jobs.NIN, // JobNinja (13)
-		c.Jobs.DRG, // JobDragoon (14)
-		c.Jobs.SMN, // JobSummoner (15)
-		c.Jobs.BLU, // JobBlueMage (16)
-		c.Jobs.COR, // JobCorsair (17)
-		c.Jobs.PUP, // JobPuppetmaster (18)
-		c.Jobs.DNC, // JobDancer (19)
-		c.Jobs.SCH, // JobScholar (20)
-		c.Jobs.GEO, // JobGeomancer (21)
-		c.Jobs.RUN, // JobRuneFencer (22)
-	}
-
-	//nolint:gosec // the length will never overflow
-	if c.Stats.MainJob < uint8(len(jobLevels)) {
-		return jobLevels[c.Stats.MainJob]
-	}
-
-	return 0
+	return c.Jobs.Level(c.Stats.MainJob)
 }
 
 type CharacterQueries interface {
 	GetCharacterByID(ctx context.Context, characterID uint32) (Character, error)
+	LockCharacter(ctx context.Context, characterID uint32) (Character, error)
+	GetCharacterByName(ctx context.Context, characterName string) (Character, error)
 	GetCharactersByAccountID(ctx context.Context, accountID uint32) ([]Character, error)
 	CountCharactersByAccountID(ctx context.Context, accountID uint32) (int, error)
 	CreateCharacter(ctx context.Context, character *Character) (Character, error)
 	UpdateCharacter(ctx context.Context, character *Character) (Character, error)
+	UpdateCharacterPosition(ctx context.Context, characterID uint32, zoneID uint16, x, y, z float32, rotation uint8) error
+	UpdateCharacterZone(ctx context.Context, characterID uint32, prevZoneID, zoneID uint16, x, y, z float32, rotation uint8) error
+	UpdateCharacterHomePoint(ctx context.Context, characterID uint32, zoneID uint16, x, y, z float32, rotation uint8) error
+	UpdateCharacterMogHouse(ctx context.Context, characterID uint32, inMogHouse bool) error
+	UpdateCharacterZonesVisited(ctx context.Context, characterID uint32, zonesVisited []byte) error
 	DeleteCharacter(ctx context.Context, characterID uint32) error
 	CharacterNameExists(ctx context.Context, characterName string) (bool, error)
 }
@@ -96,6 +88,37 @@ func (q *queriesImpl) GetCharacterByID(ctx context.Context, characterID uint32)
 	return character, nil
 }
 
+// LockCharacter returns a character, locking it until the end of the transaction.
+func (q *queriesImpl) LockCharacter(ctx context.Context, characterID uint32) (Character, error) {
+	var character Character
+
+	err := q.db.NewSelect().Model(&character).Where("id = ?", characterID).For("UPDATE").Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Character{}, ErrNotFound
+		}
+
+		return Character{}, err
+	}
+
+	return character, nil
+}
+
+func (q *queriesImpl) GetCharacterByName(ctx context.Context, characterName string) (Character, error) {
+	var character Character
+
+	err := q.db.NewSelect().Model(&character).Where("name = ?", characterName).Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Character{}, ErrNotFound
+		}
+
+		return Character{}, err
+	}
+
+	return character, nil
+}
+
 func (q *queriesImpl) GetCharactersByAccountID(ctx context.Context, accountID uint32) ([]Character, error) {
 	var characters []Character
 
@@ -154,6 +177,73 @@ func (q *queriesImpl) UpdateCharacter(ctx context.Context, character *Character)
 	return *character, nil
 }
 
+func (q *queriesImpl) UpdateCharacterPosition(ctx context.Context, characterID uint32, zoneID uint16, x, y, z float32, rotation uint8) error {
+	_, err := q.db.NewUpdate().
+		Model((*Character)(nil)).
+		Set("pos_zone = ?", zoneID).
+		Set("pos_x = ?", x).
+		Set("pos_y = ?", y).
+		Set("pos_z = ?", z).
+		Set("pos_rot = ?", rotation).
+		Where("id = ?", characterID).
+		Exec(ctx)
+
+	return err
+}
+
+func (q *queriesImpl) UpdateCharacterZone(ctx context.Context, characterID uint32, prevZoneID, zoneID uint16, x, y, z float32, rotation uint8) error {
+	_, err := q.db.NewUpdate().
+		Model((*Character)(nil)).
+		Set("pos_prev_zone = ?", prevZoneID).
+		Set("pos_zone = ?", zoneID).
+		Set("pos_x = ?", x).
+		Set("pos_y = ?", y).
+		Set("pos_z = ?", z).
+		Set("pos_rot = ?", rotation).
+		Set("in_mog_house = ?", false).
+		Where("id = ?", characterID).
+		Exec(ctx)
+
+	return err
+}
+
+// UpdateCharacterHomePoint sets where a character returns to when defeated.
+func (q *queriesImpl) UpdateCharacterHomePoint(ctx context.Context, characterID uint32, zoneID uint16, x, y, z float32, rotation uint8) error {
+	_, err := q.db.NewUpdate().
+		Model((*Character)(nil)).
+		Set("home_zone = ?", zoneID).
+		Set("home_x = ?", x).
+		Set("home_y = ?", y).
+		Set("home_z = ?", z).
+		Set("home_rot = ?", rotation).
+		Where("id = ?", characterID).
+		Exec(ctx)
+
+	return err
+}
+
+// UpdateCharacterMogHouse records whether a character is in its mog house; changing
+// zones always leaves it.
+func (q *queriesImpl) UpdateCharacterMogHouse(ctx context.Context, characterID uint32, inMogHouse bool) error {
+	_, err := q.db.NewUpdate().
+		Model((*Character)(nil)).
+		Set("in_mog_house = ?", inMogHouse).
+		Where("id = ?", characterID).
+		Exec(ctx)
+
+	return err
+}
+
+func (q *queriesImpl) UpdateCharacterZonesVisited(ctx context.Context, characterID uint32, zonesVisited []byte) error {
+	_, err := q.db.NewUpdate().
+		Model((*Character)(nil)).
+		Set("zones_visited = ?", zonesVisited).
+		Where("id = ?", characterID).
+		Exec(ctx)
+
+	return err
+}
+
 func (q *queriesImpl) DeleteCharacter(ctx context.Context, characterID uint32) error {
 	_, err := q.db.NewDelete().Model((*Character)(nil)).Where("id = ?", characterID).Exec(ctx)
 	return err
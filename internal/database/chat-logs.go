@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// ChatLog is a chat message kept for moderation.
+type ChatLog struct {
+	ID            uint64 `bun:"type:bigint unsigned,pk,autoincrement"`
+	CharacterID   uint32 `bun:"type:int unsigned,notnull"`
+	CharacterName string `bun:"type:varchar(16),notnull"`
+	ZoneID        uint16 `bun:"type:smallint unsigned,notnull"`
+	Kind          uint8  `bun:"type:tinyint unsigned,notnull"`
+
+	// Recipient is the character a tell was sent to, or the party/linkshell name
+	Recipient string `bun:"type:varchar(32),notnull,default:''"`
+
+	// Message holds the raw message bytes, as sent by the client
+	Message   []byte    `bun:"type:varbinary(255),notnull"`
+	CreatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+}
+
+type ChatLogQueries interface {
+	CreateChatLog(ctx context.Context, chatLog *ChatLog) error
+}
+
+func (q *queriesImpl) CreateChatLog(ctx context.Context, chatLog *ChatLog) error {
+	_, err := q.db.NewInsert().Model(chatLog).Exec(ctx)
+	return err
+}
@@ -8,14 +8,25 @@ import (
 
 type Queries interface {
 	AccountBanQueries
+	AccountEntitlementQueries
 	AccountIPRecordQueries
 	AccountSessionQueries
 	AccountTOTPQueries
 	AccountQueries
+	CharacterContainerQueries
+	CharacterEffectQueries
+	CharacterEquipmentQueries
+	CharacterExpQueries
+	CharacterItemQueries
 	CharacterJobsQueries
+	CharacterKeyItemQueries
 	CharacterLooksQueries
 	CharacterStatsQueries
+	CharacterTransferQueries
+	CharacterVarQueries
 	CharacterQueries
+	ChatLogQueries
+	GMCommandLogQueries
 }
 
 type Tx interface {
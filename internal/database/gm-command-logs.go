@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// GMCommandLog is a GM command run by a staff member, kept for auditing.
+type GMCommandLog struct {
+	ID            uint64 `bun:"type:bigint unsigned,pk,autoincrement"`
+	AccountID     uint32 `bun:"type:int unsigned,notnull"`
+	CharacterID   uint32 `bun:"type:int unsigned,notnull"`
+	CharacterName string `bun:"type:varchar(16),notnull"`
+	GMLevel       uint8  `bun:"type:tinyint unsigned,notnull"`
+	ZoneID        uint16 `bun:"type:smallint unsigned,notnull"`
+
+	// Command is the full command line, without the leading '!'
+	Command string `bun:"type:varchar(255),notnull"`
+
+	// Result is empty when the command succeeded, or the error it failed with
+	Result    string    `bun:"type:varchar(255),notnull,default:''"`
+	CreatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+}
+
+type GMCommandLogQueries interface {
+	CreateGMCommandLog(ctx context.Context, gmCommandLog *GMCommandLog) error
+}
+
+func (q *queriesImpl) CreateGMCommandLog(ctx context.Context, gmCommandLog *GMCommandLog) error {
+	_, err := q.db.NewInsert().Model(gmCommandLog).Exec(ctx)
+	return err
+}
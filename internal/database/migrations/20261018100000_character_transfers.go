@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewCreateTable().
+			Model((*CharacterTransfer20261018100000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*CharacterTransfer20261018100000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type CharacterTransfer20261018100000 struct {
+	bun.BaseModel `bun:"table:character_transfers"`
+
+	ID            uint32    `bun:"type:int unsigned,pk,autoincrement"`
+	CharacterID   uint32    `bun:"type:int unsigned,notnull"`
+	FromAccountID uint32    `bun:"type:int unsigned,notnull"`
+	ToAccountID   uint32    `bun:"type:int unsigned,notnull"`
+	Reason        string    `bun:"type:varchar(512),notnull"`
+	CreatedAt     time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewCreateTable().
+			Model((*AccountEntitlement20261018110000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*AccountEntitlement20261018110000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type AccountEntitlement20261018110000 struct {
+	bun.BaseModel `bun:"table:account_entitlements"`
+
+	AccountID  uint32 `bun:"type:int unsigned,pk"`
+	ContentIDs uint8  `bun:"type:tinyint unsigned,notnull"`
+
+	CreatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+}
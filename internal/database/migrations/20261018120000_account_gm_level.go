@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewAddColumn().
+			Table("accounts").
+			ColumnExpr("gm_level TINYINT UNSIGNED NOT NULL DEFAULT 0").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropColumn().
+			Table("accounts").
+			Column("gm_level").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
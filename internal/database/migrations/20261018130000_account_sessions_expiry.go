@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// account sessions are short-lived, so instead of altering the table in place
+// (the old primary key was the character ID, which is 0 for every session until
+// a character is selected) it is simply recreated; players only need to log in again.
+//
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*AccountSession20251101172200)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.NewCreateTable().
+			Model((*AccountSession20261018130000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*AccountSession20261018130000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.NewCreateTable().
+			Model((*AccountSession20251101172200)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type AccountSession20261018130000 struct {
+	bun.BaseModel `bun:"table:account_sessions"`
+
+	AccountID   uint32    `bun:"type:int unsigned,pk"`
+	CharacterID uint32    `bun:"type:int unsigned,notnull,default:0"`
+	SessionKey  []byte    `bun:"type:binary(20),notnull,unique"`
+	ClientIP    string    `bun:"type:varchar(15),notnull"`
+	ExpiresAt   time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+
+	CreatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+}
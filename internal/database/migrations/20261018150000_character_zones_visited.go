@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewAddColumn().
+			Table("characters").
+			ColumnExpr("zones_visited VARBINARY(48) NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropColumn().
+			Table("characters").
+			Column("zones_visited").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
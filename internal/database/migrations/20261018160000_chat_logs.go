@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewCreateTable().
+			Model((*ChatLog20261018160000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.NewCreateIndex().
+			Model((*ChatLog20261018160000)(nil)).
+			Index("chat_logs_character_id_idx").
+			Column("character_id", "created_at").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*ChatLog20261018160000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type ChatLog20261018160000 struct {
+	bun.BaseModel `bun:"table:chat_logs"`
+
+	ID            uint64    `bun:"type:bigint unsigned,pk,autoincrement"`
+	CharacterID   uint32    `bun:"type:int unsigned,notnull"`
+	CharacterName string    `bun:"type:varchar(16),notnull"`
+	ZoneID        uint16    `bun:"type:smallint unsigned,notnull"`
+	Kind          uint8     `bun:"type:tinyint unsigned,notnull"`
+	Recipient     string    `bun:"type:varchar(32),notnull,default:''"`
+	Message       []byte    `bun:"type:varbinary(255),notnull"`
+	CreatedAt     time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+}
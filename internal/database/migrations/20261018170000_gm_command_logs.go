@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewCreateTable().
+			Model((*GMCommandLog20261018170000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.NewCreateIndex().
+			Model((*GMCommandLog20261018170000)(nil)).
+			Index("gm_command_logs_account_id_idx").
+			Column("account_id", "created_at").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*GMCommandLog20261018170000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type GMCommandLog20261018170000 struct {
+	bun.BaseModel `bun:"table:gm_command_logs"`
+
+	ID            uint64    `bun:"type:bigint unsigned,pk,autoincrement"`
+	AccountID     uint32    `bun:"type:int unsigned,notnull"`
+	CharacterID   uint32    `bun:"type:int unsigned,notnull"`
+	CharacterName string    `bun:"type:varchar(16),notnull"`
+	GMLevel       uint8     `bun:"type:tinyint unsigned,notnull"`
+	ZoneID        uint16    `bun:"type:smallint unsigned,notnull"`
+	Command       string    `bun:"type:varchar(255),notnull"`
+	Result        string    `bun:"type:varchar(255),notnull,default:''"`
+	CreatedAt     time.Time `bun:"type:timestamp,notnull,default:current_timestamp"`
+}
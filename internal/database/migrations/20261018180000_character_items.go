@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewCreateTable().
+			Model((*CharacterContainer20261018180000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.NewCreateTable().
+			Model((*CharacterItem20261018180000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*CharacterItem20261018180000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.NewDropTable().
+			Model((*CharacterContainer20261018180000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type CharacterContainer20261018180000 struct {
+	bun.BaseModel `bun:"table:character_containers"`
+
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	Container   uint8  `bun:"type:tinyint unsigned,pk"`
+	Size        uint8  `bun:"type:tinyint unsigned,notnull"`
+}
+
+type CharacterItem20261018180000 struct {
+	bun.BaseModel `bun:"table:character_items"`
+
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	Container   uint8  `bun:"type:tinyint unsigned,pk"`
+	Slot        uint8  `bun:"type:tinyint unsigned,pk"`
+	ItemID      uint16 `bun:"type:smallint unsigned,notnull"`
+	Quantity    uint32 `bun:"type:int unsigned,notnull,default:1"`
+	Extra       []byte `bun:"type:varbinary(24)"`
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewCreateTable().
+			Model((*CharacterEquipment20261018190000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*CharacterEquipment20261018190000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type CharacterEquipment20261018190000 struct {
+	bun.BaseModel `bun:"table:character_equipment"`
+
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	EquipSlot   uint8  `bun:"type:tinyint unsigned,pk"`
+	Container   uint8  `bun:"type:tinyint unsigned,notnull"`
+	Slot        uint8  `bun:"type:tinyint unsigned,notnull"`
+}
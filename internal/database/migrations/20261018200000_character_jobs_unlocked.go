@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewAddColumn().
+			Table("character_jobs").
+			ColumnExpr("unlocked INT UNSIGNED NOT NULL DEFAULT 126").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.NewAddColumn().
+			Table("character_jobs").
+			ColumnExpr("sub_job_unlocked BOOLEAN NOT NULL DEFAULT FALSE").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropColumn().
+			Table("character_jobs").
+			Column("sub_job_unlocked").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.NewDropColumn().
+			Table("character_jobs").
+			Column("unlocked").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
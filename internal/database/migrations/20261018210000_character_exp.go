@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewCreateTable().
+			Model((*CharacterExp20261018210000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*CharacterExp20261018210000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type CharacterExp20261018210000 struct {
+	bun.BaseModel `bun:"table:character_exp"`
+
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	WAR         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	MNK         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	WHM         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	BLM         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	RDM         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	THF         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	PLD         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	DRK         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	BST         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	BRD         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	RNG         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	SAM         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	NIN         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	DRG         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	SMN         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	BLU         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	COR         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	PUP         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	DNC         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	SCH         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	GEO         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+	RUN         uint16 `bun:"type:smallint unsigned,notnull,default:0"`
+}
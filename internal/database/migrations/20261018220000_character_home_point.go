@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		for _, column := range []string{
+			"home_zone SMALLINT UNSIGNED NOT NULL DEFAULT 0",
+			"home_x FLOAT NOT NULL DEFAULT 0",
+			"home_y FLOAT NOT NULL DEFAULT 0",
+			"home_z FLOAT NOT NULL DEFAULT 0",
+			"home_rot TINYINT UNSIGNED NOT NULL DEFAULT 0",
+		} {
+			_, err := db.NewAddColumn().
+				Table("characters").
+				ColumnExpr(column).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		// existing characters get the zone they are in as their home point
+		_, err := db.NewUpdate().
+			Table("characters").
+			Set("home_zone = pos_zone").
+			Where("home_zone = 0").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		for _, column := range []string{"home_rot", "home_z", "home_y", "home_x", "home_zone"} {
+			_, err := db.NewDropColumn().
+				Table("characters").
+				Column(column).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
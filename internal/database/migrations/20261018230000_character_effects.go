@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewCreateTable().
+			Model((*CharacterEffect20261018230000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*CharacterEffect20261018230000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type CharacterEffect20261018230000 struct {
+	bun.BaseModel `bun:"table:character_effects"`
+
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	Slot        uint8  `bun:"type:tinyint unsigned,pk"`
+	EffectID    uint16 `bun:"type:smallint unsigned,notnull"`
+	Power       int32  `bun:"type:int,notnull"`
+	SourceID    uint32 `bun:"type:int unsigned,notnull"`
+	Tick        uint32 `bun:"type:int unsigned,notnull"`
+	Remaining   uint32 `bun:"type:int unsigned,notnull"`
+}
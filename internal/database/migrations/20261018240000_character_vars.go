@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewCreateTable().
+			Model((*CharacterVar20261018240000)(nil)).
+			IfNotExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropTable().
+			Model((*CharacterVar20261018240000)(nil)).
+			IfExists().
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+type CharacterVar20261018240000 struct {
+	bun.BaseModel `bun:"table:character_vars"`
+
+	CharacterID uint32 `bun:"type:int unsigned,pk"`
+	Name        string `bun:"type:varchar(64),pk"`
+	Value       int32  `bun:"type:int,notnull"`
+}
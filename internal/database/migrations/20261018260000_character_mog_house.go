@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+//nolint:gochecknoinits // this is the typical way to register bun migrations
+func init() {
+	migrations.MustRegister(func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewAddColumn().
+			Table("characters").
+			ColumnExpr("in_mog_house BOOLEAN NOT NULL DEFAULT FALSE").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}, func(ctx context.Context, db *bun.DB) error {
+		_, err := db.NewDropColumn().
+			Table("characters").
+			Column("in_mog_house").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,229 @@
+package gamedata
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// jobCount is the number of jobs (WAR to RUN)
+const jobCount = 22
+
+// Target flags of spells and abilities, using LandSandBoat's values.
+const (
+	TargetSelf       = 0x01
+	TargetParty      = 0x02
+	TargetEnemy      = 0x04
+	TargetAlliance   = 0x08
+	TargetPlayer     = 0x10
+	TargetPlayerDead = 0x20
+)
+
+// Magic skills of spells, using LandSandBoat's skill IDs.
+const (
+	SkillDivine     = 32
+	SkillHealing    = 33
+	SkillEnhancing  = 34
+	SkillEnfeebling = 35
+	SkillElemental  = 36
+	SkillDark       = 37
+)
+
+// Spell is the static definition of a spell. What it does is resolved from its data
+// (damage or healing from its base and multiplier, then its status effect), unless
+// the map server has a hook of the spell's name.
+type Spell struct {
+	ID   uint16
+	Name string
+
+	// Jobs is the level each job learns the spell at, indexed by job ID - 1; 0 when it cannot
+	Jobs [jobCount]uint8
+
+	// ValidTargets are the Target flags of what the spell can be cast on
+	ValidTargets uint16
+
+	Skill  uint8
+	MPCost uint16
+
+	// CastTime and RecastTime are in milliseconds
+	CastTime   uint32
+	RecastTime uint32
+
+	Animation uint16
+
+	// Base and Multiplier are the potency of damage and healing spells
+	Base       uint16
+	Multiplier float32
+
+	// CE and VE are the cumulative and volatile enmity of casting the spell
+	CE uint16
+	VE uint16
+
+	// Range is in tenths of yalms, as LandSandBoat stores it
+	Range uint16
+
+	// EffectID is the status effect the spell applies, with its Power and Duration (in seconds); 0 for none
+	EffectID uint16
+	Power    int32
+	Duration uint32
+}
+
+// Level returns the level a job learns the spell at, or 0 when it cannot.
+func (s Spell) Level(job uint8) uint8 {
+	if job == 0 || int(job) > len(s.Jobs) {
+		return 0
+	}
+
+	return s.Jobs[job-1]
+}
+
+// Yalms returns the range of the spell.
+func (s Spell) Yalms() float64 {
+	return float64(s.Range) / 10
+}
+
+// Ability is the static definition of a job ability. Like spells, abilities are
+// resolved from their data unless the map server has a hook of the ability's name.
+type Ability struct {
+	ID    uint16
+	Name  string
+	Job   uint8
+	Level uint8
+
+	// ValidTarget are the Target flags of what the ability can be used on
+	ValidTarget uint16
+
+	// RecastTime is in seconds; abilities sharing a RecastID share their timer
+	RecastTime uint16
+	RecastID   uint16
+
+	Animation uint16
+
+	// Range is in yalms
+	Range float32
+
+	TPCost uint16
+
+	// CE and VE are the cumulative and volatile enmity of using the ability
+	CE uint16
+	VE uint16
+
+	// EffectID is the status effect the ability applies, with its Power and Duration (in seconds); 0 for none
+	EffectID uint16
+	Power    int32
+	Duration uint32
+}
+
+// spellColumns matches the columns of LandSandBoat's spell_list table, plus the status
+// effect a spell applies (LandSandBoat leaves it to the spell's script)
+//
+//nolint:gochecknoglobals // static column list
+var spellColumns = []string{
+	"spellid", "name", "jobs", "validtargets", "skill", "mpcost", "casttime", "recasttime", "animation",
+	"base", "multiplier", "ce", "ve", "spell_range", "effect", "power", "duration",
+}
+
+// abilityColumns matches the columns of LandSandBoat's abilities table, plus the TP
+// cost and status effect of an ability
+//
+//nolint:gochecknoglobals // static column list
+var abilityColumns = []string{
+	"abilityid", "name", "job", "level", "validtarget", "recasttime", "recastid", "animation", "range",
+	"ce", "ve", "tpcost", "effect", "power", "duration",
+}
+
+// LoadSpells reads the spell list CSV file, keyed by spell ID.
+func LoadSpells(path string) (map[uint16]Spell, error) {
+	rows, err := readCSV(path, spellColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	spells := make(map[uint16]Spell, len(rows))
+	for _, row := range rows {
+		var spell Spell
+		if err = row.parse(
+			uintField(&spell.ID, "spellid"),
+			stringField(&spell.Name, "name"),
+			jobLevelsField(&spell.Jobs, "jobs"),
+			uintField(&spell.ValidTargets, "validtargets"),
+			uintField(&spell.Skill, "skill"),
+			uintField(&spell.MPCost, "mpcost"),
+			uintField(&spell.CastTime, "casttime"),
+			uintField(&spell.RecastTime, "recasttime"),
+			uintField(&spell.Animation, "animation"),
+			uintField(&spell.Base, "base"),
+			floatField(&spell.Multiplier, "multiplier"),
+			uintField(&spell.CE, "ce"),
+			uintField(&spell.VE, "ve"),
+			uintField(&spell.Range, "spell_range"),
+			uintField(&spell.EffectID, "effect"),
+			intField(&spell.Power, "power"),
+			uintField(&spell.Duration, "duration"),
+		); err != nil {
+			return nil, err
+		}
+
+		if _, exists := spells[spell.ID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate spell %d", ErrInvalidData, path, row.line, spell.ID)
+		}
+
+		spells[spell.ID] = spell
+	}
+
+	return spells, nil
+}
+
+// LoadAbilities reads the abilities CSV file, keyed by ability ID.
+func LoadAbilities(path string) (map[uint16]Ability, error) {
+	rows, err := readCSV(path, abilityColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	abilities := make(map[uint16]Ability, len(rows))
+	for _, row := range rows {
+		var ability Ability
+		if err = row.parse(
+			uintField(&ability.ID, "abilityid"),
+			stringField(&ability.Name, "name"),
+			uintField(&ability.Job, "job"),
+			uintField(&ability.Level, "level"),
+			uintField(&ability.ValidTarget, "validtarget"),
+			uintField(&ability.RecastTime, "recasttime"),
+			uintField(&ability.RecastID, "recastid"),
+			uintField(&ability.Animation, "animation"),
+			floatField(&ability.Range, "range"),
+			uintField(&ability.CE, "ce"),
+			uintField(&ability.VE, "ve"),
+			uintField(&ability.TPCost, "tpcost"),
+			uintField(&ability.EffectID, "effect"),
+			intField(&ability.Power, "power"),
+			uintField(&ability.Duration, "duration"),
+		); err != nil {
+			return nil, err
+		}
+
+		if _, exists := abilities[ability.ID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate ability %d", ErrInvalidData, path, row.line, ability.ID)
+		}
+
+		abilities[ability.ID] = ability
+	}
+
+	return abilities, nil
+}
+
+// jobLevelsField parses LandSandBoat's binary jobs column, exported as hex: one byte
+// per job, holding the level it learns the spell at.
+func jobLevelsField(dest *[jobCount]uint8, column string) fieldParser {
+	return func(row csvRow) error {
+		levels, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(row.values[column]), "0x"))
+		if err != nil || len(levels) != jobCount {
+			return row.errorf(column, "%q is not a %d-byte hex job list", row.values[column], jobCount)
+		}
+
+		copy(dest[:], levels)
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+package gamedata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadSpells(t *testing.T) {
+	path := writeFile(t, "spell_list.csv", `spellid,name,jobs,group,validtargets,skill,mpcost,casttime,recasttime,animation,base,multiplier,ce,ve,spell_range,effect,power,duration
+1,cure,0x00000100050000000000000000000000000000000000,6,63,33,8,2000,5000,1,20,1.5,0,0,204,0,0,0
+`)
+
+	spells, err := LoadSpells(path)
+	if err != nil {
+		t.Fatalf("LoadSpells() error = %v", err)
+	}
+
+	cure := spells[1]
+	if cure.Name != "cure" || cure.Multiplier != 1.5 || cure.Yalms() != 20.4 || cure.Skill != SkillHealing {
+		t.Fatalf("LoadSpells()[1] = %+v", cure)
+	}
+
+	if cure.Level(3) != 1 || cure.Level(5) != 5 || cure.Level(1) != 0 || cure.Level(0) != 0 || cure.Level(23) != 0 {
+		t.Fatalf("Level() = %v, want WHM 1 and RDM 5", cure.Jobs)
+	}
+
+	path = writeFile(t, "spell_list.csv", "spellid,name,jobs,validtargets,skill,mpcost,casttime,recasttime,animation,base,multiplier,ce,ve,spell_range,effect,power,duration\n1,cure,0x0001,63,33,8,2000,5000,1,20,1.5,0,0,204,0,0,0\n")
+	if _, err := LoadSpells(path); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("LoadSpells() error = %v, want %v", err, ErrInvalidData)
+	}
+}
+
+func TestLoadAbilities(t *testing.T) {
+	path := writeFile(t, "abilities.csv", "abilityid,name,job,level,validtarget,recasttime,recastid,animation,range,ce,ve,tpcost,effect,power,duration\n35,provoke,1,5,4,30,5,0,16.2,1,1800,0,0,0,0\n")
+
+	abilities, err := LoadAbilities(path)
+	if err != nil {
+		t.Fatalf("LoadAbilities() error = %v", err)
+	}
+
+	want := Ability{ID: 35, Name: "provoke", Job: 1, Level: 5, ValidTarget: TargetEnemy, RecastTime: 30, RecastID: 5, Range: 16.2, CE: 1, VE: 1800}
+	if len(abilities) != 1 || abilities[35] != want {
+		t.Fatalf("LoadAbilities() = %+v, want %+v", abilities, want)
+	}
+
+	path = writeFile(t, "abilities.csv", "abilityid,name,job,level,validtarget,recasttime,recastid,animation,range,ce,ve,tpcost,effect,power,duration\n35,provoke,1,5,4,30,5,0,16.2,1,1800,0,0,0,0\n35,provoke,1,5,4,30,5,0,16.2,1,1800,0,0,0,0\n")
+	if _, err := LoadAbilities(path); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("LoadAbilities() error = %v, want %v", err, ErrInvalidData)
+	}
+}
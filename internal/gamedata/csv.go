@@ -0,0 +1,152 @@
+// Package gamedata loads the static game data (zones, items, mobs, ...) the map
+// servers need. Data files use the column names of LandSandBoat's tables so they
+// can be exported from an existing database.
+package gamedata
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidData = errors.New("invalid game data")
+
+// csvRow is a single data row, with its values keyed by column name.
+type csvRow struct {
+	path   string
+	line   int
+	values map[string]string
+}
+
+// fieldParser parses a single column of a row into its destination.
+type fieldParser func(row csvRow) error
+
+// readCSV reads a CSV file with a header row containing (at least) the required columns.
+// Blank lines and lines starting with # are skipped.
+func readCSV(path string, required []string) ([]csvRow, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	//nolint:errcheck // read-only file
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: missing header: %w", ErrInvalidData, path, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %s: missing column %q", ErrInvalidData, path, name)
+		}
+	}
+
+	var rows []csvRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidData, path, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := csvRow{path: path, line: line, values: make(map[string]string, len(columns))}
+		for name, i := range columns {
+			if i < len(record) {
+				row.values[name] = strings.TrimSpace(record[i])
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (r csvRow) parse(fields ...fieldParser) error {
+	for _, field := range fields {
+		if err := field(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r csvRow) errorf(column, format string, args ...any) error {
+	return fmt.Errorf("%w: %s:%d: column %q: %s", ErrInvalidData, r.path, r.line, column, fmt.Sprintf(format, args...))
+}
+
+type unsigned interface {
+	~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+func uintField[T unsigned](dest *T, column string) fieldParser {
+	return func(row csvRow) error {
+		bits := reflect.TypeFor[T]().Bits()
+
+		value, err := strconv.ParseUint(row.values[column], 10, bits)
+		if err != nil {
+			return row.errorf(column, "%q is not a valid %d-bit unsigned number", row.values[column], bits)
+		}
+
+		*dest = T(value)
+		return nil
+	}
+}
+
+type signed interface {
+	~int8 | ~int16 | ~int32 | ~int64
+}
+
+func intField[T signed](dest *T, column string) fieldParser {
+	return func(row csvRow) error {
+		bits := reflect.TypeFor[T]().Bits()
+
+		value, err := strconv.ParseInt(row.values[column], 10, bits)
+		if err != nil {
+			return row.errorf(column, "%q is not a valid %d-bit number", row.values[column], bits)
+		}
+
+		*dest = T(value)
+		return nil
+	}
+}
+
+func floatField(dest *float32, column string) fieldParser {
+	return func(row csvRow) error {
+		value, err := strconv.ParseFloat(row.values[column], 32)
+		if err != nil {
+			return row.errorf(column, "%q is not a valid number", row.values[column])
+		}
+
+		*dest = float32(value)
+		return nil
+	}
+}
+
+func stringField(dest *string, column string) fieldParser {
+	return func(row csvRow) error {
+		*dest = row.values[column]
+		return nil
+	}
+}
@@ -0,0 +1,317 @@
+package gamedata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+)
+
+// FormatVersion is the data directory layout this package reads. It is bumped when
+// files or columns change in a way older data directories cannot be read with.
+const FormatVersion = 3
+
+// data file names, relative to the data directory
+const (
+	manifestFile      = "manifest.json"
+	itemsFile         = "items.csv"
+	itemEquipmentFile = "item_equipment.csv"
+	itemModsFile      = "item_mods.csv"
+	itemWeaponsFile   = "item_weapon.csv"
+	zonesFile         = "zone_settings.csv"
+	zoneLinesFile     = "zone_lines.csv"
+	npcsFile          = "npc_list.csv"
+	mobGroupsFile     = "mob_groups.csv"
+	spawnPointsFile   = "mob_spawn_points.csv"
+	dropTablesFile    = "mob_droplist.csv"
+	mobPoolsFile      = "mob_pools.csv"
+	mobFamiliesFile   = "mob_family_system.csv"
+	statusEffectsFile = "status_effects.csv"
+	effectModsFile    = "status_effect_mods.csv"
+	spellsFile        = "spell_list.csv"
+	abilitiesFile     = "abilities.csv"
+	startingZonesFile = "starting_zones.csv"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported game data format")
+
+// Manifest describes a data directory.
+type Manifest struct {
+	// Version identifies the content of the data (e.g. the LandSandBoat commit it was exported from)
+	Version string `json:"version"`
+
+	// Format is the layout of the data directory; see FormatVersion
+	Format int `json:"format"`
+}
+
+// Data holds every game data index. It is never modified once loaded, so it can be
+// shared between goroutines; reloading builds a new one.
+type Data struct {
+	Manifest Manifest
+
+	Items       map[uint16]Item
+	Equipment   map[uint16]Equipment
+	ItemMods    map[uint16][]ItemMod
+	Weapons     map[uint16]Weapon
+	Zones       map[uint16]Zone
+	ZoneLines   map[uint32]ZoneLine
+	NPCs        map[uint32]NPC
+	MobGroups   map[MobGroupKey]MobGroup
+	SpawnPoints map[uint32]SpawnPoint
+	DropTables  map[uint32][]Drop
+	MobPools    map[uint32]MobPool
+	MobFamilies map[uint16]MobFamily
+
+	StatusEffects map[uint16]StatusEffect
+	EffectMods    map[uint16][]StatusEffectMod
+
+	Spells    map[uint16]Spell
+	Abilities map[uint16]Ability
+
+	// StartingZones are where new characters start, by nation
+	StartingZones map[uint8][]StartingZone
+
+	// Missing lists the data files that were not found and are treated as empty
+	Missing []string
+}
+
+// Load reads every data file of a data directory. A missing data file is treated as
+// empty and listed in Data.Missing; a missing manifest, or any invalid file, is an error.
+func Load(dir string) (*Data, error) {
+	manifest, err := loadManifest(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	data := &Data{Manifest: manifest}
+	if err = errors.Join(
+		loadFile(data, dir, itemsFile, LoadItems, &data.Items),
+		loadFile(data, dir, itemEquipmentFile, LoadEquipment, &data.Equipment),
+		loadFile(data, dir, itemModsFile, LoadItemMods, &data.ItemMods),
+		loadFile(data, dir, itemWeaponsFile, LoadWeapons, &data.Weapons),
+		loadFile(data, dir, zonesFile, LoadZones, &data.Zones),
+		loadFile(data, dir, zoneLinesFile, LoadZoneLines, &data.ZoneLines),
+		loadFile(data, dir, npcsFile, LoadNPCs, &data.NPCs),
+		loadFile(data, dir, mobGroupsFile, LoadMobGroups, &data.MobGroups),
+		loadFile(data, dir, spawnPointsFile, LoadSpawnPoints, &data.SpawnPoints),
+		loadFile(data, dir, dropTablesFile, LoadDropTables, &data.DropTables),
+		loadFile(data, dir, mobPoolsFile, LoadMobPools, &data.MobPools),
+		loadFile(data, dir, mobFamiliesFile, LoadMobFamilies, &data.MobFamilies),
+		loadFile(data, dir, statusEffectsFile, LoadStatusEffects, &data.StatusEffects),
+		loadFile(data, dir, effectModsFile, LoadStatusEffectMods, &data.EffectMods),
+		loadFile(data, dir, spellsFile, LoadSpells, &data.Spells),
+		loadFile(data, dir, abilitiesFile, LoadAbilities, &data.Abilities),
+		loadFile(data, dir, startingZonesFile, LoadStartingZones, &data.StartingZones),
+	); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// loadFile runs a loader, leaving an empty index when the file does not exist.
+func loadFile[K comparable, V any](data *Data, dir, name string, load func(string) (map[K]V, error), dest *map[K]V) error {
+	index, err := load(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		data.Missing = append(data.Missing, name)
+		*dest = make(map[K]V)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	*dest = index
+	return nil
+}
+
+func loadManifest(path string) (Manifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var manifest Manifest
+	if err = json.Unmarshal(content, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("%w: %s: %w", ErrInvalidData, path, err)
+	}
+
+	if manifest.Format != FormatVersion {
+		return Manifest{}, fmt.Errorf("%w: %s: format %d, want %d", ErrUnsupportedFormat, path, manifest.Format, FormatVersion)
+	}
+
+	return manifest, nil
+}
+
+// Validate checks the references between the indexes (zone lines to zones, spawn
+// points to mob groups, ...) and returns every broken one. References into a missing
+// file are not checked, so a partial data directory stays usable.
+func (d *Data) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidData, fmt.Sprintf(format, args...)))
+	}
+
+	hasItems := !slices.Contains(d.Missing, itemsFile)
+	hasZones := !slices.Contains(d.Missing, zonesFile)
+	hasDropTables := !slices.Contains(d.Missing, dropTablesFile)
+	hasMobPools := !slices.Contains(d.Missing, mobPoolsFile)
+	hasMobFamilies := !slices.Contains(d.Missing, mobFamiliesFile)
+	hasStatusEffects := !slices.Contains(d.Missing, statusEffectsFile)
+
+	for _, equipment := range d.Equipment {
+		if _, ok := d.Items[equipment.ItemID]; hasItems && !ok {
+			invalid("equipment %d: unknown item", equipment.ItemID)
+		}
+	}
+
+	for itemID := range d.ItemMods {
+		if _, ok := d.Items[itemID]; hasItems && !ok {
+			invalid("item mods %d: unknown item", itemID)
+		}
+	}
+
+	for _, weapon := range d.Weapons {
+		if _, ok := d.Items[weapon.ItemID]; hasItems && !ok {
+			invalid("weapon %d: unknown item", weapon.ItemID)
+		}
+	}
+
+	for _, zoneLine := range d.ZoneLines {
+		if _, ok := d.Zones[zoneLine.FromZone]; hasZones && !ok {
+			invalid("zone line %d: unknown zone %d", zoneLine.ID, zoneLine.FromZone)
+		}
+
+		if _, ok := d.Zones[zoneLine.ToZone]; hasZones && !ok && zoneLine.ToZone != MogHouseZoneID {
+			invalid("zone line %d: unknown zone %d", zoneLine.ID, zoneLine.ToZone)
+		}
+	}
+
+	for _, npc := range d.NPCs {
+		if _, ok := d.Zones[npc.Zone()]; hasZones && !ok {
+			invalid("NPC %d: unknown zone %d", npc.ID, npc.Zone())
+		}
+	}
+
+	for _, group := range d.MobGroups {
+		if _, ok := d.Zones[group.ZoneID]; hasZones && !ok {
+			invalid("mob group %d: unknown zone %d", group.GroupID, group.ZoneID)
+		}
+
+		if _, ok := d.DropTables[group.DropID]; hasDropTables && group.DropID != 0 && !ok {
+			invalid("mob group %d in zone %d: unknown drop table %d", group.GroupID, group.ZoneID, group.DropID)
+		}
+
+		if _, ok := d.MobPools[group.PoolID]; hasMobPools && !ok {
+			invalid("mob group %d in zone %d: unknown mob pool %d", group.GroupID, group.ZoneID, group.PoolID)
+		}
+	}
+
+	for _, pool := range d.MobPools {
+		if _, ok := d.MobFamilies[pool.FamilyID]; hasMobFamilies && !ok {
+			invalid("mob pool %d: unknown mob family %d", pool.PoolID, pool.FamilyID)
+		}
+	}
+
+	for _, point := range d.SpawnPoints {
+		if _, ok := d.MobGroups[point.Group()]; !ok {
+			invalid("mob %d: unknown mob group %d in zone %d", point.MobID, point.GroupID, point.Zone())
+		}
+	}
+
+	for _, effect := range d.StatusEffects {
+		if _, ok := d.StatusEffects[effect.NegativeID]; effect.NegativeID != 0 && !ok {
+			invalid("status effect %d: unknown negative effect %d", effect.ID, effect.NegativeID)
+		}
+	}
+
+	for effectID := range d.EffectMods {
+		if _, ok := d.StatusEffects[effectID]; hasStatusEffects && !ok {
+			invalid("status effect mods %d: unknown status effect", effectID)
+		}
+	}
+
+	for _, spell := range d.Spells {
+		if _, ok := d.StatusEffects[spell.EffectID]; hasStatusEffects && spell.EffectID != 0 && !ok {
+			invalid("spell %d: unknown status effect %d", spell.ID, spell.EffectID)
+		}
+	}
+
+	for _, ability := range d.Abilities {
+		if _, ok := d.StatusEffects[ability.EffectID]; hasStatusEffects && ability.EffectID != 0 && !ok {
+			invalid("ability %d: unknown status effect %d", ability.ID, ability.EffectID)
+		}
+	}
+
+	for nation, startingZones := range d.StartingZones {
+		for _, startingZone := range startingZones {
+			if _, ok := d.Zones[startingZone.ZoneID]; hasZones && !ok {
+				invalid("starting zone of nation %d: unknown zone %d", nation, startingZone.ZoneID)
+			}
+		}
+	}
+
+	for dropID, drops := range d.DropTables {
+		for _, drop := range drops {
+			if _, ok := d.Items[drop.ItemID]; hasItems && !ok {
+				invalid("drop table %d: unknown item %d", dropID, drop.ItemID)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Store holds the current game data of a server and swaps it on reload. Callers
+// should fetch Current once per use rather than keep it, so they see reloads.
+type Store struct {
+	dir     string
+	current atomic.Pointer[Data]
+}
+
+// NewStore loads and validates the data directory.
+func NewStore(dir string) (*Store, error) {
+	store := &Store{dir: dir}
+	if _, err := store.Reload(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// NewStoreFromData returns a store holding already loaded data; it cannot be reloaded.
+func NewStoreFromData(data *Data) *Store {
+	store := &Store{}
+	store.current.Store(data)
+
+	return store
+}
+
+// Current returns the game data currently in use.
+func (s *Store) Current() *Data {
+	return s.current.Load()
+}
+
+// Reload loads and validates the data directory again. The current data is only
+// replaced when both succeed, so a bad edit keeps the server on the previous data.
+func (s *Store) Reload() (*Data, error) {
+	if s.dir == "" {
+		return nil, fmt.Errorf("%w: no data directory", ErrInvalidData)
+	}
+
+	data, err := Load(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = data.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.current.Store(data)
+	return data, nil
+}
@@ -0,0 +1,223 @@
+package gamedata
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeDataDir writes a data directory with the given files and a valid manifest.
+func writeDataDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files[manifestFile] = fmt.Sprintf(`{"version": "test", "format": %d}`, FormatVersion)
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	return dir
+}
+
+func validDataFiles() map[string]string {
+	return map[string]string{
+		itemsFile:         "itemid,name,stacksize,flags\n4096,fire_crystal,12,0\n",
+		itemModsFile:      "itemid,modid,value\n4096,8,-2\n",
+		itemWeaponsFile:   "itemid,skill,dmgtype,hit,delay,dmg\n4096,1,4,1,480,3\n",
+		zonesFile:         "zoneid,name,zonetype,music_day,music_night,battlesolo,battlemulti,misc\n100,West_Ronfaure,2,109,109,101,103,0\n101,East_Ronfaure,2,109,109,101,103,0\n",
+		zoneLinesFile:     "zoneline,fromzone,tozone,tox,toy,toz,rotation\n1,100,101,0,0,0,0\n2,100,0,0,0,0,0\n",
+		npcsFile:          "npcid,name,pos_rot,pos_x,pos_y,pos_z,flag,animation,status,look\n17187500,Gate,0,1,2,3,0,0,0,0x0000320000000000000000000000000000000000\n",
+		mobGroupsFile:     "groupid,poolid,zoneid,name,respawntime,spawntype,dropid,hp,mp,minlevel,maxlevel\n1,4321,100,Wild_Rabbit,330,0,7,0,0,1,3\n",
+		spawnPointsFile:   "mobid,mobname,groupid,pos_x,pos_y,pos_z,pos_rot\n17187110,Wild_Rabbit,1,-1,0,5,64\n",
+		dropTablesFile:    "dropid,droptype,itemid,itemrate\n7,0,4096,100\n",
+		mobPoolsFile:      "poolid,name,familyid,modelid,mjob,sjob,cmbdelay,aggro,links\n4321,Wild_Rabbit,206,0x0000940100000000000000000000000000000000,1,1,240,0,0\n",
+		mobFamiliesFile:   "familyid,family,detects\n206,Rabbit,1\n",
+		statusEffectsFile: "id,name,flags,type,negative_id,overwrite\n13,slow,34,0,33,0\n33,haste,33,0,13,0\n",
+		effectModsFile:    "effectid,modid,value\n33,1,1\n",
+		spellsFile:        "spellid,name,jobs,validtargets,skill,mpcost,casttime,recasttime,animation,base,multiplier,ce,ve,spell_range,effect,power,duration\n56,slow,00000000130000000000000000000000000000000000,4,35,15,2000,10000,250,0,0,1,300,204,13,300,180\n",
+		abilitiesFile:     "abilityid,name,job,level,validtarget,recasttime,recastid,animation,range,ce,ve,tpcost,effect,power,duration\n16,haste_samba,19,45,1,60,216,0,0,0,0,350,33,50,120\n",
+		startingZonesFile: "nation,zoneid,pos_x,pos_y,pos_z,pos_rot\n0,100,1,2,3,64\n0,101,-1,0,5,0\n",
+	}
+}
+
+func TestLoad(t *testing.T) {
+	data, err := Load(writeDataDir(t, validDataFiles()))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if err = data.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if mods := data.ItemMods[4096]; len(mods) != 1 || mods[0] != (ItemMod{ItemID: 4096, ModID: 8, Value: -2}) {
+		t.Fatalf("ItemMods[4096] = %+v", mods)
+	}
+
+	if data.Manifest.Version != "test" {
+		t.Fatalf("Manifest.Version = %q, want %q", data.Manifest.Version, "test")
+	}
+
+	if want := []string{itemEquipmentFile}; !slices.Equal(data.Missing, want) {
+		t.Fatalf("Missing = %v, want %v", data.Missing, want)
+	}
+
+	if data.Equipment == nil || len(data.Equipment) != 0 {
+		t.Fatalf("Equipment = %v, want an empty index", data.Equipment)
+	}
+
+	point := data.SpawnPoints[17187110]
+	if point.Zone() != 100 || point.Group() != (MobGroupKey{ZoneID: 100, GroupID: 1}) {
+		t.Fatalf("spawn point zone = %d, group = %+v", point.Zone(), point.Group())
+	}
+
+	if startingZones := data.StartingZones[0]; len(startingZones) != 2 || startingZones[0] != (StartingZone{ZoneID: 100, X: 1, Y: 2, Z: 3, Rotation: 64}) {
+		t.Fatalf("StartingZones[0] = %+v", startingZones)
+	}
+
+	if got := data.Zones[100].Music(); got != [5]uint16{109, 109, 101, 103, mountMusic} {
+		t.Fatalf("Music() = %v", got)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	files := validDataFiles()
+	dir := writeDataDir(t, files)
+
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), fmt.Appendf(nil, `{"version": "test", "format": %d}`, FormatVersion+1), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := Load(dir); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("Load() error = %v, want %v", err, ErrUnsupportedFormat)
+	}
+
+	if _, err := Load(t.TempDir()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Load() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{name: "zone line to unknown zone", file: zoneLinesFile, data: "zoneline,fromzone,tozone,tox,toy,toz,rotation\n1,100,999,0,0,0,0\n"},
+		{name: "NPC in unknown zone", file: npcsFile, data: "npcid,name,pos_rot,pos_x,pos_y,pos_z,flag,animation,status,look\n16781312,Gate,0,1,2,3,0,0,0,0x0000320000000000000000000000000000000000\n"},
+		{name: "unknown drop table", file: mobGroupsFile, data: "groupid,poolid,zoneid,name,respawntime,spawntype,dropid,hp,mp,minlevel,maxlevel\n1,4321,100,Wild_Rabbit,330,0,8,0,0,1,3\n"},
+		{name: "unknown mob pool", file: mobGroupsFile, data: "groupid,poolid,zoneid,name,respawntime,spawntype,dropid,hp,mp,minlevel,maxlevel\n1,4322,100,Wild_Rabbit,330,0,7,0,0,1,3\n"},
+		{name: "unknown mob family", file: mobPoolsFile, data: "poolid,name,familyid,modelid,mjob,sjob,cmbdelay,aggro,links\n4321,Wild_Rabbit,207,0x0000940100000000000000000000000000000000,1,1,240,0,0\n"},
+		{name: "unknown mob group", file: spawnPointsFile, data: "mobid,mobname,groupid,pos_x,pos_y,pos_z,pos_rot\n17187110,Wild_Rabbit,2,-1,0,5,64\n"},
+		{name: "unknown drop item", file: dropTablesFile, data: "dropid,droptype,itemid,itemrate\n7,0,4097,100\n"},
+		{name: "unknown modified item", file: itemModsFile, data: "itemid,modid,value\n4097,8,1\n"},
+		{name: "unknown equipment item", file: itemEquipmentFile, data: "itemid,level,jobs,mid,slot,race\n4097,1,1,1,1,1\n"},
+		{name: "unknown negative effect", file: statusEffectsFile, data: "id,name,flags,type,negative_id,overwrite\n33,haste,33,0,13,0\n"},
+		{name: "unknown modified effect", file: effectModsFile, data: "effectid,modid,value\n41,1,1\n"},
+		{name: "unknown spell effect", file: spellsFile, data: "spellid,name,jobs,validtargets,skill,mpcost,casttime,recasttime,animation,base,multiplier,ce,ve,spell_range,effect,power,duration\n56,slow,00000000130000000000000000000000000000000000,4,35,15,2000,10000,250,0,0,1,300,204,14,300,180\n"},
+		{name: "unknown ability effect", file: abilitiesFile, data: "abilityid,name,job,level,validtarget,recasttime,recastid,animation,range,ce,ve,tpcost,effect,power,duration\n16,haste_samba,19,45,1,60,216,0,0,0,0,350,34,50,120\n"},
+		{name: "starting zone in unknown zone", file: startingZonesFile, data: "nation,zoneid,pos_x,pos_y,pos_z,pos_rot\n1,999,0,0,0,0\n"},
+		{name: "unknown weapon item", file: itemWeaponsFile, data: "itemid,skill,dmgtype,hit,delay,dmg\n4097,1,4,1,480,3\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := validDataFiles()
+			files[tt.file] = tt.data
+
+			data, err := Load(writeDataDir(t, files))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if err = data.Validate(); !errors.Is(err, ErrInvalidData) {
+				t.Fatalf("Validate() error = %v, want %v", err, ErrInvalidData)
+			}
+		})
+	}
+}
+
+func TestValidateSkipsMissingFiles(t *testing.T) {
+	files := validDataFiles()
+	delete(files, zonesFile)
+
+	data, err := Load(writeDataDir(t, files))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if err = data.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want zone references unchecked", err)
+	}
+}
+
+func TestStoreReload(t *testing.T) {
+	dir := writeDataDir(t, validDataFiles())
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	before := store.Current()
+
+	// a broken reference must not replace the data in use
+	content := "zoneline,fromzone,tozone,tox,toy,toz,rotation\n1,100,999,0,0,0,0\n"
+	if err = os.WriteFile(filepath.Join(dir, zoneLinesFile), []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err = store.Reload(); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("Reload() error = %v, want %v", err, ErrInvalidData)
+	}
+
+	if store.Current() != before {
+		t.Fatalf("Current() changed after a failed reload")
+	}
+
+	content = "zoneline,fromzone,tozone,tox,toy,toz,rotation\n1,100,101,0,0,0,0\n2,101,100,0,0,0,0\n"
+	if err = os.WriteFile(filepath.Join(dir, zoneLinesFile), []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err = store.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if got := len(store.Current().ZoneLines); got != 2 {
+		t.Fatalf("zone lines after reload = %d, want 2", got)
+	}
+}
+
+func TestLoadShippedData(t *testing.T) {
+	data, err := Load(filepath.Join("..", "..", "resources", "gamedata"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if err = data.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	// the starter set a fresh server plays with
+	counts := map[string]int{
+		"items":          len(data.Items),
+		"equipment":      len(data.Equipment),
+		"weapons":        len(data.Weapons),
+		"status effects": len(data.StatusEffects),
+		"spells":         len(data.Spells),
+		"abilities":      len(data.Abilities),
+		"mob groups":     len(data.MobGroups),
+		"spawn points":   len(data.SpawnPoints),
+	}
+
+	for name, count := range counts {
+		if count == 0 {
+			t.Errorf("shipped data has no %s", name)
+		}
+	}
+}
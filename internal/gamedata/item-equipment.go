@@ -0,0 +1,77 @@
+package gamedata
+
+import (
+	"fmt"
+)
+
+// Equipment is the static definition of an item that can be equipped.
+type Equipment struct {
+	ItemID uint16
+
+	// Level is the job level required to equip the item
+	Level uint8
+
+	// Jobs is a bitmask of the jobs that can equip the item (bit 0 = WAR)
+	Jobs uint32
+
+	// ModelID is the model shown on the character for head, body, hands, legs, feet,
+	// main, sub and ranged items
+	ModelID uint16
+
+	// Slots is a bitmask of the equipment slots the item fits in (bit 0 = main)
+	Slots uint16
+
+	// Races is a bitmask of the races that can equip the item (bit 0 = Hume male)
+	Races uint16
+}
+
+// AllowsSlot reports whether the item fits in an equipment slot.
+func (e Equipment) AllowsSlot(slot uint8) bool {
+	return slot < 16 && e.Slots&(1<<slot) != 0
+}
+
+// AllowsJob reports whether a job (1 = WAR) can equip the item.
+func (e Equipment) AllowsJob(job uint8) bool {
+	return job >= 1 && job <= 32 && e.Jobs&(1<<(job-1)) != 0
+}
+
+// AllowsRace reports whether a race (1 = Hume male) can equip the item.
+func (e Equipment) AllowsRace(race uint8) bool {
+	return race >= 1 && race <= 16 && e.Races&(1<<(race-1)) != 0
+}
+
+// equipmentColumns matches the columns of LandSandBoat's item_equipment table
+//
+//nolint:gochecknoglobals // static column list
+var equipmentColumns = []string{"itemid", "level", "jobs", "mid", "slot", "race"}
+
+// LoadEquipment reads the item equipment CSV file, keyed by item ID.
+func LoadEquipment(path string) (map[uint16]Equipment, error) {
+	rows, err := readCSV(path, equipmentColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	equipment := make(map[uint16]Equipment, len(rows))
+	for _, row := range rows {
+		var item Equipment
+		if err = row.parse(
+			uintField(&item.ItemID, "itemid"),
+			uintField(&item.Level, "level"),
+			uintField(&item.Jobs, "jobs"),
+			uintField(&item.ModelID, "mid"),
+			uintField(&item.Slots, "slot"),
+			uintField(&item.Races, "race"),
+		); err != nil {
+			return nil, err
+		}
+
+		if _, exists := equipment[item.ItemID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate equipment %d", ErrInvalidData, path, row.line, item.ItemID)
+		}
+
+		equipment[item.ItemID] = item
+	}
+
+	return equipment, nil
+}
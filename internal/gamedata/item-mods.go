@@ -0,0 +1,38 @@
+package gamedata
+
+// ItemMod is a modifier an item gives while equipped (e.g. +5 STR). ModID uses
+// LandSandBoat's modifier IDs.
+type ItemMod struct {
+	ItemID uint16
+	ModID  uint16
+	Value  int16
+}
+
+// itemModColumns matches the columns of LandSandBoat's item_mods table
+//
+//nolint:gochecknoglobals // static column list
+var itemModColumns = []string{"itemid", "modid", "value"}
+
+// LoadItemMods reads the item modifiers CSV file, keyed by item ID.
+func LoadItemMods(path string) (map[uint16][]ItemMod, error) {
+	rows, err := readCSV(path, itemModColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	mods := make(map[uint16][]ItemMod)
+	for _, row := range rows {
+		var mod ItemMod
+		if err = row.parse(
+			uintField(&mod.ItemID, "itemid"),
+			uintField(&mod.ModID, "modid"),
+			intField(&mod.Value, "value"),
+		); err != nil {
+			return nil, err
+		}
+
+		mods[mod.ItemID] = append(mods[mod.ItemID], mod)
+	}
+
+	return mods, nil
+}
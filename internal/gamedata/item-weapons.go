@@ -0,0 +1,65 @@
+package gamedata
+
+import (
+	"fmt"
+)
+
+// Weapon is the static definition of the combat stats of a weapon.
+type Weapon struct {
+	ItemID uint16
+
+	// Skill is the combat skill the weapon uses (1 = hand-to-hand)
+	Skill uint8
+
+	// DamageType is how the weapon deals damage (piercing, slashing, blunt, hand-to-hand)
+	DamageType uint8
+
+	// Hits is how many times the weapon strikes per attack round
+	Hits uint8
+
+	// Delay is the time between attack rounds, in 1/60ths of a second
+	Delay uint16
+
+	// Damage is the base damage of a hit
+	Damage uint16
+}
+
+// weaponColumns matches the columns of LandSandBoat's item_weapon table
+//
+//nolint:gochecknoglobals // static column list
+var weaponColumns = []string{"itemid", "skill", "dmgtype", "hit", "delay", "dmg"}
+
+// LoadWeapons reads the item weapon CSV file, keyed by item ID.
+func LoadWeapons(path string) (map[uint16]Weapon, error) {
+	rows, err := readCSV(path, weaponColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	weapons := make(map[uint16]Weapon, len(rows))
+	for _, row := range rows {
+		var weapon Weapon
+		if err = row.parse(
+			uintField(&weapon.ItemID, "itemid"),
+			uintField(&weapon.Skill, "skill"),
+			uintField(&weapon.DamageType, "dmgtype"),
+			uintField(&weapon.Hits, "hit"),
+			uintField(&weapon.Delay, "delay"),
+			uintField(&weapon.Damage, "dmg"),
+		); err != nil {
+			return nil, err
+		}
+
+		if weapon.Delay == 0 {
+			return nil, row.errorf("delay", "weapons need a delay")
+		}
+
+		if _, exists := weapons[weapon.ItemID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate weapon %d", ErrInvalidData, path, row.line, weapon.ItemID)
+		}
+
+		weapons[weapon.ItemID] = weapon
+	}
+
+	return weapons, nil
+}
@@ -0,0 +1,55 @@
+package gamedata
+
+import (
+	"fmt"
+)
+
+// Item is the static definition of an item.
+type Item struct {
+	ID   uint16
+	Name string
+
+	// StackSize is how many of the item fit in a single container slot
+	StackSize uint8
+
+	// Flags are LandSandBoat's item flags (rare, ex, ...)
+	Flags uint16
+}
+
+// itemColumns matches the columns of LandSandBoat's item_basic table
+//
+//nolint:gochecknoglobals // static column list
+var itemColumns = []string{"itemid", "name", "stacksize", "flags"}
+
+// LoadItems reads the items CSV file, keyed by item ID.
+func LoadItems(path string) (map[uint16]Item, error) {
+	rows, err := readCSV(path, itemColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[uint16]Item, len(rows))
+	for _, row := range rows {
+		var item Item
+		if err = row.parse(
+			uintField(&item.ID, "itemid"),
+			stringField(&item.Name, "name"),
+			uintField(&item.StackSize, "stacksize"),
+			uintField(&item.Flags, "flags"),
+		); err != nil {
+			return nil, err
+		}
+
+		if item.StackSize == 0 {
+			return nil, row.errorf("stacksize", "must be at least 1")
+		}
+
+		if _, exists := items[item.ID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate item %d", ErrInvalidData, path, row.line, item.ID)
+		}
+
+		items[item.ID] = item
+	}
+
+	return items, nil
+}
@@ -0,0 +1,49 @@
+package gamedata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadItems(t *testing.T) {
+	path := writeFile(t, "items.csv", `itemid,subid,name,sortname,stacksize,flags
+4096,0,fire_crystal,fire_crystal,12,0
+65535,0,gil,gil,1,0
+`)
+
+	items, err := LoadItems(path)
+	if err != nil {
+		t.Fatalf("LoadItems() error = %v", err)
+	}
+
+	want := Item{ID: 4096, Name: "fire_crystal", StackSize: 12}
+	if len(items) != 2 || items[4096] != want {
+		t.Fatalf("LoadItems() = %+v, want %+v among 2 items", items, want)
+	}
+
+	path = writeFile(t, "items.csv", "itemid,name,stacksize,flags\n1,a,0,0\n")
+	if _, err := LoadItems(path); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("LoadItems() error = %v, want %v", err, ErrInvalidData)
+	}
+}
+
+func TestLoadWeapons(t *testing.T) {
+	path := writeFile(t, "item_weapon.csv", `itemId,name,skill,subskill,ilvl_skill,ilvl_parry,ilvl_macc,dmgType,hit,delay,dmg,unlock_points
+16640,bronze_axe,5,0,0,0,0,1,1,276,11,0
+`)
+
+	weapons, err := LoadWeapons(path)
+	if err != nil {
+		t.Fatalf("LoadWeapons() error = %v", err)
+	}
+
+	want := Weapon{ItemID: 16640, Skill: 5, DamageType: 1, Hits: 1, Delay: 276, Damage: 11}
+	if len(weapons) != 1 || weapons[16640] != want {
+		t.Fatalf("LoadWeapons() = %+v, want %+v", weapons, want)
+	}
+
+	path = writeFile(t, "item_weapon.csv", "itemid,skill,dmgtype,hit,delay,dmg\n16640,5,1,1,0,11\n")
+	if _, err := LoadWeapons(path); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("LoadWeapons() error = %v, want %v", err, ErrInvalidData)
+	}
+}
@@ -0,0 +1,307 @@
+package gamedata
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Detection flags of a mob family, matching LandSandBoat's detects column
+const (
+	DetectSight   uint16 = 0x0001
+	DetectHearing uint16 = 0x0002
+	DetectLowHP   uint16 = 0x0004
+	DetectMagic   uint16 = 0x0020
+	DetectScent   uint16 = 0x0100
+)
+
+// lookSize is the size of LandSandBoat's binary look column
+const lookSize = 20
+
+// MobGroupKey identifies a mob group; group IDs are only unique within a zone.
+type MobGroupKey struct {
+	ZoneID  uint16
+	GroupID uint32
+}
+
+// MobGroup holds what the mobs spawned from a group share.
+type MobGroup struct {
+	GroupID uint32
+	ZoneID  uint16
+	PoolID  uint32
+	Name    string
+
+	// RespawnSeconds is how long after dying the group's mobs respawn
+	RespawnSeconds uint32
+
+	// SpawnType is LandSandBoat's spawn type (normal, scripted, time of day, ...)
+	SpawnType uint32
+
+	// DropID is the drop table of the group's mobs, or 0 for none
+	DropID uint32
+
+	// HP and MP override the computed values when not 0
+	HP uint32
+	MP uint32
+
+	MinLevel uint8
+	MaxLevel uint8
+}
+
+// Key returns the key of the group in the mob group index.
+func (g MobGroup) Key() MobGroupKey {
+	return MobGroupKey{ZoneID: g.ZoneID, GroupID: g.GroupID}
+}
+
+// MobPool holds what mobs of the same kind share across zones.
+type MobPool struct {
+	PoolID   uint32
+	Name     string
+	FamilyID uint16
+
+	// ModelID is the model the client draws the mob with
+	ModelID uint16
+
+	MainJob uint8
+	SubJob  uint8
+
+	// CombatDelay is the delay between two melee attacks, in 1/60ths of a second
+	CombatDelay uint16
+
+	// Aggro mobs attack the players they detect; Links mobs join mobs of their group in a fight
+	Aggro bool
+	Links bool
+}
+
+// MobFamily holds what mobs of the same family (rabbits, goblins, ...) share.
+type MobFamily struct {
+	FamilyID uint16
+	Name     string
+
+	// Detects holds the Detect flags of how the family notices players
+	Detects uint16
+}
+
+// SpawnPoint is where a mob spawns.
+type SpawnPoint struct {
+	// MobID is the entity ID of the mob; it encodes the zone and target index
+	MobID   uint32
+	Name    string
+	GroupID uint32
+
+	X        float32
+	Y        float32
+	Z        float32
+	Rotation uint8
+}
+
+// Zone returns the zone the mob spawns in.
+func (p SpawnPoint) Zone() uint16 {
+	return EntityZone(p.MobID)
+}
+
+// Group returns the key of the mob's group.
+func (p SpawnPoint) Group() MobGroupKey {
+	return MobGroupKey{ZoneID: p.Zone(), GroupID: p.GroupID}
+}
+
+// Drop is an item a mob may drop. ItemRate is out of 1000.
+type Drop struct {
+	DropType uint8
+	ItemID   uint16
+	ItemRate uint16
+}
+
+// mob data columns match those of LandSandBoat's mob_groups, mob_spawn_points and mob_droplist tables
+//
+//nolint:gochecknoglobals // static column lists
+var (
+	mobGroupColumns   = []string{"groupid", "poolid", "zoneid", "name", "respawntime", "spawntype", "dropid", "hp", "mp", "minlevel", "maxlevel"}
+	spawnPointColumns = []string{"mobid", "mobname", "groupid", "pos_x", "pos_y", "pos_z", "pos_rot"}
+	dropColumns       = []string{"dropid", "droptype", "itemid", "itemrate"}
+	mobPoolColumns    = []string{"poolid", "name", "familyid", "modelid", "mjob", "sjob", "cmbdelay", "aggro", "links"}
+	mobFamilyColumns  = []string{"familyid", "family", "detects"}
+)
+
+// LoadMobGroups reads the mob groups CSV file.
+func LoadMobGroups(path string) (map[MobGroupKey]MobGroup, error) {
+	rows, err := readCSV(path, mobGroupColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[MobGroupKey]MobGroup, len(rows))
+	for _, row := range rows {
+		var group MobGroup
+		if err = row.parse(
+			uintField(&group.GroupID, "groupid"),
+			uintField(&group.PoolID, "poolid"),
+			uintField(&group.ZoneID, "zoneid"),
+			stringField(&group.Name, "name"),
+			uintField(&group.RespawnSeconds, "respawntime"),
+			uintField(&group.SpawnType, "spawntype"),
+			uintField(&group.DropID, "dropid"),
+			uintField(&group.HP, "hp"),
+			uintField(&group.MP, "mp"),
+			uintField(&group.MinLevel, "minlevel"),
+			uintField(&group.MaxLevel, "maxlevel"),
+		); err != nil {
+			return nil, err
+		}
+
+		if group.MinLevel > group.MaxLevel {
+			return nil, row.errorf("minlevel", "%d is above maxlevel %d", group.MinLevel, group.MaxLevel)
+		}
+
+		if _, exists := groups[group.Key()]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate mob group %d in zone %d", ErrInvalidData, path, row.line, group.GroupID, group.ZoneID)
+		}
+
+		groups[group.Key()] = group
+	}
+
+	return groups, nil
+}
+
+// LoadSpawnPoints reads the mob spawn points CSV file, keyed by mob ID.
+func LoadSpawnPoints(path string) (map[uint32]SpawnPoint, error) {
+	rows, err := readCSV(path, spawnPointColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make(map[uint32]SpawnPoint, len(rows))
+	for _, row := range rows {
+		var point SpawnPoint
+		if err = row.parse(
+			uintField(&point.MobID, "mobid"),
+			stringField(&point.Name, "mobname"),
+			uintField(&point.GroupID, "groupid"),
+			floatField(&point.X, "pos_x"),
+			floatField(&point.Y, "pos_y"),
+			floatField(&point.Z, "pos_z"),
+			uintField(&point.Rotation, "pos_rot"),
+		); err != nil {
+			return nil, err
+		}
+
+		if _, exists := points[point.MobID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate spawn point for mob %d", ErrInvalidData, path, row.line, point.MobID)
+		}
+
+		points[point.MobID] = point
+	}
+
+	return points, nil
+}
+
+// LoadDropTables reads the mob drop list CSV file, keyed by drop ID.
+func LoadDropTables(path string) (map[uint32][]Drop, error) {
+	rows, err := readCSV(path, dropColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[uint32][]Drop)
+	for _, row := range rows {
+		var dropID uint32
+		var drop Drop
+		if err = row.parse(
+			uintField(&dropID, "dropid"),
+			uintField(&drop.DropType, "droptype"),
+			uintField(&drop.ItemID, "itemid"),
+			uintField(&drop.ItemRate, "itemrate"),
+		); err != nil {
+			return nil, err
+		}
+
+		if drop.ItemRate > 1000 {
+			return nil, row.errorf("itemrate", "%d is above 1000", drop.ItemRate)
+		}
+
+		tables[dropID] = append(tables[dropID], drop)
+	}
+
+	return tables, nil
+}
+
+// LoadMobPools reads the mob pools CSV file, keyed by pool ID.
+func LoadMobPools(path string) (map[uint32]MobPool, error) {
+	rows, err := readCSV(path, mobPoolColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	pools := make(map[uint32]MobPool, len(rows))
+	for _, row := range rows {
+		var pool MobPool
+		var aggro, links uint8
+		if err = row.parse(
+			uintField(&pool.PoolID, "poolid"),
+			stringField(&pool.Name, "name"),
+			uintField(&pool.FamilyID, "familyid"),
+			modelField(&pool.ModelID, "modelid"),
+			uintField(&pool.MainJob, "mjob"),
+			uintField(&pool.SubJob, "sjob"),
+			uintField(&pool.CombatDelay, "cmbdelay"),
+			uintField(&aggro, "aggro"),
+			uintField(&links, "links"),
+		); err != nil {
+			return nil, err
+		}
+
+		pool.Aggro = aggro != 0
+		pool.Links = links != 0
+
+		if _, exists := pools[pool.PoolID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate mob pool %d", ErrInvalidData, path, row.line, pool.PoolID)
+		}
+
+		pools[pool.PoolID] = pool
+	}
+
+	return pools, nil
+}
+
+// LoadMobFamilies reads the mob families CSV file, keyed by family ID.
+func LoadMobFamilies(path string) (map[uint16]MobFamily, error) {
+	rows, err := readCSV(path, mobFamilyColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	families := make(map[uint16]MobFamily, len(rows))
+	for _, row := range rows {
+		var family MobFamily
+		if err = row.parse(
+			uintField(&family.FamilyID, "familyid"),
+			stringField(&family.Name, "family"),
+			uintField(&family.Detects, "detects"),
+		); err != nil {
+			return nil, err
+		}
+
+		if _, exists := families[family.FamilyID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate mob family %d", ErrInvalidData, path, row.line, family.FamilyID)
+		}
+
+		families[family.FamilyID] = family
+	}
+
+	return families, nil
+}
+
+// modelField parses LandSandBoat's binary look column, exported as hex, into the
+// model ID it holds for mobs (the second 16-bit word).
+func modelField(dest *uint16, column string) fieldParser {
+	return func(row csvRow) error {
+		look, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(row.values[column]), "0x"))
+		if err != nil || len(look) != lookSize {
+			return row.errorf(column, "%q is not a %d-byte hex look", row.values[column], lookSize)
+		}
+
+		*dest = binary.LittleEndian.Uint16(look[2:4])
+		return nil
+	}
+}
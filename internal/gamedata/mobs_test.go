@@ -0,0 +1,61 @@
+package gamedata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadMobGroupsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "levels reversed", content: "groupid,poolid,zoneid,name,respawntime,spawntype,dropid,hp,mp,minlevel,maxlevel\n1,1,100,a,0,0,0,0,0,5,3\n"},
+		{name: "duplicate", content: "groupid,poolid,zoneid,name,respawntime,spawntype,dropid,hp,mp,minlevel,maxlevel\n1,1,100,a,0,0,0,0,0,1,3\n1,1,100,b,0,0,0,0,0,1,3\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadMobGroups(writeFile(t, "mob_groups.csv", tt.content)); !errors.Is(err, ErrInvalidData) {
+				t.Fatalf("LoadMobGroups() error = %v, want %v", err, ErrInvalidData)
+			}
+		})
+	}
+
+	// the same group ID may be used in another zone
+	groups, err := LoadMobGroups(writeFile(t, "mob_groups.csv", "groupid,poolid,zoneid,name,respawntime,spawntype,dropid,hp,mp,minlevel,maxlevel\n1,1,100,a,0,0,0,0,0,1,3\n1,1,101,b,0,0,0,0,0,1,3\n"))
+	if err != nil || len(groups) != 2 {
+		t.Fatalf("LoadMobGroups() = %d groups, %v, want 2", len(groups), err)
+	}
+}
+
+func TestLoadDropTables(t *testing.T) {
+	tables, err := LoadDropTables(writeFile(t, "mob_droplist.csv", "dropid,droptype,itemid,itemrate\n7,0,4096,100\n7,0,4097,50\n8,2,4098,1000\n"))
+	if err != nil {
+		t.Fatalf("LoadDropTables() error = %v", err)
+	}
+
+	if len(tables[7]) != 2 || tables[8][0] != (Drop{DropType: 2, ItemID: 4098, ItemRate: 1000}) {
+		t.Fatalf("LoadDropTables() = %+v", tables)
+	}
+
+	if _, err := LoadDropTables(writeFile(t, "mob_droplist.csv", "dropid,droptype,itemid,itemrate\n7,0,4096,1001\n")); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("LoadDropTables() error = %v, want %v", err, ErrInvalidData)
+	}
+}
+
+func TestLoadMobPools(t *testing.T) {
+	pools, err := LoadMobPools(writeFile(t, "mob_pools.csv", "poolid,name,familyid,modelid,mjob,sjob,cmbdelay,aggro,links\n4321,Wild_Rabbit,206,0x0000940100000000000000000000000000000000,1,1,240,1,0\n"))
+	if err != nil {
+		t.Fatalf("LoadMobPools() error = %v", err)
+	}
+
+	want := MobPool{PoolID: 4321, Name: "Wild_Rabbit", FamilyID: 206, ModelID: 0x0194, MainJob: 1, SubJob: 1, CombatDelay: 240, Aggro: true}
+	if pools[4321] != want {
+		t.Fatalf("LoadMobPools() = %+v, want %+v", pools[4321], want)
+	}
+
+	if _, err := LoadMobPools(writeFile(t, "mob_pools.csv", "poolid,name,familyid,modelid,mjob,sjob,cmbdelay,aggro,links\n1,a,1,0x0194,1,1,240,1,0\n")); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("LoadMobPools() error = %v, want %v", err, ErrInvalidData)
+	}
+}
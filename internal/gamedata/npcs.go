@@ -0,0 +1,85 @@
+package gamedata
+
+import (
+	"fmt"
+)
+
+// NPC is an NPC placed in a zone.
+type NPC struct {
+	// ID is the NPC's entity ID; it encodes the zone and target index
+	ID   uint32
+	Name string
+
+	X        float32
+	Y        float32
+	Z        float32
+	Rotation uint8
+
+	// ModelID is the model the client draws the NPC with
+	ModelID uint16
+
+	// Flags, Animation and Status are sent as-is in the NPC's entity updates
+	Flags     uint32
+	Animation uint8
+	Status    uint8
+}
+
+// Zone returns the zone the NPC is in.
+func (n NPC) Zone() uint16 {
+	return EntityZone(n.ID)
+}
+
+// Index returns the NPC's target index in its zone.
+func (n NPC) Index() uint16 {
+	return EntityIndex(n.ID)
+}
+
+// EntityZone returns the zone encoded in an NPC or mob entity ID.
+func EntityZone(entityID uint32) uint16 {
+	return uint16((entityID >> 12) & 0x0FFF)
+}
+
+// EntityIndex returns the target index encoded in an NPC or mob entity ID.
+func EntityIndex(entityID uint32) uint16 {
+	return uint16(entityID & 0x0FFF)
+}
+
+// npcColumns matches the columns of LandSandBoat's npc_list table
+//
+//nolint:gochecknoglobals // static column list
+var npcColumns = []string{"npcid", "name", "pos_rot", "pos_x", "pos_y", "pos_z", "flag", "animation", "status", "look"}
+
+// LoadNPCs reads the NPC list CSV file, keyed by NPC ID.
+func LoadNPCs(path string) (map[uint32]NPC, error) {
+	rows, err := readCSV(path, npcColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	npcs := make(map[uint32]NPC, len(rows))
+	for _, row := range rows {
+		var npc NPC
+		if err = row.parse(
+			uintField(&npc.ID, "npcid"),
+			stringField(&npc.Name, "name"),
+			uintField(&npc.Rotation, "pos_rot"),
+			floatField(&npc.X, "pos_x"),
+			floatField(&npc.Y, "pos_y"),
+			floatField(&npc.Z, "pos_z"),
+			uintField(&npc.Flags, "flag"),
+			uintField(&npc.Animation, "animation"),
+			uintField(&npc.Status, "status"),
+			modelField(&npc.ModelID, "look"),
+		); err != nil {
+			return nil, err
+		}
+
+		if _, exists := npcs[npc.ID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate NPC %d", ErrInvalidData, path, row.line, npc.ID)
+		}
+
+		npcs[npc.ID] = npc
+	}
+
+	return npcs, nil
+}
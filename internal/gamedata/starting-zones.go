@@ -0,0 +1,44 @@
+package gamedata
+
+// StartingZone is a place where new characters of a nation start, and their first home point.
+type StartingZone struct {
+	Nation   uint8
+	ZoneID   uint16
+	X        float32
+	Y        float32
+	Z        float32
+	Rotation uint8
+}
+
+// startingZoneColumns are the columns of the starting zones CSV file
+//
+//nolint:gochecknoglobals // static column list
+var startingZoneColumns = []string{"nation", "zoneid", "pos_x", "pos_y", "pos_z", "pos_rot"}
+
+// LoadStartingZones reads the starting zones CSV file, keyed by nation (0 = San d'Oria,
+// 1 = Bastok, 2 = Windurst).
+func LoadStartingZones(path string) (map[uint8][]StartingZone, error) {
+	rows, err := readCSV(path, startingZoneColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	startingZones := make(map[uint8][]StartingZone)
+	for _, row := range rows {
+		var startingZone StartingZone
+		if err = row.parse(
+			uintField(&startingZone.Nation, "nation"),
+			uintField(&startingZone.ZoneID, "zoneid"),
+			floatField(&startingZone.X, "pos_x"),
+			floatField(&startingZone.Y, "pos_y"),
+			floatField(&startingZone.Z, "pos_z"),
+			uintField(&startingZone.Rotation, "pos_rot"),
+		); err != nil {
+			return nil, err
+		}
+
+		startingZones[startingZone.Nation] = append(startingZones[startingZone.Nation], startingZone)
+	}
+
+	return startingZones, nil
+}
@@ -0,0 +1,96 @@
+package gamedata
+
+import (
+	"fmt"
+)
+
+// StatusEffect is the static definition of a status effect (a buff or debuff).
+type StatusEffect struct {
+	ID   uint16
+	Name string
+
+	// Flags are LandSandBoat's effect flags (what removes the effect: dispel, erase, death, zoning, ...)
+	Flags uint32
+
+	// Type groups effects that overwrite each other (e.g. the tiers of an effect); 0 for none
+	Type uint16
+
+	// NegativeID is the opposite effect (e.g. slow for haste), which the effect replaces; 0 for none
+	NegativeID uint16
+
+	// Overwrite is how the effect is applied over one of the same ID or type (see the effects package)
+	Overwrite uint8
+}
+
+// StatusEffectMod is a modifier a status effect gives, per point of its power (e.g. +1
+// DEF per power of protect). ModID uses LandSandBoat's modifier IDs.
+type StatusEffectMod struct {
+	EffectID uint16
+	ModID    uint16
+	Value    int16
+}
+
+// statusEffectColumns matches the columns of LandSandBoat's status_effects table
+//
+//nolint:gochecknoglobals // static column list
+var statusEffectColumns = []string{"id", "name", "flags", "type", "negative_id", "overwrite"}
+
+// statusEffectModColumns are the columns of the status effect modifiers file
+//
+//nolint:gochecknoglobals // static column list
+var statusEffectModColumns = []string{"effectid", "modid", "value"}
+
+// LoadStatusEffects reads the status effects CSV file, keyed by effect ID.
+func LoadStatusEffects(path string) (map[uint16]StatusEffect, error) {
+	rows, err := readCSV(path, statusEffectColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	effects := make(map[uint16]StatusEffect, len(rows))
+	for _, row := range rows {
+		var effect StatusEffect
+		if err = row.parse(
+			uintField(&effect.ID, "id"),
+			stringField(&effect.Name, "name"),
+			uintField(&effect.Flags, "flags"),
+			uintField(&effect.Type, "type"),
+			uintField(&effect.NegativeID, "negative_id"),
+			uintField(&effect.Overwrite, "overwrite"),
+		); err != nil {
+			return nil, err
+		}
+
+		if _, exists := effects[effect.ID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate status effect %d", ErrInvalidData, path, row.line, effect.ID)
+		}
+
+		effects[effect.ID] = effect
+	}
+
+	return effects, nil
+}
+
+// LoadStatusEffectMods reads the status effect modifiers CSV file, keyed by effect ID.
+func LoadStatusEffectMods(path string) (map[uint16][]StatusEffectMod, error) {
+	rows, err := readCSV(path, statusEffectModColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	mods := make(map[uint16][]StatusEffectMod)
+	for _, row := range rows {
+		var mod StatusEffectMod
+		if err = row.parse(
+			uintField(&mod.EffectID, "effectid"),
+			uintField(&mod.ModID, "modid"),
+			intField(&mod.Value, "value"),
+		); err != nil {
+			return nil, err
+		}
+
+		mods[mod.EffectID] = append(mods[mod.EffectID], mod)
+	}
+
+	return mods, nil
+}
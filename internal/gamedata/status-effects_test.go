@@ -0,0 +1,40 @@
+package gamedata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadStatusEffects(t *testing.T) {
+	path := writeFile(t, "status_effects.csv", `id,name,flags,type,negative_id,overwrite,block_id,remove_id,element,min_duration,sort_key
+40,protect,33,0,0,0,0,0,0,0,5
+`)
+
+	effects, err := LoadStatusEffects(path)
+	if err != nil {
+		t.Fatalf("LoadStatusEffects() error = %v", err)
+	}
+
+	want := StatusEffect{ID: 40, Name: "protect", Flags: 33}
+	if len(effects) != 1 || effects[40] != want {
+		t.Fatalf("LoadStatusEffects() = %+v, want %+v", effects, want)
+	}
+
+	path = writeFile(t, "status_effects.csv", "id,name,flags,type,negative_id,overwrite\n40,protect,33,0,0,0\n40,protect,33,0,0,0\n")
+	if _, err := LoadStatusEffects(path); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("LoadStatusEffects() error = %v, want %v", err, ErrInvalidData)
+	}
+}
+
+func TestLoadStatusEffectMods(t *testing.T) {
+	path := writeFile(t, "status_effect_mods.csv", "effectid,modid,value\n40,1,1\n3,404,1\n40,29,-1\n")
+
+	mods, err := LoadStatusEffectMods(path)
+	if err != nil {
+		t.Fatalf("LoadStatusEffectMods() error = %v", err)
+	}
+
+	if len(mods[40]) != 2 || mods[40][1] != (StatusEffectMod{EffectID: 40, ModID: 29, Value: -1}) {
+		t.Fatalf("LoadStatusEffectMods()[40] = %+v", mods[40])
+	}
+}
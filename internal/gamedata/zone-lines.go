@@ -0,0 +1,83 @@
+package gamedata
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// MogHouseZoneID is the destination of the zone lines leading into a mog house
+const MogHouseZoneID = 0
+
+// ZoneLine is a region that moves a character to another zone. The client sends
+// the zone line's ID when the character walks into it.
+type ZoneLine struct {
+	ID         uint32
+	FromZone   uint16
+	ToZone     uint16
+	ToX        float32
+	ToY        float32
+	ToZ        float32
+	ToRotation uint8
+}
+
+// zoneLineColumns matches the columns of LandSandBoat's zonelines table
+//
+//nolint:gochecknoglobals // static column list
+var zoneLineColumns = []string{"zoneline", "fromzone", "tozone", "tox", "toy", "toz", "rotation"}
+
+// LoadZoneLines reads the zone lines CSV file, keyed by zone line ID.
+func LoadZoneLines(path string) (map[uint32]ZoneLine, error) {
+	rows, err := readCSV(path, zoneLineColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	zoneLines := make(map[uint32]ZoneLine, len(rows))
+	for _, row := range rows {
+		var zoneLine ZoneLine
+		if err = row.parse(
+			uintField(&zoneLine.ID, "zoneline"),
+			uintField(&zoneLine.FromZone, "fromzone"),
+			uintField(&zoneLine.ToZone, "tozone"),
+			floatField(&zoneLine.ToX, "tox"),
+			floatField(&zoneLine.ToY, "toy"),
+			floatField(&zoneLine.ToZ, "toz"),
+			uintField(&zoneLine.ToRotation, "rotation"),
+		); err != nil {
+			return nil, err
+		}
+
+		if _, exists := zoneLines[zoneLine.ID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate zone line %d", ErrInvalidData, path, row.line, zoneLine.ID)
+		}
+
+		zoneLines[zoneLine.ID] = zoneLine
+	}
+
+	return zoneLines, nil
+}
+
+// ZoneEntry returns where characters enter a zone when no position is given: the
+// starting position of new characters if it is a starting zone, or else where the zone
+// line with the lowest ID into it leads. Starting positions are returned as a zone line
+// of ID 0.
+func (d *Data) ZoneEntry(zoneID uint16) (ZoneLine, bool) {
+	for _, nation := range slices.Sorted(maps.Keys(d.StartingZones)) {
+		for _, startingZone := range d.StartingZones[nation] {
+			if startingZone.ZoneID == zoneID {
+				return ZoneLine{ToZone: zoneID, ToX: startingZone.X, ToY: startingZone.Y, ToZ: startingZone.Z, ToRotation: startingZone.Rotation}, true
+			}
+		}
+	}
+
+	var entry ZoneLine
+	found := false
+	for _, zoneLine := range d.ZoneLines {
+		if zoneLine.ToZone == zoneID && (!found || zoneLine.ID < entry.ID) {
+			entry, found = zoneLine, true
+		}
+	}
+
+	return entry, found
+}
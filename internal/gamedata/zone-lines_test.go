@@ -0,0 +1,96 @@
+package gamedata
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return path
+}
+
+func TestLoadZoneLines(t *testing.T) {
+	path := writeFile(t, "zone_lines.csv", `# comment
+toz,zoneline,fromzone,tozone,tox,toy,rotation
+-92.5,1,230,231,10.5,0,128
+
+3,2,231,230,-1,2,0
+`)
+
+	zoneLines, err := LoadZoneLines(path)
+	if err != nil {
+		t.Fatalf("LoadZoneLines() error = %v", err)
+	}
+
+	if len(zoneLines) != 2 {
+		t.Fatalf("LoadZoneLines() returned %d zone lines, want 2", len(zoneLines))
+	}
+
+	want := ZoneLine{ID: 1, FromZone: 230, ToZone: 231, ToX: 10.5, ToY: 0, ToZ: -92.5, ToRotation: 128}
+	if zoneLines[1] != want {
+		t.Fatalf("zoneLines[1] = %+v, want %+v", zoneLines[1], want)
+	}
+}
+
+func TestLoadZoneLinesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "missing column", content: "zoneline,fromzone,tozone,tox,toy,toz\n1,2,3,4,5,6\n"},
+		{name: "invalid number", content: "zoneline,fromzone,tozone,tox,toy,toz,rotation\n1,2,3,x,5,6,7\n"},
+		{name: "out of range", content: "zoneline,fromzone,tozone,tox,toy,toz,rotation\n1,2,3,4,5,6,256\n"},
+		{name: "duplicate", content: "zoneline,fromzone,tozone,tox,toy,toz,rotation\n1,2,3,4,5,6,7\n1,2,3,4,5,6,7\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadZoneLines(writeFile(t, "zone_lines.csv", tt.content)); !errors.Is(err, ErrInvalidData) {
+				t.Fatalf("LoadZoneLines() error = %v, want %v", err, ErrInvalidData)
+			}
+		})
+	}
+
+	if _, err := LoadZoneLines(filepath.Join(t.TempDir(), "missing.csv")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("LoadZoneLines() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestZoneEntry(t *testing.T) {
+	data := &Data{
+		ZoneLines: map[uint32]ZoneLine{
+			1: {ID: 1, FromZone: 230, ToZone: 231, ToX: 10},
+			2: {ID: 2, FromZone: 232, ToZone: 100, ToX: 20},
+			3: {ID: 3, FromZone: 231, ToZone: 100, ToX: 30},
+		},
+		StartingZones: map[uint8][]StartingZone{
+			0: {{Nation: 0, ZoneID: 231, X: 40, Rotation: 64}},
+		},
+	}
+
+	tests := []struct {
+		zoneID uint16
+		want   ZoneLine
+		ok     bool
+	}{
+		// starting zones win over zone lines
+		{231, ZoneLine{ToZone: 231, ToX: 40, ToRotation: 64}, true},
+		{100, ZoneLine{ID: 2, FromZone: 232, ToZone: 100, ToX: 20}, true},
+		{230, ZoneLine{}, false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := data.ZoneEntry(tt.zoneID); got != tt.want || ok != tt.ok {
+			t.Fatalf("ZoneEntry(%d) = %+v, %v, want %+v, %v", tt.zoneID, got, ok, tt.want, tt.ok)
+		}
+	}
+}
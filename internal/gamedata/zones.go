@@ -0,0 +1,78 @@
+package gamedata
+
+import (
+	"fmt"
+)
+
+const (
+	// mountMusic is the music played while riding a chocobo (the same in every zone)
+	mountMusic = 212
+
+	// ZoneMiscMogMenu is the Misc flag of zones with a mog house menu (jobs can be changed there)
+	ZoneMiscMogMenu uint16 = 0x0040
+)
+
+// Zone is the metadata of a zone.
+type Zone struct {
+	ID   uint16
+	Name string
+
+	// Type is LandSandBoat's zone type (city, outdoors, dungeon, ...)
+	Type uint8
+
+	MusicDay         uint16
+	MusicNight       uint16
+	MusicBattleSolo  uint16
+	MusicBattleParty uint16
+
+	// Misc holds LandSandBoat's zone flags (mog house access, fellows, ...)
+	Misc uint16
+}
+
+// Music returns the music of the zone, in the order the login packet expects.
+func (z Zone) Music() [5]uint16 {
+	return [5]uint16{z.MusicDay, z.MusicNight, z.MusicBattleSolo, z.MusicBattleParty, mountMusic}
+}
+
+// HasMogMenu reports whether characters can use the mog house menu in the zone.
+func (z Zone) HasMogMenu() bool {
+	return z.Misc&ZoneMiscMogMenu != 0
+}
+
+// zoneColumns matches the columns of LandSandBoat's zone_settings table
+//
+//nolint:gochecknoglobals // static column list
+var zoneColumns = []string{"zoneid", "name", "zonetype", "music_day", "music_night", "battlesolo", "battlemulti", "misc"}
+
+// LoadZones reads the zone settings CSV file, keyed by zone ID.
+func LoadZones(path string) (map[uint16]Zone, error) {
+	rows, err := readCSV(path, zoneColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make(map[uint16]Zone, len(rows))
+	for _, row := range rows {
+		var zone Zone
+		if err = row.parse(
+			uintField(&zone.ID, "zoneid"),
+			stringField(&zone.Name, "name"),
+			uintField(&zone.Type, "zonetype"),
+			uintField(&zone.MusicDay, "music_day"),
+			uintField(&zone.MusicNight, "music_night"),
+			uintField(&zone.MusicBattleSolo, "battlesolo"),
+			uintField(&zone.MusicBattleParty, "battlemulti"),
+			uintField(&zone.Misc, "misc"),
+		); err != nil {
+			return nil, err
+		}
+
+		if _, exists := zones[zone.ID]; exists {
+			return nil, fmt.Errorf("%w: %s:%d: duplicate zone %d", ErrInvalidData, path, row.line, zone.ID)
+		}
+
+		zones[zone.ID] = zone
+	}
+
+	return zones, nil
+}
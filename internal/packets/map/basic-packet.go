@@ -5,14 +5,36 @@ import "encoding/json"
 type BasicPacket struct {
 	Type     uint16
 	Size     uint16
-	Sequence uint16 `json:"omitempty"`
-	Data     []byte
+	Sequence uint16 `json:",omitempty"`
+
+	// Data holds the whole sub-packet (header included) for packets sent by the client,
+	// and only the payload for packets sent by the server; the router adds that header.
+	Data []byte
 }
 
 type RoutedPacket struct {
 	ClientAddr  string
-	CharacterID uint32 `json:"omitempty"`
+	CharacterID uint32 `json:",omitempty"`
 	Packet      BasicPacket
+
+	// ZoneID is set on a server packet that sends the client to another zone; once the
+	// packet is delivered, the router rotates the session key and routes to that zone.
+	ZoneID uint16 `json:",omitempty"`
+
+	// Disconnect is set on the last server packet sent to a client that leaves the game;
+	// once the packet is delivered, the router ends the client's session.
+	Disconnect bool `json:",omitempty"`
+}
+
+// ClientDisconnect is published by the router when a client stopped responding.
+type ClientDisconnect struct {
+	ClientAddr  string
+	CharacterID uint32
+}
+
+func (cd *ClientDisconnect) ToJSON() []byte {
+	bytes, _ := json.Marshal(cd)
+	return bytes
 }
 
 func (rp *RoutedPacket) ToJSON() []byte {
@@ -100,6 +100,33 @@ type LoginPacket struct {
 	DammyArea uint16
 }
 
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeLogin,
+		Name:    "login",
+		MinSize: PacketSizeLogin,
+		Parse:   parseLoginSubPacket,
+	})
+}
+
+func (p *LoginPacket) Type() uint16 {
+	return PacketTypeLogin
+}
+
+func parseLoginSubPacket(data []byte) (Packet, error) {
+	packet, err := decode[LoginPacket](data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !packet.validateChecksum(data) {
+		return nil, fmt.Errorf("invalid login packet checksum")
+	}
+
+	return packet, nil
+}
+
 func (p *LoginPacket) validateChecksum(rawData []byte) bool {
 	// In Go, we need to calculate the offset manually
 	// The checksum starts from the Unknown01 field
@@ -0,0 +1,58 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypePosition uint16 = 0x0015
+	PacketSizePosition uint16 = 0x001C
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x0015/README.md
+type PositionPacket struct {
+	Header mapPackets.PacketHeader
+
+	// The client's position. PosZ is the vertical axis.
+	PosX float32
+	PosZ float32
+	PosY float32
+
+	// The time, in frames, the client has been moving for.
+	MovTime uint16
+
+	// The number of frames the client has spent moving since the last update.
+	MoveFrame uint16
+
+	// The direction the client is facing (0-255, a full turn).
+	Direction uint8
+
+	// Movement state flags.
+	//
+	// bit 0 - the client is in target mode
+	// bit 1 - the client is running (as opposed to walking)
+	// bit 2 - the client is standing on the ground
+	Flags uint8
+
+	// The target index of the entity the client is facing or targeting.
+	FaceTarget uint16
+
+	// The client's timestamp when the packet was built.
+	TimeNow uint32
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypePosition,
+		Name:    "position",
+		MinSize: PacketSizePosition,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[PositionPacket](data)
+		},
+	})
+}
+
+func (p *PositionPacket) Type() uint16 {
+	return PacketTypePosition
+}
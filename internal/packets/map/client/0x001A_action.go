@@ -0,0 +1,55 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeAction uint16 = 0x001A
+	PacketSizeAction uint16 = 0x001C
+)
+
+// Action IDs of the action packet
+const (
+	ActionTalk         uint16 = 0x00
+	ActionEngage       uint16 = 0x02
+	ActionCastMagic    uint16 = 0x03
+	ActionDisengage    uint16 = 0x04
+	ActionWeaponSkill  uint16 = 0x07
+	ActionJobAbility   uint16 = 0x09
+	ActionHomePoint    uint16 = 0x0B
+	ActionChangeTarget uint16 = 0x0F
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x001A/README.md
+type ActionPacket struct {
+	Header mapPackets.PacketHeader
+
+	// The server id of the action's target.
+	UniqueNo uint32
+
+	// The target index of the action's target.
+	ActIndex uint16
+
+	// The action to perform (see the Action values).
+	ActionID uint16
+
+	// Action specific parameters (the spell or ability id, ...).
+	ActionBuf [4]uint32
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeAction,
+		Name:    "action",
+		MinSize: PacketSizeAction,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[ActionPacket](data)
+		},
+	})
+}
+
+func (p *ActionPacket) Type() uint16 {
+	return PacketTypeAction
+}
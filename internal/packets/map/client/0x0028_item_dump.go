@@ -0,0 +1,43 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeItemDump uint16 = 0x0028
+	PacketSizeItemDump uint16 = 0x000C
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x0028/README.md
+type ItemDumpPacket struct {
+	Header mapPackets.PacketHeader
+
+	// The quantity of the item to drop.
+	ItemNum uint32
+
+	// The container holding the item.
+	Category uint8
+
+	// The index of the item within the container.
+	ItemIndex uint8
+
+	// Padding; unused.
+	Padding0A uint16
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeItemDump,
+		Name:    "item dump",
+		MinSize: PacketSizeItemDump,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[ItemDumpPacket](data)
+		},
+	})
+}
+
+func (p *ItemDumpPacket) Type() uint16 {
+	return PacketTypeItemDump
+}
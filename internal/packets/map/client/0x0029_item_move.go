@@ -0,0 +1,46 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeItemMove uint16 = 0x0029
+	PacketSizeItemMove uint16 = 0x000C
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x0029/README.md
+type ItemMovePacket struct {
+	Header mapPackets.PacketHeader
+
+	// The quantity of the item to move.
+	ItemNum uint32
+
+	// The container the item is moved from.
+	Category1 uint8
+
+	// The container the item is moved to.
+	Category2 uint8
+
+	// The index of the item within the source container.
+	ItemIndex1 uint8
+
+	// The index within the destination container, or 0x52 for any free slot.
+	ItemIndex2 uint8
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeItemMove,
+		Name:    "item move",
+		MinSize: PacketSizeItemMove,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[ItemMovePacket](data)
+		},
+	})
+}
+
+func (p *ItemMovePacket) Type() uint16 {
+	return PacketTypeItemMove
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeItemStack uint16 = 0x003A
+	PacketSizeItemStack uint16 = 0x0008
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x003A/README.md
+type ItemStackPacket struct {
+	Header mapPackets.PacketHeader
+
+	// The container to sort (merge the partial stacks of).
+	Category uint8
+
+	// Padding; unused.
+	Padding05 [3]uint8
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeItemStack,
+		Name:    "item stack",
+		MinSize: PacketSizeItemStack,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[ItemStackPacket](data)
+		},
+	})
+}
+
+func (p *ItemStackPacket) Type() uint16 {
+	return PacketTypeItemStack
+}
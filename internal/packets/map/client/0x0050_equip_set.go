@@ -0,0 +1,43 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeEquipSet uint16 = 0x0050
+	PacketSizeEquipSet uint16 = 0x0008
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x0050/README.md
+type EquipSetPacket struct {
+	Header mapPackets.PacketHeader
+
+	// The index of the item within the container; 0 unequips the slot.
+	PropertyItemIndex uint8
+
+	// The equipment slot enumeration id.
+	EquipKind uint8
+
+	// The container holding the item being equipped.
+	Category uint8
+
+	// Padding; unused.
+	Padding07 uint8
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeEquipSet,
+		Name:    "equip set",
+		MinSize: PacketSizeEquipSet,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[EquipSetPacket](data)
+		},
+	})
+}
+
+func (p *EquipSetPacket) Type() uint16 {
+	return PacketTypeEquipSet
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeEventEnd uint16 = 0x005B
+	PacketSizeEventEnd uint16 = 0x0014
+)
+
+// Modes of the event end packet
+const (
+	// EventEndModeFinish ends the event
+	EventEndModeFinish uint16 = 0x00
+
+	// EventEndModeUpdate keeps the event going and asks the server for new parameters
+	EventEndModeUpdate uint16 = 0x01
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x005B/README.md
+type EventEndPacket struct {
+	Header mapPackets.PacketHeader
+
+	// The server id of the entity the event is with.
+	UniqueNo uint32
+
+	// The option the player chose in the event (its result).
+	EndPara uint32
+
+	// The target index of the entity the event is with.
+	ActIndex uint16
+
+	// Whether the event ends or asks for an update (see the EventEndMode values).
+	Mode uint16
+
+	// The zone whose event table holds the event.
+	EventNum uint16
+
+	// The event ID.
+	EventPara uint16
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeEventEnd,
+		Name:    "event end",
+		MinSize: PacketSizeEventEnd,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[EventEndPacket](data)
+		},
+	})
+}
+
+func (p *EventEndPacket) Type() uint16 {
+	return PacketTypeEventEnd
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeZoneLine uint16 = 0x005E
+	PacketSizeZoneLine uint16 = 0x0018
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x005E/README.md
+type ZoneLinePacket struct {
+	Header mapPackets.PacketHeader
+
+	// The ID of the zone line (map rect) the client walked into.
+	RectID uint32
+
+	// The client's position when it entered the zone line. PosZ is the vertical axis.
+	PosX float32
+	PosZ float32
+	PosY float32
+
+	// The client's target index.
+	ActIndex uint16
+
+	// The mog house exit the client chose, when leaving a mog house.
+	MyRoomExitBit  uint8
+	MyRoomExitMode uint8
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeZoneLine,
+		Name:    "zone line",
+		MinSize: PacketSizeZoneLine,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[ZoneLinePacket](data)
+		},
+	})
+}
+
+func (p *ZoneLinePacket) Type() uint16 {
+	return PacketTypeZoneLine
+}
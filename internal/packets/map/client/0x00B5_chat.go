@@ -0,0 +1,63 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeChat uint16 = 0x00B5
+	PacketSizeChat uint16 = 0x0008
+)
+
+// chatMessageOffset is where the message starts in the chat packet
+const chatMessageOffset = 0x06
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x00B5/README.md
+type ChatPacket struct {
+	Header mapPackets.PacketHeader
+
+	// The chat channel the message is sent to (say, shout, party, ...).
+	Kind uint8
+
+	// Unknown; set by the client for some channels.
+	Unknown05 uint8
+
+	// The message, without its terminating null byte.
+	Message []byte
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeChat,
+		Name:    "chat",
+		MinSize: PacketSizeChat,
+		Parse: func(data []byte) (Packet, error) {
+			return &ChatPacket{
+				Header: mapPackets.PacketHeader{
+					ID:   binary.LittleEndian.Uint16(data[0:2]),
+					Sync: binary.LittleEndian.Uint16(data[2:4]),
+				},
+				Kind:      data[0x04],
+				Unknown05: data[0x05],
+				Message:   cString(data[chatMessageOffset:]),
+			}, nil
+		},
+	})
+}
+
+func (p *ChatPacket) Type() uint16 {
+	return PacketTypeChat
+}
+
+// cString returns a copy of the bytes before the first null byte.
+func cString(data []byte) []byte {
+	if end := bytes.IndexByte(data, 0); end >= 0 {
+		data = data[:end]
+	}
+
+	return bytes.Clone(data)
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"encoding/binary"
+
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeTell uint16 = 0x00B6
+	PacketSizeTell uint16 = 0x0018
+)
+
+const (
+	// tellNameOffset and tellNameLength locate the recipient's name in the tell packet
+	tellNameOffset = 0x06
+	tellNameLength = 15
+
+	// tellMessageOffset is where the message starts in the tell packet
+	tellMessageOffset = tellNameOffset + tellNameLength
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x00B6/README.md
+type TellPacket struct {
+	Header mapPackets.PacketHeader
+
+	// Unknown; not used by the server.
+	Unknown04 uint16
+
+	// The name of the character the tell is sent to.
+	Name string
+
+	// The message, without its terminating null byte.
+	Message []byte
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeTell,
+		Name:    "tell",
+		MinSize: PacketSizeTell,
+		Parse: func(data []byte) (Packet, error) {
+			return &TellPacket{
+				Header: mapPackets.PacketHeader{
+					ID:   binary.LittleEndian.Uint16(data[0:2]),
+					Sync: binary.LittleEndian.Uint16(data[2:4]),
+				},
+				Unknown04: binary.LittleEndian.Uint16(data[0x04:]),
+				Name:      string(cString(data[tellNameOffset : tellNameOffset+tellNameLength])),
+				Message:   cString(data[tellMessageOffset:]),
+			}, nil
+		},
+	})
+}
+
+func (p *TellPacket) Type() uint16 {
+	return PacketTypeTell
+}
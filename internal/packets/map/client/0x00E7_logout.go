@@ -0,0 +1,49 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeLogout uint16 = 0x00E7
+	PacketSizeLogout uint16 = 0x0008
+)
+
+const (
+	// LogoutModeToggle starts the logout timer, or cancels it if it is already running
+	LogoutModeToggle uint16 = 0
+	LogoutModeStart  uint16 = 1
+	LogoutModeCancel uint16 = 2
+)
+
+const (
+	LogoutKindLogout   uint16 = 1
+	LogoutKindShutdown uint16 = 2
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x00E7/README.md
+type LogoutPacket struct {
+	Header mapPackets.PacketHeader
+
+	// Whether the client starts, cancels or toggles its logout request.
+	Mode uint16
+
+	// Whether the client is logging out (/logout) or quitting the game (/shutdown).
+	Kind uint16
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeLogout,
+		Name:    "logout",
+		MinSize: PacketSizeLogout,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[LogoutPacket](data)
+		},
+	})
+}
+
+func (p *LogoutPacket) Type() uint16 {
+	return PacketTypeLogout
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+const (
+	PacketTypeMyRoomJob uint16 = 0x0100
+	PacketSizeMyRoomJob uint16 = 0x0008
+)
+
+// https://github.com/atom0s/XiPackets/blob/main/world/client/0x0100/README.md
+type MyRoomJobPacket struct {
+	Header mapPackets.PacketHeader
+
+	// The main job to change to; 0 keeps the current main job.
+	MainJobIndex uint8
+
+	// The support job to change to; 0 keeps the current support job.
+	SupportJobIndex uint8
+
+	// Padding; unused.
+	Padding06 [2]uint8
+}
+
+//nolint:gochecknoinits // client packets register their parsers with the registry
+func init() {
+	Register(Definition{
+		Type:    PacketTypeMyRoomJob,
+		Name:    "myroom job",
+		MinSize: PacketSizeMyRoomJob,
+		Parse: func(data []byte) (Packet, error) {
+			return decode[MyRoomJobPacket](data)
+		},
+	})
+}
+
+func (p *MyRoomJobPacket) Type() uint16 {
+	return PacketTypeMyRoomJob
+}
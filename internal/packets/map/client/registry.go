@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+var (
+	ErrUnknownPacket      = errors.New("unknown client packet")
+	ErrPacketSizeMismatch = errors.New("client packet size mismatch")
+	ErrPacketTooSmall     = errors.New("client packet too small")
+)
+
+// Packet is implemented by every parsed client packet.
+type Packet interface {
+	Type() uint16
+}
+
+// ParseFunc decodes a single client sub-packet. The data starts at the 4 byte
+// sub-packet header and has already been checked against Definition.MinSize.
+type ParseFunc func(data []byte) (Packet, error)
+
+// Definition describes how a client packet type is decoded.
+type Definition struct {
+	Type uint16
+	Name string
+
+	// MinSize is the smallest valid size of the packet in bytes, including the sub-packet header.
+	MinSize uint16
+
+	Parse ParseFunc
+}
+
+//nolint:gochecknoglobals // populated once by the packet files' init functions
+var definitions = make(map[uint16]Definition)
+
+// Register adds a packet definition to the registry. Each packet file registers
+// itself from an init function; registering the same type twice panics.
+func Register(definition Definition) {
+	if _, exists := definitions[definition.Type]; exists {
+		panic(fmt.Sprintf("client packet 0x%03X registered twice", definition.Type))
+	}
+
+	if definition.Parse == nil {
+		panic(fmt.Sprintf("client packet 0x%03X registered without a parser", definition.Type))
+	}
+
+	definitions[definition.Type] = definition
+}
+
+// Lookup returns the definition of a packet type.
+func Lookup(packetType uint16) (Definition, bool) {
+	definition, ok := definitions[packetType]
+	return definition, ok
+}
+
+// Parse validates and decodes a client sub-packet (header included).
+func Parse(data []byte) (Packet, Definition, error) {
+	if len(data) < 4 {
+		return nil, Definition{}, fmt.Errorf("%w: %d bytes", ErrPacketTooSmall, len(data))
+	}
+
+	header := mapPackets.PacketHeader{
+		ID:   binary.LittleEndian.Uint16(data[0:2]),
+		Sync: binary.LittleEndian.Uint16(data[2:4]),
+	}
+
+	definition, ok := Lookup(header.GetPacketID())
+	if !ok {
+		return nil, Definition{}, fmt.Errorf("%w: 0x%03X", ErrUnknownPacket, header.GetPacketID())
+	}
+
+	// the header declares the packet size in 4 byte units
+	declaredSize := int(header.GetPacketSize()) * 4
+	if declaredSize != len(data) {
+		return nil, definition, fmt.Errorf("%w: %s declares %d bytes, got %d", ErrPacketSizeMismatch, definition.Name, declaredSize, len(data))
+	}
+
+	if declaredSize < int(definition.MinSize) {
+		return nil, definition, fmt.Errorf("%w: %s must be at least %d bytes, got %d", ErrPacketTooSmall, definition.Name, definition.MinSize, declaredSize)
+	}
+
+	packet, err := definition.Parse(data)
+	if err != nil {
+		return nil, definition, fmt.Errorf("failed to parse %s packet: %w", definition.Name, err)
+	}
+
+	return packet, definition, nil
+}
+
+// decode reads a fixed-layout packet struct from the start of data.
+func decode[T any](data []byte) (*T, error) {
+	var packet T
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &packet); err != nil {
+		return nil, err
+	}
+
+	return &packet, nil
+}
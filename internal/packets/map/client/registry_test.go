@@ -0,0 +1,65 @@
+package client
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func buildLoginSubPacket() []byte {
+	data := make([]byte, PacketSizeLogin)
+	binary.LittleEndian.PutUint16(data[0:], PacketTypeLogin|(PacketSizeLogin/4)<<9)
+	binary.LittleEndian.PutUint32(data[12:], 0x01020304)
+
+	var checksum uint8
+	for _, b := range data[8:] {
+		checksum += b
+	}
+	data[4] = checksum
+
+	return data
+}
+
+func TestParse(t *testing.T) {
+	t.Run("valid packet", func(t *testing.T) {
+		packet, definition, err := Parse(buildLoginSubPacket())
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+
+		if definition.Type != PacketTypeLogin {
+			t.Fatalf("Parse() definition = 0x%03X, want 0x%03X", definition.Type, PacketTypeLogin)
+		}
+
+		login, ok := packet.(*LoginPacket)
+		if !ok {
+			t.Fatalf("Parse() packet = %T, want *LoginPacket", packet)
+		}
+
+		if login.UniqueNo != 0x01020304 {
+			t.Fatalf("UniqueNo = %08X, want %08X", login.UniqueNo, 0x01020304)
+		}
+	})
+
+	t.Run("declared size does not match", func(t *testing.T) {
+		data := buildLoginSubPacket()
+		if _, _, err := Parse(data[:len(data)-4]); !errors.Is(err, ErrPacketSizeMismatch) {
+			t.Fatalf("Parse() error = %v, want %v", err, ErrPacketSizeMismatch)
+		}
+	})
+
+	t.Run("smaller than the packet layout", func(t *testing.T) {
+		data := buildLoginSubPacket()[:8]
+		binary.LittleEndian.PutUint16(data[0:], PacketTypeLogin|2<<9)
+		if _, _, err := Parse(data); !errors.Is(err, ErrPacketTooSmall) {
+			t.Fatalf("Parse() error = %v, want %v", err, ErrPacketTooSmall)
+		}
+	})
+
+	t.Run("unknown packet", func(t *testing.T) {
+		data := []byte{0xFF, 0x03, 0x00, 0x00}
+		if _, _, err := Parse(data); !errors.Is(err, ErrUnknownPacket) {
+			t.Fatalf("Parse() error = %v, want %v", err, ErrUnknownPacket)
+		}
+	})
+}
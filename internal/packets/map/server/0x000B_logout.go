@@ -0,0 +1,59 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	LogoutPacketType = 0x000B
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	LogoutPacketSize = 0x0018
+)
+
+const (
+	// LogoutStateLogout tells the client to log out
+	LogoutStateLogout uint32 = 1
+
+	// LogoutStateZoneChange tells the client to change zones, reconnecting to the given map server
+	LogoutStateZoneChange uint32 = 2
+
+	// LogoutStateShutdown tells the client to quit the game
+	LogoutStateShutdown uint32 = 3
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x000B
+type LogoutPacket struct {
+	// The reason the client is leaving the zone.
+	LogoutState uint32
+
+	// The IP address (network order) and port of the map server the client connects to next.
+	IP   uint32
+	Port uint32
+
+	// Padding; unused.
+	Padding10 [8]uint8
+
+	// The error code displayed by the client, if any.
+	ErrorCode uint32
+}
+
+func (p *LogoutPacket) Type() uint16 {
+	return LogoutPacketType
+}
+
+func (p *LogoutPacket) Size() uint16 {
+	return LogoutPacketSize
+}
+
+func (p *LogoutPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeCharNPC = 0x000E
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeCharNPC = 0x0044
+
+	// CharNPCNameLength is the longest name the packet holds
+	CharNPCNameLength = 20
+)
+
+// Server status values of an NPC or mob (the animation it plays)
+const (
+	CharNPCStatusNormal  uint8 = 0
+	CharNPCStatusEngaged uint8 = 1
+	CharNPCStatusDead    uint8 = 3
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x000E
+type CharNPCPacket struct {
+	// The entity server id.
+	UniqueNo uint32
+
+	// The entity target index.
+	ActIndex uint16
+
+	// The parts of the entity the packet updates.
+	SendFlg CharUpdateSendFlags
+
+	// The entity heading.
+	Dir uint8
+
+	// The entity position; PosZ is the height.
+	PosX float32
+	PosZ float32
+	PosY float32
+
+	// Movement bits; the upper 15 bits hold the target index the entity faces.
+	Flags0 uint32
+
+	// The entity movement speed.
+	Speed uint8
+
+	// The entity base movement speed.
+	SpeedBase uint8
+
+	// The entity health percentage.
+	Hpp uint8
+
+	// The entity server status (see the CharNPCStatus values).
+	ServerStatus uint8
+
+	// Various entity flags.
+	Flags1 uint32
+	Flags2 uint32
+	Flags3 uint32
+
+	// The server id of the character that claimed the entity.
+	BtTargetID uint32
+
+	// The kind of model data that follows; 0 is a model id.
+	SubKind uint16
+
+	// The entity model id.
+	ModelID uint16
+
+	// The entity name.
+	Name [CharNPCNameLength]byte
+}
+
+func (p *CharNPCPacket) Type() uint16 {
+	return PacketTypeCharNPC
+}
+
+func (p *CharNPCPacket) Size() uint16 {
+	return PacketSizeCharNPC
+}
+
+func (p *CharNPCPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
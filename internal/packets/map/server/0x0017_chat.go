@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	ChatPacketType = 0x0017
+
+	// chatPacketHeaderSize is the size of the fields before the message
+	chatPacketHeaderSize = 0x14
+
+	// ChatPacketMaxMessageLength is the longest message the packet carries, in bytes
+	ChatPacketMaxMessageLength = 0xD8
+)
+
+// Chat message kinds, shared by the client chat packets.
+const (
+	ChatKindSay        uint8 = 0
+	ChatKindShout      uint8 = 1
+	ChatKindTell       uint8 = 3
+	ChatKindParty      uint8 = 4
+	ChatKindLinkshell  uint8 = 5
+	ChatKindSystem     uint8 = 6
+	ChatKindEmote      uint8 = 8
+	ChatKindYell       uint8 = 26
+	ChatKindLinkshell2 uint8 = 27
+	ChatKindUnity      uint8 = 33
+)
+
+const (
+	// ChatAttrGM marks the message as sent by a GM
+	ChatAttrGM uint8 = 0x01
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0017
+type ChatPacket struct {
+	// The chat channel the message was sent to.
+	Kind uint8
+
+	// Message attributes (e.g. sent by a GM).
+	Attr uint8
+
+	// Extra data; the zone the message was sent from for yells.
+	Data uint16
+
+	// The name of the sender.
+	Name [15]byte
+
+	// Padding; unused.
+	Padding17 uint8
+
+	// The message; it is truncated to ChatPacketMaxMessageLength bytes.
+	Message []byte
+}
+
+func (p *ChatPacket) Type() uint16 {
+	return ChatPacketType
+}
+
+// Size is the size of the fields plus the null terminated message, rounded up to 4 bytes.
+func (p *ChatPacket) Size() uint16 {
+	length := min(len(p.Message), ChatPacketMaxMessageLength) + 1
+	return uint16((chatPacketHeaderSize + length + 3) &^ 3) //nolint:gosec // bounded by ChatPacketMaxMessageLength
+}
+
+func (p *ChatPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	header := struct {
+		Kind      uint8
+		Attr      uint8
+		Data      uint16
+		Name      [15]byte
+		Padding17 uint8
+	}{p.Kind, p.Attr, p.Data, p.Name, p.Padding17}
+
+	if err := binary.Write(buf, binary.LittleEndian, header); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	buf.Write(p.Message[:min(len(p.Message), ChatPacketMaxMessageLength)])
+
+	// null terminate the message and pad the packet to 4 bytes
+	buf.Write(make([]byte, int(p.Size())-buf.Len()))
+
+	return buf.Bytes(), nil
+}
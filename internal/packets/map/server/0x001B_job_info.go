@@ -0,0 +1,87 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeJobInfo = 0x001B
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeJobInfo = 0x0098
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x001B
+type JobInfoPacket struct {
+	// The local players race id.
+	RaceID uint8
+
+	// Unknown.
+	Unknown05 [3]uint8
+
+	// The local players main job id.
+	MainJobID uint8
+
+	// Unknown.
+	Unknown09 [2]uint8
+
+	// The local players sub job id.
+	SubJobID uint8
+
+	// The local players unlocked job flags.
+	//
+	// Bit N is set when job N is unlocked; bit 0 is set when sub jobs are unlocked.
+	GetJobFlag uint32
+
+	// The array of the local players job levels, for the first 16 jobs (index 0 is unused).
+	JobLevel [16]uint8
+
+	// The local players base stats.
+	BPBase [7]uint16
+
+	// The local players stat adjustments.
+	BPAdj [7]int16
+
+	// The local players max health.
+	HPMax int32
+
+	// The local players max mana.
+	MPMax int32
+
+	// The local players sub job flag.
+	//
+	// This value is used to determine if the player has unlocked and can change
+	// their sub job.
+	SubJobFlag uint8
+
+	// Unknown.
+	Unknown45 [3]uint8
+
+	// The array of the local players job levels, for every job (index 0 is unused).
+	JobLevelEx [24]uint8
+
+	// Unknown.
+	//
+	// This data holds the mentor and master level information, which is not supported.
+	Unknown60 [60]uint8
+}
+
+func (p *JobInfoPacket) Type() uint16 {
+	return PacketTypeJobInfo
+}
+
+func (p *JobInfoPacket) Size() uint16 {
+	return PacketSizeJobInfo
+}
+
+func (p *JobInfoPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	ItemSamePacketType = 0x001D
+	ItemSamePacketSize = 0x0008
+)
+
+const (
+	// ItemSameStateDone tells the client every container has been sent and is up to date
+	ItemSameStateDone uint8 = 0x01
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x001D
+type ItemSamePacket struct {
+	// The state of the containers; the client only considers its inventory
+	// loaded (and usable) once it received the done state.
+	State uint8
+
+	// Padding; unused.
+	Padding05 [3]uint8
+
+	// Bit flags of the containers that were synchronized.
+	Flags uint32
+}
+
+func (p *ItemSamePacket) Type() uint16 {
+	return ItemSamePacketType
+}
+
+func (p *ItemSamePacket) Size() uint16 {
+	return ItemSamePacketSize
+}
+
+func (p *ItemSamePacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	ItemNumPacketType = 0x001E
+	ItemNumPacketSize = 0x0008
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x001E
+type ItemNumPacket struct {
+	// The new quantity of the item; 0 empties the slot.
+	ItemNum uint32
+
+	// The container holding the item.
+	Category uint8
+
+	// The index of the item within the container.
+	ItemIndex uint8
+
+	// The lock state of the item (e.g. equipped, bazaar).
+	LockFlg uint8
+
+	// Padding; unused.
+	Padding0B uint8
+}
+
+func (p *ItemNumPacket) Type() uint16 {
+	return ItemNumPacketType
+}
+
+func (p *ItemNumPacket) Size() uint16 {
+	return ItemNumPacketSize
+}
+
+func (p *ItemNumPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
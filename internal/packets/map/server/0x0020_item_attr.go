@@ -0,0 +1,58 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	ItemAttrPacketType = 0x0020
+	ItemAttrPacketSize = 0x0028
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0020
+type ItemAttrPacket struct {
+	// The quantity of the item.
+	ItemNum uint32
+
+	// The price of the item, when it is for sale in the character's bazaar.
+	Price uint32
+
+	// The item id.
+	ItemNo uint16
+
+	// The container holding the item.
+	Category uint8
+
+	// The index of the item within the container.
+	ItemIndex uint8
+
+	// The lock state of the item (e.g. equipped, bazaar).
+	LockFlg uint8
+
+	// The extra data of the item (signature, augments, charges, ...).
+	Attr [24]uint8
+
+	// Padding; unused.
+	Padding25 [3]uint8
+}
+
+func (p *ItemAttrPacket) Type() uint16 {
+	return ItemAttrPacketType
+}
+
+func (p *ItemAttrPacket) Size() uint16 {
+	return ItemAttrPacketSize
+}
+
+func (p *ItemAttrPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
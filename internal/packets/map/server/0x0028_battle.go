@@ -0,0 +1,207 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	PacketTypeBattle = 0x0028
+
+	// battleHeaderBits is the size of the fields before the first target, counted from
+	// the start of the sub-packet (header included) as the client does
+	battleHeaderBits = 150
+
+	// battleMaxTargets and battleMaxResults are the largest counts the packet carries
+	battleMaxTargets = 0x3FF
+	battleMaxResults = 0x0F
+)
+
+// Battle categories (the kind of action a battle packet shows)
+const (
+	BattleCategoryAttack        uint8 = 1
+	BattleCategoryMagicFinish   uint8 = 4
+	BattleCategoryAbilityFinish uint8 = 6
+	BattleCategoryMagicStart    uint8 = 8
+)
+
+// Command arguments of the magic start category, telling a spell starting from one interrupted
+const (
+	BattleCastStart     uint32 = 24931
+	BattleCastInterrupt uint32 = 28787
+)
+
+// Battle reactions (how the target reacted to the action)
+const (
+	BattleReactionNone  uint8 = 0x00
+	BattleReactionMiss  uint8 = 0x01
+	BattleReactionParry uint8 = 0x03
+	BattleReactionBlock uint8 = 0x04
+	BattleReactionHit   uint8 = 0x08
+	BattleReactionEvade uint8 = 0x09
+)
+
+// Battle special effects (the hit effect the client shows)
+const (
+	BattleEffectNone     uint8 = 0x00
+	BattleEffectHit      uint8 = 0x10
+	BattleEffectCritical uint8 = 0x22
+)
+
+// Basic messages of the battle packet results
+const (
+	MessageHit                   uint16 = 1
+	MessageMagicDamage           uint16 = 2
+	MessageMagicRecoversHP       uint16 = 7
+	MessageMiss                  uint16 = 15
+	MessageIsInterrupted         uint16 = 16
+	MessageCritical              uint16 = 67
+	MessageMagicNoEffect         uint16 = 75
+	MessageUsesAbility           uint16 = 100
+	MessageAbilityRecoversHP     uint16 = 102
+	MessageAbilityDamage         uint16 = 110
+	MessageAbilityNoEffect       uint16 = 156
+	MessageMagicGainsEffect      uint16 = 230
+	MessageMagicEnfeebles        uint16 = 236
+	MessageStartsCasting         uint16 = 327
+	MessageMagicEffectDisappears uint16 = 341
+)
+
+var ErrBattleTooLarge = errors.New("battle packet has too many targets or results")
+
+// BattlePacket is an action of an entity (an auto-attack round, a spell, ...) along
+// with its results on each target. Its fields are bit-packed.
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0028
+type BattlePacket struct {
+	// The server id of the entity performing the action.
+	UniqueNo uint32
+
+	// The kind of action (see the BattleCategory values).
+	Category uint8
+
+	// The action id (the spell, ability, ...); unused by auto-attacks.
+	CmdArg uint32
+
+	// Extra information about the action (the recast of abilities).
+	Info uint32
+
+	// The targets of the action.
+	Targets []BattleTarget
+}
+
+// BattleTarget holds the results of an action on one target.
+type BattleTarget struct {
+	// The server id of the target.
+	UniqueNo uint32
+
+	// The results of the action on the target (one per hit of an attack round).
+	Results []BattleResult
+}
+
+// BattleResult is one result of an action on a target.
+type BattleResult struct {
+	// How the target reacted (see the BattleReaction values). 5 bits.
+	Reaction uint8
+
+	// The animation played (for attacks, which hand or foot strikes). 12 bits.
+	Animation uint16
+
+	// The special effect shown (see the BattleEffect values). 7 bits.
+	Effect uint8
+
+	// How far the target is knocked back. 3 bits.
+	Knockback uint8
+
+	// The message parameter (the damage dealt, ...). 17 bits.
+	Param uint32
+
+	// The message shown for the result. 10 bits.
+	Message uint16
+
+	// Extra information about the result. 31 bits.
+	Info uint32
+}
+
+func (p *BattlePacket) Type() uint16 {
+	return PacketTypeBattle
+}
+
+// Size is the size of the packed fields, rounded up to 4 bytes.
+func (p *BattlePacket) Size() uint16 {
+	return uint16((p.bytes() - 4 + 3) &^ 3) //nolint:gosec // bounded by the target and result counts
+}
+
+// bits returns the number of bits the packet uses, header included.
+func (p *BattlePacket) bits() int {
+	bits := battleHeaderBits
+	for _, target := range p.Targets {
+		// each result ends with the flags of its (unused) additional and spike effects
+		bits += 32 + 4 + len(target.Results)*(5+12+7+3+17+10+31+1+1)
+	}
+
+	return bits
+}
+
+// bytes returns the number of bytes the packet uses, header included.
+func (p *BattlePacket) bytes() int {
+	return (p.bits() + 7) / 8
+}
+
+func (p *BattlePacket) Serialize() ([]byte, error) {
+	if len(p.Targets) > battleMaxTargets {
+		return nil, fmt.Errorf("%w: %d targets", ErrBattleTooLarge, len(p.Targets))
+	}
+
+	for _, target := range p.Targets {
+		if len(target.Results) > battleMaxResults {
+			return nil, fmt.Errorf("%w: %d results", ErrBattleTooLarge, len(target.Results))
+		}
+	}
+
+	// the bits are counted from the start of the sub-packet, whose header the router adds
+	w := bitWriter{data: make([]byte, int(p.Size())+4), offset: 32}
+	w.write(uint64(p.bytes()), 8) //nolint:gosec // the size of the packet
+	w.write(uint64(p.UniqueNo), 32)
+	w.write(uint64(len(p.Targets)), 10)
+	w.write(uint64(p.Category), 4)
+	w.write(uint64(p.CmdArg), 32)
+	w.write(uint64(p.Info), 32)
+
+	for _, target := range p.Targets {
+		w.write(uint64(target.UniqueNo), 32)
+		w.write(uint64(len(target.Results)), 4)
+
+		for _, result := range target.Results {
+			w.write(uint64(result.Reaction), 5)
+			w.write(uint64(result.Animation), 12)
+			w.write(uint64(result.Effect), 7)
+			w.write(uint64(result.Knockback), 3)
+			w.write(uint64(result.Param), 17)
+			w.write(uint64(result.Message), 10)
+			w.write(uint64(result.Info), 31)
+
+			// no additional effect, no spike effect
+			w.write(0, 1)
+			w.write(0, 1)
+		}
+	}
+
+	return w.data[4:], nil
+}
+
+// bitWriter packs values into a buffer, least significant bit first.
+type bitWriter struct {
+	data   []byte
+	offset int
+}
+
+// write packs the low n bits of value; higher bits are dropped.
+func (w *bitWriter) write(value uint64, n int) {
+	for i := range n {
+		if value&(1<<i) != 0 {
+			w.data[w.offset/8] |= 1 << (w.offset % 8)
+		}
+
+		w.offset++
+	}
+}
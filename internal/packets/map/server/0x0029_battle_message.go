@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeBattleMessage = 0x0029
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeBattleMessage = 0x0018
+)
+
+// Basic message IDs (the client message table used by the battle message packets)
+const (
+	MessageOutOfRange     uint16 = 4
+	MessageUnableToSee    uint16 = 5
+	MessageDefeats        uint16 = 6
+	MessageGainExp        uint16 = 8
+	MessageLevelUp        uint16 = 9
+	MessageLoseExp        uint16 = 10
+	MessageLevelDown      uint16 = 11
+	MessageAlreadyClaimed uint16 = 12
+	MessageUnableToCast   uint16 = 18
+	MessageFallsToGround  uint16 = 20
+	MessageNotEnoughMP    uint16 = 34
+	MessageTooFarAway     uint16 = 78
+	MessageUnableToUseJA  uint16 = 87
+	MessageWaitLonger     uint16 = 94
+	MessageCannotOnTarget uint16 = 155
+	MessageNotEnoughTP    uint16 = 192
+	MessageGainsEffect    uint16 = 205
+	MessageEffectWearsOff uint16 = 206
+	MessageTooFarToCast   uint16 = 313
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0029
+type BattleMessagePacket struct {
+	// The server id of the entity the message is from.
+	UniqueNoCas uint32
+
+	// The server id of the entity the message is about.
+	UniqueNoTar uint32
+
+	// The first message parameter.
+	Data uint32
+
+	// The second message parameter.
+	Data2 uint32
+
+	// The target index of the entity the message is from.
+	ActIndexCas uint16
+
+	// The target index of the entity the message is about.
+	ActIndexTar uint16
+
+	// The message id.
+	MessageNum uint16
+
+	// The message type.
+	Kind uint8
+
+	// Padding; unused.
+	Padding1B uint8
+}
+
+func (p *BattleMessagePacket) Type() uint16 {
+	return PacketTypeBattleMessage
+}
+
+func (p *BattleMessagePacket) Size() uint16 {
+	return PacketSizeBattleMessage
+}
+
+func (p *BattleMessagePacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
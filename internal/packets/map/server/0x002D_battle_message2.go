@@ -0,0 +1,62 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeBattleMessage2 = 0x002D
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeBattleMessage2 = 0x0018
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x002D
+type BattleMessage2Packet struct {
+	// The server id of the entity the message is from.
+	UniqueNoCas uint32
+
+	// The server id of the entity the message is about.
+	UniqueNoTar uint32
+
+	// The target index of the entity the message is from.
+	ActIndexCas uint16
+
+	// The target index of the entity the message is about.
+	ActIndexTar uint16
+
+	// The first message parameter.
+	Data uint32
+
+	// The second message parameter.
+	Data2 uint32
+
+	// The message id.
+	MessageNum uint16
+
+	// The message type.
+	Kind uint8
+
+	// Padding; unused.
+	Padding1B uint8
+}
+
+func (p *BattleMessage2Packet) Type() uint16 {
+	return PacketTypeBattleMessage2
+}
+
+func (p *BattleMessage2Packet) Size() uint16 {
+	return PacketSizeBattleMessage2
+}
+
+func (p *BattleMessage2Packet) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeEvent = 0x0032
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeEvent = 0x0010
+)
+
+// EventPacket starts an event (a dialog or cutscene) without parameters.
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0032
+type EventPacket struct {
+	// The entity ID of the NPC the event is with (the character itself for cutscenes).
+	UniqueNo uint32
+
+	// The target index of the NPC the event is with.
+	ActIndex uint16
+
+	// The zone whose event table holds the event.
+	EventNum uint16
+
+	// The event ID.
+	EventPara uint16
+
+	// How the event runs (EventModeDefault).
+	Mode uint16
+
+	// The zone whose text table the event reads its messages from.
+	EventNum2 uint16
+
+	// Unused.
+	EventPara2 uint16
+}
+
+func (p *EventPacket) Type() uint16 {
+	return PacketTypeEvent
+}
+
+func (p *EventPacket) Size() uint16 {
+	return PacketSizeEvent
+}
+
+func (p *EventPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeEventNum = 0x0034
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeEventNum = 0x0030
+)
+
+// EventModeDefault is the mode of regular events and cutscenes.
+const EventModeDefault uint16 = 0x0008
+
+// EventParamCount is the number of parameters an event starts with.
+const EventParamCount = 8
+
+// EventNumPacket starts an event (a dialog or cutscene) with parameters the event
+// script of the client reads.
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0034
+type EventNumPacket struct {
+	// The entity ID of the NPC the event is with (the character itself for cutscenes).
+	UniqueNo uint32
+
+	// The event parameters.
+	Num [EventParamCount]uint32
+
+	// The target index of the NPC the event is with.
+	ActIndex uint16
+
+	// The zone whose event table holds the event.
+	EventNum uint16
+
+	// The event ID.
+	EventPara uint16
+
+	// How the event runs (EventModeDefault).
+	Mode uint16
+
+	// The zone whose text table the event reads its messages from.
+	EventNum2 uint16
+
+	// Unused.
+	EventPara2 uint16
+}
+
+func (p *EventNumPacket) Type() uint16 {
+	return PacketTypeEventNum
+}
+
+func (p *EventNumPacket) Size() uint16 {
+	return PacketSizeEventNum
+}
+
+func (p *EventNumPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeServerStatus = 0x0037
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeServerStatus = 0x005C
+)
+
+// Server status values shared by the character update packets (the animation the
+// client plays for the entity)
+const (
+	ServerStatusNormal  uint8 = 0
+	ServerStatusEngaged uint8 = 1
+	ServerStatusDead    uint8 = 3
+)
+
+// ServerStatusPacket tells the client the state of its own character (its status
+// effect icons, health and animation), which the character update packets only
+// tell about other characters.
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0037
+type ServerStatusPacket struct {
+	// The low bytes of the status effect icons (0xFF for an empty icon).
+	BufStatus [32]uint8
+
+	// The server id of the character.
+	UniqueNo uint32
+
+	// Unknown flags.
+	Flags0 uint16
+
+	// The character's health percentage.
+	Hpp uint8
+
+	// Padding; unused.
+	Padding2B uint8
+
+	// Unknown flags.
+	Flags1 uint32
+
+	// The character's server status (see the ServerStatus values).
+	ServerStatus uint8
+
+	// Unknown; unused.
+	Unknown31 [27]uint8
+
+	// The high 2 bits of each status effect icon.
+	BufStatusHigh uint64
+
+	// Unknown; unused.
+	Unknown54 [12]uint8
+}
+
+func (p *ServerStatusPacket) Type() uint16 {
+	return PacketTypeServerStatus
+}
+
+func (p *ServerStatusPacket) Size() uint16 {
+	return PacketSizeServerStatus
+}
+
+func (p *ServerStatusPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeEventRelease = 0x0052
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeEventRelease = 0x0004
+)
+
+// Modes of the event release packet
+const (
+	// EventReleaseStandard frees the client once it is done talking to an NPC, or with an event
+	EventReleaseStandard uint8 = 0x00
+
+	// EventReleaseEvent frees the client waiting on the server during an event
+	EventReleaseEvent uint8 = 0x01
+)
+
+// EventReleasePacket frees a client locked in place by an interaction or an event.
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0052
+type EventReleasePacket struct {
+	// What the client is released from (see the EventRelease values).
+	Mode uint8
+
+	// Padding; unused.
+	Padding05 [3]uint8
+}
+
+func (p *EventReleasePacket) Type() uint16 {
+	return PacketTypeEventRelease
+}
+
+func (p *EventReleasePacket) Size() uint16 {
+	return PacketSizeEventRelease
+}
+
+func (p *EventReleasePacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeScenarioItem = 0x0055
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeScenarioItem = 0x0084
+)
+
+const (
+	// KeyItemsPerTable is the number of key items one key item list packet covers
+	KeyItemsPerTable = 512
+
+	// KeyItemTableCount is the number of key item tables the client keeps
+	KeyItemTableCount = 7
+)
+
+// ScenarioItemPacket tells the client which key items of a table its character holds.
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0055
+type ScenarioItemPacket struct {
+	// A bit per key item of the table, set for those the character holds.
+	GetItemFlag [KeyItemsPerTable / 32]uint32
+
+	// A bit per key item of the table, set for those the character has looked at.
+	LookItemFlag [KeyItemsPerTable / 32]uint32
+
+	// The key item table (key item ID / KeyItemsPerTable).
+	TableIndex uint16
+
+	// Padding; unused.
+	Padding86 uint16
+}
+
+func (p *ScenarioItemPacket) Type() uint16 {
+	return PacketTypeScenarioItem
+}
+
+func (p *ScenarioItemPacket) Size() uint16 {
+	return PacketSizeScenarioItem
+}
+
+func (p *ScenarioItemPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
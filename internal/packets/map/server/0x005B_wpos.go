@@ -0,0 +1,63 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeWPos = 0x005B
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeWPos = 0x0018
+)
+
+// Modes of the position packet
+const (
+	// WPosModeNormal snaps the entity to the position
+	WPosModeNormal uint8 = 0x01
+)
+
+// WPosPacket moves an entity to a position, such as a player the server did not let
+// move.
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x005B
+type WPosPacket struct {
+	// The entity position; PosZ is the height.
+	PosX float32
+	PosZ float32
+	PosY float32
+
+	// The entity server id.
+	UniqueNo uint32
+
+	// The entity target index.
+	ActIndex uint16
+
+	// How the entity is moved (see the WPosMode values).
+	Mode uint8
+
+	// The entity rotation.
+	Direction int8
+
+	// Padding; unused.
+	Padding18 uint32
+}
+
+func (p *WPosPacket) Type() uint16 {
+	return PacketTypeWPos
+}
+
+func (p *WPosPacket) Size() uint16 {
+	return PacketSizeWPos
+}
+
+func (p *WPosPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypePendingNum = 0x005C
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizePendingNum = 0x0020
+)
+
+// PendingNumPacket answers the update request of a running event with new parameters.
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x005C
+type PendingNumPacket struct {
+	// The event parameters.
+	Num [EventParamCount]uint32
+}
+
+func (p *PendingNumPacket) Type() uint16 {
+	return PacketTypePendingNum
+}
+
+func (p *PendingNumPacket) Size() uint16 {
+	return PacketSizePendingNum
+}
+
+func (p *PendingNumPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeCliStatus = 0x0061
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeCliStatus = 0x006C
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0061
+type CliStatusPacket struct {
+	// The local players max health.
+	HPMax int32
+
+	// The local players max mana.
+	MPMax int32
+
+	// The local players main job id.
+	MainJobID uint8
+
+	// The local players main job level.
+	MainJobLevel uint8
+
+	// The local players sub job id.
+	SubJobID uint8
+
+	// The local players sub job level.
+	SubJobLevel uint8
+
+	// The local players current experience points.
+	ExpNow uint16
+
+	// The local players experience points needed for the next level.
+	ExpNext uint16
+
+	// The local players base stats.
+	//
+	// 0 = STR, 1 = DEX, 2 = VIT, 3 = AGI, 4 = INT, 5 = MND, 6 = CHR
+	BPBase [7]uint16
+
+	// The local players stat adjustments from equipment and effects.
+	BPAdj [7]int16
+
+	// The local players attack.
+	Atk int16
+
+	// The local players defense.
+	Def int16
+
+	// The local players elemental resistances.
+	//
+	// 0 = Fire, 1 = Ice, 2 = Wind, 3 = Earth, 4 = Lightning, 5 = Water, 6 = Light, 7 = Dark
+	DefElem [8]int16
+
+	// The local players title id.
+	Designation uint16
+
+	// The local players nation rank.
+	Rank uint16
+
+	// The local players nation rank points.
+	RankBar uint16
+
+	// The zone the local players home point is in.
+	BindZoneNo uint16
+
+	// The local players monster buster value.
+	MonsterBuster uint32
+
+	// The local players nation.
+	Nation uint8
+
+	// The local players mog house flag.
+	MyRoom uint8
+
+	// The local players superior level.
+	SuLevel uint8
+
+	// Padding; unused.
+	Padding53 uint8
+
+	// The local players highest item level.
+	HighestILvl uint8
+
+	// The local players item level.
+	ILvl uint8
+
+	// The local players main hand item level.
+	ILvlMainHand uint8
+
+	// The local players ranged item level.
+	ILvlRanged uint8
+
+	// The local players unity information.
+	UnityInfo uint32
+
+	// The local players unity points.
+	UnityPoints1 uint16
+	UnityPoints2 uint16
+
+	// The local players unity chat color flags.
+	UnityChatColorFlag uint32
+
+	// The local players mastery information.
+	MasteryInfo uint32
+
+	// The local players mastery experience.
+	MasteryExpNow  uint32
+	MasteryExpNext uint32
+}
+
+func (p *CliStatusPacket) Type() uint16 {
+	return PacketTypeCliStatus
+}
+
+func (p *CliStatusPacket) Size() uint16 {
+	return PacketSizeCliStatus
+}
+
+func (p *CliStatusPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeMiscData = 0x0063
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeStatusIcons = 0x00C4
+)
+
+// Kinds of the misc data packet
+const (
+	MiscDataStatusIcons uint16 = 0x09
+)
+
+// StatusIconEmpty is the icon of an unused status icon slot.
+const StatusIconEmpty uint16 = 0x00FF
+
+// StatusIconsPacket tells the client the status effect icons of its character and
+// when each effect wears off. It is the status icons kind of the misc data packet.
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x0063
+type StatusIconsPacket struct {
+	// The kind of misc data (MiscDataStatusIcons).
+	Kind uint16
+
+	// The size of the data, header included (PacketSizeStatusIcons).
+	Length uint16
+
+	// The status effect icons (StatusIconEmpty for an unused slot).
+	Icons [32]uint16
+
+	// When each effect wears off, in 1/60ths of a second since the Vana'diel epoch
+	// (0x7FFFFFFF for an effect that never wears off).
+	Timestamps [32]uint32
+}
+
+func (p *StatusIconsPacket) Type() uint16 {
+	return PacketTypeMiscData
+}
+
+func (p *StatusIconsPacket) Size() uint16 {
+	return PacketSizeStatusIcons
+}
+
+func (p *StatusIconsPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	PacketTypeGroupAttr = 0x00DF
+	// Payload size only; the sub-packet header (4 bytes) is added by the router.
+	PacketSizeGroupAttr = 0x0024
+)
+
+// https://github.com/atom0s/XiPackets/tree/main/world/server/0x00DF
+type GroupAttrPacket struct {
+	// The entity server id.
+	UniqueNo uint32
+
+	// The entity current health.
+	Hp uint32
+
+	// The entity current mana.
+	Mp uint32
+
+	// The entity current TP.
+	Tp uint32
+
+	// The entity target index.
+	ActIndex uint16
+
+	// The entity health percentage.
+	Hpp uint8
+
+	// The entity mana percentage.
+	Mpp uint8
+
+	// Unknown.
+	Kind uint16
+
+	// Unknown.
+	Unknown1A uint16
+
+	// Unknown.
+	Unknown1C uint32
+
+	// The entity main job id.
+	MainJobID uint8
+
+	// The entity main job level.
+	MainJobLevel uint8
+
+	// The entity sub job id.
+	SubJobID uint8
+
+	// The entity sub job level.
+	SubJobLevel uint8
+
+	// The entity master level.
+	MasterJobLevel uint8
+
+	// The entity master level flags.
+	MasterJobFlags uint8
+
+	// Padding; unused.
+	Padding26 [2]uint8
+}
+
+func (p *GroupAttrPacket) Type() uint16 {
+	return PacketTypeGroupAttr
+}
+
+func (p *GroupAttrPacket) Size() uint16 {
+	return PacketSizeGroupAttr
+}
+
+func (p *GroupAttrPacket) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Write all fields in order
+	if err := binary.Write(buf, binary.LittleEndian, p); err != nil {
+		return nil, fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -178,6 +178,16 @@ func (s *TCPServer) ProcessConnections(ctx context.Context, wg *sync.WaitGroup,
 	}
 }
 
+// RemoteIP returns the IP address (without port) of the remote end of a connection.
+func RemoteIP(conn net.Conn) string {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return ""
+	}
+
+	return host
+}
+
 func (s *TCPServer) WaitForShutdown(cancelCtx context.CancelFunc, wg *sync.WaitGroup) error {
 	// setup signal handling
 	signalChannel := make(chan os.Signal, 1)
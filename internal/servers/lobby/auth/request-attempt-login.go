@@ -3,10 +3,10 @@ package auth
 import (
 	"context"
 	"crypto/rand"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"net"
+	"time"
 
 	"github.com/pquerna/otp/totp"
 	"golang.org/x/crypto/bcrypt"
@@ -106,8 +106,14 @@ func (s *AuthServer) handleRequestAttemptLogin(ctx context.Context, conn net.Con
 
 	// generate a session token
 	logger.Info("login successful", "username", header.Username)
-	sessionKey := generateSessionKey()
-	logger.Debug("session token generated", "username", header.Username, "sessionToken", sessionKey)
+	sessionKey, err := generateSessionKey()
+	if err != nil {
+		logger.Error("failed to generate session key", "error", err)
+		response := NewResponseResult(ErrorCodeAttemptLoginError)
+		_, _ = conn.Write(response.ToJSON())
+
+		return false
+	}
 
 	// parse the client IP address
 	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
@@ -148,6 +154,7 @@ func (s *AuthServer) handleRequestAttemptLogin(ctx context.Context, conn net.Con
 		CharacterID: 0, // No character selected yet
 		ClientIP:    clientAddr.String(),
 		SessionKey:  sessionKeyBytes,
+		ExpiresAt:   time.Now().Add(time.Duration(s.Config().SessionKeyTTLSeconds) * time.Second),
 	}
 
 	_, err = s.DB().CreateAccountSession(ctx, accountSession)
@@ -166,16 +173,13 @@ func (s *AuthServer) handleRequestAttemptLogin(ctx context.Context, conn net.Con
 	return true
 }
 
-func generateSessionKey() [16]byte {
-	// Generate a random 8-byte session key
-	randomBytes := make([]byte, 8)
-	_, _ = rand.Read(randomBytes)
-
-	// Convert to hex string (8 bytes = 16 hex characters)
-	encoded := hex.EncodeToString(randomBytes)
-
+// generateSessionKey returns 128 bits of cryptographically secure randomness.
+// xiloader echoes the raw bytes back to the view and data servers as the packet identifier.
+func generateSessionKey() ([16]byte, error) {
 	var sessionKey [16]byte
-	copy(sessionKey[:], encoded)
+	if _, err := rand.Read(sessionKey[:]); err != nil {
+		return [16]byte{}, err
+	}
 
-	return sessionKey
+	return sessionKey, nil
 }
@@ -7,17 +7,57 @@ import (
 	"io"
 	"log/slog"
 	"net"
+	"sync/atomic"
 	"time"
 
+	"github.com/nats-io/nats.go"
+
 	"github.com/GoFFXI/GoFFXI/internal/servers/base/tcp"
 )
 
 const (
 	ErrorInvalidClientVersion = 0x0B
+
+	// SubjectVersionPolicyReload is published to make every lobby-auth server reload its version policy
+	SubjectVersionPolicyReload = "lobby.auth.version-policy.reload"
 )
 
 type AuthServer struct {
 	*tcp.TCPServer
+
+	versionPolicy atomic.Pointer[VersionPolicy]
+}
+
+// ReloadVersionPolicy (re)loads the xiloader version policy from the configured policy
+// file, or from the legacy version settings when no file is configured. The current
+// policy is kept if the new one fails validation.
+func (s *AuthServer) ReloadVersionPolicy() error {
+	var policy *VersionPolicy
+	var err error
+
+	if s.Config().XILoaderVersionPolicyPath != "" {
+		policy, err = LoadVersionPolicy(s.Config().XILoaderVersionPolicyPath)
+	} else {
+		policy, err = LegacyVersionPolicy(s.Config().XILoaderVersion, s.Config().XILoaderEnforceVersion)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.versionPolicy.Store(policy)
+	s.Logger().Info("loaded xiloader version policy", "path", s.Config().XILoaderVersionPolicyPath)
+
+	return nil
+}
+
+// SubscribeVersionPolicyReload reloads the version policy whenever a reload is requested over NATS.
+func (s *AuthServer) SubscribeVersionPolicyReload() (*nats.Subscription, error) {
+	return s.NATS().Subscribe(SubjectVersionPolicyReload, func(_ *nats.Msg) {
+		if err := s.ReloadVersionPolicy(); err != nil {
+			s.Logger().Error("failed to reload xiloader version policy, keeping the current one", "error", err)
+		}
+	})
 }
 
 func (s *AuthServer) HandleConnection(ctx context.Context, conn net.Conn) {
@@ -79,22 +119,11 @@ func (s *AuthServer) parseIncomingRequest(ctx context.Context, logger *slog.Logg
 
 	// handle client version enforcement
 	logger.Info("detected client version", "version", header.ClientVersion())
-	if s.Config().XILoaderEnforceVersion == 2 {
-		// enforce minimum version
-		if !header.VersionAtLeast(s.Config().XILoaderVersion) {
-			logger.Error("client version mismatch - disconnecting", "got", header.ClientVersion())
-			responseError := NewResponseError("Your XI Loader version is outdated. Please update to at least " + s.Config().XILoaderVersion)
-			_, _ = conn.Write(responseError.ToJSON())
-			return true
-		}
-	} else if s.Config().XILoaderEnforceVersion == 1 {
-		// enforce exact version match
-		if !header.VersionMatches(s.Config().XILoaderVersion) {
-			logger.Error("client version mismatch - disconnecting", "got", header.ClientVersion())
-			responseError := NewResponseError("Your XI Loader version is incompatible. Please use version " + s.Config().XILoaderVersion)
-			_, _ = conn.Write(responseError.ToJSON())
-			return true
-		}
+	if allowed, message := s.versionPolicy.Load().Check(header.ClientSemver()); !allowed {
+		logger.Error("client version rejected - disconnecting", "got", header.ClientVersion())
+		responseError := NewResponseError(message)
+		_, _ = conn.Write(responseError.ToJSON())
+		return true
 	}
 
 	// handle commands
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SweepExpiredSessions periodically removes account sessions that have outlived their TTL.
+func (s *AuthServer) SweepExpiredSessions(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	interval := time.Duration(max(s.Config().SessionSweepIntervalSeconds, 1)) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.Logger().Info("stopping session sweeper")
+			return
+		case <-ticker.C:
+			removed, err := s.DB().DeleteExpiredAccountSessions(ctx, time.Now())
+			if err != nil {
+				s.Logger().Error("failed to sweep expired sessions", "error", err)
+				continue
+			}
+
+			if removed > 0 {
+				s.Logger().Info("swept expired sessions", "count", removed)
+			}
+		}
+	}
+}
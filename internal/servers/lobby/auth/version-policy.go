@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/GoFFXI/GoFFXI/internal/tools/semver"
+)
+
+const (
+	VersionEnforcementNone    = 0
+	VersionEnforcementExact   = 1
+	VersionEnforcementAtLeast = 2
+
+	defaultVersionRejectMessage = "Your XI Loader version is not supported by this server."
+)
+
+var ErrInvalidVersionPolicy = errors.New("invalid version policy")
+
+// VersionPolicy decides which xiloader versions are allowed to talk to the lobby.
+//
+// A version is rejected when it matches any deny rule, or when allow rules are
+// present and none of them match. The message sent to a rejected client is taken
+// from the matching deny rule, then from the first matching message rule, and
+// finally from the policy's default message.
+type VersionPolicy struct {
+	allow          []semver.Range
+	deny           []versionRule
+	messages       []versionRule
+	defaultMessage string
+}
+
+type versionRule struct {
+	versions semver.Range
+	message  string
+}
+
+// versionPolicyFile is the on-disk (JSON) representation of a VersionPolicy.
+//
+//	{
+//	  "allow": [">=2.0.0 <3.0.0"],
+//	  "deny": [{"range": "2.1.3", "message": "2.1.3 cannot log in, please update to 2.1.4"}],
+//	  "messages": [{"range": "<2.0.0", "message": "Please update to at least 2.0.0"}],
+//	  "default_message": "Your XI Loader version is not supported."
+//	}
+type versionPolicyFile struct {
+	Allow          []string                `json:"allow"`
+	Deny           []versionPolicyFileRule `json:"deny"`
+	Messages       []versionPolicyFileRule `json:"messages"`
+	DefaultMessage string                  `json:"default_message"`
+}
+
+type versionPolicyFileRule struct {
+	Range   string `json:"range"`
+	Message string `json:"message"`
+}
+
+// ParseVersionPolicy parses and validates a JSON version policy.
+func ParseVersionPolicy(data []byte) (*VersionPolicy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var file versionPolicyFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidVersionPolicy, err)
+	}
+
+	policy := &VersionPolicy{
+		defaultMessage: file.DefaultMessage,
+	}
+
+	if policy.defaultMessage == "" {
+		policy.defaultMessage = defaultVersionRejectMessage
+	}
+
+	for _, raw := range file.Allow {
+		versions, err := semver.ParseRange(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: allow: %w", ErrInvalidVersionPolicy, err)
+		}
+
+		policy.allow = append(policy.allow, versions)
+	}
+
+	var err error
+	if policy.deny, err = parseVersionRules(file.Deny); err != nil {
+		return nil, fmt.Errorf("%w: deny: %w", ErrInvalidVersionPolicy, err)
+	}
+
+	if policy.messages, err = parseVersionRules(file.Messages); err != nil {
+		return nil, fmt.Errorf("%w: messages: %w", ErrInvalidVersionPolicy, err)
+	}
+
+	return policy, nil
+}
+
+func parseVersionRules(rules []versionPolicyFileRule) ([]versionRule, error) {
+	parsed := make([]versionRule, 0, len(rules))
+	for _, rule := range rules {
+		versions, err := semver.ParseRange(rule.Range)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, versionRule{versions: versions, message: rule.Message})
+	}
+
+	return parsed, nil
+}
+
+// LoadVersionPolicy reads a JSON version policy from disk.
+func LoadVersionPolicy(path string) (*VersionPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version policy: %w", err)
+	}
+
+	return ParseVersionPolicy(data)
+}
+
+// LegacyVersionPolicy builds a policy from the XI_LOADER_VERSION and
+// XI_LOADER_ENFORCE_VERSION settings, for servers without a policy file. As before
+// policies, the version may leave out its minor and patch components ("2.0").
+func LegacyVersionPolicy(version string, enforcement int) (*VersionPolicy, error) {
+	policy := &VersionPolicy{
+		defaultMessage: defaultVersionRejectMessage,
+	}
+
+	if enforcement == VersionEnforcementNone {
+		return policy, nil
+	}
+
+	expected, err := semver.ParsePadded(version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidVersionPolicy, err)
+	}
+
+	switch enforcement {
+	case VersionEnforcementExact:
+		policy.allow = []semver.Range{semver.MustParseRange("=" + expected.String())}
+		policy.defaultMessage = "Your XI Loader version is incompatible. Please use version " + expected.String()
+	case VersionEnforcementAtLeast:
+		policy.allow = []semver.Range{semver.MustParseRange(">=" + expected.String())}
+		policy.defaultMessage = "Your XI Loader version is outdated. Please update to at least " + expected.String()
+	default:
+		return nil, fmt.Errorf("%w: unknown enforcement mode %d", ErrInvalidVersionPolicy, enforcement)
+	}
+
+	return policy, nil
+}
+
+// Check reports whether the version is allowed and, if not, the message to show the player.
+func (p *VersionPolicy) Check(version semver.Version) (bool, string) {
+	for _, rule := range p.deny {
+		if rule.versions.Contains(version) {
+			if rule.message != "" {
+				return false, rule.message
+			}
+
+			return false, p.rejectMessage(version)
+		}
+	}
+
+	if len(p.allow) == 0 {
+		return true, ""
+	}
+
+	for _, versions := range p.allow {
+		if versions.Contains(version) {
+			return true, ""
+		}
+	}
+
+	return false, p.rejectMessage(version)
+}
+
+func (p *VersionPolicy) rejectMessage(version semver.Version) string {
+	for _, rule := range p.messages {
+		if rule.message != "" && rule.versions.Contains(version) {
+			return rule.message
+		}
+	}
+
+	return p.defaultMessage
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/GoFFXI/GoFFXI/internal/tools/semver"
+)
+
+func TestVersionPolicyCheck(t *testing.T) {
+	policy, err := ParseVersionPolicy([]byte(`{
+		"allow": [">=2.0.0 <3.0.0"],
+		"deny": [{"range": "2.1.3", "message": "2.1.3 is broken"}, {"range": "2.2.x"}],
+		"messages": [{"range": "<2.0.0", "message": "please update"}],
+		"default_message": "not supported"
+	}`))
+	if err != nil {
+		t.Fatalf("ParseVersionPolicy() error = %v", err)
+	}
+
+	cases := []struct {
+		version string
+		allowed bool
+		message string
+	}{
+		{"2.0.0", true, ""},
+		{"2.1.4", true, ""},
+		{"2.1.3", false, "2.1.3 is broken"},
+		{"2.2.0", false, "not supported"},
+		{"1.9.9", false, "please update"},
+		{"3.0.0", false, "not supported"},
+	}
+
+	for _, tc := range cases {
+		allowed, message := policy.Check(semver.MustParse(tc.version))
+		if allowed != tc.allowed || message != tc.message {
+			t.Fatalf("Check(%s) = (%v, %q), want (%v, %q)", tc.version, allowed, message, tc.allowed, tc.message)
+		}
+	}
+}
+
+func TestParseVersionPolicyInvalid(t *testing.T) {
+	for _, input := range []string{
+		`{"allow": ["not a range"]}`,
+		`{"deny": [{"range": ">=2.0.x.1"}]}`,
+		`{"messages": [{"range": ""}]}`,
+		`{"alow": [">=2.0.0"]}`,
+		`not json`,
+	} {
+		if _, err := ParseVersionPolicy([]byte(input)); !errors.Is(err, ErrInvalidVersionPolicy) {
+			t.Fatalf("ParseVersionPolicy(%s) error = %v, want %v", input, err, ErrInvalidVersionPolicy)
+		}
+	}
+}
+
+func TestLegacyVersionPolicy(t *testing.T) {
+	t.Run("at least", func(t *testing.T) {
+		policy, err := LegacyVersionPolicy("2.0.0", VersionEnforcementAtLeast)
+		if err != nil {
+			t.Fatalf("LegacyVersionPolicy() error = %v", err)
+		}
+
+		if allowed, _ := policy.Check(semver.New(2, 5, 0)); !allowed {
+			t.Fatal("Check(2.5.0) = false, want true")
+		}
+
+		if allowed, _ := policy.Check(semver.New(1, 9, 0)); allowed {
+			t.Fatal("Check(1.9.0) = true, want false")
+		}
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		policy, err := LegacyVersionPolicy("2.0.0", VersionEnforcementExact)
+		if err != nil {
+			t.Fatalf("LegacyVersionPolicy() error = %v", err)
+		}
+
+		if allowed, _ := policy.Check(semver.New(2, 0, 1)); allowed {
+			t.Fatal("Check(2.0.1) = true, want false")
+		}
+	})
+
+	t.Run("short version", func(t *testing.T) {
+		// existing configurations leave out the patch component
+		policy, err := LegacyVersionPolicy("2.0", VersionEnforcementExact)
+		if err != nil {
+			t.Fatalf("LegacyVersionPolicy() error = %v", err)
+		}
+
+		if allowed, _ := policy.Check(semver.New(2, 0, 0)); !allowed {
+			t.Fatal("Check(2.0.0) = false, want true")
+		}
+
+		if allowed, _ := policy.Check(semver.New(2, 0, 1)); allowed {
+			t.Fatal("Check(2.0.1) = true, want false")
+		}
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		for _, version := range []string{"2.0.x", "two", "2.0.0.0"} {
+			if _, err := LegacyVersionPolicy(version, VersionEnforcementAtLeast); !errors.Is(err, ErrInvalidVersionPolicy) {
+				t.Fatalf("LegacyVersionPolicy(%q) error = %v, want %v", version, err, ErrInvalidVersionPolicy)
+			}
+		}
+	})
+}
+
+func TestExampleVersionPolicy(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	path := filepath.Join(filepath.Dir(filename), "..", "..", "..", "..", "resources", "xiloader-version-policy.example.json")
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("example policy not found: %v", err)
+	}
+
+	if _, err := LoadVersionPolicy(path); err != nil {
+		t.Fatalf("LoadVersionPolicy() error = %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// queueTicket represents a single player waiting at character select for room in the world.
+type queueTicket struct {
+	accountID  uint32
+	enqueuedAt time.Time
+	admitted   chan struct{}
+}
+
+// loginQueue is a FIFO of players waiting for the world population to drop below the cap.
+// Admitted tickets stay reserved until the player's session is bound to a character, so
+// the capacity they take is not handed out twice before the database catches up.
+type loginQueue struct {
+	mu       sync.Mutex
+	waiting  []*queueTicket
+	reserved int
+	wake     chan struct{}
+}
+
+func newLoginQueue() *loginQueue {
+	return &loginQueue{
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// join appends a new ticket to the back of the queue.
+func (q *loginQueue) join(accountID uint32) *queueTicket {
+	ticket := &queueTicket{
+		accountID:  accountID,
+		enqueuedAt: time.Now(),
+		admitted:   make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	q.waiting = append(q.waiting, ticket)
+	q.mu.Unlock()
+
+	// let the queue processor re-evaluate right away instead of waiting for the next interval
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	return ticket
+}
+
+// leave removes a ticket from the queue, or releases its reservation if it was already admitted.
+func (q *loginQueue) leave(ticket *queueTicket) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case <-ticket.admitted:
+		q.reserved--
+		return
+	default:
+	}
+
+	for i, waiting := range q.waiting {
+		if waiting == ticket {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+// position returns the 1-based position of the ticket, or 0 if it is no longer waiting.
+func (q *loginQueue) position(ticket *queueTicket) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, waiting := range q.waiting {
+		if waiting == ticket {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// admit lets in as many players from the front of the queue as there are free slots.
+func (q *loginQueue) admit(online, capacity int) []*queueTicket {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	free := capacity - online - q.reserved
+	if free <= 0 || len(q.waiting) == 0 {
+		return nil
+	}
+
+	count := min(free, len(q.waiting))
+	admitted := q.waiting[:count:count]
+	q.waiting = q.waiting[count:]
+	q.reserved += count
+
+	for _, ticket := range admitted {
+		close(ticket.admitted)
+	}
+
+	return admitted
+}
+
+// snapshot returns a copy of the waiting tickets in queue order.
+func (q *loginQueue) snapshot() []*queueTicket {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]*queueTicket(nil), q.waiting...)
+}
+
+// ProcessLoginQueue periodically admits queued players while there is room in the world.
+func (s *DataServer) ProcessLoginQueue(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	interval := time.Duration(max(s.Config().LoginQueueCheckIntervalSeconds, 1)) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.Logger().Info("stopping login queue processor")
+			return
+		case <-ticker.C:
+		case <-s.queue.wake:
+		}
+
+		s.admitQueuedPlayers(ctx)
+	}
+}
+
+func (s *DataServer) admitQueuedPlayers(ctx context.Context) {
+	if len(s.queue.snapshot()) == 0 {
+		return
+	}
+
+	online, err := s.DB().CountOnlineCharacters(ctx)
+	if err != nil {
+		s.Logger().Error("failed to count online characters", "error", err)
+		return
+	}
+
+	for _, ticket := range s.queue.admit(online, s.Config().WorldPopulationCap) {
+		s.Logger().Info("admitting account from login queue", "accountID", ticket.accountID, "waited", time.Since(ticket.enqueuedAt).String())
+	}
+
+	for i, ticket := range s.queue.snapshot() {
+		s.Logger().Debug("account waiting in login queue", "accountID", ticket.accountID, "position", i+1)
+	}
+}
+
+// waitForWorldAdmission blocks until the account may enter the world. The returned ticket
+// must be released with s.queue.leave once the session is bound to the character (it is
+// nil when the account did not need to queue). It returns false if the player gave up,
+// timed out or the server is shutting down.
+func (s *DataServer) waitForWorldAdmission(sessionCtx *sessionContext) (*queueTicket, bool) {
+	logger := sessionCtx.logger.With("accountID", sessionCtx.accountID)
+
+	capacity := s.Config().WorldPopulationCap
+	if capacity <= 0 {
+		return nil, true
+	}
+
+	// staff should always be able to get in, especially when the world is full
+	account, err := s.DB().GetAccountByID(sessionCtx.ctx, uint(sessionCtx.accountID))
+	if err != nil {
+		logger.Error("failed to lookup account", "error", err)
+		return nil, false
+	}
+
+	if account.IsStaff() {
+		logger.Info("staff account bypassing login queue", "gmLevel", account.GMLevel)
+		return nil, true
+	}
+
+	ticket := s.queue.join(sessionCtx.accountID)
+	position := s.queue.position(ticket)
+	logger.Info("account joined login queue", "position", position)
+
+	timeout := time.NewTimer(time.Duration(s.Config().LoginQueueTimeoutSeconds) * time.Second)
+	defer timeout.Stop()
+
+	// the loader has no message for the queue position, so it is only logged as
+	// players ahead are admitted or leave
+	ticker := time.NewTicker(time.Duration(max(s.Config().LoginQueueCheckIntervalSeconds, 1)) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticket.admitted:
+			return ticket, true
+		case <-ticker.C:
+			if current := s.queue.position(ticket); current != position && current != 0 {
+				position = current
+				logger.Debug("account moved up in login queue", "position", position)
+			}
+
+			continue
+		case <-timeout.C:
+			logger.Warn("account timed out in login queue", "position", s.queue.position(ticket))
+		case <-sessionCtx.ctx.Done():
+			// the client disconnected (or the server is shutting down)
+			logger.Info("account left login queue", "position", s.queue.position(ticket))
+		}
+
+		s.queue.leave(ticket)
+
+		return nil, false
+	}
+}
@@ -0,0 +1,72 @@
+package data
+
+import "testing"
+
+func isAdmitted(ticket *queueTicket) bool {
+	select {
+	case <-ticket.admitted:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestLoginQueueAdmitsInOrder(t *testing.T) {
+	queue := newLoginQueue()
+	first := queue.join(1)
+	second := queue.join(2)
+	third := queue.join(3)
+
+	if got := queue.position(third); got != 3 {
+		t.Fatalf("position() = %d, want %d", got, 3)
+	}
+
+	admitted := queue.admit(8, 10)
+	if len(admitted) != 2 {
+		t.Fatalf("admit() admitted %d tickets, want %d", len(admitted), 2)
+	}
+
+	if !isAdmitted(first) || !isAdmitted(second) || isAdmitted(third) {
+		t.Fatal("admit() did not admit the front of the queue")
+	}
+
+	if got := queue.position(third); got != 1 {
+		t.Fatalf("position() = %d, want %d", got, 1)
+	}
+}
+
+func TestLoginQueueReservesAdmittedSlots(t *testing.T) {
+	queue := newLoginQueue()
+	first := queue.join(1)
+	second := queue.join(2)
+
+	queue.admit(9, 10)
+	if !isAdmitted(first) {
+		t.Fatal("admit() did not admit the first ticket")
+	}
+
+	// the admitted player has not been counted as online yet, so nobody else fits
+	if admitted := queue.admit(9, 10); len(admitted) != 0 {
+		t.Fatalf("admit() admitted %d tickets while the slot is reserved, want 0", len(admitted))
+	}
+
+	queue.leave(first)
+	if admitted := queue.admit(9, 10); len(admitted) != 1 || !isAdmitted(second) {
+		t.Fatal("admit() did not admit the next ticket after the reservation was released")
+	}
+}
+
+func TestLoginQueueLeave(t *testing.T) {
+	queue := newLoginQueue()
+	first := queue.join(1)
+	second := queue.join(2)
+
+	queue.leave(first)
+	if got := queue.position(first); got != 0 {
+		t.Fatalf("position() = %d after leave, want 0", got)
+	}
+
+	if got := queue.position(second); got != 1 {
+		t.Fatalf("position() = %d, want %d", got, 1)
+	}
+}
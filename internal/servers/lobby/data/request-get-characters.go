@@ -22,7 +22,7 @@ const (
 	CharacterStatusAvailable      uint16 = 1
 	CharacterStatusDisabledUnpaid uint16 = 2
 
-	MaxCharacterSlots = 16
+	MaxCharacterSlots = constants.MaxCharacterSlots
 )
 
 // https://github.com/atom0s/XiPackets/blob/main/lobby/C2S_0x001F_RequestGetChr.md
@@ -301,25 +301,31 @@ func (s DataServer) handleRequestGetCharacters(sessionCtx *sessionContext, accou
 		Relation("Jobs").
 		Relation("Stats").
 		Relation("Looks").
+		Order("character.id ASC").
 		Scan(sessionCtx.ctx)
 	if err != nil {
 		logger.Error("failed to fetch characters", "error", err)
 		return true
 	}
 
-	// limit to max character slots to it's maximum of 16
-	// note how this is not the server configurable max, but the protocol max
-	// the server config will be applied when creating new characters
-	totalCharacters := uint8(len(characters)) //nolint:gosec // we trust the length will not exceed uint8
-	if totalCharacters > MaxCharacterSlots {
-		totalCharacters = MaxCharacterSlots
+	// lookup how many content IDs this account is entitled to
+	contentIDLimit, err := s.DB().GetAccountContentIDLimit(sessionCtx.ctx, accountSession.AccountID, s.Config().MaxContentIDsPerAccount)
+	if err != nil {
+		logger.Error("failed to lookup content ID entitlement", "error", err)
+		return true
+	}
+
+	// the list can never show more than the protocol max of 16 slots, regardless of entitlement
+	contentIDLimit = min(contentIDLimit, MaxCharacterSlots)
+	if len(characters) > MaxCharacterSlots {
+		characters = characters[:MaxCharacterSlots]
 	}
 
 	// setup our data response (so we can add characters to it later)
 	dataResponse := NewResponseCharacterList()
 
 	// now, let's prepare the character data for the view connection
-	characterSlots := make([]ResponseChrInfo2Sub, 0, totalCharacters)
+	characterSlots := make([]ResponseChrInfo2Sub, 0, max(contentIDLimit, len(characters)))
 
 	// loop through existing characters and add them to the response
 	for _, character := range characters {
@@ -328,7 +334,8 @@ func (s DataServer) handleRequestGetCharacters(sessionCtx *sessionContext, accou
 	}
 
 	// finally, fill remaining slots with empty slots
-	emptySlots := s.Config().MaxContentIDsPerAccount - len(characters)
+	// accounts that lost entitlements keep their characters but get no empty slots
+	emptySlots := max(contentIDLimit-len(characters), 0)
 	for range emptySlots {
 		characterSlots = append(characterSlots, CreateEmptySlot())
 	}
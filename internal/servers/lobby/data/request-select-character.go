@@ -7,10 +7,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net"
-	"strings"
+	"time"
 
 	"github.com/GoFFXI/GoFFXI/internal/constants"
 	"github.com/GoFFXI/GoFFXI/internal/packets/lobby"
+	"github.com/GoFFXI/GoFFXI/internal/servers/base/tcp"
 )
 
 const (
@@ -163,6 +164,18 @@ func (s *DataServer) handleRequestSelectCharacter(sessionCtx *sessionContext, da
 		return true
 	}
 
+	// wait for room in the world (this may block while the player is queued)
+	ticket, admitted := s.waitForWorldAdmission(sessionCtx)
+	if !admitted {
+		s.sendErrorResponse(sessionCtx)
+
+		return true
+	}
+
+	if ticket != nil {
+		defer s.queue.leave(ticket)
+	}
+
 	// update the character's new previous zone to be their current zone
 	logger.Info("updating character previous zone", "characterID", character.ID, "from", character.PosPrevZone, "to", character.PosZone)
 	character.PosPrevZone = character.PosZone
@@ -214,8 +227,10 @@ func (s *DataServer) handleRequestSelectCharacter(sessionCtx *sessionContext, da
 	// todo: update character stats for zoning = 2
 
 	// update the account session with the client ip and selected character id
-	clientIP := strings.Split(sessionCtx.conn.RemoteAddr().String(), ":")[0]
-	err = s.DB().UpdateAccountSession(sessionCtx.ctx, character.AccountID, character.ID, clientIP, magicKey[:])
+	// the account ID was verified against a session bound to this same connection's IP address
+	clientIP := tcp.RemoteIP(sessionCtx.conn)
+	expiresAt := time.Now().Add(time.Duration(s.Config().SessionKeyTTLSeconds) * time.Second)
+	err = s.DB().UpdateAccountSession(sessionCtx.ctx, character.AccountID, character.ID, clientIP, magicKey[:], expiresAt)
 	if err != nil {
 		logger.Error("failed to update account session with client ip", "error", err)
 		s.sendErrorResponse(sessionCtx)
@@ -2,9 +2,12 @@ package data
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"io"
+	"log/slog"
 	"net"
+	"time"
 
 	"github.com/GoFFXI/GoFFXI/internal/database"
 	"github.com/GoFFXI/GoFFXI/internal/packets/lobby"
@@ -13,53 +16,85 @@ import (
 
 const (
 	CommandRequestKeepXILoaderSpinning = 0xFE
+
+	// requestBacklog is how many requests of a connection may wait while one is handled
+	requestBacklog = 16
 )
 
 type DataServer struct {
 	*tcp.TCPServer
+
+	queue *loginQueue
+}
+
+func NewDataServer(baseServer *tcp.TCPServer) *DataServer {
+	return &DataServer{
+		TCPServer: baseServer,
+		queue:     newLoginQueue(),
+	}
 }
 
 func (s *DataServer) HandleConnection(ctx context.Context, conn net.Conn) {
 	logger := s.Logger().With("client", conn.RemoteAddr().String())
 	logger.Info("processing connection")
 
+	// the connection's context ends with the connection, so requests blocked on it (such
+	// as waiting in the login queue) give up as soon as the client goes away
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// create a new session for this connection
 	sessionCtx := sessionContext{
-		ctx:    ctx,
+		ctx:    connCtx,
 		conn:   conn,
 		server: s,
 		logger: logger,
 	}
 	defer sessionCtx.Close()
 
-	// buffer for reading data
-	buffer := make([]byte, 4096)
+	requests := make(chan []byte, requestBacklog)
+	go readRequests(connCtx, cancel, conn, logger, requests)
 
 	// connection handling loop
 	for {
-		// make sure we exit if the server is shutting down
 		select {
-		case <-ctx.Done():
+		case <-connCtx.Done():
 			return
-		default:
+		case request := <-requests:
+			if shouldExit := s.parseIncomingRequest(&sessionCtx, request); shouldExit {
+				return
+			}
 		}
+	}
+}
 
-		// read data from client
+// readRequests reads the requests of a connection until it closes, then cancels its
+// context. Reading goes on while a request is being handled, so a disconnect is noticed
+// right away (a request may wait minutes in the login queue). A client sending more
+// requests than the backlog holds is disconnected rather than have any of them lost.
+func readRequests(ctx context.Context, cancel context.CancelFunc, conn net.Conn, logger *slog.Logger, requests chan<- []byte) {
+	defer cancel()
+
+	buffer := make([]byte, 4096)
+	for {
 		length, err := conn.Read(buffer)
 		if err != nil {
-			if errors.Is(err, io.EOF) {
+			switch {
+			case errors.Is(err, io.EOF):
 				logger.Info("client disconnected")
-				break
-			} else if errors.Is(err, net.ErrClosed) {
-				break
+			case errors.Is(err, net.ErrClosed), ctx.Err() != nil:
+			default:
+				logger.Error("error reading from connection", "error", err)
 			}
 
-			logger.Error("error reading from connection", "error", err)
-			break
+			return
 		}
 
-		if shouldExit := s.parseIncomingRequest(&sessionCtx, buffer[:length]); shouldExit {
-			break
+		select {
+		case requests <- append([]byte(nil), buffer[:length]...):
+		default:
+			logger.Warn("closing connection, too many pending requests", "backlog", cap(requests))
+			return
 		}
 	}
 }
@@ -76,14 +111,30 @@ func (s *DataServer) parseIncomingRequest(sessionCtx *sessionContext, request []
 	copy(sessionKeyBytes, header.Identifier[:])
 
 	// attempt to lookup the account session
-	sessionKey := string(header.Identifier[:])
+	// the raw key is random binary, so it is hex-encoded wherever it is used as a NATS subject
+	sessionKey := hex.EncodeToString(header.Identifier[:])
 	sessionCtx.logger.Info("looking up session", "sessionKey", sessionKey, "opCode", header.Command)
 
+	var verifiedSession *database.AccountSession
 	accountSession, err := s.DB().GetAccountSessionBySessionKey(sessionCtx.ctx, sessionKeyBytes)
 	if err != nil {
 		// don't treat missing session as an error, just log and continue
 		// the 2nd part of selecting a character won't pass in a valid session key for whatever reason
 		sessionCtx.logger.Warn("failed to lookup account session", "sessionKey", sessionKey, "error", err)
+	} else {
+		// the session must not be expired and must be used from the IP address that authenticated it
+		now := time.Now()
+		if err = accountSession.Verify(tcp.RemoteIP(sessionCtx.conn), now); err != nil {
+			sessionCtx.logger.Warn("rejecting account session", "sessionKey", sessionKey, "accountID", accountSession.AccountID, "error", err)
+			return true
+		}
+
+		expiresAt := now.Add(time.Duration(s.Config().SessionKeyTTLSeconds) * time.Second)
+		if err = s.DB().TouchAccountSession(sessionCtx.ctx, accountSession.AccountID, expiresAt); err != nil {
+			sessionCtx.logger.Warn("failed to extend account session", "accountID", accountSession.AccountID, "error", err)
+		}
+
+		verifiedSession = &accountSession
 	}
 
 	// make sure this session context has subscriptions set up
@@ -98,7 +149,7 @@ func (s *DataServer) parseIncomingRequest(sessionCtx *sessionContext, request []
 		// this is just a keep-alive, respond with empty payload
 		_, _ = sessionCtx.conn.Write([]byte{})
 	case CommandRequestGetCharacters:
-		return s.handleRequestGetCharacters(sessionCtx, &accountSession, request)
+		return s.handleRequestGetCharacters(sessionCtx, verifiedSession, request)
 	case CommandRequestSelectCharacter:
 		return s.handleRequestSelectCharacter(sessionCtx, request)
 	}
@@ -0,0 +1,72 @@
+package data
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestReadRequestsCancelsOnDisconnect(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := make(chan []byte, requestBacklog)
+	go readRequests(ctx, cancel, server, slog.New(slog.NewTextHandler(io.Discard, nil)), requests)
+
+	if _, err := client.Write([]byte{0xA1, 0x02}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case request := <-requests:
+		if len(request) != 2 || request[0] != 0xA1 {
+			t.Fatalf("request = %v, want the bytes written", request)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("request not read")
+	}
+
+	// a client going away ends the connection's context, even while a request is handled
+	_ = client.Close()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context not cancelled after the client disconnected")
+	}
+}
+
+func TestReadRequestsCancelsOnFullBacklog(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// nothing handles the requests, so the second one overflows the backlog
+	requests := make(chan []byte, 1)
+	go readRequests(ctx, cancel, server, slog.New(slog.NewTextHandler(io.Discard, nil)), requests)
+
+	for range 2 {
+		if _, err := client.Write([]byte{0xA1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context not cancelled with the backlog full")
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("queued requests = %d, want the first one kept", len(requests))
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/rand"
 
 	"github.com/GoFFXI/GoFFXI/internal/constants"
 	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
 	"github.com/GoFFXI/GoFFXI/internal/packets/lobby"
 )
 
@@ -16,6 +18,8 @@ const (
 	CommandRequestCreateCharacter = 0x0021
 )
 
+var ErrNoStartingZone = errors.New("no starting zone")
+
 type RequestCreateCharacter struct {
 	FFXIID        uint32
 	Password      [16]byte
@@ -96,9 +100,17 @@ func (s *ViewServer) handleRequestCreateCharacter(sessionCtx *sessionContext, ac
 		return true
 	}
 
+	// lookup how many content IDs this account is entitled to
+	contentIDLimit, err := s.DB().GetAccountContentIDLimit(sessionCtx.ctx, accountSession.AccountID, s.Config().MaxContentIDsPerAccount)
+	if err != nil {
+		logger.Error("failed to lookup content ID entitlement", "error", err)
+		s.sendErrorResponse(sessionCtx, lobby.ErrorCodeFailedToRegisterWithNameServer)
+		return true
+	}
+
 	// make sure the account hasn't reached the character limit
-	if characterCount >= s.Config().MaxContentIDsPerAccount {
-		logger.Warn("account has reached character limit", "accountID", accountSession.AccountID, "characterCount", characterCount)
+	if characterCount >= min(contentIDLimit, constants.MaxCharacterSlots) {
+		logger.Warn("account has reached character limit", "accountID", accountSession.AccountID, "characterCount", characterCount, "contentIDLimit", contentIDLimit)
 		s.sendErrorResponse(sessionCtx, lobby.ErrorCodeFailedToRegisterWithNameServer)
 		return false
 	}
@@ -129,12 +141,26 @@ func (s *ViewServer) handleRequestCreateCharacter(sessionCtx *sessionContext, ac
 }
 
 func (s *ViewServer) saveNewCharacterToDatabase(ctx context.Context, accountID uint32, characterName string, charInfo *lobby.CharacterInfo) error {
-	// first, create the character record
+	// first, create the character record; it starts out with its starting zone as home point
+	startingZone, err := s.randomStartingZone(charInfo.TownNumber)
+	if err != nil {
+		return err
+	}
+
 	character := &database.Character{
 		AccountID: accountID,
 		Name:      characterName,
 		Nation:    charInfo.TownNumber,
-		PosZone:   s.getRandomStartingZoneForNation(charInfo.TownNumber),
+		PosZone:   startingZone.ZoneID,
+		PosX:      startingZone.X,
+		PosY:      startingZone.Y,
+		PosZ:      startingZone.Z,
+		PosRot:    startingZone.Rotation,
+		HomeZone:  startingZone.ZoneID,
+		HomeX:     startingZone.X,
+		HomeY:     startingZone.Y,
+		HomeZ:     startingZone.Z,
+		HomeRot:   startingZone.Rotation,
 	}
 
 	savedCharacter, err := s.DB().CreateCharacter(ctx, character)
@@ -176,10 +202,15 @@ func (s *ViewServer) saveNewCharacterToDatabase(ctx context.Context, accountID u
 		return fmt.Errorf("failed to create character jobs in database: %w", err)
 	}
 
+	// next, create the character experience record
+	err = s.DB().SaveCharacterExp(ctx, &database.CharacterExp{CharacterID: savedCharacter.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create character exp in database: %w", err)
+	}
+
 	return nil
 
 	// todo: extra tables:
-	// - character exp
 	// - character flags
 	// - character jobs
 	// - character points
@@ -190,18 +221,13 @@ func (s *ViewServer) saveNewCharacterToDatabase(ctx context.Context, accountID u
 	// - character variables
 }
 
-func (s *ViewServer) getRandomStartingZoneForNation(nationID uint8) uint16 {
-	var startingLocations []uint16
-
-	switch nationID {
-	case constants.TownSandoria:
-		startingLocations = []uint16{0xE6, 0xE7, 0xE8}
-	case constants.TownBastok:
-		startingLocations = []uint16{0xEA, 0xEB, 0xEC}
-	case constants.TownWindurst:
-		startingLocations = []uint16{0xEE, 0xF0, 0xF1}
+// randomStartingZone picks one of the starting zones of a nation from the game data.
+func (s *ViewServer) randomStartingZone(nation uint8) (gamedata.StartingZone, error) {
+	startingZones := s.GameData.Current().StartingZones[nation]
+	if len(startingZones) == 0 {
+		return gamedata.StartingZone{}, fmt.Errorf("%w: nation %d", ErrNoStartingZone, nation)
 	}
 
 	//nolint:gosec // Starting location selection is not security sensitive
-	return startingLocations[rand.Intn(len(startingLocations))]
+	return startingZones[rand.Intn(len(startingZones))], nil
 }
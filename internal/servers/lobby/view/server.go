@@ -2,17 +2,23 @@ package view
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"io"
 	"net"
+	"time"
 
 	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
 	"github.com/GoFFXI/GoFFXI/internal/packets/lobby"
 	"github.com/GoFFXI/GoFFXI/internal/servers/base/tcp"
 )
 
 type ViewServer struct {
 	*tcp.TCPServer
+
+	// GameData holds the starting zones of new characters
+	GameData *gamedata.Store
 }
 
 func (s *ViewServer) HandleConnection(ctx context.Context, conn net.Conn) {
@@ -72,16 +78,29 @@ func (s ViewServer) parseIncomingRequest(sessionCtx *sessionContext, request []b
 	copy(sessionKeyBytes, header.Identifier[:])
 
 	// attempt to lookup the account session
-	sessionKey := string(header.Identifier[:])
+	// the raw key is random binary, so it is hex-encoded wherever it is used as a NATS subject
+	sessionKey := hex.EncodeToString(header.Identifier[:])
 	sessionCtx.logger.Info("looking up session", "sessionKey", sessionKey, "opCode", header.Command)
 
 	accountSession, err := s.DB().GetAccountSessionBySessionKey(sessionCtx.ctx, sessionKeyBytes)
 	if err != nil {
 		// this shouldn't happen normally, log and close the connection
-		sessionCtx.logger.Error("failed to lookup account session", "session_key", header.Identifier, "error", err)
+		sessionCtx.logger.Error("failed to lookup account session", "sessionKey", sessionKey, "error", err)
+		return true
+	}
+
+	// the session must not be expired and must be used from the IP address that authenticated it
+	now := time.Now()
+	if err = accountSession.Verify(tcp.RemoteIP(sessionCtx.conn), now); err != nil {
+		sessionCtx.logger.Warn("rejecting account session", "sessionKey", sessionKey, "accountID", accountSession.AccountID, "error", err)
 		return true
 	}
 
+	expiresAt := now.Add(time.Duration(s.Config().SessionKeyTTLSeconds) * time.Second)
+	if err = s.DB().TouchAccountSession(sessionCtx.ctx, accountSession.AccountID, expiresAt); err != nil {
+		sessionCtx.logger.Warn("failed to extend account session", "accountID", accountSession.AccountID, "error", err)
+	}
+
 	// make sure this session context has subscriptions set up
 	// this should only be done once per session
 	if err = sessionCtx.SetupSubscriptions(sessionKey); err != nil {
@@ -102,7 +121,7 @@ func (s ViewServer) parseIncomingRequest(sessionCtx *sessionContext, request []b
 	case CommandRequestCreateCharacter:
 		return s.handleRequestCreateCharacter(sessionCtx, &accountSession, request)
 	case CommandRequestSelectCharacter:
-		return s.handleRequestSelectCharacter(sessionCtx, string(header.Identifier[:]), &accountSession, request)
+		return s.handleRequestSelectCharacter(sessionCtx, sessionKey, &accountSession, request)
 	case CommandRequestDeleteCharacter:
 		return s.handleRequestDeleteCharacter(sessionCtx, &accountSession, request)
 	}
@@ -0,0 +1,241 @@
+// Package charstats computes the stats of a character (max HP and MP, the seven
+// attributes, attack, defense, accuracy and evasion) from its race, jobs and levels, and the modifiers
+// of its equipment and status effects.
+//
+// The formulas follow LandSandBoat's, minus what the server does not model yet
+// (weapon skills, food and merits).
+package charstats
+
+// Attribute is one of the seven base attributes, in the order the client lists them.
+type Attribute int
+
+const (
+	STR Attribute = iota
+	DEX
+	VIT
+	AGI
+	INT
+	MND
+	CHR
+
+	// AttributeCount is the number of attributes
+	AttributeCount = 7
+)
+
+// Mod identifies a modifier, using LandSandBoat's modifier IDs so item modifiers can
+// be exported as-is.
+type Mod uint16
+
+const (
+	ModDEF  Mod = 1
+	ModHP   Mod = 2
+	ModHPP  Mod = 3
+	ModMP   Mod = 5
+	ModMPP  Mod = 7
+	ModSTR  Mod = 8
+	ModDEX  Mod = 9
+	ModVIT  Mod = 10
+	ModAGI  Mod = 11
+	ModINT  Mod = 12
+	ModMND  Mod = 13
+	ModCHR  Mod = 14
+	ModATT  Mod = 23
+	ModACC  Mod = 25
+	ModATTP Mod = 62
+	ModDEFP Mod = 63
+	ModEVA  Mod = 68
+
+	// the HP, MP and TP status effects give or take on each of their ticks
+	ModRegain      Mod = 368
+	ModRefresh     Mod = 369
+	ModRegen       Mod = 370
+	ModRegenDown   Mod = 404
+	ModRefreshDown Mod = 405
+	ModRegainDown  Mod = 406
+)
+
+// Modifiers holds the sum of every modifier applying to a character.
+type Modifiers map[Mod]int32
+
+// Add adds value to a modifier.
+func (m Modifiers) Add(mod Mod, value int32) {
+	m[mod] += value
+}
+
+// Merge adds every modifier of other.
+func (m Modifiers) Merge(other Modifiers) {
+	for mod, value := range other {
+		m[mod] += value
+	}
+}
+
+// Input is everything the stats of a character depend on.
+type Input struct {
+	Race      uint8
+	MainJob   uint8
+	MainLevel uint8
+	SubJob    uint8
+
+	// SubLevel is the effective support job level; see SubJobLevel
+	SubLevel uint8
+
+	// Mods are the modifiers of the character's equipment and status effects
+	Mods Modifiers
+}
+
+// Stats are the computed stats of a character.
+type Stats struct {
+	MaxHP int32
+	MaxMP int32
+
+	// Base are the attributes from race, jobs and levels; Bonus are what equipment and effects add
+	Base  [AttributeCount]uint16
+	Bonus [AttributeCount]int16
+
+	Attack  int32
+	Defense int32
+
+	Accuracy int32
+	Evasion  int32
+}
+
+// Attribute returns the total of an attribute.
+func (s Stats) Attribute(attribute Attribute) int32 {
+	return max(0, int32(s.Base[attribute])+int32(s.Bonus[attribute]))
+}
+
+// SubJobLevel returns the effective level of a support job: at most half of the main
+// job's level (but at least 1).
+func SubJobLevel(mainLevel, subJobLevel uint8) uint8 {
+	return min(subJobLevel, max(mainLevel/2, 1))
+}
+
+// Calculate computes the stats of a character.
+func Calculate(in Input) Stats {
+	var stats Stats
+	for attribute := range Attribute(AttributeCount) {
+		stats.Base[attribute] = uint16(attributeValue(in, attribute))
+		stats.Bonus[attribute] = int16(in.Mods[ModSTR+Mod(attribute)]) //nolint:gosec // modifiers are small
+	}
+
+	stats.MaxHP = max(1, percent(int32(baseHP(in)), in.Mods[ModHPP])+in.Mods[ModHP])
+
+	// jobs without MP do not gain any from equipment either
+	if mp := int32(baseMP(in)); mp > 0 {
+		stats.MaxMP = max(0, percent(mp, in.Mods[ModMPP])+in.Mods[ModMP])
+	}
+
+	attack := 8 + in.Mods[ModATT] + stats.Attribute(STR)*3/4
+	stats.Attack = max(1, percent(attack, in.Mods[ModATTP]))
+
+	defense := 8 + in.Mods[ModDEF] + stats.Attribute(VIT)/2
+	stats.Defense = max(1, percent(defense, in.Mods[ModDEFP]))
+
+	stats.Accuracy = max(0, in.Mods[ModACC]+stats.Attribute(DEX)*3/4)
+	stats.Evasion = max(0, in.Mods[ModEVA]+stats.Attribute(AGI)/2)
+
+	return stats
+}
+
+// levelSpans splits the main job's level into the ranges the scales apply to.
+type levelSpans struct {
+	upTo60     float32
+	over30     float32
+	over60To75 float32
+	over60     float32
+	over75     float32
+}
+
+func mainLevelSpans(level uint8) levelSpans {
+	l := int(max(level, 1))
+
+	return levelSpans{
+		upTo60:     float32(min(l, 60) - 1),
+		over30:     float32(min(max(l-30, 0), 30)),
+		over60To75: float32(min(max(l-60, 0), 15)),
+		over60:     float32(max(l-60, 0)),
+		over75:     float32(max(l-75, 0)),
+	}
+}
+
+func baseHP(in Input) float32 {
+	spans := mainLevelSpans(in.MainLevel)
+	scaled := func(grade uint8) float32 {
+		scale := hpScale[grade]
+		return scale[0] + scale[1]*spans.upTo60 + scale[2]*spans.over30 + scale[3]*spans.over60To75 + scale[4]*spans.over75
+	}
+
+	level := int(in.MainLevel)
+	bonus := float32(max(level-10, 0)+min(max(level-50, 0), 10)) * 2
+
+	var sub float32
+	if in.SubLevel > 0 {
+		scale := hpScale[jobGrade(in.SubJob, gradeHP)]
+		subOver10 := float32(min(max(int(in.SubLevel)-10, 0), 20))
+		subOver30 := float32(max(int(in.SubLevel)-30, 0))
+		sub = (scale[0] + scale[1]*float32(in.SubLevel-1) + scale[2]*subOver30 + subOver30 + subOver10) / 2
+	}
+
+	return scaled(raceGrade(in.Race, gradeHP)) + scaled(jobGrade(in.MainJob, gradeHP)) + bonus + sub
+}
+
+func baseMP(in Input) float32 {
+	spans := mainLevelSpans(in.MainLevel)
+	scaled := func(grade uint8) float32 {
+		scale := mpScale[grade]
+		return scale[0] + scale[1]*spans.upTo60 + scale[2]*spans.over60
+	}
+
+	subScaled := func(grade uint8) float32 {
+		scale := mpScale[grade]
+		return (scale[0] + scale[1]*float32(in.SubLevel-1)) / 2
+	}
+
+	mainGrade := jobGrade(in.MainJob, gradeMP)
+	subGrade := jobGrade(in.SubJob, gradeMP)
+	hasSub := in.SubLevel > 0 && subGrade > 0
+
+	var race, main, sub float32
+	switch {
+	case mainGrade > 0:
+		// the race's MP only counts when one of the jobs uses MP, and at the sub job's level if only it does
+		race = scaled(raceGrade(in.Race, gradeMP))
+		main = scaled(mainGrade)
+	case hasSub:
+		race = subScaled(raceGrade(in.Race, gradeMP))
+	}
+
+	if hasSub {
+		sub = subScaled(subGrade)
+	}
+
+	return race + main + sub
+}
+
+func attributeValue(in Input, attribute Attribute) float32 {
+	spans := mainLevelSpans(in.MainLevel)
+	column := gradeAttributes + int(attribute)
+	scaled := func(grade uint8, over75Offset float32) float32 {
+		scale := attributeScale[grade]
+		value := scale[0] + scale[1]*spans.upTo60 + scale[2]*spans.over60
+		if spans.over75 > 0 {
+			value += scale[3]*spans.over75 - over75Offset
+		}
+
+		return value
+	}
+
+	var sub float32
+	if in.SubLevel > 0 {
+		scale := attributeScale[jobGrade(in.SubJob, column)]
+		sub = (scale[0] + scale[1]*float32(in.SubLevel-1)) / 2
+	}
+
+	// the race's share above level 75 is shaved slightly so it rounds down like retail's
+	return scaled(raceGrade(in.Race, column), 0.01) + scaled(jobGrade(in.MainJob, column), 0) + sub
+}
+
+// percent returns value increased by pct percent.
+func percent(value, pct int32) int32 {
+	return value + value*pct/100
+}
@@ -0,0 +1,98 @@
+package charstats
+
+import (
+	"testing"
+)
+
+func TestCalculateGolden(t *testing.T) {
+	// expected values are LandSandBoat's for the same race, jobs and levels, with nothing equipped
+	cases := []struct {
+		name     string
+		in       Input
+		hp, mp   int32
+		base     [AttributeCount]uint16
+		att, def int32
+	}{
+		{"Hume WAR1", Input{Race: 1, MainJob: 1, MainLevel: 1}, 31, 0, [7]uint16{8, 7, 6, 7, 5, 5, 6}, 14, 11},
+		{"Hume WAR75/NIN37", Input{Race: 1, MainJob: 1, MainLevel: 75, SubJob: 13, SubLevel: 37}, 1255, 0, [7]uint16{74, 70, 67, 70, 59, 56, 60}, 63, 41},
+		{"Tarutaru BLM1", Input{Race: 5, MainJob: 4, MainLevel: 1}, 21, 30, [7]uint16{4, 7, 5, 8, 10, 6, 6}, 11, 10},
+		{"Tarutaru BLM75/RDM37", Input{Race: 5, MainJob: 4, MainLevel: 75, SubJob: 5, SubLevel: 37}, 772, 858, [7]uint16{53, 68, 55, 69, 81, 61, 65}, 47, 35},
+		{"Galka WAR50/MNK25", Input{Race: 8, MainJob: 1, MainLevel: 50, SubJob: 2, SubLevel: 25}, 1114, 0, [7]uint16{59, 51, 58, 45, 35, 40, 37}, 52, 37},
+		{"Elvaan PLD99/WAR49", Input{Race: 3, MainJob: 7, MainLevel: 99, SubJob: 1, SubLevel: 49}, 1579, 386, [7]uint16{107, 97, 102, 90, 85, 99, 100}, 88, 59},
+		{"Mithra THF30/WAR15", Input{Race: 7, MainJob: 6, MainLevel: 30, SubJob: 1, SubLevel: 15}, 483, 0, [7]uint16{30, 39, 28, 38, 31, 23, 22}, 30, 22},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Calculate(tc.in)
+			if got.MaxHP != tc.hp || got.MaxMP != tc.mp {
+				t.Fatalf("HP/MP = %d/%d, want %d/%d", got.MaxHP, got.MaxMP, tc.hp, tc.mp)
+			}
+
+			if got.Base != tc.base {
+				t.Fatalf("Base = %v, want %v", got.Base, tc.base)
+			}
+
+			if got.Attack != tc.att || got.Defense != tc.def {
+				t.Fatalf("ATK/DEF = %d/%d, want %d/%d", got.Attack, got.Defense, tc.att, tc.def)
+			}
+		})
+	}
+}
+
+func TestCalculateModifiers(t *testing.T) {
+	in := Input{Race: 1, MainJob: 1, MainLevel: 75, SubJob: 13, SubLevel: 37, Mods: Modifiers{
+		ModHP: 50, ModHPP: 10, ModMP: 30, ModSTR: 8, ModVIT: -6, ModATT: 20, ModDEF: 100, ModDEFP: 10,
+		ModACC: 15, ModEVA: -5,
+	}}
+
+	got := Calculate(in)
+
+	// 1255 * 110% + 50
+	if got.MaxHP != 1430 {
+		t.Fatalf("MaxHP = %d, want %d", got.MaxHP, 1430)
+	}
+
+	// WAR/NIN has no MP, so MP gear does nothing
+	if got.MaxMP != 0 {
+		t.Fatalf("MaxMP = %d, want 0", got.MaxMP)
+	}
+
+	if got.Bonus[STR] != 8 || got.Attribute(STR) != 82 || got.Attribute(VIT) != 61 {
+		t.Fatalf("Bonus = %v, STR = %d, VIT = %d", got.Bonus, got.Attribute(STR), got.Attribute(VIT))
+	}
+
+	// 8 + 20 + 82 * 3/4
+	if got.Attack != 89 {
+		t.Fatalf("Attack = %d, want %d", got.Attack, 89)
+	}
+
+	// (8 + 100 + 61/2) * 110%
+	if got.Defense != 151 {
+		t.Fatalf("Defense = %d, want %d", got.Defense, 151)
+	}
+
+	if want := 15 + got.Attribute(DEX)*3/4; got.Accuracy != want {
+		t.Fatalf("Accuracy = %d, want %d", got.Accuracy, want)
+	}
+
+	if want := -5 + got.Attribute(AGI)/2; got.Evasion != want {
+		t.Fatalf("Evasion = %d, want %d", got.Evasion, want)
+	}
+}
+
+func TestSubJobLevel(t *testing.T) {
+	cases := []struct{ main, sub, want uint8 }{
+		{75, 60, 37},
+		{75, 20, 20},
+		{1, 10, 1},
+		{3, 10, 1},
+		{10, 0, 0},
+	}
+
+	for _, tc := range cases {
+		if got := SubJobLevel(tc.main, tc.sub); got != tc.want {
+			t.Fatalf("SubJobLevel(%d, %d) = %d, want %d", tc.main, tc.sub, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package charstats
+
+// Grades are letter ratings (A = 1 ... G = 7, 0 = none) of how much a race or job adds to
+// each stat. The tables and scales are LandSandBoat's, which were fitted to retail values.
+
+// grade columns: HP, MP, then the attributes in Attribute order
+const (
+	gradeHP = iota
+	gradeMP
+	gradeAttributes
+)
+
+// raceGrades are indexed by race ID
+//
+//nolint:gochecknoglobals // static table
+var raceGrades = [...][gradeAttributes + AttributeCount]uint8{
+	{0, 0, 0, 0, 0, 0, 0, 0, 0}, // none
+	{4, 4, 4, 4, 4, 4, 4, 4, 4}, // Hume male
+	{4, 4, 4, 4, 4, 4, 4, 4, 4}, // Hume female
+	{3, 5, 2, 5, 3, 6, 6, 2, 4}, // Elvaan male
+	{3, 5, 2, 5, 3, 6, 6, 2, 4}, // Elvaan female
+	{7, 1, 6, 4, 5, 3, 1, 5, 4}, // Tarutaru male
+	{7, 1, 6, 4, 5, 3, 1, 5, 4}, // Tarutaru female
+	{4, 4, 5, 3, 5, 2, 4, 5, 6}, // Mithra
+	{1, 7, 3, 4, 1, 5, 5, 4, 6}, // Galka
+}
+
+// jobGrades are indexed by job ID
+//
+//nolint:gochecknoglobals // static table
+var jobGrades = [...][gradeAttributes + AttributeCount]uint8{
+	{0, 0, 0, 0, 0, 0, 0, 0, 0}, // none
+	{2, 0, 1, 3, 4, 3, 6, 6, 5}, // WAR
+	{1, 0, 3, 2, 1, 6, 7, 4, 5}, // MNK
+	{5, 3, 4, 6, 4, 5, 5, 1, 3}, // WHM
+	{6, 2, 6, 3, 6, 3, 1, 5, 4}, // BLM
+	{4, 4, 4, 4, 5, 5, 3, 3, 4}, // RDM
+	{4, 0, 4, 1, 4, 2, 3, 6, 6}, // THF
+	{3, 6, 2, 5, 1, 7, 7, 3, 3}, // PLD
+	{3, 6, 1, 3, 3, 4, 3, 7, 7}, // DRK
+	{3, 0, 4, 3, 4, 6, 5, 5, 1}, // BST
+	{4, 0, 4, 4, 4, 6, 4, 4, 2}, // BRD
+	{5, 0, 5, 4, 4, 1, 5, 4, 5}, // RNG
+	{2, 0, 3, 3, 3, 4, 5, 5, 4}, // SAM
+	{4, 0, 3, 2, 3, 2, 4, 7, 6}, // NIN
+	{3, 0, 2, 4, 3, 4, 6, 5, 3}, // DRG
+	{7, 1, 6, 5, 6, 4, 2, 2, 2}, // SMN
+	{4, 4, 5, 5, 5, 5, 5, 5, 5}, // BLU
+	{4, 0, 5, 3, 5, 2, 3, 5, 5}, // COR
+	{4, 0, 5, 2, 4, 3, 5, 6, 3}, // PUP
+	{4, 0, 4, 3, 5, 2, 6, 6, 2}, // DNC
+	{5, 4, 6, 4, 5, 4, 3, 4, 3}, // SCH
+	{3, 2, 6, 4, 5, 4, 3, 3, 6}, // GEO
+	{3, 6, 3, 4, 5, 2, 4, 4, 6}, // RUN
+}
+
+// hpScale columns: base, per level up to 60, per level from 31 to 60, per level from 61 to 75, per level above 75
+//
+//nolint:gochecknoglobals // static table
+var hpScale = [8][5]float32{
+	{0, 0, 0, 0, 0},
+	{19, 9, 1, 3, 3}, // A
+	{17, 8, 1, 3, 3}, // B
+	{16, 7, 1, 3, 3}, // C
+	{14, 6, 0, 3, 3}, // D
+	{13, 5, 0, 2, 2}, // E
+	{11, 4, 0, 2, 2}, // F
+	{10, 3, 0, 2, 2}, // G
+}
+
+// mpScale columns: base, per level up to 60, per level above 60
+//
+//nolint:gochecknoglobals // static table
+var mpScale = [8][3]float32{
+	{0, 0, 0},
+	{16, 6, 4},  // A
+	{14, 5, 4},  // B
+	{12, 4, 4},  // C
+	{10, 3, 4},  // D
+	{8, 2, 3},   // E
+	{6, 1, 2},   // F
+	{4, 0.5, 1}, // G
+}
+
+// attributeScale columns: base, per level up to 60, per level above 60, extra per level above 75
+//
+//nolint:gochecknoglobals // static table
+var attributeScale = [8][4]float32{
+	{0, 0, 0, 0},
+	{5, 0.50, 0.10, 0.35}, // A
+	{4, 0.45, 0.20, 0.35}, // B
+	{4, 0.40, 0.25, 0.35}, // C
+	{3, 0.35, 0.35, 0.35}, // D
+	{3, 0.30, 0.35, 0.35}, // E
+	{2, 0.25, 0.40, 0.35}, // F
+	{2, 0.20, 0.40, 0.35}, // G
+}
+
+func raceGrade(race uint8, column int) uint8 {
+	if int(race) >= len(raceGrades) {
+		return 0
+	}
+
+	return raceGrades[race][column]
+}
+
+func jobGrade(job uint8, column int) uint8 {
+	if int(job) >= len(jobGrades) {
+		return 0
+	}
+
+	return jobGrades[job][column]
+}
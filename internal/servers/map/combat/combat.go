@@ -0,0 +1,218 @@
+// Package combat holds the battle formulas: the chance to hit and to land critical
+// hits, the damage of a hit, the TP attacks build up, and the damage, healing and
+// interruption of spells.
+//
+// The formulas follow LandSandBoat's, minus what the server does not model yet
+// (combat skills, dual wielding, multi-attacks and the level correction of attack).
+package combat
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// MaxTP is the most TP a fighter can hold
+	MaxTP = 3000
+
+	// the chance to hit always stays within MinHitRate and MaxHitRate
+	MinHitRate = 0.20
+	MaxHitRate = 0.95
+
+	// BaseCritRate is the chance of a critical hit between evenly matched fighters
+	BaseCritRate = 0.05
+
+	// MaxCritRate bounds the chance of a critical hit from the DEX difference
+	MaxCritRate = 0.15
+
+	// maxRatio bounds the attack to defense ratio damage is computed from
+	maxRatio = 2.0
+
+	// maxPDIF bounds the damage multiplier, critical hits included
+	maxPDIF = 3.0
+)
+
+// Fighter is what a melee swing depends on about each side.
+type Fighter struct {
+	Level uint8
+
+	STR int32
+	DEX int32
+	VIT int32
+	AGI int32
+
+	Attack   int32
+	Defense  int32
+	Accuracy int32
+	Evasion  int32
+
+	// Damage is the base damage of the weapon (or fists) the fighter swings
+	Damage int32
+
+	// Delay is the time between attack rounds, in 1/60ths of a second
+	Delay uint16
+}
+
+// Swing is the outcome of a melee swing.
+type Swing struct {
+	Hit      bool
+	Critical bool
+	Damage   int32
+}
+
+// AttackInterval converts a delay to the time between attack rounds.
+func AttackInterval(delay uint16) time.Duration {
+	return time.Duration(delay) * time.Second / 60
+}
+
+// HitRate returns the chance of the attacker hitting the defender: 75% between
+// evenly matched fighters, moved by half the accuracy to evasion difference and 2%
+// per level of difference.
+func HitRate(attacker, defender Fighter) float64 {
+	rate := 0.75 + float64(attacker.Accuracy-defender.Evasion)/200 + float64(int(attacker.Level)-int(defender.Level))*0.02
+	return min(max(rate, MinHitRate), MaxHitRate)
+}
+
+// CritRate returns the chance of a hit of the attacker being critical, which goes up
+// with how much more DEX the attacker has than the defender has AGI.
+func CritRate(attacker, defender Fighter) float64 {
+	dDEX := attacker.DEX - defender.AGI
+
+	var bonus float64
+	switch {
+	case dDEX >= 50:
+		bonus = float64(dDEX) / 1000
+	case dDEX >= 40:
+		bonus = 0.05
+	case dDEX >= 30:
+		bonus = 0.04
+	case dDEX >= 20:
+		bonus = 0.03
+	case dDEX >= 14:
+		bonus = 0.02
+	case dDEX >= 7:
+		bonus = 0.01
+	}
+
+	return min(BaseCritRate+bonus, MaxCritRate)
+}
+
+// FSTR returns the damage the attacker's STR adds to (or takes from) a hit against
+// the defender's VIT. It is bounded by the weapon's damage.
+func FSTR(attacker, defender Fighter) int32 {
+	dSTR := attacker.STR - defender.VIT
+
+	var fSTR int32
+	switch {
+	case dSTR >= 12:
+		fSTR = dSTR + 4
+	case dSTR >= 6:
+		fSTR = dSTR + 6
+	case dSTR >= 1:
+		fSTR = dSTR + 7
+	case dSTR >= -2:
+		fSTR = dSTR + 8
+	case dSTR >= -7:
+		fSTR = dSTR + 9
+	case dSTR >= -15:
+		fSTR = dSTR + 10
+	case dSTR >= -21:
+		fSTR = dSTR + 12
+	default:
+		fSTR = dSTR + 13
+	}
+
+	rank := attacker.Damage / 9
+	return min(max(fSTR/4, -rank), rank+8)
+}
+
+// PDIFRange returns the bounds of the damage multiplier of a hit, from the ratio of
+// the attacker's attack to the defender's defense.
+func PDIFRange(attack, defense int32) (float64, float64) {
+	ratio := maxRatio
+	if defense > 0 {
+		ratio = min(float64(attack)/float64(defense), maxRatio)
+	}
+
+	var upper float64
+	switch {
+	case ratio < 0.5:
+		upper = ratio + 0.5
+	case ratio < 0.7:
+		upper = 1
+	case ratio < 1.2:
+		upper = ratio + 0.3
+	case ratio < 1.5:
+		upper = ratio * 1.25
+	default:
+		upper = ratio + 0.375
+	}
+
+	var lower float64
+	switch {
+	case ratio < 0.38:
+		lower = 0
+	case ratio < 1.25:
+		lower = ratio*1176/1024 - 448.0/1024
+	case ratio < 1.51:
+		lower = 1
+	case ratio < 2.44:
+		lower = ratio*1176/1024 - 755.0/1024
+	default:
+		lower = ratio - 0.375
+	}
+
+	return lower, upper
+}
+
+// Attack rolls a melee swing of the attacker at the defender.
+func Attack(attacker, defender Fighter, rng *rand.Rand) Swing {
+	if rng.Float64() >= HitRate(attacker, defender) {
+		return Swing{}
+	}
+
+	swing := Swing{Hit: true, Critical: rng.Float64() < CritRate(attacker, defender)}
+
+	lower, upper := PDIFRange(attacker.Attack, defender.Defense)
+	pDIF := (lower + rng.Float64()*(upper-lower)) * (1 + rng.Float64()*0.05)
+	if swing.Critical {
+		pDIF++
+	}
+
+	pDIF = min(pDIF, maxPDIF)
+	swing.Damage = max(0, int32(float64(attacker.Damage+FSTR(attacker, defender))*pDIF))
+
+	return swing
+}
+
+// BaseTP returns the TP a hit builds up for the attacker, which grows with the delay
+// of the weapon.
+func BaseTP(delay uint16) int32 {
+	d := int32(delay)
+
+	switch {
+	case d <= 180:
+		return 61 + (d-180)*63/360
+	case d <= 540:
+		return 61 + (d-180)*88/360
+	case d <= 630:
+		return 149 + (d-540)*20/360
+	case d <= 720:
+		return 154 + (d-630)*28/360
+	case d <= 900:
+		return 161 + (d-720)*24/360
+	default:
+		return 173 + (d-900)*28/360
+	}
+}
+
+// DefenderTP returns the TP a hit builds up for the defender: a third of the
+// attacker's.
+func DefenderTP(delay uint16) int32 {
+	return BaseTP(delay) / 3
+}
+
+// AddTP adds TP, keeping the total within MaxTP.
+func AddTP(tp, gained int32) int32 {
+	return min(max(tp+gained, 0), MaxTP)
+}
@@ -0,0 +1,156 @@
+package combat
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func testFighter() Fighter {
+	return Fighter{Level: 30, STR: 30, DEX: 30, VIT: 30, AGI: 30, Attack: 30, Defense: 30, Accuracy: 22, Evasion: 15, Damage: 12, Delay: 240}
+}
+
+func TestAttackInterval(t *testing.T) {
+	if got := AttackInterval(240); got != 4*time.Second {
+		t.Fatalf("AttackInterval(240) = %v, want %v", got, 4*time.Second)
+	}
+}
+
+func TestHitRate(t *testing.T) {
+	fighter := testFighter()
+	if got := HitRate(fighter, fighter); got < 0.785 || got > 0.786 {
+		t.Fatalf("HitRate() = %v, want 0.785", got)
+	}
+
+	weak, strong := fighter, fighter
+	weak.Level, strong.Level = 1, 75
+	if got := HitRate(weak, strong); got != MinHitRate {
+		t.Fatalf("HitRate() = %v, want %v", got, MinHitRate)
+	}
+
+	if got := HitRate(strong, weak); got != MaxHitRate {
+		t.Fatalf("HitRate() = %v, want %v", got, MaxHitRate)
+	}
+}
+
+func TestCritRate(t *testing.T) {
+	cases := []struct {
+		dex  int32
+		want float64
+	}{
+		{30, 0.05},
+		{40, 0.06},
+		{75, 0.10},
+		{90, 0.11},
+		{200, MaxCritRate},
+	}
+
+	for _, tc := range cases {
+		attacker, defender := testFighter(), testFighter()
+		attacker.DEX = tc.dex
+
+		if got := CritRate(attacker, defender); got < tc.want-1e-9 || got > tc.want+1e-9 {
+			t.Fatalf("CritRate(DEX %d) = %v, want %v", tc.dex, got, tc.want)
+		}
+	}
+}
+
+func TestFSTR(t *testing.T) {
+	cases := []struct {
+		str  int32
+		want int32
+	}{
+		{30, 2},
+		{42, 4},
+		{100, 9},
+		{0, -1},
+	}
+
+	for _, tc := range cases {
+		attacker, defender := testFighter(), testFighter()
+		attacker.STR = tc.str
+
+		if got := FSTR(attacker, defender); got != tc.want {
+			t.Fatalf("FSTR(STR %d) = %d, want %d", tc.str, got, tc.want)
+		}
+	}
+}
+
+func TestPDIFRange(t *testing.T) {
+	lower, upper := PDIFRange(100, 100)
+	if lower < 0.710 || lower > 0.711 || upper != 1.3 {
+		t.Fatalf("PDIFRange(100, 100) = %v, %v, want 0.71, 1.3", lower, upper)
+	}
+
+	if lower, upper = PDIFRange(500, 100); lower < 1.559 || lower > 1.560 || upper != 2.375 {
+		t.Fatalf("PDIFRange(500, 100) = %v, %v, want the capped ratio's range", lower, upper)
+	}
+
+	if lower, _ = PDIFRange(10, 100); lower != 0 {
+		t.Fatalf("PDIFRange(10, 100) lower = %v, want 0", lower)
+	}
+}
+
+func TestAttack(t *testing.T) {
+	attacker, defender := testFighter(), testFighter()
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	var hits, crits int
+	for range 1000 {
+		swing := Attack(attacker, defender, rng)
+		if !swing.Hit {
+			if swing.Critical || swing.Damage != 0 {
+				t.Fatalf("Attack() = %+v, want no damage on a miss", swing)
+			}
+
+			continue
+		}
+
+		hits++
+		if swing.Critical {
+			crits++
+		}
+
+		// (12 + 2) * up to 1.3 * 1.05, plus 1 on critical hits
+		if swing.Damage < 9 || swing.Damage > 33 {
+			t.Fatalf("Attack() damage = %d, want within the pDIF range", swing.Damage)
+		}
+	}
+
+	if hits < 740 || hits > 830 {
+		t.Fatalf("hits = %d, want about 785 out of 1000", hits)
+	}
+
+	if crits < 20 || crits > 60 {
+		t.Fatalf("critical hits = %d, want about 5%% of %d", crits, hits)
+	}
+
+	// the same seed gives the same swings
+	first := Attack(attacker, defender, rand.New(rand.NewPCG(7, 7)))
+	if second := Attack(attacker, defender, rand.New(rand.NewPCG(7, 7))); first != second {
+		t.Fatalf("Attack() = %+v then %+v, want the same swing from the same seed", first, second)
+	}
+}
+
+func TestBaseTP(t *testing.T) {
+	cases := map[uint16]int32{180: 61, 240: 75, 480: 134, 540: 149, 600: 152, 720: 161, 900: 173, 960: 177}
+	for delay, want := range cases {
+		if got := BaseTP(delay); got != want {
+			t.Fatalf("BaseTP(%d) = %d, want %d", delay, got, want)
+		}
+	}
+
+	if got := DefenderTP(240); got != 25 {
+		t.Fatalf("DefenderTP(240) = %d, want 25", got)
+	}
+}
+
+func TestAddTP(t *testing.T) {
+	if got := AddTP(2950, 100); got != MaxTP {
+		t.Fatalf("AddTP() = %d, want %d", got, MaxTP)
+	}
+
+	if got := AddTP(10, -20); got != 0 {
+		t.Fatalf("AddTP() = %d, want 0", got)
+	}
+}
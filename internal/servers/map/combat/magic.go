@@ -0,0 +1,31 @@
+package combat
+
+const (
+	// BaseInterruptRate is the chance of a hit interrupting the spell of an evenly matched caster
+	BaseInterruptRate = 0.5
+
+	// the chance of a hit interrupting a spell always stays within MinInterruptRate and MaxInterruptRate
+	MinInterruptRate = 0.10
+	MaxInterruptRate = 0.95
+)
+
+// MagicDamage returns the damage of a spell (or ability) of base damage base, which
+// goes up by multiplier for each point of INT the caster has over the target.
+func MagicDamage(base uint16, multiplier float32, dINT int32) int32 {
+	return max(int32(float64(base)+float64(dINT)*float64(multiplier)), 0)
+}
+
+// Healing returns the HP a healing spell (or ability) of base potency base restores:
+// the caster's healing power (half its MND and a quarter of its VIT) is scaled by
+// multiplier.
+func Healing(base uint16, multiplier float32, mnd, vit int32) int32 {
+	power := mnd/2 + vit/4
+	return max(int32(base)+int32(float64(power)*float64(multiplier)), 0)
+}
+
+// InterruptRate returns the chance of a hit interrupting the spell the caster is
+// casting, 5% more per level the attacker has over the caster.
+func InterruptRate(casterLevel, attackerLevel uint8) float64 {
+	rate := BaseInterruptRate + float64(int(attackerLevel)-int(casterLevel))*0.05
+	return min(max(rate, MinInterruptRate), MaxInterruptRate)
+}
@@ -0,0 +1,42 @@
+package combat
+
+import (
+	"testing"
+)
+
+func TestMagicDamage(t *testing.T) {
+	cases := []struct {
+		dINT int32
+		want int32
+	}{
+		{0, 60},
+		{10, 70},
+		{-100, 0},
+	}
+
+	for _, tc := range cases {
+		if got := MagicDamage(60, 1, tc.dINT); got != tc.want {
+			t.Fatalf("MagicDamage(60, 1, %d) = %d, want %d", tc.dINT, got, tc.want)
+		}
+	}
+}
+
+func TestHealing(t *testing.T) {
+	if got := Healing(20, 1.5, 40, 40); got != 65 {
+		t.Fatalf("Healing() = %d, want 65", got)
+	}
+}
+
+func TestInterruptRate(t *testing.T) {
+	if got := InterruptRate(30, 30); got != BaseInterruptRate {
+		t.Fatalf("InterruptRate(30, 30) = %v, want %v", got, BaseInterruptRate)
+	}
+
+	if got := InterruptRate(75, 1); got != MinInterruptRate {
+		t.Fatalf("InterruptRate(75, 1) = %v, want %v", got, MinInterruptRate)
+	}
+
+	if got := InterruptRate(1, 75); got != MaxInterruptRate {
+		t.Fatalf("InterruptRate(1, 75) = %v, want %v", got, MaxInterruptRate)
+	}
+}
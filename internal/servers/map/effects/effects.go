@@ -0,0 +1,293 @@
+// Package effects holds the status effects (buffs and debuffs) of a battle entity: how
+// a new effect is applied over the ones already active, when effects tick and wear
+// off, and which ones dispel, erase, death or zoning remove.
+//
+// The rules follow LandSandBoat's, minus what the server does not model yet (effect
+// tiers, sub-powers and the effects that block others).
+package effects
+
+import (
+	"slices"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+)
+
+// MaxEffects is the most effects an entity can have at once; the client shows 32 icons.
+const MaxEffects = 32
+
+// Flag tells what removes an effect, using LandSandBoat's effect flag values so status
+// effects can be exported as-is.
+type Flag uint32
+
+const (
+	// FlagDispelable effects are removed by dispel (they are usually buffs)
+	FlagDispelable Flag = 0x00000001
+
+	// FlagErasable effects are removed by erase (they are usually debuffs)
+	FlagErasable Flag = 0x00000002
+
+	// FlagDeath effects are lost when their owner is defeated
+	FlagDeath Flag = 0x00000020
+
+	// FlagOnZone effects are lost when changing zones
+	FlagOnZone Flag = 0x00000100
+
+	// FlagLogout effects are lost when logging out
+	FlagLogout Flag = 0x00100000
+
+	// FlagOnJobChange effects are lost when changing jobs
+	FlagOnJobChange Flag = 0x00400000
+)
+
+// Overwrite is how an effect is applied over an active effect of the same ID or type.
+type Overwrite uint8
+
+const (
+	// OverwriteEqualOrHigher effects replace one of lower or equal power
+	OverwriteEqualOrHigher Overwrite = iota
+
+	// OverwriteHigher effects only replace one of lower power
+	OverwriteHigher
+
+	// OverwriteAlways effects always replace the active one
+	OverwriteAlways
+
+	// OverwriteNever effects do not land while one is active
+	OverwriteNever
+
+	// OverwriteStack effects add up: each application is an effect of its own
+	OverwriteStack
+)
+
+// Effect is a status effect on an entity.
+type Effect struct {
+	ID    uint16
+	Flags Flag
+
+	// Type groups effects that overwrite each other; 0 for none
+	Type uint16
+
+	// NegativeID is the opposite effect, which this one replaces; 0 for none
+	NegativeID uint16
+
+	Overwrite Overwrite
+
+	// Power is the strength of the effect (e.g. the defense of protect, the damage of poison)
+	Power int32
+
+	// Mods are the modifiers the effect gives per point of power
+	Mods charstats.Modifiers
+
+	// SourceID is the entity that applied the effect, or 0
+	SourceID uint32
+
+	// Tick is the time between the effect's ticks, or 0 for an effect that does not tick
+	Tick time.Duration
+
+	// Duration is how long the effect lasts, or 0 for an effect that never wears off
+	Duration time.Duration
+
+	// StartedAt and NextTickAt are zone times, set when the effect is added
+	StartedAt  time.Time
+	NextTickAt time.Time
+}
+
+// Has reports whether the effect has a flag.
+func (e *Effect) Has(flag Flag) bool {
+	return e.Flags&flag != 0
+}
+
+// Remaining returns how long the effect still lasts, or 0 for an effect that never wears off.
+func (e *Effect) Remaining(now time.Time) time.Duration {
+	if e.Duration == 0 {
+		return 0
+	}
+
+	return max(e.StartedAt.Add(e.Duration).Sub(now), 0)
+}
+
+// Expired reports whether the effect wore off.
+func (e *Effect) Expired(now time.Time) bool {
+	return e.Duration != 0 && !now.Before(e.StartedAt.Add(e.Duration))
+}
+
+// Modifiers returns the modifiers the effect gives at its power.
+func (e *Effect) Modifiers() charstats.Modifiers {
+	mods := make(charstats.Modifiers, len(e.Mods))
+	for mod, value := range e.Mods {
+		mods.Add(mod, value*e.Power)
+	}
+
+	return mods
+}
+
+// overwrites reports whether the effect may replace an active one it conflicts with.
+func (e *Effect) overwrites(active *Effect) bool {
+	switch e.Overwrite {
+	case OverwriteEqualOrHigher:
+		return e.Power >= active.Power
+	case OverwriteHigher:
+		return e.Power > active.Power
+	case OverwriteAlways, OverwriteStack:
+		return true
+	case OverwriteNever:
+		return false
+	default:
+		return false
+	}
+}
+
+// conflicts reports whether the effect is applied over an active one rather than next to it.
+func (e *Effect) conflicts(active *Effect) bool {
+	if e.ID == active.ID {
+		return e.Overwrite != OverwriteStack
+	}
+
+	return e.Type != 0 && e.Type == active.Type
+}
+
+// List holds the status effects of an entity, in the order they were applied. The
+// zero value is an empty list.
+type List struct {
+	effects []*Effect
+}
+
+// Len returns the number of active effects.
+func (l *List) Len() int {
+	return len(l.effects)
+}
+
+// All returns the active effects, in the order they were applied.
+func (l *List) All() []*Effect {
+	return l.effects
+}
+
+// Get returns the first active effect with an ID, or nil.
+func (l *List) Get(id uint16) *Effect {
+	for _, effect := range l.effects {
+		if effect.ID == id {
+			return effect
+		}
+	}
+
+	return nil
+}
+
+// Add applies an effect, starting it at now. Active effects it conflicts with are
+// replaced when the overwrite rule of the new effect allows it, and its opposite
+// effect is replaced as well. It returns the replaced effects, and whether the new
+// effect landed; when it did not, the list is left unchanged.
+func (l *List) Add(effect Effect, now time.Time) ([]Effect, bool) {
+	var replaced []*Effect
+	for _, active := range l.effects {
+		switch {
+		case effect.conflicts(active):
+			if !effect.overwrites(active) {
+				return nil, false
+			}
+
+			replaced = append(replaced, active)
+		case effect.NegativeID != 0 && active.ID == effect.NegativeID:
+			replaced = append(replaced, active)
+		}
+	}
+
+	if len(l.effects)-len(replaced) >= MaxEffects {
+		return nil, false
+	}
+
+	effect.StartedAt = now
+	if effect.Tick > 0 {
+		effect.NextTickAt = now.Add(effect.Tick)
+	}
+
+	removed := l.remove(func(active *Effect) bool {
+		return slices.Contains(replaced, active)
+	})
+	l.effects = append(l.effects, &effect)
+
+	return removed, true
+}
+
+// Remove removes every effect with an ID and returns them.
+func (l *List) Remove(id uint16) []Effect {
+	return l.remove(func(effect *Effect) bool {
+		return effect.ID == id
+	})
+}
+
+// RemoveFlagged removes every effect with a flag (e.g. FlagDeath) and returns them.
+func (l *List) RemoveFlagged(flag Flag) []Effect {
+	return l.remove(func(effect *Effect) bool {
+		return effect.Has(flag)
+	})
+}
+
+// Clear removes every effect and returns them.
+func (l *List) Clear() []Effect {
+	return l.remove(func(*Effect) bool {
+		return true
+	})
+}
+
+// Dispel removes the most recently applied effect with a flag (FlagDispelable for
+// dispel, FlagErasable for erase), reporting whether there was one.
+func (l *List) Dispel(flag Flag) (Effect, bool) {
+	for i := len(l.effects) - 1; i >= 0; i-- {
+		if effect := l.effects[i]; effect.Has(flag) {
+			l.effects = slices.Delete(l.effects, i, i+1)
+			return *effect, true
+		}
+	}
+
+	return Effect{}, false
+}
+
+// Expire removes the effects that wore off by now and returns them.
+func (l *List) Expire(now time.Time) []Effect {
+	return l.remove(func(effect *Effect) bool {
+		return effect.Expired(now)
+	})
+}
+
+// Due returns the effects whose tick is due by now, and schedules their next tick.
+func (l *List) Due(now time.Time) []*Effect {
+	var due []*Effect
+	for _, effect := range l.effects {
+		if effect.Tick > 0 && !now.Before(effect.NextTickAt) {
+			effect.NextTickAt = effect.NextTickAt.Add(effect.Tick)
+			due = append(due, effect)
+		}
+	}
+
+	return due
+}
+
+// Modifiers returns the sum of the modifiers of every active effect.
+func (l *List) Modifiers() charstats.Modifiers {
+	mods := make(charstats.Modifiers)
+	for _, effect := range l.effects {
+		mods.Merge(effect.Modifiers())
+	}
+
+	return mods
+}
+
+// remove removes the effects matching a predicate and returns them.
+func (l *List) remove(match func(*Effect) bool) []Effect {
+	var removed []Effect
+	kept := l.effects[:0]
+	for _, effect := range l.effects {
+		if match(effect) {
+			removed = append(removed, *effect)
+		} else {
+			kept = append(kept, effect)
+		}
+	}
+
+	clear(l.effects[len(kept):])
+	l.effects = kept
+
+	return removed
+}
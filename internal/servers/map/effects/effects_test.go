@@ -0,0 +1,168 @@
+package effects
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+)
+
+// the IDs of the effects the tests use
+const (
+	poison  = 3
+	slow    = 13
+	haste   = 33
+	protect = 40
+	regen   = 42
+)
+
+func testEffect(id uint16, power int32, overwrite Overwrite) Effect {
+	return Effect{ID: id, Power: power, Overwrite: overwrite, Flags: FlagDispelable | FlagDeath, Duration: time.Minute}
+}
+
+func TestAddOverwrite(t *testing.T) {
+	now := time.Unix(0, 0)
+	cases := []struct {
+		name      string
+		overwrite Overwrite
+		power     int32
+		want      bool
+	}{
+		{"equal or higher, weaker", OverwriteEqualOrHigher, 9, false},
+		{"equal or higher, equal", OverwriteEqualOrHigher, 10, true},
+		{"higher, equal", OverwriteHigher, 10, false},
+		{"higher, stronger", OverwriteHigher, 11, true},
+		{"always, weaker", OverwriteAlways, 1, true},
+		{"never, stronger", OverwriteNever, 50, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var list List
+			list.Add(testEffect(protect, 10, tc.overwrite), now)
+
+			replaced, ok := list.Add(testEffect(protect, tc.power, tc.overwrite), now.Add(time.Second))
+			if ok != tc.want {
+				t.Fatalf("Add() = %v, want %v", ok, tc.want)
+			}
+
+			if list.Len() != 1 {
+				t.Fatalf("Len() = %d, want 1", list.Len())
+			}
+
+			if ok && (len(replaced) != 1 || list.Get(protect).Power != tc.power) {
+				t.Fatalf("replaced = %+v, power = %d, want the new effect", replaced, list.Get(protect).Power)
+			}
+		})
+	}
+}
+
+func TestAddTypeAndNegative(t *testing.T) {
+	now := time.Unix(0, 0)
+	var list List
+
+	// effects of a type overwrite each other, whatever their ID
+	first, second := testEffect(100, 5, OverwriteEqualOrHigher), testEffect(101, 10, OverwriteEqualOrHigher)
+	first.Type, second.Type = 1, 1
+	list.Add(first, now)
+	if _, ok := list.Add(second, now); !ok || list.Get(100) != nil || list.Get(101) == nil {
+		t.Fatalf("Add() = %v, effects = %+v, want the second effect only", ok, list.All())
+	}
+
+	// haste replaces slow
+	list.Add(testEffect(slow, 300, OverwriteEqualOrHigher), now)
+	fast := testEffect(haste, 150, OverwriteEqualOrHigher)
+	fast.NegativeID = slow
+
+	replaced, ok := list.Add(fast, now)
+	if !ok || len(replaced) != 1 || replaced[0].ID != slow || list.Get(slow) != nil {
+		t.Fatalf("Add(haste) = %+v, %v, want slow replaced", replaced, ok)
+	}
+}
+
+func TestAddStack(t *testing.T) {
+	now := time.Unix(0, 0)
+	var list List
+
+	for range MaxEffects {
+		if _, ok := list.Add(testEffect(poison, 1, OverwriteStack), now); !ok {
+			t.Fatalf("Add() refused a stack")
+		}
+	}
+
+	if _, ok := list.Add(testEffect(protect, 1, OverwriteStack), now); ok || list.Len() != MaxEffects {
+		t.Fatalf("Add() = %v, Len() = %d, want the list full", ok, list.Len())
+	}
+}
+
+func TestExpireAndTicks(t *testing.T) {
+	now := time.Unix(0, 0)
+	var list List
+
+	effect := testEffect(regen, 5, OverwriteEqualOrHigher)
+	effect.Tick = 3 * time.Second
+	effect.Duration = 10 * time.Second
+	list.Add(effect, now)
+	list.Add(Effect{ID: protect, Power: 10}, now)
+
+	ticks := 0
+	for second := range 10 {
+		at := now.Add(time.Duration(second+1) * time.Second)
+		if expired := list.Expire(at); len(expired) != 0 {
+			if second != 9 || expired[0].ID != regen {
+				t.Fatalf("Expire() at %ds = %+v", second+1, expired)
+			}
+
+			continue
+		}
+
+		ticks += len(list.Due(at))
+	}
+
+	if ticks != 3 {
+		t.Fatalf("ticks = %d, want 3", ticks)
+	}
+
+	if list.Len() != 1 || list.Get(protect).Remaining(now.Add(time.Hour)) != 0 {
+		t.Fatalf("effects = %+v, want protect left, without end", list.All())
+	}
+}
+
+func TestDispelAndRemove(t *testing.T) {
+	now := time.Unix(0, 0)
+	var list List
+
+	list.Add(testEffect(protect, 10, OverwriteEqualOrHigher), now)
+	list.Add(testEffect(regen, 5, OverwriteEqualOrHigher), now)
+	list.Add(Effect{ID: poison, Power: 2, Flags: FlagErasable}, now)
+
+	if effect, ok := list.Dispel(FlagDispelable); !ok || effect.ID != regen {
+		t.Fatalf("Dispel() = %+v, %v, want the last buff", effect, ok)
+	}
+
+	if effect, ok := list.Dispel(FlagErasable); !ok || effect.ID != poison {
+		t.Fatalf("Dispel(erase) = %+v, %v, want poison", effect, ok)
+	}
+
+	if _, ok := list.Dispel(FlagErasable); ok {
+		t.Fatalf("Dispel(erase) found an effect, want none")
+	}
+
+	if removed := list.RemoveFlagged(FlagDeath); len(removed) != 1 || list.Len() != 0 {
+		t.Fatalf("RemoveFlagged() = %+v, want protect", removed)
+	}
+}
+
+func TestModifiers(t *testing.T) {
+	now := time.Unix(0, 0)
+	var list List
+
+	effect := testEffect(protect, 10, OverwriteEqualOrHigher)
+	effect.Mods = charstats.Modifiers{charstats.ModDEF: 1, charstats.ModEVA: -2}
+	list.Add(effect, now)
+
+	mods := list.Modifiers()
+	if mods[charstats.ModDEF] != 10 || mods[charstats.ModEVA] != -20 {
+		t.Fatalf("Modifiers() = %v, want DEF 10 and EVA -20", mods)
+	}
+}
@@ -0,0 +1,122 @@
+// Package experience holds the experience point table and the rules for gaining and
+// losing levels with experience points.
+package experience
+
+const (
+	// MaxLevel is the highest level a job can reach
+	MaxLevel = 99
+
+	// DefaultLevelCap is the level cap before the Abyssea expansions raised it
+	DefaultLevelCap = 75
+)
+
+// toNextLevel holds the experience points needed to go from each level to the next
+// one, indexed by level.
+var toNextLevel = [MaxLevel + 1]uint16{
+	0, 500, 750, 1000, 1250, 1500, 1750, 2000, 2200, 2400, // 0-9
+	2600, 2800, 3000, 3200, 3400, 3600, 3800, 4000, 4200, 4400, // 10-19
+	4600, 4800, 5000, 5100, 5200, 5300, 5400, 5500, 5600, 5700, // 20-29
+	5800, 5900, 6000, 6100, 6200, 6300, 6400, 6500, 6600, 6700, // 30-39
+	6800, 6900, 7000, 7100, 7200, 7300, 7400, 7500, 7600, 7700, // 40-49
+	7800, 8000, 9200, 10400, 11600, 12800, 14000, 15200, 16400, 17600, // 50-59
+	18800, 20000, 21500, 23000, 24500, 26000, 27500, 29000, 30500, 32000, // 60-69
+	34000, 36000, 38000, 40000, 42000, 44000, 45000, 46000, 47000, 48000, // 70-79
+	49000, 50000, 51000, 52000, 53000, 54000, 55000, 56000, 56000, 56000, // 80-89
+	56000, 56000, 56000, 56000, 56000, 56000, 56000, 56000, 56000, 0, // 90-99
+}
+
+// ToNextLevel returns the experience points needed to go from level to the next one,
+// or 0 at the maximum level.
+func ToNextLevel(level uint8) uint16 {
+	if level >= MaxLevel {
+		return 0
+	}
+
+	return toNextLevel[level]
+}
+
+// Progress is a job level together with the experience points earned towards the next one.
+type Progress struct {
+	Level uint8
+	Exp   uint16
+}
+
+// Gain adds experience points. Reaching the next level levels up at most once; the
+// points left over stop one short of the level after. At levelCap the points stop one
+// short of the next level instead.
+func (p Progress) Gain(points uint32, levelCap uint8) Progress {
+	next := ToNextLevel(p.Level)
+	if next == 0 {
+		return Progress{Level: p.Level}
+	}
+
+	exp := uint32(p.Exp) + points
+	if exp < uint32(next) {
+		return Progress{Level: p.Level, Exp: uint16(exp)}
+	}
+
+	if p.Level >= levelCap {
+		return Progress{Level: p.Level, Exp: next - 1}
+	}
+
+	level := p.Level + 1
+	return Progress{Level: level, Exp: uint16(min(exp-uint32(next), uint32(max(ToNextLevel(level), 1)-1)))} //nolint:gosec // bounded by the table
+}
+
+// Lose removes experience points. Losing more than were earned towards the next level
+// drops one level, keeping the rest of the loss off the points needed for that level;
+// level 1 bottoms out at 0 points.
+func (p Progress) Lose(points uint32) Progress {
+	if points <= uint32(p.Exp) {
+		return Progress{Level: p.Level, Exp: p.Exp - uint16(points)} //nolint:gosec // bounded by the current points
+	}
+
+	if p.Level <= 1 {
+		return Progress{Level: p.Level}
+	}
+
+	level := p.Level - 1
+	next := uint32(ToNextLevel(level))
+	shortfall := min(points-uint32(p.Exp), next)
+
+	return Progress{Level: level, Exp: uint16(next - shortfall)} //nolint:gosec // bounded by the table
+}
+
+// TooWeak reports whether a mob of mobLevel checks as too weak to a character of
+// level, which makes it worth no experience points and keeps it from aggroing. The
+// band grows with the character's level, approximating the retail /check ranges
+// (at 75, mobs below 56 are too weak).
+func TooWeak(level, mobLevel uint8) bool {
+	return int(mobLevel) < int(level)-int(level)/4-1
+}
+
+// KillExp returns the experience points a character of level earns for defeating a
+// mob of mobLevel: 100 for an even match, 25 more per level the mob has over the
+// character up to 200, and 15 less per level under down to 15. Mobs too weak are
+// worth nothing. This approximates the retail table for characters fighting alone.
+func KillExp(level, mobLevel uint8) uint32 {
+	if TooWeak(level, mobLevel) {
+		return 0
+	}
+
+	diff := int(mobLevel) - int(level)
+	if diff >= 0 {
+		return uint32(min(100+25*diff, 200)) //nolint:gosec // bounded
+	}
+
+	return uint32(max(100+15*diff, 15)) //nolint:gosec // bounded
+}
+
+// DeathExpLoss returns the experience points a character of level loses when
+// defeated: 8% of what the level needs up to level 67, and 2400 from 68 on.
+// Characters below level 4 lose nothing.
+func DeathExpLoss(level uint8) uint32 {
+	switch {
+	case level < 4:
+		return 0
+	case level <= 67:
+		return uint32(ToNextLevel(level)) * 8 / 100
+	default:
+		return 2400
+	}
+}
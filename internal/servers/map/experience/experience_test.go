@@ -0,0 +1,111 @@
+package experience
+
+import "testing"
+
+func TestToNextLevel(t *testing.T) {
+	cases := map[uint8]uint16{1: 500, 7: 2000, 8: 2200, 22: 5000, 23: 5100, 50: 7800, 51: 8000, 52: 9200, 61: 20000, 62: 21500, 69: 32000, 70: 34000, 75: 44000, 76: 45000, 87: 56000, 98: 56000, 99: 0, 120: 0}
+	for level, want := range cases {
+		if got := ToNextLevel(level); got != want {
+			t.Fatalf("ToNextLevel(%d) = %d, want %d", level, got, want)
+		}
+	}
+}
+
+func TestGain(t *testing.T) {
+	cases := []struct {
+		name     string
+		progress Progress
+		points   uint32
+		levelCap uint8
+		want     Progress
+	}{
+		{"below next level", Progress{Level: 1, Exp: 100}, 200, DefaultLevelCap, Progress{Level: 1, Exp: 300}},
+		{"level up", Progress{Level: 1, Exp: 400}, 200, DefaultLevelCap, Progress{Level: 2, Exp: 100}},
+		{"one level at a time", Progress{Level: 1}, 5000, DefaultLevelCap, Progress{Level: 2, Exp: 749}},
+		{"at the cap", Progress{Level: 75, Exp: 43000}, 5000, DefaultLevelCap, Progress{Level: 75, Exp: 43999}},
+		{"above the cap", Progress{Level: 80, Exp: 100}, 100000, DefaultLevelCap, Progress{Level: 80, Exp: 48999}},
+		{"higher cap", Progress{Level: 75, Exp: 43000}, 1500, 80, Progress{Level: 76, Exp: 500}},
+		{"maximum level", Progress{Level: 98, Exp: 55000}, 2000, MaxLevel, Progress{Level: 99}},
+		{"past the maximum level", Progress{Level: 99}, 2000, MaxLevel, Progress{Level: 99}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.progress.Gain(tc.points, tc.levelCap); got != tc.want {
+				t.Fatalf("Gain(%d, %d) = %+v, want %+v", tc.points, tc.levelCap, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLose(t *testing.T) {
+	cases := []struct {
+		name     string
+		progress Progress
+		points   uint32
+		want     Progress
+	}{
+		{"within the level", Progress{Level: 30, Exp: 1000}, 400, Progress{Level: 30, Exp: 600}},
+		{"all points", Progress{Level: 30, Exp: 1000}, 1000, Progress{Level: 30}},
+		{"level down", Progress{Level: 30, Exp: 100}, 600, Progress{Level: 29, Exp: 5200}},
+		{"one level at most", Progress{Level: 2}, 100000, Progress{Level: 1}},
+		{"level 1", Progress{Level: 1, Exp: 50}, 100, Progress{Level: 1}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.progress.Lose(tc.points); got != tc.want {
+				t.Fatalf("Lose(%d) = %+v, want %+v", tc.points, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTooWeak(t *testing.T) {
+	cases := []struct {
+		level, mobLevel uint8
+		want            bool
+	}{
+		{1, 1, false},
+		{10, 7, false},
+		{10, 6, true},
+		{75, 56, false},
+		{75, 55, true},
+		{75, 80, false},
+	}
+
+	for _, tc := range cases {
+		if got := TooWeak(tc.level, tc.mobLevel); got != tc.want {
+			t.Fatalf("TooWeak(%d, %d) = %v, want %v", tc.level, tc.mobLevel, got, tc.want)
+		}
+	}
+}
+
+func TestKillExp(t *testing.T) {
+	cases := []struct {
+		level, mobLevel uint8
+		want            uint32
+	}{
+		{10, 10, 100},
+		{10, 12, 150},
+		{10, 20, 200},
+		{10, 8, 70},
+		{75, 60, 15},
+		{75, 50, 0},
+	}
+
+	for _, tc := range cases {
+		if got := KillExp(tc.level, tc.mobLevel); got != tc.want {
+			t.Fatalf("KillExp(%d, %d) = %d, want %d", tc.level, tc.mobLevel, got, tc.want)
+		}
+	}
+}
+
+func TestDeathExpLoss(t *testing.T) {
+	cases := map[uint8]uint32{1: 0, 3: 0, 4: 100, 30: 464, 67: 2320, 68: 2400, 99: 2400}
+	for level, want := range cases {
+		if got := DeathExpLoss(level); got != want {
+			t.Fatalf("DeathExpLoss(%d) = %d, want %d", level, got, want)
+		}
+	}
+}
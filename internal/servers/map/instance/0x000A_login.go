@@ -1,118 +1,235 @@
 package instance
 
 import (
+	"context"
+	"fmt"
 	"math"
 	"time"
 
 	"github.com/GoFFXI/GoFFXI/internal/database"
-	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
 	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/scripting"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
 )
 
-func (s *InstanceWorker) processLoginPacket(routedPacket mapPackets.RoutedPacket) {
-	s.Logger().Info("processing login packet", "clientAddr", routedPacket.ClientAddr)
+const (
+	// defaultLoginZone is used when the character could not be loaded (231 = Northern San d'Oria)
+	defaultLoginZone = 231
 
-	character, err := s.DB().GetCharacterByID(s.ctx, routedPacket.CharacterID)
+	// vanadielEpoch is the Unix time Vana'diel time counts from
+	vanadielEpoch = 1009810800
+)
+
+func (s *InstanceWorker) handleLoginPacket(pctx *PacketContext, _ *clientPackets.LoginPacket) error {
+	s.Logger().Info("processing login packet", "clientAddr", pctx.ClientAddr)
+
+	character, err := s.DB().GetCharacterByID(s.ctx, pctx.CharacterID)
 	if err != nil {
-		s.Logger().Warn("failed to load character for login", "characterID", routedPacket.CharacterID, "error", err)
+		s.Logger().Warn("failed to load character for login", "characterID", pctx.CharacterID, "error", err)
 	}
 
 	var looks *database.CharacterLooks
-	if cl, err := s.DB().GetCharacterLooksByID(s.ctx, routedPacket.CharacterID); err == nil {
+	if cl, err := s.DB().GetCharacterLooksByID(s.ctx, pctx.CharacterID); err == nil {
 		looks = &cl
 	}
 
 	var stats *database.CharacterStats
-	if cs, err := s.DB().GetCharacterStatsByID(s.ctx, routedPacket.CharacterID); err == nil {
+	if cs, err := s.DB().GetCharacterStatsByID(s.ctx, pctx.CharacterID); err == nil {
 		stats = &cs
 	}
 
-	// send a character update packet first so the client has entity context
-	charUpdatePacket := CreateCharacterUpdatePacket(&character, looks, stats)
-	if err := s.sendPacket(routedPacket.ClientAddr, charUpdatePacket); err != nil {
-		s.Logger().Warn("failed to send char update", "clientAddr", routedPacket.ClientAddr, "error", err)
+	if jobs, err := s.DB().GetCharacterJobsByID(s.ctx, pctx.CharacterID); err == nil {
+		character.Jobs = &jobs
+		character.Stats = stats
 	}
 
-	equipClearPacket := serverPackets.EquipClearPacket{}
-	equipListPackets := CreateEquipListPackets(&character)
-	graphListPacket := CreateGrapListPacket(looks, &character)
-	itemMaxPacket := CreateItemMaxPacket(&character)
-	loginPacket := CreateLoginPacketFromCharacter(&character, looks, stats)
-	enterZonePacket := CreateEnterZonePacket()
-
-	clientAddr := routedPacket.ClientAddr
-	if err := s.sendPacket(clientAddr, &equipClearPacket); err != nil {
-		s.Logger().Warn("failed to send equip clear", "clientAddr", clientAddr, "error", err)
-	}
-	for _, equipPacket := range equipListPackets {
-		if err := s.sendPacket(clientAddr, equipPacket); err != nil {
-			s.Logger().Warn("failed to send equip list", "clientAddr", clientAddr, "slot", equipPacket.EquipKind, "error", err)
+	// characters that never earned experience points have no row until they do
+	character.Exp = &database.CharacterExp{CharacterID: pctx.CharacterID}
+	if exp, err := s.DB().GetCharacterExpByID(s.ctx, pctx.CharacterID); err == nil {
+		character.Exp = &exp
+	}
+
+	var gmLevel uint8
+	if character.ID != 0 {
+		if account, err := s.DB().GetAccountByID(s.ctx, uint(character.AccountID)); err == nil {
+			gmLevel = account.GMLevel
 		}
 	}
-	if err := s.sendPacket(clientAddr, graphListPacket); err != nil {
-		s.Logger().Warn("failed to send grap list", "clientAddr", clientAddr, "error", err)
+
+	inv, err := s.loadInventory(s.ctx, pctx.CharacterID)
+	if err == nil {
+		err = s.loadEquipment(s.ctx, pctx.CharacterID, inv)
 	}
-	if err := s.sendPacket(clientAddr, itemMaxPacket); err != nil {
-		s.Logger().Warn("failed to send item max", "clientAddr", clientAddr, "error", err)
+
+	if err != nil {
+		// an empty inventory would overwrite the character's items as soon as one changes
+		return s.failLogin(pctx, "inventory", err)
 	}
-	if err := s.sendPacket(clientAddr, loginPacket); err != nil {
-		s.Logger().Warn("failed to send login", "clientAddr", clientAddr, "error", err)
+
+	savedEffects, err := s.DB().GetCharacterEffects(s.ctx, pctx.CharacterID)
+	if err != nil {
+		s.Logger().Warn("failed to load status effects for login", "characterID", pctx.CharacterID, "error", err)
 	}
-	if err := s.sendPacket(clientAddr, enterZonePacket); err != nil {
-		s.Logger().Warn("failed to send enter zone", "clientAddr", clientAddr, "error", err)
+
+	// scripts reading missing key items or variables would replay (and re-reward) quests
+	keyItems, err := s.loadKeyItems(s.ctx, pctx.CharacterID)
+	if err != nil {
+		return s.failLogin(pctx, "key items", err)
 	}
-}
 
-func CreateFakeGrapIDTbl() [9]uint16 {
-	return [9]uint16{0x0100, 0, 8, 8, 8, 8, 0, 0, 0}
+	vars, err := s.loadCharacterVars(s.ctx, pctx.CharacterID)
+	if err != nil {
+		return s.failLogin(pctx, "character variables", err)
+	}
+
+	// the models shown are whatever is actually equipped
+	if looks != nil {
+		applyEquipmentLooks(looks, inv, s.gameData.Current().Equipment)
+	}
+
+	clientAddr := pctx.ClientAddr
+	player := &zone.Player{
+		CharacterID: pctx.CharacterID,
+		ClientAddr:  clientAddr,
+		Name:        character.Name,
+		Character:   &character,
+		Looks:       looks,
+		Stats:       stats,
+		Inventory:   inv,
+		KeyItems:    keyItems,
+		Vars:        vars,
+		GMLevel:     gmLevel,
+		InMogHouse:  character.InMogHouse,
+		Position: zone.Position{
+			X:        character.PosX,
+			Y:        character.PosY,
+			Z:        character.PosZ,
+			Rotation: character.PosRot,
+		},
+	}
+
+	// the character's zone is known first, so the effects are restored on its clock
+	zoneID := loginZoneID(&character)
+	z := s.placeCharacter(pctx.CharacterID, zoneID)
+
+	// the stats (which the status effects modify) are needed by the login packets already
+	s.restoreEffects(player, savedEffects, z.Now())
+	s.refreshStats(player)
+
+	// send a character update packet first so the client has entity context
+	charUpdatePacket := CreateCharacterUpdatePacket(&character, looks, playerHPP(player))
+	equipClearPacket := serverPackets.EquipClearPacket{}
+	itemMaxPacket := CreateItemMaxPacket(inv)
+	itemPackets := createItemPackets(inv)
+	equipListPackets := CreateEquipListPackets(inv)
+	graphListPacket := CreateGrapListPacket(looks)
+	loginPacket := CreateLoginPacketFromCharacter(&character, looks, stats, player.Derived)
+	jobInfoPacket := CreateJobInfoPacket(character.Jobs, looks, stats, player.Derived)
+
+	// the character's zone takes over from here; the login sequence is sent on its first tick
+	if character.ID != 0 {
+		s.recordZoneVisit(&character, zoneID)
+	}
+
+	// zones without metadata keep the default music
+	if zoneData, ok := s.gameData.Current().Zones[zoneID]; ok {
+		loginPacket.MusicNum = zoneData.Music()
+	}
+
+	enterZonePacket := CreateEnterZonePacket(character.ZonesVisited)
+
+	return z.Post(func(z *zone.Zone) {
+		player.MovedAt = z.Now()
+		z.AddPlayer(player)
+		s.subscribePlayer(player)
+
+		// the target index is only known once the zone has assigned it
+		charUpdatePacket.ActIndex = player.ActIndex
+		loginPacket.PosHead.ActIndex = player.ActIndex
+
+		sendPackets(z, clientAddr, charUpdatePacket, &equipClearPacket, itemMaxPacket, loginPacket, enterZonePacket, jobInfoPacket)
+		sendPackets(z, clientAddr, CreateCliStatusPacket(player), CreateGroupAttrPacket(player), CreateServerStatusPacket(player), CreateStatusIconsPacket(player, z.Now()))
+
+		// the items have to be known before the client is told which of them are equipped
+		sendPackets(z, clientAddr, itemPackets...)
+		for _, equipPacket := range equipListPackets {
+			sendPackets(z, clientAddr, equipPacket)
+		}
+		sendPackets(z, clientAddr, graphListPacket)
+		sendPackets(z, clientAddr, createKeyItemPackets(player.KeyItems)...)
+
+		s.callScript(z, zoneScriptModule, "onZoneIn", scripting.Player{CharacterID: player.CharacterID})
+	})
 }
 
-func CreateEquipListPackets(character *database.Character) []*serverPackets.EquipListPacket {
-	stubs := stubbedEquipmentForCharacter(character)
-	if len(stubs) == 0 {
-		stubs = defaultEquipmentStubs()
+// failLogin ends the session of a client whose character could not be loaded, rather
+// than letting the character in without part of its state: what is missing would be
+// overwritten the first time it changes.
+func (s *InstanceWorker) failLogin(pctx *PacketContext, what string, err error) error {
+	if sendErr := s.sendPacket(pctx.ClientAddr, disconnectPacket()); sendErr != nil {
+		s.Logger().Error("failed to send login failure packet", "characterID", pctx.CharacterID, "error", sendErr)
 	}
 
-	packets := make([]*serverPackets.EquipListPacket, 0, len(stubs))
-	for _, entry := range stubs {
-		packets = append(packets, &serverPackets.EquipListPacket{
-			PropertyItemIndex: entry.ItemIndex,
-			EquipKind:         entry.Slot,
-			Category:          entry.ContainerID,
-		})
+	return fmt.Errorf("failed to load %s of character %d: %w", what, pctx.CharacterID, err)
+}
+
+// recordZoneVisit marks the zone as visited by the character, persisting it on the first visit.
+func (s *InstanceWorker) recordZoneVisit(character *database.Character, zoneID uint16) {
+	visited, changed := markZoneVisited(character.ZonesVisited, zoneID)
+	character.ZonesVisited = visited
+	if !changed {
+		return
 	}
 
-	return packets
+	characterID := character.ID
+	zonesVisited := append([]byte(nil), visited...)
+	s.queueSave("zones visited", characterID, func(ctx context.Context) error {
+		return s.DB().UpdateCharacterZonesVisited(ctx, characterID, zonesVisited)
+	})
 }
 
-func CreateGrapListPacket(looks *database.CharacterLooks, character *database.Character) *serverPackets.GrapListPacket {
-	packet := &serverPackets.GrapListPacket{GrapIDTbl: buildCharacterGrapIDs(looks)}
-	if len(packet.GrapIDTbl) == 0 {
-		packet.GrapIDTbl = CreateFakeGrapIDTbl()
+// loginZoneID returns the zone a character logs into.
+func loginZoneID(character *database.Character) uint16 {
+	if character != nil && character.ID != 0 {
+		return character.PosZone
 	}
-	return packet
+
+	return defaultLoginZone
 }
 
-func CreateEnterZonePacket() *serverPackets.EnterZonePacket {
+// CreateEnterZonePacket builds the packet holding the bitmap of the zones a character has visited.
+func CreateEnterZonePacket(zonesVisited []byte) *serverPackets.EnterZonePacket {
 	packet := &serverPackets.EnterZonePacket{}
-	copy(packet.EnterZoneTbl[:], stubbedEnterZoneHistory())
+	copy(packet.EnterZoneTbl[:], zonesVisited)
 	return packet
 }
 
-func CreateLoginPacketFromCharacter(character *database.Character, looks *database.CharacterLooks, stats *database.CharacterStats) *serverPackets.LoginPacket {
+func CreateLoginPacketFromCharacter(character *database.Character, looks *database.CharacterLooks, stats *database.CharacterStats, derived charstats.Stats) *serverPackets.LoginPacket {
 	stub := stubbedLoginData(character)
 
 	name := "Adventurer"
-	zone := uint32(231)
+	zone := uint32(defaultLoginZone)
 	posX, posY, posZ := float32(0), float32(0), float32(0)
+	direction := uint8(0)
 	uniqueID := uint32(1)
 
+	// characters in their mog house log into it, where the mog menu is open
+	loginState := serverPackets.LoginPacketStateGame
+	mogZoneFlag := uint8(0)
+
 	if character != nil && character.ID != 0 {
 		uniqueID = character.ID
 		zone = uint32(character.PosZone)
 		posX = character.PosX
 		posY = character.PosY
 		posZ = character.PosZ
+		direction = character.PosRot
+		if character.InMogHouse {
+			loginState = serverPackets.LoginPacketStateMyRoom
+			mogZoneFlag = 1
+		}
 		if character.Name != "" {
 			name = character.Name
 		}
@@ -135,24 +252,25 @@ func CreateLoginPacketFromCharacter(character *database.Character, looks *databa
 	flags2 := ((gender * 128) + sizeBit) << 8
 
 	hpp := uint8(100)
-	hpMax := int32(100)
-	mpMax := int32(50)
 	mainJob := uint8(1)
 	subJob := uint8(0)
 	if stats != nil {
-		if stats.HP == 0 {
-			hpp = 0
-		} else if stats.HP < 100 {
-			hpp = uint8(stats.HP)
-		}
-		hpMax = int32(stats.HP)
-		mpMax = int32(stats.MP)
+		hpp = percentOf(int32(stats.HP), derived.MaxHP)
 		if stats.MainJob != 0 {
 			mainJob = stats.MainJob
 		}
 		subJob = stats.SubJob
 	}
 
+	var jobFlag uint32
+	var jobLevel [16]uint8
+	var subJobFlag uint8
+	if character != nil && character.Jobs != nil {
+		jobFlag = jobFlags(character.Jobs)
+		jobLevel, _ = jobLevels(character.Jobs)
+		subJobFlag = boolToUint8(character.Jobs.SubJobUnlocked)
+	}
+
 	now := uint32(time.Now().Unix())
 
 	packet := &serverPackets.LoginPacket{
@@ -160,7 +278,7 @@ func CreateLoginPacketFromCharacter(character *database.Character, looks *databa
 			UniqueNo:     uniqueID,
 			ActIndex:     0x0400,
 			Padding06:    0,
-			Direction:    0,
+			Direction:    int8(direction), //nolint:gosec // the packet stores the rotation byte as a signed value
 			PosX:         posX,
 			PosZ:         posY,
 			PosY:         posZ,
@@ -194,7 +312,7 @@ func CreateLoginPacketFromCharacter(character *database.Character, looks *databa
 		ShipStart:         0,
 		ShipEnd:           0,
 		IsMonstrosity:     0,
-		LoginState:        serverPackets.LoginPacketStateGame,
+		LoginState:        loginState,
 		Name:              nameToBytes(name),
 		Certificate: [2]int32{
 			int32(uniqueID * 12345),
@@ -209,7 +327,7 @@ func CreateLoginPacketFromCharacter(character *database.Character, looks *databa
 		MyRoomMapNumber:    stub.MyRoomMapID,
 		SendCount:          0,
 		MyRoomExitBit:      stub.MyRoomExitBit,
-		MogZoneFlag:        stub.MogZoneFlag,
+		MogZoneFlag:        mogZoneFlag,
 		Dancer: serverPackets.LoginPacketMyRoomDancer{
 			RaceID:        raceID,
 			FaceID:        faceID,
@@ -217,13 +335,13 @@ func CreateLoginPacketFromCharacter(character *database.Character, looks *databa
 			HairID:        0,
 			CharacterSize: sizeIdx,
 			SubJobID:      subJob,
-			GetJobFlag:    stub.UnlockedJobs,
-			JobLevel:      stub.JobLevels,
-			BPBase:        [7]uint16{50, 50, 50, 50, 50, 50, 50},
-			BPAdj:         [7]int16{0, 0, 0, 0, 0, 0, 0},
-			HPMax:         hpMax,
-			MPMax:         mpMax,
-			SubJobFlag:    boolToUint8(stub.SubJobUnlocked),
+			GetJobFlag:    jobFlag,
+			JobLevel:      jobLevel,
+			BPBase:        derived.Base,
+			BPAdj:         derived.Bonus,
+			HPMax:         derived.MaxHP,
+			MPMax:         derived.MaxMP,
+			SubJobFlag:    subJobFlag,
 			Unknown41:     [3]uint8{0, 0, 0},
 		},
 		ConfData: serverPackets.LoginPacketSaveConf{
@@ -235,9 +353,10 @@ func CreateLoginPacketFromCharacter(character *database.Character, looks *databa
 	return packet
 }
 
-func CreateCharacterUpdatePacket(character *database.Character, looks *database.CharacterLooks, stats *database.CharacterStats) *serverPackets.CharUpdatePacket {
+func CreateCharacterUpdatePacket(character *database.Character, looks *database.CharacterLooks, hpp uint8) *serverPackets.CharUpdatePacket {
 	name := "Adventurer"
 	posX, posY, posZ := float32(0), float32(0), float32(0)
+	direction := uint8(0)
 	uniqueID := uint32(1)
 
 	if character != nil && character.ID != 0 {
@@ -246,6 +365,7 @@ func CreateCharacterUpdatePacket(character *database.Character, looks *database.
 		posX = character.PosX
 		posY = character.PosY
 		posZ = character.PosZ
+		direction = character.PosRot
 	}
 
 	grapIDs := buildCharacterGrapIDs(looks)
@@ -271,8 +391,6 @@ func CreateCharacterUpdatePacket(character *database.Character, looks *database.
 	flags5 := uint8(0x10)
 	flags6 := uint32(0)
 
-	hpp := uint8(100)
-
 	nameBytes := nameToBytes(name)
 	nameLen := len(name)
 	if nameLen > len(nameBytes) {
@@ -283,7 +401,7 @@ func CreateCharacterUpdatePacket(character *database.Character, looks *database.
 		UniqueID:           uniqueID,
 		ActIndex:           0x0400,
 		SendFlags:          serverPackets.CharUpdateFlagPosition | serverPackets.CharUpdateFlagClaimStatus | serverPackets.CharUpdateFlagGeneral | serverPackets.CharUpdateFlagName | serverPackets.CharUpdateFlagModel,
-		Direction:          0,
+		Direction:          int8(direction), //nolint:gosec // the packet stores the rotation byte as a signed value
 		PosX:               posX,
 		PosZ:               posY,
 		PosY:               posZ,
@@ -322,30 +440,18 @@ func nameToBytes(name string) [16]byte {
 }
 
 func getVanadielTime() uint32 {
-	const vanadielEpoch = 1009810800
 	earthTime := time.Now().Unix()
 	earthElapsed := earthTime - vanadielEpoch
 	vanadielElapsed := earthElapsed * 25
 	return uint32(vanadielElapsed % math.MaxUint32)
 }
 
-func CreateItemMaxPacket(character *database.Character) *serverPackets.ItemMaxPacket {
-	stub := stubbedItemMax(character)
-	packet := &serverPackets.ItemMaxPacket{
-		ItemNum:   stub.ItemNum,
-		ItemNum2:  stub.ItemNum2,
-		Padding16: [14]uint8{},
-		Padding48: [28]uint8{},
-	}
-	return packet
-}
-
 func buildCharacterGrapIDs(looks *database.CharacterLooks) [9]uint16 {
-	grapIDs := CreateFakeGrapIDTbl()
 	if looks == nil {
-		return grapIDs
+		looks = defaultLooks()
 	}
 
+	var grapIDs [9]uint16
 	grapIDs[0] = uint16(looks.Race)<<8 | uint16(looks.Face)
 	grapIDs[1] = looks.Head + 0x1000
 	grapIDs[2] = looks.Body + 0x2000
@@ -358,38 +464,14 @@ func buildCharacterGrapIDs(looks *database.CharacterLooks) [9]uint16 {
 	return grapIDs
 }
 
-type itemMaxStub struct {
-	ItemNum  [18]uint8
-	ItemNum2 [18]uint16
-}
-
-func stubbedItemMax(character *database.Character) itemMaxStub {
-	return itemMaxStub{
-		ItemNum: [18]uint8{
-			80, 80, 80, 30, 80, 80, 80, 80, 80,
-			80, 80, 80, 80, 80, 80, 80, 80, 80,
-		},
-		ItemNum2: [18]uint16{
-			80, 80, 80, 30, 80, 80, 80, 80, 80,
-			80, 80, 80, 80, 80, 80, 80, 80, 80,
-		},
-	}
-}
-
-func stubbedEnterZoneHistory() []byte {
-	return []byte{
-		0x10, 0x00, 0x00, 0x00,
-		0x01, 0x00, 0x00, 0x00,
-		0x40, 0x00, 0x00, 0x00,
-		0x08, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00,
+// defaultLooks is used when a character's looks could not be loaded: a Hume with nothing equipped.
+func defaultLooks() *database.CharacterLooks {
+	return &database.CharacterLooks{
+		Race:  1,
+		Body:  nakedBodyModel,
+		Hands: nakedBodyModel,
+		Legs:  nakedBodyModel,
+		Feet:  nakedBodyModel,
 	}
 }
 
@@ -0,0 +1,95 @@
+package instance
+
+import (
+	"fmt"
+	"time"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// movementSlack is the distance, in yalms, a position update may exceed the speed limit
+// by, to absorb client/server timing jitter
+const movementSlack = 1.0
+
+func (s *InstanceWorker) handlePositionPacket(pctx *PacketContext, packet *clientPackets.PositionPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	// positions in the mog house are in a room of its own, not in the zone; the player
+	// stays at the entrance
+	if player.InMogHouse {
+		return nil
+	}
+
+	next := zone.Position{
+		X:        packet.PosX,
+		Y:        packet.PosZ,
+		Z:        packet.PosY,
+		Rotation: packet.Direction,
+	}
+
+	now := pctx.Zone.Now()
+	if !movementAllowed(player.Position, next, now.Sub(player.MovedAt), pctx.Zone.TickInterval(), s.Config().MapMaxMovementSpeed) {
+		s.Logger().Warn("rejecting position update", "characterID", player.CharacterID, "from", player.Position, "to", next, "elapsed", now.Sub(player.MovedAt))
+		correctPosition(pctx.Zone, player)
+		return fmt.Errorf("%w: character %d moved too fast", ErrPacketRejected, player.CharacterID)
+	}
+
+	// the defeated cannot move until they return to their home point or are raised
+	moved := player.Position.HorizontalDistance(next) > 0
+	if moved && playerDefeated(player) {
+		correctPosition(pctx.Zone, player)
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, ErrDefeated)
+	}
+
+	// players are held in place during events, though they may turn to face the NPC
+	if moved && player.Event != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, ErrInEvent)
+	}
+
+	// moving cancels a pending logout
+	if moved {
+		s.cancelLogout(pctx.Zone, player, "moved")
+	}
+
+	player.MoveTo(next, now)
+	player.FaceTarget = packet.FaceTarget
+
+	return nil
+}
+
+// correctPosition moves the client back to where the server has the player, after
+// rejecting a position update.
+func correctPosition(z *zone.Zone, player *zone.Player) {
+	sendPackets(z, player.ClientAddr, &serverPackets.WPosPacket{
+		PosX:      player.Position.X,
+		PosZ:      player.Position.Y,
+		PosY:      player.Position.Z,
+		UniqueNo:  player.CharacterID,
+		ActIndex:  player.ActIndex,
+		Mode:      serverPackets.WPosModeNormal,
+		Direction: int8(player.Position.Rotation), //nolint:gosec // the packet stores the rotation byte as a signed value
+	})
+}
+
+// movementAllowed reports whether moving between two positions in the elapsed time
+// stays under the maximum speed. Updates are never considered closer together than
+// one tick, since they are only applied once per tick.
+func movementAllowed(from, to zone.Position, elapsed, minElapsed time.Duration, maxSpeed float64) bool {
+	if !to.IsFinite() {
+		return false
+	}
+
+	if maxSpeed <= 0 {
+		return true
+	}
+
+	elapsed = max(elapsed, minElapsed)
+	allowed := maxSpeed*elapsed.Seconds() + movementSlack
+
+	return from.HorizontalDistance(to) <= allowed
+}
@@ -0,0 +1,94 @@
+package instance
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+func TestMovementAllowed(t *testing.T) {
+	origin := zone.Position{}
+	tick := 400 * time.Millisecond
+
+	cases := []struct {
+		name    string
+		to      zone.Position
+		elapsed time.Duration
+		want    bool
+	}{
+		{"standing still", origin, 0, true},
+		{"running", zone.Position{X: 5}, time.Second, true},
+		{"vertical movement is not limited", zone.Position{Y: 50}, time.Second, true},
+		{"too fast", zone.Position{X: 30}, time.Second, false},
+		{"updates closer than a tick use the tick", zone.Position{X: 5}, 0, true},
+		{"not a number", zone.Position{X: float32(math.NaN())}, time.Second, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := movementAllowed(origin, tc.to, tc.elapsed, tick, 12); got != tc.want {
+				t.Fatalf("movementAllowed() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRejectedPositionIsCorrected(t *testing.T) {
+	s := newTestWorker()
+	s.cfg.MapMaxMovementSpeed = 12
+
+	var sent []serverPackets.ServerPacket
+	z := zone.New(230, zone.Options{
+		TickInterval: time.Second,
+		Clock:        zone.NewVirtualClock(time.Unix(0, 0)),
+		Sender: func(_ string, packet serverPackets.ServerPacket) error {
+			sent = append(sent, packet)
+			return nil
+		},
+	})
+
+	player := &zone.Player{CharacterID: 1, ClientAddr: "127.0.0.1:1000", ActIndex: 0x0400, Position: zone.Position{X: 1, Y: 2, Z: 3, Rotation: 64}, MovedAt: time.Unix(0, 0)}
+	z.AddPlayer(player)
+
+	pctx := &PacketContext{CharacterID: player.CharacterID, Zone: z}
+	if err := s.handlePositionPacket(pctx, &clientPackets.PositionPacket{PosX: 500}); !errors.Is(err, ErrPacketRejected) {
+		t.Fatalf("handlePositionPacket() error = %v, want %v", err, ErrPacketRejected)
+	}
+
+	z.Step()
+
+	var correction *serverPackets.WPosPacket
+	for _, packet := range sent {
+		if p, ok := packet.(*serverPackets.WPosPacket); ok {
+			correction = p
+		}
+	}
+
+	want := &serverPackets.WPosPacket{PosX: 1, PosZ: 2, PosY: 3, UniqueNo: 1, ActIndex: 0x0400, Mode: serverPackets.WPosModeNormal, Direction: 64}
+	if correction == nil || *correction != *want {
+		t.Fatalf("correction = %+v, want %+v", correction, want)
+	}
+}
+
+func TestDefeatedPlayerCannotMove(t *testing.T) {
+	s := newTestWorker()
+	z, player := newTestPlayerZone(t)
+	player.Stats = &database.CharacterStats{CharacterID: player.CharacterID, HP: 0}
+	player.MovedAt = z.Now()
+
+	pctx := &PacketContext{CharacterID: player.CharacterID, Zone: z}
+	if err := s.handlePositionPacket(pctx, &clientPackets.PositionPacket{PosX: 2}); !errors.Is(err, ErrDefeated) || player.Position.X != 0 {
+		t.Fatalf("moving while defeated = %v, X = %v, want ErrDefeated", err, player.Position.X)
+	}
+
+	player.Stats.HP = 10
+	if err := s.handlePositionPacket(pctx, &clientPackets.PositionPacket{PosX: 2}); err != nil || player.Position.X != 2 {
+		t.Fatalf("moving once alive = %v, X = %v", err, player.Position.X)
+	}
+}
@@ -0,0 +1,122 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/combat"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// playerEngageRange is how far from a mob players can engage it
+const playerEngageRange = 30.0
+
+var (
+	ErrUnsupportedAction = errors.New("unsupported action")
+	ErrInvalidTarget     = errors.New("invalid target")
+	ErrDefeated          = errors.New("character is defeated")
+	ErrNotDefeated       = errors.New("character is not defeated")
+)
+
+func (s *InstanceWorker) handleActionPacket(pctx *PacketContext, packet *clientPackets.ActionPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	// players act again once their event is over
+	if player.Event != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, ErrInEvent)
+	}
+
+	var err error
+	switch packet.ActionID {
+	case clientPackets.ActionTalk:
+		err = s.talk(pctx.Zone, player, packet.UniqueNo, packet.ActIndex)
+	case clientPackets.ActionEngage:
+		err = s.engage(pctx.Zone, player, packet.UniqueNo, packet.ActIndex)
+	case clientPackets.ActionCastMagic:
+		err = s.startCasting(pctx.Zone, player, uint16(packet.ActionBuf[0]), packet.UniqueNo, packet.ActIndex) //nolint:gosec // spell IDs are 16-bit
+	case clientPackets.ActionJobAbility:
+		err = s.useAbility(pctx.Zone, player, uint16(packet.ActionBuf[0]), packet.UniqueNo, packet.ActIndex) //nolint:gosec // ability IDs are 16-bit
+	case clientPackets.ActionDisengage:
+		s.disengage(pctx.Zone, player)
+	case clientPackets.ActionHomePoint:
+		err = s.returnToHomePoint(pctx.Zone, player)
+	default:
+		err = fmt.Errorf("%w: 0x%02X", ErrUnsupportedAction, packet.ActionID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	return nil
+}
+
+// engage makes a player fight a mob. Mobs claimed by someone else, or too far away,
+// are refused with a message rather than an error: the client cannot know either.
+func (s *InstanceWorker) engage(z *zone.Zone, player *zone.Player, targetID uint32, targetIndex uint16) error {
+	if playerDefeated(player) {
+		return ErrDefeated
+	}
+
+	mob := z.MobByIndex(targetIndex)
+	if mob == nil || mob.ID != targetID || !mob.Spawned || mob.HP <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidTarget, targetID)
+	}
+
+	if mobClaimedByOther(mob, player) {
+		sendPackets(z, player.ClientAddr, createCombatMessagePacket(player, mob, serverPackets.MessageAlreadyClaimed))
+		return nil
+	}
+
+	if player.Position.HorizontalDistance(mob.Position) > playerEngageRange {
+		sendPackets(z, player.ClientAddr, createCombatMessagePacket(player, mob, serverPackets.MessageTooFarAway))
+		return nil
+	}
+
+	s.cancelLogout(z, player, "entered combat")
+
+	wasEngaged := player.BattleTarget != 0
+	player.BattleTarget = mob.ID
+	player.NextAttackAt = z.Now().Add(combat.AttackInterval(s.playerFighter(player).Delay))
+	s.engageMob(z, mob, player)
+
+	// switching targets keeps the player in battle stance
+	if !wasEngaged {
+		player.MarkChanged()
+		sendPackets(z, player.ClientAddr, CreateServerStatusPacket(player))
+	}
+
+	return nil
+}
+
+// disengage ends the fight of a player.
+func (s *InstanceWorker) disengage(z *zone.Zone, player *zone.Player) {
+	if player.BattleTarget == 0 {
+		return
+	}
+
+	player.BattleTarget = 0
+	player.MarkChanged()
+	sendPackets(z, player.ClientAddr, CreateServerStatusPacket(player))
+}
+
+// mobClaimedByOther reports whether a mob is claimed by someone other than the player.
+func mobClaimedByOther(mob *zone.Mob, player *zone.Player) bool {
+	return mob.ClaimedBy != 0 && mob.ClaimedBy != player.CharacterID
+}
+
+// createCombatMessagePacket builds a basic message from one entity about another.
+func createCombatMessagePacket(from, to zone.Entity, message uint16) *serverPackets.BattleMessagePacket {
+	return &serverPackets.BattleMessagePacket{
+		UniqueNoCas: from.EntityID(),
+		UniqueNoTar: to.EntityID(),
+		ActIndexCas: from.EntityIndex(),
+		ActIndexTar: to.EntityIndex(),
+		MessageNum:  message,
+	}
+}
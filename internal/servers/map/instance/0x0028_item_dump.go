@@ -0,0 +1,32 @@
+package instance
+
+import (
+	"fmt"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+)
+
+func (s *InstanceWorker) handleItemDumpPacket(pctx *PacketContext, packet *clientPackets.ItemDumpPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil || player.Inventory == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	from := inventory.Location{Container: serverPackets.ContainerKind(packet.Category), Slot: packet.ItemIndex}
+	if err := checkContainers(player, from.Container); err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	item, _ := player.Inventory.Get(from)
+
+	changed, err := player.Inventory.Drop(from, packet.ItemNum)
+	if err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	s.Logger().Info("character dropped item", "characterID", player.CharacterID, "itemID", item.ID, "quantity", packet.ItemNum)
+	s.syncItems(pctx.Zone, player, changed)
+	return nil
+}
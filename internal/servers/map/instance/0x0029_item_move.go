@@ -0,0 +1,31 @@
+package instance
+
+import (
+	"fmt"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+)
+
+func (s *InstanceWorker) handleItemMovePacket(pctx *PacketContext, packet *clientPackets.ItemMovePacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil || player.Inventory == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	from := inventory.Location{Container: serverPackets.ContainerKind(packet.Category1), Slot: packet.ItemIndex1}
+	to := inventory.Location{Container: serverPackets.ContainerKind(packet.Category2), Slot: packet.ItemIndex2}
+
+	if err := checkContainers(player, from.Container, to.Container); err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	changed, err := player.Inventory.Move(from, to, packet.ItemNum, s.stackSize)
+	if err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	s.syncItems(pctx.Zone, player, changed)
+	return nil
+}
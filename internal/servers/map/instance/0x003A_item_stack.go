@@ -0,0 +1,27 @@
+package instance
+
+import (
+	"fmt"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+)
+
+func (s *InstanceWorker) handleItemStackPacket(pctx *PacketContext, packet *clientPackets.ItemStackPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil || player.Inventory == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	container := serverPackets.ContainerKind(packet.Category)
+	if err := checkContainers(player, container); err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	if player.Inventory.Size(container) == 0 {
+		return fmt.Errorf("%w: character %d: cannot sort container %d", ErrPacketRejected, player.CharacterID, container)
+	}
+
+	s.syncItems(pctx.Zone, player, player.Inventory.Sort(container, s.stackSize))
+	return nil
+}
@@ -0,0 +1,34 @@
+package instance
+
+import (
+	"fmt"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+)
+
+func (s *InstanceWorker) handleEquipSetPacket(pctx *PacketContext, packet *clientPackets.EquipSetPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil || player.Inventory == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	slot := serverPackets.EquipKind(packet.EquipKind)
+	if slot >= serverPackets.EquipKindEnd {
+		return fmt.Errorf("%w: character %d: %w: %d", ErrPacketRejected, player.CharacterID, inventory.ErrInvalidEquipSlot, slot)
+	}
+
+	// slot 0 never holds an item (it is the gil slot), so the client uses it to unequip
+	if packet.PropertyItemIndex == 0 {
+		s.unequipItem(pctx.Zone, player, slot)
+		return nil
+	}
+
+	location := inventory.Location{Container: serverPackets.ContainerKind(packet.Category), Slot: packet.PropertyItemIndex}
+	if err := s.equipItem(pctx.Zone, player, slot, location); err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+)
+
+var ErrUnsupportedEventMode = errors.New("unsupported event end mode")
+
+func (s *InstanceWorker) handleEventEndPacket(pctx *PacketContext, packet *clientPackets.EventEndPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	var err error
+	switch {
+	case packet.Mode != clientPackets.EventEndModeFinish && packet.Mode != clientPackets.EventEndModeUpdate:
+		err = fmt.Errorf("%w: %d", ErrUnsupportedEventMode, packet.Mode)
+	case packet.EventNum != pctx.Zone.ID():
+		// events are started from the event table of the zone the player is in
+		err = fmt.Errorf("%w: event %d of zone %d", ErrEventMismatch, packet.EventPara, packet.EventNum)
+	default:
+		finish := packet.Mode == clientPackets.EventEndModeFinish
+		err = s.endEvent(pctx.Zone, player, packet.EventPara, packet.UniqueNo, packet.ActIndex, packet.EndPara, finish)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	return nil
+}
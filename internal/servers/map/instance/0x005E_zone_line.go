@@ -0,0 +1,72 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// zoneLineMaxDistance is how far, in yalms, the position a client reports when entering
+// a zone line may be from the position the server knows
+const zoneLineMaxDistance = 10.0
+
+var (
+	ErrUnknownZoneLine = errors.New("unknown zone line")
+	ErrWrongZoneLine   = errors.New("zone line is in another zone")
+	ErrZoneLineTooFar  = errors.New("too far from zone line")
+)
+
+func (s *InstanceWorker) handleZoneLinePacket(pctx *PacketContext, packet *clientPackets.ZoneLinePacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	// the mog house has no zone lines of its own; any exit leads back into the zone
+	if player.InMogHouse {
+		s.setMogHouse(pctx.Zone, player, false)
+		return nil
+	}
+
+	reported := zone.Position{X: packet.PosX, Y: packet.PosZ, Z: packet.PosY}
+	zoneLine, err := checkZoneLine(s.gameData.Current().ZoneLines, pctx.Zone.ID(), packet.RectID, player.Position, reported)
+	if err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	if zoneLine.ToZone == gamedata.MogHouseZoneID {
+		s.setMogHouse(pctx.Zone, player, true)
+		return nil
+	}
+
+	s.changeZone(pctx.Zone, player, zoneLine.ToZone, zone.Position{
+		X:        zoneLine.ToX,
+		Y:        zoneLine.ToY,
+		Z:        zoneLine.ToZ,
+		Rotation: zoneLine.ToRotation,
+	})
+
+	return nil
+}
+
+// checkZoneLine returns the zone line a client asked to take, after checking it leaves
+// the client's zone and the client is (roughly) where the server thinks it is.
+func checkZoneLine(zoneLines map[uint32]gamedata.ZoneLine, zoneID uint16, rectID uint32, current, reported zone.Position) (gamedata.ZoneLine, error) {
+	zoneLine, ok := zoneLines[rectID]
+	if !ok {
+		return gamedata.ZoneLine{}, fmt.Errorf("%w: %d", ErrUnknownZoneLine, rectID)
+	}
+
+	if zoneLine.FromZone != zoneID {
+		return gamedata.ZoneLine{}, fmt.Errorf("%w: %d leaves zone %d, not %d", ErrWrongZoneLine, rectID, zoneLine.FromZone, zoneID)
+	}
+
+	if !reported.IsFinite() || current.HorizontalDistance(reported) > zoneLineMaxDistance {
+		return gamedata.ZoneLine{}, fmt.Errorf("%w: %d", ErrZoneLineTooFar, rectID)
+	}
+
+	return zoneLine, nil
+}
@@ -0,0 +1,93 @@
+package instance
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+func TestCheckZoneLine(t *testing.T) {
+	zoneLines := map[uint32]gamedata.ZoneLine{
+		100: {ID: 100, FromZone: 230, ToZone: 231, ToX: 1, ToY: 2, ToZ: 3},
+	}
+	current := zone.Position{X: 10, Y: 0, Z: 10}
+
+	tests := []struct {
+		name     string
+		zoneID   uint16
+		rectID   uint32
+		reported zone.Position
+		wantErr  error
+	}{
+		{name: "valid", zoneID: 230, rectID: 100, reported: zone.Position{X: 12, Y: 5, Z: 14}},
+		{name: "unknown", zoneID: 230, rectID: 101, reported: current, wantErr: ErrUnknownZoneLine},
+		{name: "other zone", zoneID: 231, rectID: 100, reported: current, wantErr: ErrWrongZoneLine},
+		{name: "too far", zoneID: 230, rectID: 100, reported: zone.Position{X: 30, Z: 10}, wantErr: ErrZoneLineTooFar},
+		{name: "not finite", zoneID: 230, rectID: 100, reported: zone.Position{X: float32(math.NaN())}, wantErr: ErrZoneLineTooFar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zoneLine, err := checkZoneLine(zoneLines, tt.zoneID, tt.rectID, current, tt.reported)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkZoneLine() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && zoneLine.ToZone != 231 {
+				t.Fatalf("checkZoneLine() = %+v, want zone line 100", zoneLine)
+			}
+		})
+	}
+}
+
+func TestMarkZoneVisited(t *testing.T) {
+	visited, changed := markZoneVisited(nil, 10)
+	if !changed || len(visited) != 2 || visited[1] != 0x04 {
+		t.Fatalf("markZoneVisited(nil, 10) = %v, %v, want [0 4], true", visited, changed)
+	}
+
+	visited, changed = markZoneVisited(visited, 10)
+	if changed {
+		t.Fatalf("markZoneVisited() on a visited zone = %v, %v, want unchanged", visited, changed)
+	}
+
+	if _, changed = markZoneVisited(visited, 48*8); changed {
+		t.Fatalf("markZoneVisited() outside of the bitmap changed it")
+	}
+}
+
+func TestMogHouseZoneLine(t *testing.T) {
+	s := newTestWorker()
+	s.gameData = gamedata.NewStoreFromData(&gamedata.Data{ZoneLines: map[uint32]gamedata.ZoneLine{
+		100: {ID: 100, FromZone: 230, ToZone: gamedata.MogHouseZoneID},
+	}})
+
+	z, player := newTestPlayerZone(t)
+	pctx := &PacketContext{CharacterID: player.CharacterID, Zone: z}
+
+	// the mog house entrance zones the client back into the same zone
+	if err := s.handleZoneLinePacket(pctx, &clientPackets.ZoneLinePacket{RectID: 100}); err != nil {
+		t.Fatalf("entering the mog house error = %v", err)
+	}
+
+	if z.Player(player.CharacterID) != nil || len(s.saves) != 1 {
+		t.Fatalf("player in zone = %v, queued saves = %d, want the player gone and the mog house saved", z.Player(player.CharacterID) != nil, len(s.saves))
+	}
+
+	// any zone line taken in the mog house leads out of it, even one the zone does not have
+	z, player = newTestPlayerZone(t)
+	player.InMogHouse = true
+	pctx = &PacketContext{CharacterID: player.CharacterID, Zone: z}
+
+	if err := s.handleZoneLinePacket(pctx, &clientPackets.ZoneLinePacket{RectID: 101}); err != nil {
+		t.Fatalf("leaving the mog house error = %v", err)
+	}
+
+	if z.Player(player.CharacterID) != nil {
+		t.Fatalf("player still in zone after leaving the mog house")
+	}
+}
@@ -0,0 +1,89 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+func (s *InstanceWorker) handleLogoutPacket(pctx *PacketContext, packet *clientPackets.LogoutPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	pending := player.LogoutTimer != 0
+	switch {
+	case packet.Mode == clientPackets.LogoutModeCancel, packet.Mode == clientPackets.LogoutModeToggle && pending:
+		s.cancelLogout(pctx.Zone, player, "requested")
+	case !pending:
+		s.startLogout(pctx.Zone, player, packet.Kind)
+	}
+
+	return nil
+}
+
+// startLogout starts the logout timer of a player; the character leaves the game once it runs out.
+func (s *InstanceWorker) startLogout(z *zone.Zone, player *zone.Player, kind uint16) {
+	state := serverPackets.LogoutStateLogout
+	if kind == clientPackets.LogoutKindShutdown {
+		state = serverPackets.LogoutStateShutdown
+	}
+
+	delay := time.Duration(s.Config().MapLogoutSeconds) * time.Second
+	player.LogoutTimer = z.After(delay, func(z *zone.Zone) {
+		player.LogoutTimer = 0
+		if z.Player(player.CharacterID) == player {
+			s.leaveGame(z, player, state)
+		}
+	})
+
+	s.Logger().Info("character logging out", "characterID", player.CharacterID, "kind", kind, "delay", delay)
+}
+
+// cancelLogout stops a pending logout, e.g. because the character moved or entered combat.
+func (s *InstanceWorker) cancelLogout(z *zone.Zone, player *zone.Player, reason string) {
+	if player.LogoutTimer == 0 {
+		return
+	}
+
+	z.Cancel(player.LogoutTimer)
+	player.LogoutTimer = 0
+
+	s.Logger().Info("character logout cancelled", "characterID", player.CharacterID, "reason", reason)
+}
+
+// leaveGame persists a player, removes it from the zone and, if the client is still
+// connected, tells it to log out and has the router end its session.
+func (s *InstanceWorker) leaveGame(z *zone.Zone, player *zone.Player, state uint32) {
+	s.cancelLogout(z, player, "left the game")
+	s.savePlayer(z, player)
+
+	z.RemovePlayer(player.CharacterID)
+	s.releaseCharacter(player.CharacterID)
+
+	s.Logger().Info("character left the game", "characterID", player.CharacterID, "zoneID", z.ID(), "disconnected", player.Disconnected)
+	if player.Disconnected {
+		return
+	}
+
+	characterID := player.CharacterID
+	clientAddr := player.ClientAddr
+	packet := &leavePacket{
+		LogoutPacket: &serverPackets.LogoutPacket{
+			LogoutState: state,
+			IP:          ipToUint32(s.Config().MapServerIP),
+			Port:        s.Config().MapServerPort,
+		},
+		disconnect: true,
+	}
+
+	// the client is only let go once its final state is persisted
+	s.queueSave("logout", characterID, func(_ context.Context) error {
+		return s.sendPacket(clientAddr, packet)
+	})
+}
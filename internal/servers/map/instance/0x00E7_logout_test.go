@@ -0,0 +1,62 @@
+package instance
+
+import (
+	"testing"
+	"time"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+)
+
+func TestLogoutTimer(t *testing.T) {
+	s := newTestWorker()
+	z, player := newTestPlayerZone(t)
+
+	s.startLogout(z, player, clientPackets.LogoutKindLogout)
+	z.Advance(29 * time.Second)
+	if z.Player(player.CharacterID) == nil {
+		t.Fatalf("player left before the logout timer ran out")
+	}
+
+	z.Advance(time.Second)
+	if z.Player(player.CharacterID) != nil {
+		t.Fatalf("player still in zone after the logout timer ran out")
+	}
+
+	if len(s.saves) != 1 {
+		t.Fatalf("queued saves = %d, want %d", len(s.saves), 1)
+	}
+}
+
+func TestLogoutCancelled(t *testing.T) {
+	s := newTestWorker()
+	z, player := newTestPlayerZone(t)
+
+	s.startLogout(z, player, clientPackets.LogoutKindShutdown)
+	z.Advance(10 * time.Second)
+	s.cancelLogout(z, player, "moved")
+	z.Advance(time.Minute)
+
+	if z.Player(player.CharacterID) == nil {
+		t.Fatalf("player left after the logout was cancelled")
+	}
+
+	if player.LogoutTimer != 0 {
+		t.Fatalf("LogoutTimer = %d, want 0", player.LogoutTimer)
+	}
+}
+
+func TestDisconnectedPlayerLeavesSilently(t *testing.T) {
+	s := newTestWorker()
+	z, player := newTestPlayerZone(t)
+
+	player.Disconnected = true
+	s.leaveGame(z, player, 0)
+
+	if z.Player(player.CharacterID) != nil {
+		t.Fatalf("player still in zone after leaving the game")
+	}
+
+	if len(s.saves) != 0 {
+		t.Fatalf("queued saves = %d, want %d", len(s.saves), 0)
+	}
+}
@@ -0,0 +1,34 @@
+package instance
+
+import (
+	"fmt"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+)
+
+func (s *InstanceWorker) handleMyRoomJobPacket(pctx *PacketContext, packet *clientPackets.MyRoomJobPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	if player.Character == nil || player.Character.Jobs == nil || player.Stats == nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, ErrJobsNotLoaded)
+	}
+
+	if !player.InMogHouse {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, ErrJobOutsideMogHouse)
+	}
+
+	mainJob, subJob, err := checkJobChange(player.Character.Jobs, player.Stats.MainJob, player.Stats.SubJob, packet.MainJobIndex, packet.SupportJobIndex)
+	if err != nil {
+		return fmt.Errorf("%w: character %d: %w", ErrPacketRejected, player.CharacterID, err)
+	}
+
+	if mainJob == player.Stats.MainJob && subJob == player.Stats.SubJob {
+		return nil
+	}
+
+	s.changeJob(pctx.Zone, player, mainJob, subJob)
+	return nil
+}
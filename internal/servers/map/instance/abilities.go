@@ -0,0 +1,76 @@
+package instance
+
+import (
+	"fmt"
+	"time"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// useAbility uses a job ability of a player on a target. Abilities go off at once;
+// like spells, those that cannot be used right now are refused with a message.
+func (s *InstanceWorker) useAbility(z *zone.Zone, player *zone.Player, abilityID uint16, targetID uint32, targetIndex uint16) error {
+	if playerDefeated(player) {
+		return ErrDefeated
+	}
+
+	ability, ok := s.gameData.Current().Abilities[abilityID]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownAbility, abilityID)
+	}
+
+	learnedAt := func(job uint8) uint8 {
+		if job == ability.Job {
+			return max(ability.Level, 1)
+		}
+
+		return 0
+	}
+
+	if !playerCanUse(player, learnedAt) {
+		return fmt.Errorf("%w: ability %d", ErrActionUnavailable, abilityID)
+	}
+
+	now := z.Now()
+	target, ok := actionTarget(z, player, targetID, targetIndex, ability.ValidTarget)
+
+	var message uint16
+	switch {
+	case player.Casting != nil:
+		message = serverPackets.MessageUnableToUseJA
+	case !player.Recasts.Ready(zone.RecastAbility, ability.RecastID, now):
+		message = serverPackets.MessageWaitLonger
+	case !ok:
+		message = serverPackets.MessageCannotOnTarget
+	case target != zone.Entity(player):
+		message = actionReachMessage(player, target, float64(ability.Range), serverPackets.MessageTooFarAway)
+	}
+
+	if message == 0 && player.TP < int32(ability.TPCost) {
+		message = serverPackets.MessageNotEnoughTP
+	}
+
+	if message != 0 {
+		refuseAction(z, player, target, message, ability.ID)
+		return nil
+	}
+
+	if ability.TPCost > 0 {
+		player.TP -= int32(ability.TPCost)
+		player.MarkChanged()
+		sendPackets(z, player.ClientAddr, CreateGroupAttrPacket(player))
+	}
+
+	player.Recasts.Start(zone.RecastAbility, ability.RecastID, time.Duration(ability.RecastTime)*time.Second, now)
+
+	action := abilityAction(ability)
+	s.actionEnmity(z, player, target, &action)
+	outcome := s.resolveAction(z, player, target, &action, s.abilityHooks[ability.Name])
+
+	packet := createActionPacket(player, target, serverPackets.BattleCategoryAbilityFinish, uint32(ability.ID), uint32(ability.RecastTime), outcome.result)
+	sendNearby(z, player.Position, packet)
+	s.applyActionOutcome(z, player, target, outcome)
+
+	return nil
+}
@@ -0,0 +1,386 @@
+package instance
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/combat"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// actionSightHeight is the largest height difference spells and abilities reach
+// across. Without zone geometry, it stands in for line of sight, as it does for mobs.
+const actionSightHeight = mobDetectHeight
+
+var (
+	ErrUnknownSpell      = errors.New("unknown spell")
+	ErrUnknownAbility    = errors.New("unknown ability")
+	ErrActionUnavailable = errors.New("jobs cannot use it at their level")
+)
+
+// actionMessages are the result messages of spells, or of abilities.
+type actionMessages struct {
+	// used is shown when the action has nothing to resolve (it only has a hook, or only enmity)
+	used     uint16
+	damage   uint16
+	heal     uint16
+	effect   uint16
+	enfeeble uint16
+	noEffect uint16
+}
+
+// actionData is what resolving a spell or an ability depends on, whichever it is.
+type actionData struct {
+	animation uint16
+
+	// base and multiplier are the potency of damage (on mobs) or healing (on players)
+	base       uint16
+	multiplier float32
+
+	// effectID is the status effect the action applies, or 0
+	effectID uint16
+	power    int32
+	duration time.Duration
+
+	ce int32
+	ve int32
+
+	messages actionMessages
+}
+
+// spellAction returns what resolving a spell depends on.
+func spellAction(spell gamedata.Spell) actionData {
+	return actionData{
+		animation:  spell.Animation,
+		base:       spell.Base,
+		multiplier: spell.Multiplier,
+		effectID:   spell.EffectID,
+		power:      spell.Power,
+		duration:   time.Duration(spell.Duration) * time.Second,
+		ce:         int32(spell.CE),
+		ve:         int32(spell.VE),
+		messages: actionMessages{
+			used:     serverPackets.MessageMagicNoEffect,
+			damage:   serverPackets.MessageMagicDamage,
+			heal:     serverPackets.MessageMagicRecoversHP,
+			effect:   serverPackets.MessageMagicGainsEffect,
+			enfeeble: serverPackets.MessageMagicEnfeebles,
+			noEffect: serverPackets.MessageMagicNoEffect,
+		},
+	}
+}
+
+// abilityAction returns what resolving an ability depends on.
+func abilityAction(ability gamedata.Ability) actionData {
+	return actionData{
+		animation: ability.Animation,
+		effectID:  ability.EffectID,
+		power:     ability.Power,
+		duration:  time.Duration(ability.Duration) * time.Second,
+		ce:        int32(ability.CE),
+		ve:        int32(ability.VE),
+		messages: actionMessages{
+			used:     serverPackets.MessageUsesAbility,
+			damage:   serverPackets.MessageAbilityDamage,
+			heal:     serverPackets.MessageAbilityRecoversHP,
+			effect:   serverPackets.MessageUsesAbility,
+			enfeeble: serverPackets.MessageUsesAbility,
+			noEffect: serverPackets.MessageAbilityNoEffect,
+		},
+	}
+}
+
+// actionOutcome is what a spell or ability did to its target. The damage is dealt
+// once the action is shown, so the target is not defeated before it is hit.
+type actionOutcome struct {
+	result serverPackets.BattleResult
+	damage int32
+
+	// healed is the HP restored, which the mobs fighting the target hate the player for
+	healed int32
+}
+
+// actionHook is custom logic a spell or ability resolves with instead of its data.
+// Hooks are keyed by the name of the spell or ability in the game data.
+type actionHook func(z *zone.Zone, player *zone.Player, target zone.Entity, action *actionData) actionOutcome
+
+// actionTarget resolves the target of a spell or ability and checks it against the
+// target flags of the action. Parties are not modelled yet, so party actions can only
+// target the player using them. Defeated players are only valid targets of actions
+// for them (raise), and only them.
+func actionTarget(z *zone.Zone, player *zone.Player, targetID uint32, targetIndex uint16, flags uint16) (zone.Entity, bool) {
+	if mob := z.MobByIndex(targetIndex); mob != nil && mob.ID == targetID {
+		return mob, flags&gamedata.TargetEnemy != 0 && mob.Spawned && mob.HP > 0
+	}
+
+	target := z.Player(targetID)
+	if target == nil || target.ActIndex != targetIndex {
+		return nil, false
+	}
+
+	switch {
+	case playerDefeated(target):
+		return target, flags&gamedata.TargetPlayerDead != 0
+	case target == player:
+		return target, flags&(gamedata.TargetSelf|gamedata.TargetParty|gamedata.TargetAlliance|gamedata.TargetPlayer) != 0
+	default:
+		return target, flags&gamedata.TargetPlayer != 0
+	}
+}
+
+// actionReachMessage checks that a player can reach the target of a spell or ability
+// within reach yalms, returning the message telling why it cannot, or 0.
+func actionReachMessage(player *zone.Player, target zone.Entity, reach float64, outOfRange uint16) uint16 {
+	if mob, ok := target.(*zone.Mob); ok && mobClaimedByOther(mob, player) {
+		return serverPackets.MessageAlreadyClaimed
+	}
+
+	position := target.EntityPosition()
+	switch {
+	case player.Position.HorizontalDistance(position) > reach:
+		return outOfRange
+	case math.Abs(float64(player.Position.Y-position.Y)) > actionSightHeight:
+		return serverPackets.MessageUnableToSee
+	default:
+		return 0
+	}
+}
+
+// playerCanUse reports whether the main or support job of a player is high enough to
+// use a spell or ability, given the level each job learns it at (0 when it cannot).
+func playerCanUse(player *zone.Player, learnedAt func(job uint8) uint8) bool {
+	if player.Stats == nil {
+		return false
+	}
+
+	mainLevel, subLevel := playerLevels(player)
+	if level := learnedAt(player.Stats.MainJob); level != 0 && mainLevel >= level {
+		return true
+	}
+
+	level := learnedAt(player.Stats.SubJob)
+	return player.Stats.SubJob != 0 && level != 0 && subLevel >= level
+}
+
+// resolveAction works out what a spell or ability does to its target: its hook when
+// it has one, or else its data. Damage goes to mobs and healing to players; the status
+// effect lands on either.
+func (s *InstanceWorker) resolveAction(z *zone.Zone, player *zone.Player, target zone.Entity, action *actionData, hook actionHook) actionOutcome {
+	if hook != nil {
+		return hook(z, player, target, action)
+	}
+
+	outcome := actionOutcome{result: serverPackets.BattleResult{Animation: action.animation, Message: action.messages.used}}
+	potent := action.base > 0 || action.multiplier > 0
+
+	var effect effects.Effect
+	var hasEffect bool
+	if action.effectID != 0 {
+		effect, hasEffect = s.newEffect(action.effectID, action.power, effectTickInterval, action.duration, player.CharacterID)
+	}
+
+	// the status effect of damaging and healing actions is a side effect their result does not show
+	switch target := target.(type) {
+	case *zone.Mob:
+		if potent {
+			dINT := player.Derived.Attribute(charstats.INT) - mobAttribute(target, target.Effects.Modifiers(), charstats.ModINT)
+			outcome.damage = combat.MagicDamage(action.base, action.multiplier, dINT)
+			outcome.result.Reaction = serverPackets.BattleReactionHit
+			outcome.result.Param = uint32(outcome.damage) //nolint:gosec // damage is never negative
+			outcome.result.Message = action.messages.damage
+		}
+
+		if hasEffect {
+			_, landed := target.Effects.Add(effect, z.Now())
+			if !potent {
+				outcome.result = effectResult(outcome.result, landed, action.effectID, action.messages.enfeeble, action.messages.noEffect)
+			}
+		}
+	case *zone.Player:
+		if potent {
+			outcome.healed = s.healPlayer(z, player, target, action)
+			outcome.result.Param = uint32(outcome.healed) //nolint:gosec // healing is never negative
+			outcome.result.Message = action.messages.heal
+		}
+
+		if hasEffect {
+			landed := s.applyPlayerEffect(z, target, effect)
+			if !potent {
+				outcome.result = effectResult(outcome.result, landed, action.effectID, action.messages.effect, action.messages.noEffect)
+			}
+		}
+	}
+
+	return outcome
+}
+
+// effectResult sets the result of an action whose only outcome is its status effect.
+func effectResult(result serverPackets.BattleResult, landed bool, effectID, landedMessage, failedMessage uint16) serverPackets.BattleResult {
+	if !landed {
+		result.Message = failedMessage
+		return result
+	}
+
+	result.Param = uint32(effectID)
+	result.Message = landedMessage
+
+	return result
+}
+
+// healPlayer restores the HP of a healing action of a player to its target, returning
+// how much it restored.
+func (s *InstanceWorker) healPlayer(z *zone.Zone, player, target *zone.Player, action *actionData) int32 {
+	if target.Stats == nil || playerDefeated(target) {
+		return 0
+	}
+
+	amount := combat.Healing(action.base, action.multiplier, player.Derived.Attribute(charstats.MND), player.Derived.Attribute(charstats.VIT))
+	healed := min(amount, max(target.Derived.MaxHP-int32(target.Stats.HP), 0))
+
+	target.Stats.HP += uint16(healed) //nolint:gosec // bounded by the maximum HP
+	target.StatsDirty = true
+	target.MarkChanged()
+	sendPackets(z, target.ClientAddr, CreateGroupAttrPacket(target))
+
+	return healed
+}
+
+// actionEnmity gives the enmity of a spell or ability before it resolves: a mob it
+// targets engages the player (claiming it for them) and hates them for it, and the
+// mobs fighting a player it helps hate its user.
+func (s *InstanceWorker) actionEnmity(z *zone.Zone, player *zone.Player, target zone.Entity, action *actionData) {
+	switch target := target.(type) {
+	case *zone.Mob:
+		s.engageMob(z, target, player)
+		target.Hate.Add(player.CharacterID, action.ce, action.ve)
+	case *zone.Player:
+		supportEnmity(z, player, target, action.ce, action.ve, 0)
+	}
+}
+
+// applyActionOutcome deals the damage of a spell or ability once it was shown, and
+// gives the enmity of the HP it healed.
+func (s *InstanceWorker) applyActionOutcome(z *zone.Zone, player *zone.Player, target zone.Entity, outcome actionOutcome) {
+	switch target := target.(type) {
+	case *zone.Mob:
+		if outcome.damage > 0 && target.HP > 0 {
+			s.damageMob(z, target, player, outcome.damage)
+		}
+	case *zone.Player:
+		if outcome.healed > 0 {
+			supportEnmity(z, player, target, 0, 0, outcome.healed)
+		}
+	}
+}
+
+// supportEnmity makes the mobs fighting a player hate whoever helps it: cumulative and
+// volatile enmity, plus the enmity of the HP healed.
+func supportEnmity(z *zone.Zone, player, target *zone.Player, cumulative, volatile, healed int32) {
+	for _, mob := range z.Mobs() {
+		if !mob.Spawned || !mob.Hate.Has(target.CharacterID) {
+			continue
+		}
+
+		healCumulative, healVolatile := healEnmity(mob.Level, healed)
+		mob.Hate.Add(player.CharacterID, cumulative+healCumulative, volatile+healVolatile)
+	}
+}
+
+// healEnmity returns the cumulative and volatile enmity healing a player earns with
+// the mobs fighting it; like damage, it is worth less against stronger mobs.
+func healEnmity(mobLevel uint8, healed int32) (int32, int32) {
+	if healed <= 0 {
+		return 0, 0
+	}
+
+	scale := 2*int32(mobLevel) + 30
+	return 40 * healed / scale, 240 * healed / scale
+}
+
+// refuseAction tells a player why a spell or ability cannot be used; actionID is the
+// spell or ability the message names.
+func refuseAction(z *zone.Zone, player *zone.Player, target zone.Entity, message, actionID uint16) {
+	if target == nil {
+		target = player
+	}
+
+	packet := createCombatMessagePacket(player, target, message)
+	packet.Data = uint32(actionID)
+	sendPackets(z, player.ClientAddr, packet)
+}
+
+// createActionPacket builds the packet showing a spell or ability of an entity on its target.
+func createActionPacket(actor, target zone.Entity, category uint8, cmdArg, info uint32, result serverPackets.BattleResult) *serverPackets.BattlePacket {
+	return &serverPackets.BattlePacket{
+		UniqueNo: actor.EntityID(),
+		Category: category,
+		CmdArg:   cmdArg,
+		Info:     info,
+		Targets: []serverPackets.BattleTarget{{
+			UniqueNo: target.EntityID(),
+			Results:  []serverPackets.BattleResult{result},
+		}},
+	}
+}
+
+// dispelHook removes the latest status effect with a flag from the target of an
+// action (dispel on mobs, erase on players).
+func (s *InstanceWorker) dispelHook(flag effects.Flag) actionHook {
+	return func(z *zone.Zone, _ *zone.Player, target zone.Entity, action *actionData) actionOutcome {
+		result := serverPackets.BattleResult{Animation: action.animation, Message: action.messages.noEffect}
+
+		var dispelled effects.Effect
+		var ok bool
+		switch target := target.(type) {
+		case *zone.Mob:
+			dispelled, ok = target.Effects.Dispel(flag)
+		case *zone.Player:
+			if dispelled, ok = target.Effects.Dispel(flag); ok {
+				s.playerEffectsChanged(z, target)
+			}
+		}
+
+		if ok {
+			result.Param = uint32(dispelled.ID)
+			result.Message = serverPackets.MessageMagicEffectDisappears
+		}
+
+		return actionOutcome{result: result}
+	}
+}
+
+// convertHook swaps the HP and MP of the player using it (red mage's Convert),
+// within their maximums.
+func (s *InstanceWorker) convertHook(z *zone.Zone, player *zone.Player, _ zone.Entity, action *actionData) actionOutcome {
+	result := serverPackets.BattleResult{Animation: action.animation, Message: action.messages.noEffect}
+	if player.Stats == nil || player.Stats.MP == 0 {
+		return actionOutcome{result: result}
+	}
+
+	hp, mp := int32(player.Stats.HP), int32(player.Stats.MP)
+	player.Stats.HP = uint16(min(mp, player.Derived.MaxHP)) //nolint:gosec // clamped to the maximum HP
+	player.Stats.MP = uint16(min(hp, player.Derived.MaxMP)) //nolint:gosec // clamped to the maximum MP
+	player.StatsDirty = true
+	player.MarkChanged()
+	sendPackets(z, player.ClientAddr, CreateGroupAttrPacket(player))
+
+	result.Message = action.messages.used
+	return actionOutcome{result: result}
+}
+
+// registerActionHooks builds the tables of the spells and abilities with custom logic.
+func (s *InstanceWorker) registerActionHooks() {
+	s.spellHooks = map[string]actionHook{
+		"dispel": s.dispelHook(effects.FlagDispelable),
+		"erase":  s.dispelHook(effects.FlagErasable),
+	}
+
+	s.abilityHooks = map[string]actionHook{
+		"convert": s.convertHook,
+	}
+}
@@ -0,0 +1,338 @@
+package instance
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// the spells and abilities of the tests
+const (
+	testCure    = 1
+	testStone   = 159
+	testBio     = 230
+	testBerserk = 1
+	testProvoke = 2
+	testConvert = 3
+	testWarcry  = 4
+)
+
+// newActionTest is a status effect test whose WAR knows a few spells and abilities.
+func newActionTest(t *testing.T) *combatTest {
+	t.Helper()
+
+	ct := newEffectTest(t)
+	ct.s.registerActionHooks()
+	ct.z.AddSystem(ct.s.updateCasting)
+
+	warrior := [22]uint8{1}
+	data := ct.s.gameData.Current()
+	data.Spells = map[uint16]gamedata.Spell{
+		testCure:  {ID: testCure, Name: "cure", Jobs: warrior, ValidTargets: gamedata.TargetSelf | gamedata.TargetPlayer, Skill: gamedata.SkillHealing, MPCost: 8, Base: 20, Multiplier: 1, CE: 1, VE: 100, Range: 204},
+		testStone: {ID: testStone, Name: "stone", Jobs: warrior, ValidTargets: gamedata.TargetEnemy, Skill: gamedata.SkillElemental, MPCost: 4, CastTime: 2000, RecastTime: 5000, Base: 60, Multiplier: 1, CE: 1, VE: 320, Range: 204},
+		testBio:   {ID: testBio, Name: "bio", Jobs: warrior, ValidTargets: gamedata.TargetEnemy, Skill: gamedata.SkillDark, MPCost: 15, CastTime: 1000, Range: 204, EffectID: testPoison, Power: 3, Duration: 60},
+	}
+	data.Abilities = map[uint16]gamedata.Ability{
+		testBerserk: {ID: testBerserk, Name: "berserk", Job: 1, Level: 15, ValidTarget: gamedata.TargetSelf, RecastTime: 300, RecastID: 1, EffectID: testProtect, Power: 10, Duration: 180},
+		testProvoke: {ID: testProvoke, Name: "provoke", Job: 1, Level: 5, ValidTarget: gamedata.TargetEnemy, RecastTime: 30, RecastID: 5, Range: 16, TPCost: 100, CE: 1, VE: 1800},
+		testConvert: {ID: testConvert, Name: "convert", Job: 1, Level: 1, ValidTarget: gamedata.TargetSelf, RecastTime: 600, RecastID: 49},
+		testWarcry:  {ID: testWarcry, Name: "warcry", Job: 1, Level: 31, ValidTarget: gamedata.TargetSelf, RecastTime: 300, RecastID: 2},
+	}
+
+	ct.player.Stats.MP = 50
+	return ct
+}
+
+// act sends an action packet of the player for a spell or ability.
+func (ct *combatTest) act(actionID uint16, id uint16, target zone.Entity) error {
+	pctx := &PacketContext{CharacterID: ct.player.CharacterID, Zone: ct.z}
+	return ct.s.handleActionPacket(pctx, &clientPackets.ActionPacket{
+		UniqueNo:  target.EntityID(),
+		ActIndex:  target.EntityIndex(),
+		ActionID:  actionID,
+		ActionBuf: [4]uint32{uint32(id)},
+	})
+}
+
+// battle returns the last battle packet of a category sent, clearing the recorded packets.
+func (ct *combatTest) battle(category uint8) *serverPackets.BattlePacket {
+	var found *serverPackets.BattlePacket
+	for _, battle := range ct.battles() {
+		if battle.Category == category {
+			found = battle
+		}
+	}
+
+	return found
+}
+
+// result returns the first result of a battle packet, or an empty one.
+func result(battle *serverPackets.BattlePacket) serverPackets.BattleResult {
+	if battle == nil || len(battle.Targets) == 0 || len(battle.Targets[0].Results) == 0 {
+		return serverPackets.BattleResult{}
+	}
+
+	return battle.Targets[0].Results[0]
+}
+
+func TestCastSpell(t *testing.T) {
+	ct := newActionTest(t)
+
+	if err := ct.act(clientPackets.ActionCastMagic, testStone, ct.mob); err != nil {
+		t.Fatalf("cast error = %v", err)
+	}
+
+	ct.z.Step()
+	if start := ct.battle(serverPackets.BattleCategoryMagicStart); start == nil || start.CmdArg != serverPackets.BattleCastStart || result(start).Param != testStone {
+		t.Fatalf("start packet = %+v, want stone starting", start)
+	}
+
+	// nothing happens until the casting time is over
+	ct.z.Advance(time.Second)
+	if ct.player.Casting == nil || ct.mob.HP != ct.mob.MaxHP || ct.player.Stats.MP != 50 {
+		t.Fatalf("casting = %+v, mob HP = %d, MP = %d after 1s", ct.player.Casting, ct.mob.HP, ct.player.Stats.MP)
+	}
+
+	ct.z.Advance(time.Second)
+	finish := ct.battle(serverPackets.BattleCategoryMagicFinish)
+	damage := ct.mob.MaxHP - ct.mob.HP
+	if finish == nil || finish.CmdArg != testStone || result(finish).Message != serverPackets.MessageMagicDamage || int32(result(finish).Param) != damage || damage <= 0 {
+		t.Fatalf("finish packet = %+v, mob lost %d HP", finish, damage)
+	}
+
+	if ct.player.Stats.MP != 46 || ct.player.Casting != nil {
+		t.Fatalf("MP = %d, casting = %+v, want 46 and done", ct.player.Stats.MP, ct.player.Casting)
+	}
+
+	if ct.mob.ClaimedBy != ct.player.CharacterID || !ct.mob.State.Engaged() || ct.mob.Hate.Enmity(ct.player.CharacterID) <= mobBaseEnmity+320 {
+		t.Fatalf("mob claimed by %d, state %v, enmity %d", ct.mob.ClaimedBy, ct.mob.State, ct.mob.Hate.Enmity(ct.player.CharacterID))
+	}
+
+	// the recast has to run out before casting it again
+	if err := ct.act(clientPackets.ActionCastMagic, testStone, ct.mob); err != nil {
+		t.Fatalf("cast error = %v", err)
+	}
+
+	ct.z.Step()
+	if got := ct.messages(); len(got) != 1 || got[0] != serverPackets.MessageWaitLonger {
+		t.Fatalf("messages = %v, want waiting for the recast", got)
+	}
+}
+
+func TestCastRefused(t *testing.T) {
+	cases := []struct {
+		name    string
+		spellID uint16
+		target  func(ct *combatTest) zone.Entity
+		setup   func(ct *combatTest)
+		want    uint16
+	}{
+		{"enemy spell on self", testStone, func(ct *combatTest) zone.Entity { return ct.player }, nil, serverPackets.MessageCannotOnTarget},
+		{"healing spell on a mob", testCure, func(ct *combatTest) zone.Entity { return ct.mob }, nil, serverPackets.MessageCannotOnTarget},
+		{"out of range", testStone, func(ct *combatTest) zone.Entity { return ct.mob }, func(ct *combatTest) { ct.player.Position.X = 25 }, serverPackets.MessageTooFarToCast},
+		{"out of sight", testStone, func(ct *combatTest) zone.Entity { return ct.mob }, func(ct *combatTest) { ct.player.Position.Y = 10 }, serverPackets.MessageUnableToSee},
+		{"not enough MP", testBio, func(ct *combatTest) zone.Entity { return ct.mob }, func(ct *combatTest) { ct.player.Stats.MP = 14 }, serverPackets.MessageNotEnoughMP},
+		{"claimed by someone else", testStone, func(ct *combatTest) zone.Entity { return ct.mob }, func(ct *combatTest) { ct.mob.ClaimedBy = 2 }, serverPackets.MessageAlreadyClaimed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ct := newActionTest(t)
+			if tc.setup != nil {
+				tc.setup(ct)
+			}
+
+			if err := ct.act(clientPackets.ActionCastMagic, tc.spellID, tc.target(ct)); err != nil {
+				t.Fatalf("cast error = %v", err)
+			}
+
+			ct.z.Step()
+			if got := ct.messages(); len(got) != 1 || got[0] != tc.want || ct.player.Casting != nil {
+				t.Fatalf("messages = %v, casting = %+v, want %d", got, ct.player.Casting, tc.want)
+			}
+		})
+	}
+
+	// spells the client should not offer are rejected
+	ct := newActionTest(t)
+	if err := ct.act(clientPackets.ActionCastMagic, 999, ct.mob); !errors.Is(err, ErrUnknownSpell) {
+		t.Fatalf("cast error = %v, want %v", err, ErrUnknownSpell)
+	}
+
+	ct.player.Stats.MainJob = 4
+	if err := ct.act(clientPackets.ActionCastMagic, testStone, ct.mob); !errors.Is(err, ErrActionUnavailable) {
+		t.Fatalf("cast error = %v, want %v", err, ErrActionUnavailable)
+	}
+}
+
+func TestCastInterrupted(t *testing.T) {
+	ct := newActionTest(t)
+
+	if err := ct.act(clientPackets.ActionCastMagic, testStone, ct.mob); err != nil {
+		t.Fatalf("cast error = %v", err)
+	}
+
+	ct.player.Position.X = 5
+	ct.z.Advance(time.Second)
+
+	interrupt := ct.battle(serverPackets.BattleCategoryMagicStart)
+	if interrupt == nil || interrupt.CmdArg != serverPackets.BattleCastInterrupt || result(interrupt).Message != serverPackets.MessageIsInterrupted {
+		t.Fatalf("interrupt packet = %+v, want stone interrupted", interrupt)
+	}
+
+	if ct.player.Casting != nil || ct.player.Stats.MP != 50 || ct.mob.HP != ct.mob.MaxHP || !ct.player.Recasts.Ready(zone.RecastSpell, testStone, ct.z.Now()) {
+		t.Fatalf("casting = %+v, MP = %d, mob HP = %d, want nothing spent", ct.player.Casting, ct.player.Stats.MP, ct.mob.HP)
+	}
+
+	// the target dying before the spell goes off interrupts it as well
+	if err := ct.act(clientPackets.ActionCastMagic, testStone, ct.mob); err != nil {
+		t.Fatalf("cast error = %v", err)
+	}
+
+	ct.mob.HP = 0
+	ct.z.Advance(2 * time.Second)
+	if got := ct.messages(); len(got) != 1 || got[0] != serverPackets.MessageCannotOnTarget || ct.player.Stats.MP != 50 {
+		t.Fatalf("messages = %v, MP = %d, want the spell failing for free", got, ct.player.Stats.MP)
+	}
+}
+
+func TestHealingSpell(t *testing.T) {
+	ct := newActionTest(t)
+	ct.player.Stats.HP = 10
+	ct.mob.Hate.Add(ct.player.CharacterID, 1, 0)
+
+	if err := ct.act(clientPackets.ActionCastMagic, testCure, ct.player); err != nil {
+		t.Fatalf("cast error = %v", err)
+	}
+
+	ct.z.Advance(time.Second)
+	finish := ct.battle(serverPackets.BattleCategoryMagicFinish)
+	healed := int32(ct.player.Stats.HP) - 10
+	if finish == nil || result(finish).Message != serverPackets.MessageMagicRecoversHP || int32(result(finish).Param) != healed || healed <= 20 {
+		t.Fatalf("finish packet = %+v, healed %d", finish, healed)
+	}
+
+	// the mob fighting the player hates its healer, who is the player itself here
+	cumulative, volatile := healEnmity(ct.mob.Level, healed)
+	if got, want := ct.mob.Hate.Enmity(ct.player.CharacterID), 1+1+100+cumulative+volatile; got != want {
+		t.Fatalf("enmity = %d, want %d", got, want)
+	}
+}
+
+func TestEnfeeblingSpell(t *testing.T) {
+	ct := newActionTest(t)
+
+	if err := ct.act(clientPackets.ActionCastMagic, testBio, ct.mob); err != nil {
+		t.Fatalf("cast error = %v", err)
+	}
+
+	ct.z.Advance(time.Second)
+	if got := result(ct.battle(serverPackets.BattleCategoryMagicFinish)); got.Message != serverPackets.MessageMagicEnfeebles || got.Param != testPoison {
+		t.Fatalf("result = %+v, want the mob poisoned", got)
+	}
+
+	if poison := ct.mob.Effects.Get(testPoison); poison == nil || poison.SourceID != ct.player.CharacterID || poison.Power != 3 {
+		t.Fatalf("mob poison = %+v", poison)
+	}
+
+	// the same poison does not overwrite itself
+	if err := ct.act(clientPackets.ActionCastMagic, testBio, ct.mob); err != nil {
+		t.Fatalf("cast error = %v", err)
+	}
+
+	ct.z.Advance(time.Second)
+	if got := result(ct.battle(serverPackets.BattleCategoryMagicFinish)); got.Message != serverPackets.MessageMagicNoEffect {
+		t.Fatalf("result = %+v, want no effect", got)
+	}
+}
+
+func TestUseAbility(t *testing.T) {
+	ct := newActionTest(t)
+
+	if err := ct.act(clientPackets.ActionJobAbility, testBerserk, ct.player); err != nil {
+		t.Fatalf("ability error = %v", err)
+	}
+
+	ct.z.Step()
+	finish := ct.battle(serverPackets.BattleCategoryAbilityFinish)
+	if finish == nil || finish.CmdArg != testBerserk || finish.Info != 300 || result(finish).Message != serverPackets.MessageUsesAbility || result(finish).Param != testProtect {
+		t.Fatalf("finish packet = %+v, want berserk used", finish)
+	}
+
+	if ct.player.Effects.Get(testProtect) == nil {
+		t.Fatalf("effects = %+v, want the ability's effect", ct.player.Effects.All())
+	}
+
+	if err := ct.act(clientPackets.ActionJobAbility, testBerserk, ct.player); err != nil {
+		t.Fatalf("ability error = %v", err)
+	}
+
+	ct.z.Step()
+	if got := ct.messages(); len(got) != 1 || got[0] != serverPackets.MessageWaitLonger {
+		t.Fatalf("messages = %v, want waiting for the recast", got)
+	}
+
+	if err := ct.act(clientPackets.ActionJobAbility, testWarcry, ct.player); !errors.Is(err, ErrActionUnavailable) {
+		t.Fatalf("ability error = %v, want %v", err, ErrActionUnavailable)
+	}
+}
+
+func TestAbilityCosts(t *testing.T) {
+	ct := newActionTest(t)
+
+	if err := ct.act(clientPackets.ActionJobAbility, testProvoke, ct.mob); err != nil {
+		t.Fatalf("ability error = %v", err)
+	}
+
+	ct.z.Step()
+	if got := ct.messages(); len(got) != 1 || got[0] != serverPackets.MessageNotEnoughTP {
+		t.Fatalf("messages = %v, want not enough TP", got)
+	}
+
+	ct.player.TP = 250
+	if err := ct.act(clientPackets.ActionJobAbility, testProvoke, ct.mob); err != nil {
+		t.Fatalf("ability error = %v", err)
+	}
+
+	if ct.player.TP != 150 || ct.mob.ClaimedBy != ct.player.CharacterID || ct.mob.Hate.Enmity(ct.player.CharacterID) != mobBaseEnmity+1801 {
+		t.Fatalf("TP = %d, mob claimed by %d, enmity %d", ct.player.TP, ct.mob.ClaimedBy, ct.mob.Hate.Enmity(ct.player.CharacterID))
+	}
+
+	// hooks replace what the data would do
+	ct.player.Stats.HP, ct.player.Stats.MP = 100, 30
+	ct.player.Derived.MaxMP = 80
+	if err := ct.act(clientPackets.ActionJobAbility, testConvert, ct.player); err != nil {
+		t.Fatalf("ability error = %v", err)
+	}
+
+	if ct.player.Stats.HP != 30 || ct.player.Stats.MP != 80 {
+		t.Fatalf("HP = %d, MP = %d, want 30 and 80 after convert", ct.player.Stats.HP, ct.player.Stats.MP)
+	}
+}
+
+func TestInterruptedWhenHit(t *testing.T) {
+	ct := newActionTest(t)
+
+	if err := ct.act(clientPackets.ActionCastMagic, testStone, ct.mob); err != nil {
+		t.Fatalf("cast error = %v", err)
+	}
+
+	// each hit has an even chance of interrupting the spell between evenly matched fighters
+	for hits := 1; ct.player.Casting != nil; hits++ {
+		if hits > 20 {
+			t.Fatalf("%d hits did not interrupt the spell", hits)
+		}
+
+		ct.s.damagePlayer(ct.z, ct.player, ct.mob, 1)
+	}
+
+	ct.z.Step()
+	if interrupt := ct.battle(serverPackets.BattleCategoryMagicStart); interrupt == nil || interrupt.CmdArg != serverPackets.BattleCastInterrupt {
+		t.Fatalf("interrupt packet = %+v, want stone interrupted", interrupt)
+	}
+}
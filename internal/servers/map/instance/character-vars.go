@@ -0,0 +1,47 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// loadCharacterVars loads the variables scripts keep on a character.
+func (s *InstanceWorker) loadCharacterVars(ctx context.Context, characterID uint32) (map[string]int32, error) {
+	rows, err := s.DB().GetCharacterVars(ctx, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load character variables: %w", err)
+	}
+
+	vars := make(map[string]int32, len(rows))
+	for _, row := range rows {
+		vars[row.Name] = row.Value
+	}
+
+	return vars, nil
+}
+
+// setCharacterVar sets a variable of a player and persists it; variables set to 0
+// are deleted.
+func (s *InstanceWorker) setCharacterVar(player *zone.Player, name string, value int32) {
+	if player.Vars[name] == value {
+		return
+	}
+
+	if player.Vars == nil {
+		player.Vars = make(map[string]int32)
+	}
+
+	if value == 0 {
+		delete(player.Vars, name)
+	} else {
+		player.Vars[name] = value
+	}
+
+	characterVar := &database.CharacterVar{CharacterID: player.CharacterID, Name: name, Value: value}
+	s.queueSave("character variable", player.CharacterID, func(ctx context.Context) error {
+		return s.DB().SetCharacterVar(ctx, characterVar)
+	})
+}
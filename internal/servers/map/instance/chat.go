@@ -0,0 +1,256 @@
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	// SubjectYell is the NATS subject yells are relayed to every instance on
+	SubjectYell = "map.chat.yell"
+)
+
+const (
+	tellOfflineMessage  = "Your tell was not received. The recipient is either offline or changing areas."
+	tellNotFoundMessage = "Your tell was not received. The recipient does not exist."
+	yellNotAllowed      = "You cannot yell in this area."
+)
+
+var ErrUnsupportedChat = errors.New("unsupported chat channel")
+
+// chatMessage is a chat message as it is routed, possibly to other instances.
+type chatMessage struct {
+	Kind        uint8
+	CharacterID uint32
+	Name        string
+	ZoneID      uint16
+	GM          bool `json:",omitempty"`
+	Message     []byte
+}
+
+// chatRoute delivers the messages sent to one chat channel. Party and linkshell
+// channels register their routes here once those groups exist.
+type chatRoute func(z *zone.Zone, sender *zone.Player, message chatMessage) error
+
+// registerChatRoutes wires every supported chat channel to the function delivering its messages.
+func (s *InstanceWorker) registerChatRoutes() {
+	s.chatRoutes = map[uint8]chatRoute{
+		serverPackets.ChatKindSay:   s.nearbyChat(s.Config().MapSayRange),
+		serverPackets.ChatKindEmote: s.nearbyChat(s.Config().MapSayRange),
+		serverPackets.ChatKindShout: s.nearbyChat(s.Config().MapShoutRange),
+		serverPackets.ChatKindYell:  s.yell,
+	}
+}
+
+func (s *InstanceWorker) handleChatPacket(pctx *PacketContext, packet *clientPackets.ChatPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	if len(packet.Message) == 0 {
+		return nil
+	}
+
+	// staff run commands through chat
+	if player.GMLevel > database.GMLevelNone && packet.Message[0] == gmCommandPrefix {
+		s.runGMCommand(pctx.Zone, player, string(packet.Message[1:]))
+		return nil
+	}
+
+	route, ok := s.chatRoutes[packet.Kind]
+	if !ok {
+		return fmt.Errorf("%w: %w %d", ErrPacketRejected, ErrUnsupportedChat, packet.Kind)
+	}
+
+	message := newChatMessage(packet.Kind, pctx.Zone, player, packet.Message)
+	s.logChat(message, "")
+
+	return route(pctx.Zone, player, message)
+}
+
+func (s *InstanceWorker) handleTellPacket(pctx *PacketContext, packet *clientPackets.TellPacket) error {
+	player := pctx.Zone.Player(pctx.CharacterID)
+	if player == nil {
+		return fmt.Errorf("%w: character %d is not in zone %d", ErrPacketRejected, pctx.CharacterID, pctx.Zone.ID())
+	}
+
+	if len(packet.Message) == 0 {
+		return nil
+	}
+
+	message := newChatMessage(serverPackets.ChatKindTell, pctx.Zone, player, packet.Message)
+	s.logChat(message, packet.Name)
+
+	// the recipient may be on another instance, so wait for the delivery off the zone loop
+	go s.sendTell(player.ClientAddr, packet.Name, message)
+
+	return nil
+}
+
+func newChatMessage(kind uint8, z *zone.Zone, sender *zone.Player, text []byte) chatMessage {
+	return chatMessage{
+		Kind:        kind,
+		CharacterID: sender.CharacterID,
+		Name:        sender.Name,
+		ZoneID:      z.ID(),
+		GM:          sender.GMLevel > database.GMLevelNone && !sender.Hidden,
+		Message:     text[:min(len(text), serverPackets.ChatPacketMaxMessageLength)],
+	}
+}
+
+// nearbyChat delivers messages to the players of the zone within distance yalms of the sender.
+func (s *InstanceWorker) nearbyChat(distance float64) chatRoute {
+	return func(z *zone.Zone, sender *zone.Player, message chatMessage) error {
+		packet := createChatPacket(message)
+		for _, listener := range z.PlayersNear(sender.Position, distance) {
+			if listener != sender && !listener.Disconnected {
+				z.Send(listener.ClientAddr, packet)
+			}
+		}
+
+		return nil
+	}
+}
+
+// yell relays a message to the yell zones of every instance.
+func (s *InstanceWorker) yell(z *zone.Zone, sender *zone.Player, message chatMessage) error {
+	if !slices.Contains(s.Config().MapYellZones, z.ID()) {
+		z.Send(sender.ClientAddr, createSystemChatPacket(yellNotAllowed))
+		return nil
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal yell: %w", err)
+	}
+
+	return s.NATS().Publish(SubjectYell, data)
+}
+
+// ProcessYell delivers a yell to the players in this instance's yell zones.
+func (s *InstanceWorker) ProcessYell(msg *nats.Msg) {
+	var message chatMessage
+	if err := json.Unmarshal(msg.Data, &message); err != nil {
+		s.Logger().Error("failed to unmarshal yell; discarding", "error", err)
+		return
+	}
+
+	packet := createChatPacket(message)
+	for _, z := range s.runningZones() {
+		if !slices.Contains(s.Config().MapYellZones, z.ID()) {
+			continue
+		}
+
+		err := z.Post(func(z *zone.Zone) {
+			for _, listener := range z.Players() {
+				if listener.CharacterID != message.CharacterID && !listener.Disconnected {
+					z.Send(listener.ClientAddr, packet)
+				}
+			}
+		})
+		if err != nil {
+			s.Logger().Warn("dropping yell", "zoneID", z.ID(), "error", err)
+		}
+	}
+}
+
+// sendTell routes a tell to the instance the recipient is on, telling the sender when
+// the tell could not be delivered.
+func (s *InstanceWorker) sendTell(clientAddr, recipient string, message chatMessage) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		s.Logger().Error("failed to marshal tell", "error", err)
+		return
+	}
+
+	_, err = s.requestPlayer(recipient, playerRequestTell, data)
+	if err == nil {
+		return
+	}
+
+	if !errors.Is(err, ErrPlayerOffline) {
+		s.Logger().Warn("failed to route tell", "recipient", recipient, "error", err)
+	}
+
+	text := tellNotFoundMessage
+	ctx, cancel := context.WithTimeout(s.ctx, playerRequestTimeout)
+	defer cancel()
+
+	if exists, err := s.DB().CharacterNameExists(ctx, recipient); err != nil || exists {
+		text = tellOfflineMessage
+	}
+
+	if err := s.sendPacket(clientAddr, createSystemChatPacket(text)); err != nil {
+		s.Logger().Error("failed to send tell reply", "clientAddr", clientAddr, "error", err)
+	}
+}
+
+// receiveTell delivers a tell routed to a player of this instance.
+func (s *InstanceWorker) receiveTell(z *zone.Zone, player *zone.Player, data []byte) ([]byte, error) {
+	var message chatMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tell: %w", err)
+	}
+
+	z.Send(player.ClientAddr, createChatPacket(message))
+	return nil, nil
+}
+
+// logChat stores a chat message for moderation.
+func (s *InstanceWorker) logChat(message chatMessage, recipient string) {
+	if !s.Config().MapChatLogEnabled {
+		return
+	}
+
+	chatLog := &database.ChatLog{
+		CharacterID:   message.CharacterID,
+		CharacterName: message.Name,
+		ZoneID:        message.ZoneID,
+		Kind:          message.Kind,
+		Recipient:     recipient,
+		Message:       message.Message,
+		CreatedAt:     time.Now(),
+	}
+
+	s.queueSave("chat log", message.CharacterID, func(ctx context.Context) error {
+		return s.DB().CreateChatLog(ctx, chatLog)
+	})
+}
+
+func createChatPacket(message chatMessage) *serverPackets.ChatPacket {
+	packet := &serverPackets.ChatPacket{
+		Kind:    message.Kind,
+		Message: message.Message,
+	}
+
+	if message.Kind == serverPackets.ChatKindYell {
+		packet.Data = message.ZoneID
+	}
+
+	if message.GM {
+		packet.Attr |= serverPackets.ChatAttrGM
+	}
+
+	copy(packet.Name[:], message.Name)
+
+	return packet
+}
+
+func createSystemChatPacket(text string) *serverPackets.ChatPacket {
+	return &serverPackets.ChatPacket{
+		Kind:    serverPackets.ChatKindSystem,
+		Message: []byte(text),
+	}
+}
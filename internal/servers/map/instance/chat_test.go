@@ -0,0 +1,87 @@
+package instance
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+func TestPlayerSubject(t *testing.T) {
+	cases := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"Shantotto", "map.player.shantotto.tell", true},
+		{"", "", false},
+		{"a.b", "", false},
+		{"*", "", false},
+		{"Name1", "", false},
+	}
+
+	for _, tc := range cases {
+		got, ok := playerSubject(tc.name, playerRequestTell)
+		if got != tc.want || ok != tc.wantOK {
+			t.Fatalf("playerSubject(%q) = %q, %v, want %q, %v", tc.name, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
+
+func TestNearbyChat(t *testing.T) {
+	var received []string
+	z := zone.New(230, zone.Options{
+		TickInterval: time.Second,
+		Clock:        zone.NewVirtualClock(time.Unix(0, 0)),
+		Sender: func(clientAddr string, _ serverPackets.ServerPacket) error {
+			received = append(received, clientAddr)
+			return nil
+		},
+	})
+
+	sender := &zone.Player{CharacterID: 1, ClientAddr: "sender", Name: "Sender"}
+	near := &zone.Player{CharacterID: 2, ClientAddr: "near", Position: zone.Position{X: 40}}
+	far := &zone.Player{CharacterID: 3, ClientAddr: "far", Position: zone.Position{X: 60}}
+	gone := &zone.Player{CharacterID: 4, ClientAddr: "gone", Disconnected: true}
+	for _, player := range []*zone.Player{sender, near, far, gone} {
+		z.AddPlayer(player)
+	}
+
+	s := newTestWorker()
+	message := newChatMessage(serverPackets.ChatKindSay, z, sender, []byte("hello"))
+	if err := s.nearbyChat(50)(z, sender, message); err != nil {
+		t.Fatalf("nearbyChat() error = %v", err)
+	}
+
+	z.Step()
+
+	if want := []string{"near"}; !slices.Equal(received, want) {
+		t.Fatalf("received = %v, want %v", received, want)
+	}
+}
+
+func TestChatPacketSize(t *testing.T) {
+	cases := []struct {
+		message []byte
+		want    uint16
+	}{
+		{nil, 0x18},
+		{[]byte("abc"), 0x18},
+		{[]byte("abcd"), 0x1C},
+		{make([]byte, 1000), 0x14 + serverPackets.ChatPacketMaxMessageLength + 4},
+	}
+
+	for _, tc := range cases {
+		packet := createChatPacket(chatMessage{Message: tc.message})
+		data, err := packet.Serialize()
+		if err != nil {
+			t.Fatalf("Serialize() error = %v", err)
+		}
+
+		if packet.Size() != tc.want || len(data) != int(tc.want) {
+			t.Fatalf("Size() = %d, len(Serialize()) = %d, want %d", packet.Size(), len(data), tc.want)
+		}
+	}
+}
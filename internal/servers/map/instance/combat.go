@@ -0,0 +1,267 @@
+package instance
+
+import (
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/combat"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	// playerMeleeRange is how close players have to be to their target to hit it
+	playerMeleeRange = 4.0
+
+	// playerFacingHalfAngle is how far from facing their target players can hit it (64 is 90 degrees)
+	playerFacingHalfAngle = 64
+
+	// unarmedDelay is the delay of fighting bare-handed, in 1/60ths of a second
+	unarmedDelay = 480
+
+	// mobDefaultDelay is the delay of mobs whose pool sets none, in 1/60ths of a second
+	mobDefaultDelay = 240
+)
+
+// updateCombat runs the auto-attacks of the engaged players and mobs of the zone; it
+// is a zone system.
+func (s *InstanceWorker) updateCombat(z *zone.Zone, now time.Time) {
+	for _, player := range z.Players() {
+		if player.BattleTarget != 0 {
+			s.updateEngagedPlayer(z, player, now)
+		}
+	}
+
+	for _, mob := range z.Mobs() {
+		if mob.Spawned && mob.State == zone.AIAttacking && !now.Before(mob.NextAttackAt) {
+			s.mobAttack(z, mob, now)
+		}
+	}
+}
+
+// updateEngagedPlayer swings at the target of a player when its next attack is due,
+// ending the fight once the target is gone or claimed by someone else.
+func (s *InstanceWorker) updateEngagedPlayer(z *zone.Zone, player *zone.Player, now time.Time) {
+	mob := z.Mob(player.BattleTarget)
+	if mob == nil || !mob.Spawned || mob.HP <= 0 || playerDefeated(player) || player.Disconnected {
+		s.disengage(z, player)
+		return
+	}
+
+	if mobClaimedByOther(mob, player) {
+		sendPackets(z, player.ClientAddr, createCombatMessagePacket(player, mob, serverPackets.MessageAlreadyClaimed))
+		s.disengage(z, player)
+		return
+	}
+
+	if now.Before(player.NextAttackAt) {
+		return
+	}
+
+	attacker := s.playerFighter(player)
+	player.NextAttackAt = now.Add(combat.AttackInterval(attacker.Delay))
+
+	switch {
+	case player.Position.HorizontalDistance(mob.Position) > playerMeleeRange:
+		sendPackets(z, player.ClientAddr, createCombatMessagePacket(player, mob, serverPackets.MessageOutOfRange))
+		return
+	case !player.Position.IsFacing(mob.Position, playerFacingHalfAngle):
+		sendPackets(z, player.ClientAddr, createCombatMessagePacket(player, mob, serverPackets.MessageUnableToSee))
+		return
+	}
+
+	swing := combat.Attack(attacker, mobFighter(mob), z.Rand())
+	sendNearby(z, player.Position, createAttackPacket(player, mob, swing))
+
+	if !swing.Hit {
+		return
+	}
+
+	player.TP = combat.AddTP(player.TP, combat.BaseTP(attacker.Delay))
+	mob.TP = combat.AddTP(mob.TP, combat.DefenderTP(attacker.Delay))
+	s.damageMob(z, mob, player, swing.Damage)
+}
+
+// mobAttack swings an engaged mob at its target.
+func (s *InstanceWorker) mobAttack(z *zone.Zone, mob *zone.Mob, now time.Time) {
+	target := z.Player(mob.Target)
+	if target == nil || !mobCanTarget(target) || mob.Position.HorizontalDistance(target.Position) > mobMeleeRange {
+		// the AI picks another target or closes in on its next tick
+		return
+	}
+
+	attacker := mobFighter(mob)
+	mob.NextAttackAt = now.Add(combat.AttackInterval(attacker.Delay))
+
+	swing := combat.Attack(attacker, s.playerFighter(target), z.Rand())
+	sendNearby(z, mob.Position, createAttackPacket(mob, target, swing))
+
+	if !swing.Hit {
+		return
+	}
+
+	mob.TP = combat.AddTP(mob.TP, combat.BaseTP(attacker.Delay))
+	target.TP = combat.AddTP(target.TP, combat.DefenderTP(attacker.Delay))
+	s.damagePlayer(z, target, mob, swing.Damage)
+}
+
+// damageMob takes HP from a mob hit by a player, who gains enmity for it.
+func (s *InstanceWorker) damageMob(z *zone.Zone, mob *zone.Mob, player *zone.Player, damage int32) {
+	cumulative, volatile := damageEnmity(mob.Level, damage)
+	mob.Hate.Add(player.CharacterID, cumulative, volatile)
+	claimMob(mob, player)
+
+	mob.HP = max(mob.HP-damage, 0)
+	mob.MarkChanged()
+
+	if mob.HP == 0 {
+		s.defeatMob(z, mob, player)
+	}
+}
+
+// damagePlayer takes HP from a player hit by a mob, which may interrupt its spell.
+func (s *InstanceWorker) damagePlayer(z *zone.Zone, player *zone.Player, mob *zone.Mob, damage int32) {
+	if player.Stats == nil {
+		return
+	}
+
+	s.cancelLogout(z, player, "took damage")
+
+	player.Stats.HP = uint16(max(int32(player.Stats.HP)-damage, 0)) //nolint:gosec // bounded by the current HP
+	player.StatsDirty = true
+	player.MarkChanged()
+	sendPackets(z, player.ClientAddr, CreateGroupAttrPacket(player))
+
+	if player.Stats.HP == 0 {
+		s.defeatPlayer(z, player, mob)
+		return
+	}
+
+	interruptOnHit(z, player, mob)
+}
+
+// damageEnmity returns the cumulative and volatile enmity dealing damage to a mob
+// earns. The same damage is worth less against stronger mobs.
+func damageEnmity(mobLevel uint8, damage int32) (int32, int32) {
+	scale := 2*int32(mobLevel) + 30
+	damage = max(damage, 1)
+
+	return 80 * damage / scale, 240 * damage / scale
+}
+
+// playerFighter returns the combat stats of a player, with the weapon in its main hand.
+func (s *InstanceWorker) playerFighter(player *zone.Player) combat.Fighter {
+	level, _ := playerLevels(player)
+	stats := player.Derived
+
+	fighter := combat.Fighter{
+		Level:    level,
+		STR:      stats.Attribute(charstats.STR),
+		DEX:      stats.Attribute(charstats.DEX),
+		VIT:      stats.Attribute(charstats.VIT),
+		AGI:      stats.Attribute(charstats.AGI),
+		Attack:   stats.Attack,
+		Defense:  stats.Defense,
+		Accuracy: stats.Accuracy,
+		Evasion:  stats.Evasion,
+		Damage:   3 + int32(level)/3,
+		Delay:    unarmedDelay,
+	}
+
+	if weapon, ok := s.mainWeapon(player); ok {
+		fighter.Damage, fighter.Delay = int32(weapon.Damage), weapon.Delay
+	}
+
+	return fighter
+}
+
+// mainWeapon returns the weapon a player has in its main hand, if any.
+func (s *InstanceWorker) mainWeapon(player *zone.Player) (gamedata.Weapon, bool) {
+	if player.Inventory == nil {
+		return gamedata.Weapon{}, false
+	}
+
+	location, ok := player.Inventory.Equipped(serverPackets.EquipKindMain)
+	if !ok {
+		return gamedata.Weapon{}, false
+	}
+
+	item, _ := player.Inventory.Get(location)
+	weapon, ok := s.gameData.Current().Weapons[item.ID]
+
+	return weapon, ok
+}
+
+// mobFighter returns the combat stats of a mob, with the modifiers of its status
+// effects. The attributes come from mobAttribute and the rest uses the player formulas.
+func mobFighter(mob *zone.Mob) combat.Fighter {
+	mods := mob.Effects.Modifiers()
+
+	fighter := combat.Fighter{
+		Level:  mob.Level,
+		STR:    mobAttribute(mob, mods, charstats.ModSTR),
+		DEX:    mobAttribute(mob, mods, charstats.ModDEX),
+		VIT:    mobAttribute(mob, mods, charstats.ModVIT),
+		AGI:    mobAttribute(mob, mods, charstats.ModAGI),
+		Damage: 2 + int32(mob.Level)*9/10,
+		Delay:  mob.Delay,
+	}
+
+	fighter.Attack = max(8+fighter.STR*3/4+mods[charstats.ModATT], 1)
+	fighter.Defense = max(8+fighter.VIT/2+mods[charstats.ModDEF], 1)
+	fighter.Accuracy = max(fighter.DEX*3/4+mods[charstats.ModACC], 0)
+	fighter.Evasion = max(fighter.AGI/2+mods[charstats.ModEVA], 0)
+
+	return fighter
+}
+
+// mobAttribute returns an attribute of a mob (its STR, INT, ...) with the modifier of
+// its status effects for it. Until per-family stats are loaded, attributes follow a
+// rough curve by level.
+func mobAttribute(mob *zone.Mob, mods charstats.Modifiers, mod charstats.Mod) int32 {
+	return max(6+int32(mob.Level)*9/10+mods[mod], 0)
+}
+
+// createAttackPacket builds the packet showing an auto-attack round of one entity at another.
+func createAttackPacket(attacker, target zone.Entity, swing combat.Swing) *serverPackets.BattlePacket {
+	result := serverPackets.BattleResult{
+		Reaction: serverPackets.BattleReactionEvade,
+		Message:  serverPackets.MessageMiss,
+	}
+
+	switch {
+	case swing.Critical:
+		result = serverPackets.BattleResult{
+			Reaction: serverPackets.BattleReactionHit,
+			Effect:   serverPackets.BattleEffectCritical,
+			Param:    uint32(swing.Damage), //nolint:gosec // damage is never negative
+			Message:  serverPackets.MessageCritical,
+		}
+	case swing.Hit:
+		result = serverPackets.BattleResult{
+			Reaction: serverPackets.BattleReactionHit,
+			Effect:   serverPackets.BattleEffectHit,
+			Param:    uint32(swing.Damage), //nolint:gosec // damage is never negative
+			Message:  serverPackets.MessageHit,
+		}
+	}
+
+	return &serverPackets.BattlePacket{
+		UniqueNo: attacker.EntityID(),
+		Category: serverPackets.BattleCategoryAttack,
+		Targets: []serverPackets.BattleTarget{{
+			UniqueNo: target.EntityID(),
+			Results:  []serverPackets.BattleResult{result},
+		}},
+	}
+}
+
+// sendNearby sends a packet to the players within view distance of a position.
+func sendNearby(z *zone.Zone, position zone.Position, packet serverPackets.ServerPacket) {
+	for _, nearby := range z.PlayersNear(position, zone.DefaultViewDistance) {
+		if !nearby.Disconnected {
+			z.Send(nearby.ClientAddr, packet)
+		}
+	}
+}
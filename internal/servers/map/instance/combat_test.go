@@ -0,0 +1,441 @@
+package instance
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/combat"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// combatTest is a seeded zone with a WAR 30 player standing next to a level 30 rabbit,
+// recording the packets sent to the player.
+type combatTest struct {
+	s      *InstanceWorker
+	z      *zone.Zone
+	player *zone.Player
+	mob    *zone.Mob
+	sent   []serverPackets.ServerPacket
+}
+
+func newCombatTest(t *testing.T) *combatTest {
+	t.Helper()
+
+	data := testMobData()
+	pool := data.MobPools[10]
+	pool.Aggro = false
+	data.MobPools[10] = pool
+
+	ct := &combatTest{s: newTestWorker()}
+	ct.s.gameData = gamedata.NewStoreFromData(data)
+	ct.z = zone.New(230, zone.Options{
+		TickInterval: time.Second,
+		Clock:        zone.NewVirtualClock(time.Unix(0, 0)),
+		Rand:         rand.New(rand.NewPCG(1, 2)),
+		Sender: func(_ string, packet serverPackets.ServerPacket) error {
+			ct.sent = append(ct.sent, packet)
+			return nil
+		},
+	})
+
+	stats := &database.CharacterStats{CharacterID: 1, MainJob: 1}
+	ct.player = &zone.Player{
+		CharacterID: 1,
+		ClientAddr:  "127.0.0.1:1000",
+		Character: &database.Character{
+			ID:       1,
+			Jobs:     testJobs(),
+			Exp:      &database.CharacterExp{CharacterID: 1, WAR: 5000},
+			Stats:    stats,
+			HomeZone: 231,
+			HomeX:    10,
+		},
+		Stats: stats,
+		Looks: &database.CharacterLooks{Race: 1},
+	}
+	ct.s.refreshStats(ct.player)
+	stats.HP = uint16(ct.player.Derived.MaxHP) //nolint:gosec // test stats are small
+	ct.z.AddPlayer(ct.player)
+
+	ct.s.spawnZoneMobs(ct.z)
+	ct.z.AddSystem(ct.s.updateMobs)
+	ct.z.AddSystem(ct.s.updateCombat)
+
+	ct.mob = ct.z.Mob(testMobID)
+	ct.mob.Level = 30
+	ct.mob.MaxHP = mobMaxHP(30, 0)
+	ct.mob.HP = ct.mob.MaxHP
+
+	// facing the rabbit from 2 yalms east of it
+	ct.player.Position = zone.Position{X: 2}
+	ct.player.Position.Rotation = ct.player.Position.RotationTowards(ct.mob.Position)
+
+	return ct
+}
+
+func (ct *combatTest) action(actionID uint16, targetID uint32, targetIndex uint16) error {
+	pctx := &PacketContext{CharacterID: ct.player.CharacterID, Zone: ct.z}
+	return ct.s.handleActionPacket(pctx, &clientPackets.ActionPacket{UniqueNo: targetID, ActIndex: targetIndex, ActionID: actionID})
+}
+
+func (ct *combatTest) engage() error {
+	return ct.action(clientPackets.ActionEngage, ct.mob.ID, ct.mob.Index)
+}
+
+// battles returns the attack rounds sent, clearing the recorded packets.
+func (ct *combatTest) battles() []*serverPackets.BattlePacket {
+	var battles []*serverPackets.BattlePacket
+	for _, packet := range ct.sent {
+		if battle, ok := packet.(*serverPackets.BattlePacket); ok {
+			battles = append(battles, battle)
+		}
+	}
+
+	ct.sent = nil
+	return battles
+}
+
+// messages returns the IDs of the basic messages sent, clearing the recorded packets.
+func (ct *combatTest) messages() []uint16 {
+	var messages []uint16
+	for _, packet := range ct.sent {
+		if message, ok := packet.(*serverPackets.BattleMessagePacket); ok {
+			messages = append(messages, message.MessageNum)
+		}
+	}
+
+	ct.sent = nil
+	return messages
+}
+
+func TestEngage(t *testing.T) {
+	ct := newCombatTest(t)
+
+	if err := ct.engage(); err != nil {
+		t.Fatalf("engage error = %v", err)
+	}
+
+	if ct.player.BattleTarget != ct.mob.ID || !ct.mob.State.Engaged() || ct.mob.ClaimedBy != ct.player.CharacterID {
+		t.Fatalf("player target = %d, mob state = %v, claimed by %d", ct.player.BattleTarget, ct.mob.State, ct.mob.ClaimedBy)
+	}
+
+	ct.z.Step()
+	var status *serverPackets.ServerStatusPacket
+	for _, packet := range ct.sent {
+		if p, ok := packet.(*serverPackets.ServerStatusPacket); ok {
+			status = p
+		}
+	}
+
+	if status == nil || status.ServerStatus != serverPackets.ServerStatusEngaged {
+		t.Fatalf("server status = %+v, want engaged", status)
+	}
+
+	if got := CreatePlayerUpdatePacket(ct.player, 0).ServerStatus; got != serverPackets.ServerStatusEngaged {
+		t.Fatalf("character update status = %d, want engaged", got)
+	}
+
+	if err := ct.action(clientPackets.ActionDisengage, 0, 0); err != nil || ct.player.BattleTarget != 0 {
+		t.Fatalf("disengage error = %v, target = %d", err, ct.player.BattleTarget)
+	}
+}
+
+func TestEngageRefused(t *testing.T) {
+	ct := newCombatTest(t)
+
+	if err := ct.action(clientPackets.ActionEngage, ct.mob.ID+1, ct.mob.Index); !errors.Is(err, ErrPacketRejected) {
+		t.Fatalf("engage of a mismatched target error = %v, want %v", err, ErrPacketRejected)
+	}
+
+	ct.mob.ClaimedBy = 2
+	if err := ct.engage(); err != nil || ct.player.BattleTarget != 0 {
+		t.Fatalf("engage of a claimed mob error = %v, target = %d", err, ct.player.BattleTarget)
+	}
+
+	ct.mob.ClaimedBy = 0
+	ct.player.Position = zone.Position{X: 40}
+	if err := ct.engage(); err != nil || ct.player.BattleTarget != 0 {
+		t.Fatalf("engage of a far mob error = %v, target = %d", err, ct.player.BattleTarget)
+	}
+
+	ct.z.Step()
+	if got := ct.messages(); len(got) != 2 || got[0] != serverPackets.MessageAlreadyClaimed || got[1] != serverPackets.MessageTooFarAway {
+		t.Fatalf("messages = %v, want already claimed then too far away", got)
+	}
+
+	if err := ct.action(0x7F, ct.mob.ID, ct.mob.Index); !errors.Is(err, ErrUnsupportedAction) {
+		t.Fatalf("unknown action error = %v, want %v", err, ErrUnsupportedAction)
+	}
+}
+
+func TestAutoAttack(t *testing.T) {
+	ct := newCombatTest(t)
+	if err := ct.engage(); err != nil {
+		t.Fatalf("engage error = %v", err)
+	}
+
+	// bare-handed, the first round comes after a delay of 480 (8 seconds)
+	ct.z.Advance(7 * time.Second)
+	for _, battle := range ct.battles() {
+		if battle.UniqueNo == ct.player.CharacterID {
+			t.Fatalf("player attacked before its delay")
+		}
+	}
+
+	ct.z.Advance(time.Second)
+	var rounds []*serverPackets.BattlePacket
+	for _, battle := range ct.battles() {
+		if battle.UniqueNo == ct.player.CharacterID {
+			rounds = append(rounds, battle)
+		}
+	}
+
+	if len(rounds) != 1 || rounds[0].Category != serverPackets.BattleCategoryAttack || rounds[0].Targets[0].UniqueNo != ct.mob.ID {
+		t.Fatalf("rounds = %+v, want one attack at the mob", rounds)
+	}
+
+	// the player also builds up TP from the rabbit's hits
+	result := rounds[0].Targets[0].Results[0]
+	if result.Reaction == serverPackets.BattleReactionHit {
+		if ct.mob.HP != ct.mob.MaxHP-int32(result.Param) || ct.player.TP < combat.BaseTP(unarmedDelay) { //nolint:gosec // test damage is small
+			t.Fatalf("mob HP = %d/%d after %d damage, player TP = %d", ct.mob.HP, ct.mob.MaxHP, result.Param, ct.player.TP)
+		}
+	}
+
+	// facing away or out of range, the player is told rather than swinging
+	ct.player.Position.Rotation += 128
+	ct.z.Advance(8 * time.Second)
+	if got := ct.messages(); len(got) == 0 || got[0] != serverPackets.MessageUnableToSee {
+		t.Fatalf("messages = %v, want unable to see", got)
+	}
+
+	ct.mob.Speed = 0
+	ct.player.Position = zone.Position{X: 10, Rotation: 128}
+	ct.z.Advance(8 * time.Second)
+	if got := ct.messages(); len(got) == 0 || got[0] != serverPackets.MessageOutOfRange {
+		t.Fatalf("messages = %v, want out of range", got)
+	}
+}
+
+func TestDefeatMob(t *testing.T) {
+	ct := newCombatTest(t)
+	if err := ct.engage(); err != nil {
+		t.Fatalf("engage error = %v", err)
+	}
+
+	ct.mob.HP = 1
+	for range 10 {
+		ct.z.Advance(8 * time.Second)
+		if ct.mob.HP == 0 {
+			break
+		}
+	}
+
+	if ct.mob.HP != 0 || ct.mob.State != zone.AIDespawning || ct.player.BattleTarget != 0 {
+		t.Fatalf("mob HP = %d, state = %v, player target = %d", ct.mob.HP, ct.mob.State, ct.player.BattleTarget)
+	}
+
+	// an even match is worth 100 experience points
+	if got := ct.player.Character.Exp.WAR; got != 5100 {
+		t.Fatalf("WAR exp = %d, want %d", got, 5100)
+	}
+
+	if got := CreateMobUpdatePacket(ct.mob, 0).ServerStatus; got != serverPackets.CharNPCStatusDead {
+		t.Fatalf("mob status = %d, want dead", got)
+	}
+}
+
+func TestDefeatPlayer(t *testing.T) {
+	ct := newCombatTest(t)
+	if err := ct.engage(); err != nil {
+		t.Fatalf("engage error = %v", err)
+	}
+
+	ct.mob.HP = ct.mob.MaxHP * 100
+	ct.player.Stats.HP = 1
+	for range 20 {
+		ct.z.Advance(4 * time.Second)
+		if playerDefeated(ct.player) {
+			break
+		}
+	}
+
+	if !playerDefeated(ct.player) || ct.player.BattleTarget != 0 || ct.player.TP != 0 {
+		t.Fatalf("player HP = %d, target = %d, TP = %d", ct.player.Stats.HP, ct.player.BattleTarget, ct.player.TP)
+	}
+
+	// 8% of the 5800 points level 30 needs
+	if got := ct.player.Character.Exp.WAR; got != 5000-464 {
+		t.Fatalf("WAR exp = %d, want %d", got, 5000-464)
+	}
+
+	if got := CreateServerStatusPacket(ct.player).ServerStatus; got != serverPackets.ServerStatusDead {
+		t.Fatalf("server status = %d, want dead", got)
+	}
+
+	// the mob loses interest in the defeated player
+	ct.z.Advance(time.Second)
+	if ct.mob.State != zone.AIDisengaging {
+		t.Fatalf("mob state = %v, want %v", ct.mob.State, zone.AIDisengaging)
+	}
+
+	if err := ct.engage(); !errors.Is(err, ErrDefeated) {
+		t.Fatalf("engage while defeated error = %v, want %v", err, ErrDefeated)
+	}
+}
+
+func TestReturnToHomePoint(t *testing.T) {
+	ct := newCombatTest(t)
+
+	if err := ct.action(clientPackets.ActionHomePoint, 0, 0); !errors.Is(err, ErrNotDefeated) {
+		t.Fatalf("home point while alive error = %v, want %v", err, ErrNotDefeated)
+	}
+
+	ct.player.Stats.HP = 0
+	if err := ct.action(clientPackets.ActionHomePoint, 0, 0); err != nil {
+		t.Fatalf("home point error = %v", err)
+	}
+
+	if int32(ct.player.Stats.HP) != ct.player.Derived.MaxHP || ct.z.Player(ct.player.CharacterID) != nil {
+		t.Fatalf("player HP = %d/%d, still in zone = %v", ct.player.Stats.HP, ct.player.Derived.MaxHP, ct.z.Player(ct.player.CharacterID) != nil)
+	}
+
+	// the revived stats, then the move to the home point
+	if len(ct.s.saves) != 2 {
+		t.Fatalf("queued saves = %d, want %d", len(ct.s.saves), 2)
+	}
+
+	zoneID, position := ct.s.homePoint(ct.player, 230)
+	if zoneID != 231 || position.X != 10 {
+		t.Fatalf("homePoint() = %d, %+v, want zone 231 at X 10", zoneID, position)
+	}
+}
+
+func TestHomePointFallsBackToStartingZones(t *testing.T) {
+	s := newTestWorker()
+	s.gameData = gamedata.NewStoreFromData(&gamedata.Data{
+		StartingZones: map[uint8][]gamedata.StartingZone{
+			1: {{Nation: 1, ZoneID: 234, X: -10, Z: -42}, {Nation: 1, ZoneID: 235, X: -280, Y: -12}},
+		},
+	})
+
+	cases := []struct {
+		name      string
+		character *database.Character
+		wantZone  uint16
+		wantX     float32
+	}{
+		{"no home point", &database.Character{Nation: 1}, 234, -10},
+		{"home zone without a position", &database.Character{Nation: 1, HomeZone: 235}, 235, -280},
+		{"no starting zone for the nation", &database.Character{Nation: 2}, 230, 0},
+		{"saved home point", &database.Character{Nation: 1, HomeZone: 236, HomeX: 5}, 236, 5},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			zoneID, position := s.homePoint(&zone.Player{Character: tc.character}, 230)
+			if zoneID != tc.wantZone || position.X != tc.wantX {
+				t.Fatalf("homePoint() = %d, %+v, want zone %d at X %v", zoneID, position, tc.wantZone, tc.wantX)
+			}
+		})
+	}
+}
+
+func TestAttackPacket(t *testing.T) {
+	packet := createAttackPacket(&zone.Player{CharacterID: 0x01020304}, &zone.Mob{ID: testMobID}, combat.Swing{Hit: true, Critical: true, Damage: 1234})
+
+	data, err := packet.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+
+	if len(data) != int(packet.Size()) || len(data)%4 != 0 {
+		t.Fatalf("Serialize() = %d bytes, Size() = %d", len(data), packet.Size())
+	}
+
+	// the bit offsets count the 4-byte sub-packet header the router adds
+	data = append(make([]byte, 4), data...)
+	bits := func(offset, n int) uint64 {
+		var value uint64
+		for i := range n {
+			if data[(offset+i)/8]&(1<<((offset+i)%8)) != 0 {
+				value |= 1 << i
+			}
+		}
+
+		return value
+	}
+
+	fields := []struct {
+		name         string
+		offset, size int
+		want         uint64
+	}{
+		{"size", 32, 8, 35},
+		{"actor", 40, 32, 0x01020304},
+		{"target count", 72, 10, 1},
+		{"category", 82, 4, uint64(serverPackets.BattleCategoryAttack)},
+		{"target", 150, 32, testMobID},
+		{"result count", 182, 4, 1},
+		{"reaction", 186, 5, uint64(serverPackets.BattleReactionHit)},
+		{"effect", 203, 7, uint64(serverPackets.BattleEffectCritical)},
+		{"damage", 213, 17, 1234},
+		{"message", 230, 10, uint64(serverPackets.MessageCritical)},
+	}
+
+	for _, field := range fields {
+		if got := bits(field.offset, field.size); got != field.want {
+			t.Fatalf("%s = %d, want %d", field.name, got, field.want)
+		}
+	}
+}
+
+func TestCombatCancelsLogout(t *testing.T) {
+	ct := newCombatTest(t)
+	pctx := &PacketContext{CharacterID: ct.player.CharacterID, Zone: ct.z}
+
+	ct.s.startLogout(ct.z, ct.player, clientPackets.LogoutKindLogout)
+	if err := ct.s.handleActionPacket(pctx, &clientPackets.ActionPacket{UniqueNo: ct.mob.ID, ActIndex: ct.mob.EntityIndex(), ActionID: clientPackets.ActionEngage}); err != nil {
+		t.Fatalf("engage error = %v", err)
+	}
+
+	if ct.player.LogoutTimer != 0 {
+		t.Fatalf("LogoutTimer = %d after engaging, want the logout cancelled", ct.player.LogoutTimer)
+	}
+
+	ct.s.startLogout(ct.z, ct.player, clientPackets.LogoutKindLogout)
+	ct.s.damagePlayer(ct.z, ct.player, ct.mob, 1)
+
+	if ct.player.LogoutTimer != 0 {
+		t.Fatalf("LogoutTimer = %d after taking damage, want the logout cancelled", ct.player.LogoutTimer)
+	}
+}
+
+func TestEventsAndCombat(t *testing.T) {
+	ct := newCombatTest(t)
+	ct.s.startEvent(ct.z, ct.player, 10, nil)
+
+	// players in events cannot be fought
+	if mobCanTarget(ct.player) {
+		t.Fatal("mobCanTarget() = true for a player in an event")
+	}
+
+	ct.player.Stats.HP = 1
+	ct.s.damagePlayer(ct.z, ct.player, ct.mob, 1)
+	ct.sent = nil
+	ct.z.Step()
+
+	if !playerDefeated(ct.player) || ct.player.Event != nil {
+		t.Fatalf("HP = %d, event = %+v, want the event ended by the defeat", ct.player.Stats.HP, ct.player.Event)
+	}
+
+	if modes := releases(ct.sent); len(modes) != 1 || modes[0] != serverPackets.EventReleaseStandard {
+		t.Fatalf("releases = %v, want a standard one", modes)
+	}
+}
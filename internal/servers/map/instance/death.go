@@ -0,0 +1,112 @@
+package instance
+
+import (
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/experience"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// playerDefeated reports whether a player has been defeated (and not yet revived).
+func playerDefeated(player *zone.Player) bool {
+	return player.Stats != nil && player.Stats.HP == 0
+}
+
+// defeatMob ends a defeated mob. The character who claimed the mob earns experience
+// points, and the mob lies dead, its status effects gone, until it despawns.
+func (s *InstanceWorker) defeatMob(z *zone.Zone, mob *zone.Mob, killer zone.Entity) {
+	sendNearby(z, mob.Position, createCombatMessagePacket(killer, mob, serverPackets.MessageDefeats))
+
+	claimer := z.Player(mob.ClaimedBy)
+
+	mob.Hate.Clear()
+	mob.Target, mob.TargetIndex, mob.ClaimedBy = 0, 0, 0
+	mob.TP = 0
+	mob.Effects.Clear()
+	mob.SetState(zone.AIDespawning, z.Now())
+	mob.MarkChanged()
+
+	for _, player := range z.Players() {
+		if player.BattleTarget == mob.ID {
+			s.disengage(z, player)
+		}
+	}
+
+	if claimer != nil {
+		level, _ := playerLevels(claimer)
+		s.gainExp(z, claimer, experience.KillExp(level, mob.Level))
+	}
+}
+
+// defeatPlayer handles a player falling in battle: the fight ends, the status effects
+// lost on death wear off, experience points are lost, and the client, seeing its
+// character dead, offers to return to the home point.
+func (s *InstanceWorker) defeatPlayer(z *zone.Zone, player *zone.Player, killer zone.Entity) {
+	sendNearby(z, player.Position, createCombatMessagePacket(killer, player, serverPackets.MessageFallsToGround))
+
+	// the dead do not talk; the client would be left waiting on the event
+	s.abortEvent(z, player, "defeated")
+
+	player.BattleTarget = 0
+	player.TP = 0
+	player.MarkChanged()
+
+	if lost := player.Effects.RemoveFlagged(effects.FlagDeath); len(lost) > 0 {
+		s.playerEffectsChanged(z, player)
+	} else {
+		sendPackets(z, player.ClientAddr, CreateServerStatusPacket(player))
+	}
+
+	level, _ := playerLevels(player)
+	s.loseExp(z, player, experience.DeathExpLoss(level))
+}
+
+// returnToHomePoint revives a defeated player with full HP and MP at its home point.
+func (s *InstanceWorker) returnToHomePoint(z *zone.Zone, player *zone.Player) error {
+	if !playerDefeated(player) {
+		return ErrNotDefeated
+	}
+
+	player.Stats.HP = uint16(max(player.Derived.MaxHP, 1)) //nolint:gosec // the maximum HP fits the stored HP
+	player.Stats.MP = uint16(max(player.Derived.MaxMP, 0)) //nolint:gosec // the maximum MP fits the stored MP
+	player.StatsDirty = true
+
+	zoneID, position := s.homePoint(player, z.ID())
+	s.changeZone(z, player, zoneID, position)
+
+	return nil
+}
+
+// homePoint returns where a player returns to when defeated. Characters without a
+// home point, or with one saved without its position, return to a starting zone of
+// their nation: their home zone (or else the current one) if it is one, or else the first.
+func (s *InstanceWorker) homePoint(player *zone.Player, currentZoneID uint16) (uint16, zone.Position) {
+	character := player.Character
+	if character != nil && character.HomeZone != 0 && (character.HomeX != 0 || character.HomeY != 0 || character.HomeZ != 0) {
+		return character.HomeZone, zone.Position{X: character.HomeX, Y: character.HomeY, Z: character.HomeZ, Rotation: character.HomeRot}
+	}
+
+	var nation uint8
+	homeZoneID := currentZoneID
+	if character != nil {
+		nation = character.Nation
+		if character.HomeZone != 0 {
+			homeZoneID = character.HomeZone
+		}
+	}
+
+	startingZones := s.gameData.Current().StartingZones[nation]
+	if len(startingZones) == 0 {
+		return homeZoneID, zone.Position{}
+	}
+
+	startingZone := startingZones[0]
+	for _, candidate := range startingZones {
+		if candidate.ZoneID == homeZoneID {
+			startingZone = candidate
+			break
+		}
+	}
+
+	return startingZone.ZoneID, zone.Position{X: startingZone.X, Y: startingZone.Y, Z: startingZone.Z, Rotation: startingZone.Rotation}
+}
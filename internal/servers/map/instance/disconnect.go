@@ -0,0 +1,51 @@
+package instance
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// ProcessDisconnect handles the router dropping a client that stopped responding. The
+// character is persisted right away but lingers in the world for a little while.
+func (s *InstanceWorker) ProcessDisconnect(msg *nats.Msg) {
+	var disconnect mapPackets.ClientDisconnect
+	if err := json.Unmarshal(msg.Data, &disconnect); err != nil {
+		s.Logger().Error("failed to unmarshal client disconnect; discarding", "error", err)
+		return
+	}
+
+	z := s.zoneForCharacter(disconnect.CharacterID)
+	if z == nil {
+		return
+	}
+
+	err := z.Post(func(z *zone.Zone) {
+		player := z.Player(disconnect.CharacterID)
+		if player == nil || player.ClientAddr != disconnect.ClientAddr {
+			return
+		}
+
+		s.Logger().Info("client disconnected", "characterID", player.CharacterID, "clientAddr", player.ClientAddr)
+
+		s.cancelLogout(z, player, "disconnected")
+		player.Disconnected = true
+		s.savePlayer(z, player)
+
+		linger := time.Duration(s.Config().MapDisconnectLingerSeconds) * time.Second
+		z.After(linger, func(z *zone.Zone) {
+			// the character may have logged in again in the meantime
+			if z.Player(player.CharacterID) == player {
+				s.leaveGame(z, player, serverPackets.LogoutStateLogout)
+			}
+		})
+	})
+	if err != nil {
+		s.Logger().Warn("dropping client disconnect", "characterID", disconnect.CharacterID, "error", err)
+	}
+}
@@ -0,0 +1,250 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	// itemLockEquipped marks an item as equipped in the item packets
+	itemLockEquipped uint8 = 0x05
+
+	// the models shown for empty body, hands, legs and feet slots
+	nakedBodyModel uint16 = 8
+)
+
+var (
+	ErrNotEquipment   = errors.New("item cannot be equipped")
+	ErrWrongEquipSlot = errors.New("item does not fit in this slot")
+	ErrWrongJob       = errors.New("item cannot be equipped by this job")
+	ErrLevelTooLow    = errors.New("level too low to equip item")
+	ErrWrongRace      = errors.New("item cannot be equipped by this race")
+)
+
+// loadEquipment equips the items a character had equipped when it last left the game.
+func (s *InstanceWorker) loadEquipment(ctx context.Context, characterID uint32, inv *inventory.Inventory) error {
+	equipment, err := s.DB().GetCharacterEquipment(ctx, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to load equipment: %w", err)
+	}
+
+	for _, equipped := range equipment {
+		location := inventory.Location{Container: serverPackets.ContainerKind(equipped.Container), Slot: equipped.Slot}
+		if err := inv.Equip(serverPackets.EquipKind(equipped.EquipSlot), location); err != nil {
+			s.Logger().Warn("skipping invalid equipment", "characterID", characterID, "equipSlot", equipped.EquipSlot, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// checkEquip validates that a character can equip an item in a slot.
+func checkEquip(equipment map[uint16]gamedata.Equipment, itemID uint16, slot serverPackets.EquipKind, job, level, race uint8) error {
+	definition, ok := equipment[itemID]
+	switch {
+	case !ok:
+		return fmt.Errorf("%w: item %d", ErrNotEquipment, itemID)
+	case !definition.AllowsSlot(uint8(slot)):
+		return fmt.Errorf("%w: item %d, slot %d", ErrWrongEquipSlot, itemID, slot)
+	case !definition.AllowsJob(job):
+		return fmt.Errorf("%w: item %d, job %d", ErrWrongJob, itemID, job)
+	case level < definition.Level:
+		return fmt.Errorf("%w: item %d needs level %d, has %d", ErrLevelTooLow, itemID, definition.Level, level)
+	case !definition.AllowsRace(race):
+		return fmt.Errorf("%w: item %d, race %d", ErrWrongRace, itemID, race)
+	}
+
+	return nil
+}
+
+// equipItem equips the item at a location in an equipment slot, after checking the
+// character's job, level and race can use it.
+func (s *InstanceWorker) equipItem(z *zone.Zone, player *zone.Player, slot serverPackets.EquipKind, location inventory.Location) error {
+	inv := player.Inventory
+
+	item, ok := inv.Get(location)
+	if !ok {
+		return fmt.Errorf("%w: container %d slot %d", inventory.ErrEmptySlot, location.Container, location.Slot)
+	}
+
+	var job, race uint8
+	if player.Stats != nil {
+		job = player.Stats.MainJob
+	}
+
+	if player.Looks != nil {
+		race = player.Looks.Race
+	}
+
+	if err := checkEquip(s.gameData.Current().Equipment, item.ID, slot, job, player.Character.GetMainJobLevel(), race); err != nil {
+		return err
+	}
+
+	if current, ok := inv.Equipped(slot); ok && current == location {
+		return nil
+	}
+
+	previous, hadPrevious := inv.Unequip(slot)
+	if err := inv.Equip(slot, location); err != nil {
+		if hadPrevious {
+			_ = inv.Equip(slot, previous)
+		}
+
+		return err
+	}
+
+	changed := []inventory.Location{location}
+	if hadPrevious {
+		changed = append(changed, previous)
+	}
+
+	s.sendEquipmentChange(z, player, slot, changed)
+	return nil
+}
+
+// unequipItem empties an equipment slot.
+func (s *InstanceWorker) unequipItem(z *zone.Zone, player *zone.Player, slot serverPackets.EquipKind) {
+	previous, ok := player.Inventory.Unequip(slot)
+	if !ok {
+		return
+	}
+
+	s.sendEquipmentChange(z, player, slot, []inventory.Location{previous})
+}
+
+// unequipUnusable takes off the equipment the character can no longer use, after its
+// job or level changed.
+func (s *InstanceWorker) unequipUnusable(z *zone.Zone, player *zone.Player) {
+	if player.Inventory == nil {
+		return
+	}
+
+	var race uint8
+	if player.Looks != nil {
+		race = player.Looks.Race
+	}
+
+	for slot := serverPackets.EquipKind(0); slot < serverPackets.EquipKindEnd; slot++ {
+		location, ok := player.Inventory.Equipped(slot)
+		if !ok {
+			continue
+		}
+
+		item, _ := player.Inventory.Get(location)
+		if err := checkEquip(s.gameData.Current().Equipment, item.ID, slot, player.Stats.MainJob, player.Character.GetMainJobLevel(), race); err != nil {
+			s.unequipItem(z, player, slot)
+		}
+	}
+}
+
+// sendEquipmentChange tells the client about a changed equipment slot (and the items that
+// got locked or unlocked by it), then persists the slot and the character's new looks.
+func (s *InstanceWorker) sendEquipmentChange(z *zone.Zone, player *zone.Player, slot serverPackets.EquipKind, changed []inventory.Location) {
+	for _, location := range changed {
+		if item, ok := player.Inventory.Get(location); ok {
+			z.Send(player.ClientAddr, createItemAttrPacket(player.Inventory, location, item))
+		}
+	}
+
+	location, equipped := player.Inventory.Equipped(slot)
+	z.Send(player.ClientAddr, createEquipListPacket(slot, location, equipped))
+
+	characterID := player.CharacterID
+	s.queueSave("equipment", characterID, func(ctx context.Context) error {
+		if !equipped {
+			return s.DB().DeleteCharacterEquipment(ctx, characterID, uint8(slot))
+		}
+
+		return s.DB().SaveCharacterEquipment(ctx, &database.CharacterEquipment{
+			CharacterID: characterID,
+			EquipSlot:   uint8(slot),
+			Container:   uint8(location.Container),
+			Slot:        location.Slot,
+		})
+	})
+
+	s.refreshLooks(z, player)
+	s.updateStats(z, player)
+}
+
+// refreshLooks updates the models of the character from its equipment, showing them to
+// everyone and persisting them for the lobby's character list.
+func (s *InstanceWorker) refreshLooks(z *zone.Zone, player *zone.Player) {
+	if player.Looks == nil {
+		return
+	}
+
+	looks := *player.Looks
+	applyEquipmentLooks(&looks, player.Inventory, s.gameData.Current().Equipment)
+	if looks == *player.Looks {
+		return
+	}
+
+	*player.Looks = looks
+	player.MarkChanged()
+	z.Send(player.ClientAddr, CreateGrapListPacket(player.Looks))
+
+	s.queueSave("looks", player.CharacterID, func(ctx context.Context) error {
+		_, err := s.DB().UpdateCharacterLooks(ctx, &looks)
+		return err
+	})
+}
+
+// applyEquipmentLooks sets the visible models of looks from the items equipped in an inventory.
+func applyEquipmentLooks(looks *database.CharacterLooks, inv *inventory.Inventory, equipment map[uint16]gamedata.Equipment) {
+	model := func(slot serverPackets.EquipKind, empty uint16) uint16 {
+		location, ok := inv.Equipped(slot)
+		if !ok {
+			return empty
+		}
+
+		item, _ := inv.Get(location)
+		if definition, ok := equipment[item.ID]; ok {
+			return definition.ModelID
+		}
+
+		return empty
+	}
+
+	looks.Head = model(serverPackets.EquipKindHead, 0)
+	looks.Body = model(serverPackets.EquipKindBody, nakedBodyModel)
+	looks.Hands = model(serverPackets.EquipKindHands, nakedBodyModel)
+	looks.Legs = model(serverPackets.EquipKindLegs, nakedBodyModel)
+	looks.Feet = model(serverPackets.EquipKindFeet, nakedBodyModel)
+	looks.Main = model(serverPackets.EquipKindMain, 0)
+	looks.Sub = model(serverPackets.EquipKindSub, 0)
+	looks.Ranged = model(serverPackets.EquipKindRanged, 0)
+}
+
+// CreateEquipListPackets builds the packets telling the client what is equipped in every slot.
+func CreateEquipListPackets(inv *inventory.Inventory) []*serverPackets.EquipListPacket {
+	packets := make([]*serverPackets.EquipListPacket, 0, serverPackets.EquipKindEnd)
+	for slot := serverPackets.EquipKindMain; slot < serverPackets.EquipKindEnd; slot++ {
+		if location, ok := inv.Equipped(slot); ok {
+			packets = append(packets, createEquipListPacket(slot, location, true))
+		}
+	}
+
+	return packets
+}
+
+func createEquipListPacket(slot serverPackets.EquipKind, location inventory.Location, equipped bool) *serverPackets.EquipListPacket {
+	packet := &serverPackets.EquipListPacket{EquipKind: slot}
+	if equipped {
+		packet.PropertyItemIndex = location.Slot
+		packet.Category = uint8(location.Container)
+	}
+
+	return packet
+}
+
+func CreateGrapListPacket(looks *database.CharacterLooks) *serverPackets.GrapListPacket {
+	return &serverPackets.GrapListPacket{GrapIDTbl: buildCharacterGrapIDs(looks)}
+}
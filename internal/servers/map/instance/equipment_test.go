@@ -0,0 +1,70 @@
+package instance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+)
+
+const bronzeCap uint16 = 12448
+
+func testEquipment() map[uint16]gamedata.Equipment {
+	return map[uint16]gamedata.Equipment{
+		// head, WAR and MNK, level 10, Hume and Elvaan males only
+		bronzeCap: {ItemID: bronzeCap, Level: 10, Jobs: 0b11, ModelID: 64, Slots: 1 << serverPackets.EquipKindHead, Races: 0b101},
+	}
+}
+
+func TestCheckEquip(t *testing.T) {
+	cases := []struct {
+		name  string
+		item  uint16
+		slot  serverPackets.EquipKind
+		job   uint8
+		level uint8
+		race  uint8
+		want  error
+	}{
+		{"allowed", bronzeCap, serverPackets.EquipKindHead, 2, 10, 3, nil},
+		{"unknown item", 1, serverPackets.EquipKindHead, 1, 10, 1, ErrNotEquipment},
+		{"wrong slot", bronzeCap, serverPackets.EquipKindBody, 1, 10, 1, ErrWrongEquipSlot},
+		{"wrong job", bronzeCap, serverPackets.EquipKindHead, 3, 10, 1, ErrWrongJob},
+		{"level too low", bronzeCap, serverPackets.EquipKindHead, 1, 9, 1, ErrLevelTooLow},
+		{"wrong race", bronzeCap, serverPackets.EquipKindHead, 1, 10, 2, ErrWrongRace},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkEquip(testEquipment(), tc.item, tc.slot, tc.job, tc.level, tc.race)
+			if !errors.Is(err, tc.want) || (tc.want == nil && err != nil) {
+				t.Fatalf("checkEquip() error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyEquipmentLooks(t *testing.T) {
+	inv := inventory.New(inventory.DefaultSizes)
+	location := inventory.Location{Container: serverPackets.ContainerKindInventory, Slot: 1}
+	if err := inv.Set(location, inventory.Item{ID: bronzeCap, Quantity: 1}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if err := inv.Equip(serverPackets.EquipKindHead, location); err != nil {
+		t.Fatalf("Equip() error = %v", err)
+	}
+
+	looks := database.CharacterLooks{Head: 1, Body: 2}
+	applyEquipmentLooks(&looks, inv, testEquipment())
+	if looks.Head != 64 || looks.Body != nakedBodyModel || looks.Main != 0 {
+		t.Fatalf("applyEquipmentLooks() = %+v", looks)
+	}
+
+	if packets := CreateEquipListPackets(inv); len(packets) != 1 || packets[0].PropertyItemIndex != 1 {
+		t.Fatalf("CreateEquipListPackets() = %+v", packets)
+	}
+}
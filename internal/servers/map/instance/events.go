@@ -0,0 +1,329 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/scripting"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	// npcTalkRange is how far from an NPC players can talk to it; the client asks from
+	// closer, the rest absorbs position updates lagging behind
+	npcTalkRange = 10.0
+
+	// npcScriptPrefix starts the script module of an NPC, named after it ("npcs/Rycharde")
+	npcScriptPrefix = "npcs/"
+
+	// homePointNPC is the name of the crystals players set their home point at
+	homePointNPC = "Home_Point"
+
+	homePointSetMessage = "Your home point is set to this location."
+)
+
+var (
+	ErrInEvent       = errors.New("character is in an event")
+	ErrNotInEvent    = errors.New("character is not in an event")
+	ErrEventMismatch = errors.New("not the event the character is in")
+	ErrTooFarAway    = errors.New("target is too far away")
+)
+
+// npcHandler handles an NPC in Go rather than in a script. The functions left nil do
+// nothing.
+type npcHandler struct {
+	trigger     func(z *zone.Zone, player *zone.Player, npc gamedata.NPC)
+	eventUpdate func(z *zone.Zone, player *zone.Player, npc gamedata.NPC, eventID uint16, option uint32)
+	eventFinish func(z *zone.Zone, player *zone.Player, npc gamedata.NPC, eventID uint16, option uint32)
+}
+
+// registerNPCHandlers builds the table of the NPCs handled in Go, by NPC name. Every
+// other NPC runs its script.
+func (s *InstanceWorker) registerNPCHandlers() {
+	s.npcHandlers = map[string]npcHandler{
+		homePointNPC: {trigger: s.setHomePoint},
+	}
+}
+
+// spawnZoneNPCs places the zone's NPCs. They stay spawned for as long as the zone runs;
+// game data reloads do not move them.
+func (s *InstanceWorker) spawnZoneNPCs(z *zone.Zone) {
+	for _, npc := range s.gameData.Current().NPCs {
+		if npc.Zone() != z.ID() {
+			continue
+		}
+
+		z.AddNPC(&zone.NPC{
+			ID:        npc.ID,
+			Index:     npc.Index(),
+			Name:      npc.Name,
+			ModelID:   npc.ModelID,
+			Position:  zone.Position{X: npc.X, Y: npc.Y, Z: npc.Z, Rotation: npc.Rotation},
+			Flags:     npc.Flags,
+			Animation: npc.Animation,
+		})
+	}
+}
+
+// setHomePoint makes where a player stands at a home point crystal the place it returns
+// to when defeated.
+func (s *InstanceWorker) setHomePoint(z *zone.Zone, player *zone.Player, _ gamedata.NPC) {
+	if player.Character == nil {
+		return
+	}
+
+	characterID := player.CharacterID
+	zoneID := z.ID()
+	position := player.Position
+
+	player.Character.HomeZone = zoneID
+	player.Character.HomeX = position.X
+	player.Character.HomeY = position.Y
+	player.Character.HomeZ = position.Z
+	player.Character.HomeRot = position.Rotation
+	z.Send(player.ClientAddr, createSystemChatPacket(homePointSetMessage))
+
+	s.queueSave("home point", characterID, func(ctx context.Context) error {
+		return s.DB().UpdateCharacterHomePoint(ctx, characterID, zoneID, position.X, position.Y, position.Z, position.Rotation)
+	})
+}
+
+// zoneNPC returns the NPC of the zone with the given entity ID and target index. Only
+// the NPCs spawned in the zone, which clients were sent, can be found.
+func (s *InstanceWorker) zoneNPC(z *zone.Zone, npcID uint32, npcIndex uint16) (gamedata.NPC, bool) {
+	if spawned := z.NPC(npcID); spawned == nil || spawned.Index != npcIndex {
+		return gamedata.NPC{}, false
+	}
+
+	npc, ok := s.gameData.Current().NPCs[npcID]
+	if !ok || npc.Zone() != z.ID() || npc.Index() != npcIndex {
+		return gamedata.NPC{}, false
+	}
+
+	return npc, true
+}
+
+// talk makes a player talk to an NPC, which runs the NPC's handler. The client waits
+// for an event or to be released, so it is released whenever no event starts.
+func (s *InstanceWorker) talk(z *zone.Zone, player *zone.Player, npcID uint32, npcIndex uint16) error {
+	if playerDefeated(player) {
+		return ErrDefeated
+	}
+
+	npc, ok := s.zoneNPC(z, npcID, npcIndex)
+	if !ok {
+		releasePlayer(z, player, serverPackets.EventReleaseStandard)
+		return fmt.Errorf("%w: %d", ErrInvalidTarget, npcID)
+	}
+
+	npcPosition := zone.Position{X: npc.X, Y: npc.Y, Z: npc.Z}
+	if player.Position.HorizontalDistance(npcPosition) > npcTalkRange {
+		releasePlayer(z, player, serverPackets.EventReleaseStandard)
+		return fmt.Errorf("%w: NPC %d", ErrTooFarAway, npcID)
+	}
+
+	s.withNPC(player, npc, func() {
+		if handler, ok := s.npcHandlers[npc.Name]; ok {
+			if handler.trigger != nil {
+				handler.trigger(z, player, npc)
+			}
+
+			return
+		}
+
+		s.callScript(z, npcScriptPrefix+npc.Name, "onTrigger", scripting.Player{CharacterID: player.CharacterID}, scripting.NPC{ID: npc.ID})
+	})
+
+	if player.Event == nil {
+		releasePlayer(z, player, serverPackets.EventReleaseStandard)
+	}
+
+	return nil
+}
+
+// withNPC runs fn with the player talking to the NPC, so the events fn starts are with it.
+func (s *InstanceWorker) withNPC(player *zone.Player, npc gamedata.NPC, fn func()) {
+	talkingTo := player.TalkingTo
+	player.TalkingTo = npc.ID
+	defer func() { player.TalkingTo = talkingTo }()
+
+	fn()
+}
+
+// startEvent starts an event for a player, with the NPC it is talking to or, outside of
+// an NPC's handler, with the player itself (the way cutscenes are started). Players are
+// in one event at a time: it reports false if the player is in one already.
+func (s *InstanceWorker) startEvent(z *zone.Zone, player *zone.Player, eventID uint16, params []uint32) bool {
+	if player.Event != nil {
+		return false
+	}
+
+	event := &zone.PlayerEvent{ID: eventID, NPCID: player.CharacterID, NPCIndex: player.ActIndex}
+	if player.TalkingTo != 0 {
+		event.NPCID = player.TalkingTo
+		event.NPCIndex = gamedata.EntityIndex(player.TalkingTo)
+	}
+
+	player.Event = event
+	s.armEventTimeout(z, player)
+	sendPackets(z, player.ClientAddr, createEventPacket(z, event, params))
+
+	return true
+}
+
+// armEventTimeout (re)starts the timer ending the player's event if the client stops
+// answering, e.g. because the packet starting it was lost.
+func (s *InstanceWorker) armEventTimeout(z *zone.Zone, player *zone.Player) {
+	event := player.Event
+	z.Cancel(event.Timeout)
+	event.Timeout = 0
+
+	if s.Config().MapEventTimeoutSeconds <= 0 {
+		return
+	}
+
+	event.Timeout = z.After(time.Duration(s.Config().MapEventTimeoutSeconds)*time.Second, func(z *zone.Zone) {
+		event.Timeout = 0
+		if player.Event == event && z.Player(player.CharacterID) == player {
+			s.abortEvent(z, player, "timed out")
+		}
+	})
+}
+
+// clearEvent ends the event a player is in, without running its handlers.
+func clearEvent(z *zone.Zone, player *zone.Player) {
+	if player.Event == nil {
+		return
+	}
+
+	z.Cancel(player.Event.Timeout)
+	player.Event = nil
+}
+
+// abortEvent ends the event a player is in on the server's side, and lets the client go.
+func (s *InstanceWorker) abortEvent(z *zone.Zone, player *zone.Player, reason string) {
+	if player.Event == nil {
+		return
+	}
+
+	s.Logger().Info("ending event", "characterID", player.CharacterID, "eventID", player.Event.ID, "reason", reason)
+
+	clearEvent(z, player)
+	releasePlayer(z, player, serverPackets.EventReleaseStandard)
+}
+
+// updateEvent sends new parameters to the event a player is in, reporting false if it
+// is not in one.
+func updateEvent(z *zone.Zone, player *zone.Player, params []uint32) bool {
+	if player.Event == nil {
+		return false
+	}
+
+	packet := &serverPackets.PendingNumPacket{}
+	copy(packet.Num[:], params)
+	sendPackets(z, player.ClientAddr, packet)
+
+	return true
+}
+
+// endEvent handles the update request or the end of the event a player is in. The
+// client has to name the event the server started, with the entity it is with.
+func (s *InstanceWorker) endEvent(z *zone.Zone, player *zone.Player, eventID uint16, npcID uint32, npcIndex uint16, option uint32, finish bool) error {
+	event := player.Event
+	if event == nil {
+		return fmt.Errorf("%w: event %d", ErrNotInEvent, eventID)
+	}
+
+	if event.ID != eventID || event.NPCID != npcID || event.NPCIndex != npcIndex {
+		return fmt.Errorf("%w: event %d with %d, in event %d with %d", ErrEventMismatch, eventID, npcID, event.ID, event.NPCID)
+	}
+
+	if !finish {
+		// the client is still there
+		s.armEventTimeout(z, player)
+
+		s.dispatchEvent(z, player, *event, "onEventUpdate", option)
+		releasePlayer(z, player, serverPackets.EventReleaseEvent)
+
+		return nil
+	}
+
+	// the event is over before its handler runs, which may start the next one
+	clearEvent(z, player)
+	s.dispatchEvent(z, player, *event, "onEventFinish", option)
+
+	if player.Event == nil {
+		releasePlayer(z, player, serverPackets.EventReleaseStandard)
+	}
+
+	return nil
+}
+
+// dispatchEvent runs the handler of an event's update or finish: that of the NPC the
+// event is with, or the zone's script for cutscenes.
+func (s *InstanceWorker) dispatchEvent(z *zone.Zone, player *zone.Player, event zone.PlayerEvent, function string, option uint32) {
+	scriptPlayer := scripting.Player{CharacterID: player.CharacterID}
+
+	if event.NPCID == player.CharacterID {
+		s.callScript(z, zoneScriptModule, function, scriptPlayer, event.ID, option)
+		return
+	}
+
+	// the NPC may have been removed by a game data reload since the event started
+	npc, ok := s.gameData.Current().NPCs[event.NPCID]
+	if !ok {
+		return
+	}
+
+	s.withNPC(player, npc, func() {
+		handler, ok := s.npcHandlers[npc.Name]
+		if !ok {
+			s.callScript(z, npcScriptPrefix+npc.Name, function, scriptPlayer, event.ID, option, scripting.NPC{ID: npc.ID})
+			return
+		}
+
+		run := handler.eventUpdate
+		if function == "onEventFinish" {
+			run = handler.eventFinish
+		}
+
+		if run != nil {
+			run(z, player, npc, event.ID, option)
+		}
+	})
+}
+
+// releasePlayer frees a client waiting on the server.
+func releasePlayer(z *zone.Zone, player *zone.Player, mode uint8) {
+	sendPackets(z, player.ClientAddr, &serverPackets.EventReleasePacket{Mode: mode})
+}
+
+// createEventPacket builds the packet starting an event, with parameters or without.
+func createEventPacket(z *zone.Zone, event *zone.PlayerEvent, params []uint32) serverPackets.ServerPacket {
+	if len(params) == 0 {
+		return &serverPackets.EventPacket{
+			UniqueNo:  event.NPCID,
+			ActIndex:  event.NPCIndex,
+			EventNum:  z.ID(),
+			EventPara: event.ID,
+			Mode:      serverPackets.EventModeDefault,
+			EventNum2: z.ID(),
+		}
+	}
+
+	packet := &serverPackets.EventNumPacket{
+		UniqueNo:  event.NPCID,
+		ActIndex:  event.NPCIndex,
+		EventNum:  z.ID(),
+		EventPara: event.ID,
+		Mode:      serverPackets.EventModeDefault,
+		EventNum2: z.ID(),
+	}
+	copy(packet.Num[:], params)
+
+	return packet
+}
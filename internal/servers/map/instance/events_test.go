@@ -0,0 +1,357 @@
+package instance
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	testGuardID     = 0x010E6001
+	testMoogleID    = 0x010E6002
+	testHomePointID = 0x010E6003
+)
+
+// testNPCs places a guard, a moogle and a home point crystal next to the origin of zone
+// 230, and a fourth NPC of another zone.
+func testNPCs() map[uint32]gamedata.NPC {
+	return map[uint32]gamedata.NPC{
+		testGuardID:     {ID: testGuardID, Name: "Guard", X: 2},
+		testMoogleID:    {ID: testMoogleID, Name: "Moogle", Z: 2},
+		testHomePointID: {ID: testHomePointID, Name: homePointNPC, X: -2},
+		0x010F6001:      {ID: 0x010F6001, Name: "Elsewhere"},
+	}
+}
+
+const testGuardScript = `
+	function onTrigger(player, npc)
+		player:startEvent(10, npc:getID(), 5)
+	end
+
+	function onEventUpdate(player, eventID, option, npc)
+		player:updateEvent(option * 2)
+	end
+
+	function onEventFinish(player, eventID, option, npc)
+		player:setCharVar("guard", option)
+	end
+`
+
+func (st *scriptTest) talk(npcID uint32) error {
+	packet := &clientPackets.ActionPacket{UniqueNo: npcID, ActIndex: gamedata.EntityIndex(npcID), ActionID: clientPackets.ActionTalk}
+	return st.s.handleActionPacket(&PacketContext{CharacterID: st.player.CharacterID, Zone: st.z}, packet)
+}
+
+func (st *scriptTest) endEvent(npcID uint32, npcIndex, eventID, mode uint16, option uint32) error {
+	packet := &clientPackets.EventEndPacket{
+		UniqueNo:  npcID,
+		ActIndex:  npcIndex,
+		EventNum:  st.z.ID(),
+		EventPara: eventID,
+		Mode:      mode,
+		EndPara:   option,
+	}
+
+	return st.s.handleEventEndPacket(&PacketContext{CharacterID: st.player.CharacterID, Zone: st.z}, packet)
+}
+
+func (st *scriptTest) move(x float32, rotation uint8) error {
+	packet := &clientPackets.PositionPacket{PosX: x, Direction: rotation}
+	return st.s.handlePositionPacket(&PacketContext{CharacterID: st.player.CharacterID, Zone: st.z}, packet)
+}
+
+// flush sends the zone's buffered packets and returns them.
+func (st *scriptTest) flush() []serverPackets.ServerPacket {
+	st.sent = nil
+	st.z.Step()
+
+	return st.sent
+}
+
+// releases returns the modes of the release packets among packets.
+func releases(packets []serverPackets.ServerPacket) []uint8 {
+	var modes []uint8
+	for _, packet := range packets {
+		if release, ok := packet.(*serverPackets.EventReleasePacket); ok {
+			modes = append(modes, release.Mode)
+		}
+	}
+
+	return modes
+}
+
+func TestTalkToScriptedNPC(t *testing.T) {
+	st := newScriptTest(t, map[string]string{"npcs/Guard.lua": testGuardScript})
+
+	// the client is sent the NPCs around the player before it can talk to them
+	var spawn *serverPackets.CharNPCPacket
+	for _, packet := range st.flush() {
+		if p, ok := packet.(*serverPackets.CharNPCPacket); ok && p.UniqueNo == testGuardID {
+			spawn = p
+		}
+	}
+
+	if spawn == nil || spawn.ActIndex != 1 || spawn.PosX != 2 || spawn.SendFlg&serverPackets.CharUpdateFlagDespawn != 0 {
+		t.Fatalf("guard spawn packet = %+v, want the guard spawned", spawn)
+	}
+
+	if err := st.talk(testGuardID); err != nil {
+		t.Fatalf("talk error = %v", err)
+	}
+
+	if st.player.Event == nil || st.player.Event.ID != 10 || st.player.Event.NPCID != testGuardID || st.player.TalkingTo != 0 {
+		t.Fatalf("event = %+v, talking to %d, want event 10 with the guard", st.player.Event, st.player.TalkingTo)
+	}
+
+	var event *serverPackets.EventNumPacket
+	for _, packet := range st.flush() {
+		if p, ok := packet.(*serverPackets.EventNumPacket); ok {
+			event = p
+		}
+	}
+
+	if event == nil || event.UniqueNo != testGuardID || event.ActIndex != 1 || event.EventPara != 10 || event.Num[0] != testGuardID || event.Num[1] != 5 {
+		t.Fatalf("event packet = %+v, want event 10 with the guard", event)
+	}
+
+	// the player is held in place and cannot act, but may turn
+	if err := st.move(3, 0); !errors.Is(err, ErrInEvent) {
+		t.Fatalf("moving during an event error = %v, want ErrInEvent", err)
+	}
+
+	if err := st.move(0, 64); err != nil || st.player.Position.Rotation != 64 {
+		t.Fatalf("turning during an event = %v, rotation %d", err, st.player.Position.Rotation)
+	}
+
+	if err := st.talk(testMoogleID); !errors.Is(err, ErrInEvent) {
+		t.Fatalf("talking during an event error = %v, want ErrInEvent", err)
+	}
+
+	if err := st.endEvent(testGuardID, 1, 10, clientPackets.EventEndModeUpdate, 4); err != nil {
+		t.Fatalf("event update error = %v", err)
+	}
+
+	var pending *serverPackets.PendingNumPacket
+	sent := st.flush()
+	for _, packet := range sent {
+		if p, ok := packet.(*serverPackets.PendingNumPacket); ok {
+			pending = p
+		}
+	}
+
+	if pending == nil || pending.Num[0] != 8 {
+		t.Fatalf("update packet = %+v, want param 8", pending)
+	}
+
+	if modes := releases(sent); len(modes) != 1 || modes[0] != serverPackets.EventReleaseEvent {
+		t.Fatalf("releases after an update = %v, want the event's", modes)
+	}
+
+	if err := st.endEvent(testGuardID, 1, 10, clientPackets.EventEndModeFinish, 3); err != nil {
+		t.Fatalf("event finish error = %v", err)
+	}
+
+	if st.player.Event != nil || st.player.Vars["guard"] != 3 {
+		t.Fatalf("after finishing, event = %+v, vars = %v", st.player.Event, st.player.Vars)
+	}
+
+	if modes := releases(st.flush()); len(modes) != 1 || modes[0] != serverPackets.EventReleaseStandard {
+		t.Fatalf("releases after finishing = %v, want a standard one", modes)
+	}
+
+	if err := st.move(3, 0); err != nil {
+		t.Fatalf("moving after the event error = %v", err)
+	}
+}
+
+func TestEventEndRejected(t *testing.T) {
+	st := newScriptTest(t, map[string]string{"npcs/Guard.lua": testGuardScript})
+
+	// events that were never started cannot be finished
+	if err := st.endEvent(testGuardID, 1, 10, clientPackets.EventEndModeFinish, 3); !errors.Is(err, ErrNotInEvent) {
+		t.Fatalf("finishing without an event error = %v, want ErrNotInEvent", err)
+	}
+
+	if err := st.talk(testGuardID); err != nil {
+		t.Fatalf("talk error = %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		npcID    uint32
+		npcIndex uint16
+		eventID  uint16
+		mode     uint16
+		want     error
+	}{
+		{"another event", testGuardID, 1, 11, clientPackets.EventEndModeFinish, ErrEventMismatch},
+		{"another NPC", testMoogleID, 2, 10, clientPackets.EventEndModeFinish, ErrEventMismatch},
+		{"another target index", testGuardID, 2, 10, clientPackets.EventEndModeUpdate, ErrEventMismatch},
+		{"unknown mode", testGuardID, 1, 10, 7, ErrUnsupportedEventMode},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := st.endEvent(tc.npcID, tc.npcIndex, tc.eventID, tc.mode, 3); !errors.Is(err, tc.want) {
+				t.Fatalf("endEvent() error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	if st.player.Event == nil || st.player.Vars["guard"] != 0 {
+		t.Fatalf("event = %+v, vars = %v, want the event still running", st.player.Event, st.player.Vars)
+	}
+}
+
+func TestTalkRejected(t *testing.T) {
+	st := newScriptTest(t, map[string]string{"npcs/Guard.lua": testGuardScript})
+
+	cases := []struct {
+		name  string
+		npcID uint32
+		want  error
+	}{
+		{"unknown NPC", 0x010E6099, ErrInvalidTarget},
+		{"NPC of another zone", 0x010F6001, ErrInvalidTarget},
+		{"NPC without a handler", testMoogleID, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := st.talk(tc.npcID); !errors.Is(err, tc.want) {
+				t.Fatalf("talk error = %v, want %v", err, tc.want)
+			}
+
+			// the client waits on the server either way
+			if modes := releases(st.flush()); len(modes) != 1 || modes[0] != serverPackets.EventReleaseStandard {
+				t.Fatalf("releases = %v, want a standard one", modes)
+			}
+		})
+	}
+
+	st.player.Position = zone.Position{X: 50}
+	if err := st.talk(testGuardID); !errors.Is(err, ErrTooFarAway) || st.player.Event != nil {
+		t.Fatalf("talking from afar = %v, event %+v, want ErrTooFarAway", err, st.player.Event)
+	}
+}
+
+func TestNPCHandler(t *testing.T) {
+	st := newScriptTest(t, map[string]string{
+		// the NPC's Go handler takes precedence over its script
+		"npcs/Moogle.lua": `function onTrigger(player) player:setCharVar("script", 1) end`,
+		"Zone.lua": `
+			function onEventFinish(player, eventID, option)
+				player:setCharVar("cutscene", eventID)
+			end
+		`,
+	})
+
+	var finished []uint32
+	st.s.npcHandlers = map[string]npcHandler{
+		"Moogle": {
+			trigger: func(z *zone.Zone, player *zone.Player, _ gamedata.NPC) {
+				st.s.startEvent(z, player, 20, nil)
+			},
+			eventFinish: func(z *zone.Zone, player *zone.Player, _ gamedata.NPC, _ uint16, option uint32) {
+				finished = append(finished, option)
+
+				// the next event is a cutscene, outside of the moogle's handler
+				z.Post(func(z *zone.Zone) { st.s.startEvent(z, player, 30, nil) })
+			},
+		},
+	}
+
+	if err := st.talk(testMoogleID); err != nil {
+		t.Fatalf("talk error = %v", err)
+	}
+
+	var event *serverPackets.EventPacket
+	for _, packet := range st.flush() {
+		if p, ok := packet.(*serverPackets.EventPacket); ok {
+			event = p
+		}
+	}
+
+	if event == nil || event.UniqueNo != testMoogleID || event.EventPara != 20 || st.player.Vars["script"] != 0 {
+		t.Fatalf("event packet = %+v, vars = %v, want event 20 from the Go handler", event, st.player.Vars)
+	}
+
+	if err := st.endEvent(testMoogleID, 2, 20, clientPackets.EventEndModeFinish, 1); err != nil {
+		t.Fatalf("event finish error = %v", err)
+	}
+
+	st.flush()
+
+	if len(finished) != 1 || finished[0] != 1 || st.player.Event == nil || st.player.Event.NPCID != st.player.CharacterID {
+		t.Fatalf("finished = %v, event = %+v, want the cutscene started", finished, st.player.Event)
+	}
+
+	// cutscenes end in the zone's script
+	if err := st.endEvent(st.player.CharacterID, st.player.ActIndex, 30, clientPackets.EventEndModeFinish, 0); err != nil {
+		t.Fatalf("cutscene finish error = %v", err)
+	}
+
+	if st.player.Event != nil || st.player.Vars["cutscene"] != 30 {
+		t.Fatalf("after the cutscene, event = %+v, vars = %v", st.player.Event, st.player.Vars)
+	}
+}
+
+func TestHomePoint(t *testing.T) {
+	st := newScriptTest(t, nil)
+	st.player.Character = &database.Character{ID: st.player.CharacterID, HomeZone: 231}
+	st.player.Position = zone.Position{X: -1, Z: 1, Rotation: 64}
+
+	if err := st.talk(testHomePointID); err != nil {
+		t.Fatalf("talk error = %v", err)
+	}
+
+	character := st.player.Character
+	if character.HomeZone != 230 || character.HomeX != -1 || character.HomeZ != 1 || character.HomeRot != 64 {
+		t.Fatalf("home point = %d (%v, %v, %v), want where the player stands in zone 230", character.HomeZone, character.HomeX, character.HomeZ, character.HomeRot)
+	}
+
+	if save := <-st.s.saves; save.description != "home point" {
+		t.Fatalf("save = %q, want the home point", save.description)
+	}
+
+	if modes := releases(st.flush()); len(modes) != 1 || modes[0] != serverPackets.EventReleaseStandard {
+		t.Fatalf("releases = %v, want a standard one", modes)
+	}
+}
+
+func TestEventTimeout(t *testing.T) {
+	st := newScriptTest(t, map[string]string{"npcs/Guard.lua": testGuardScript})
+	st.s.cfg.MapEventTimeoutSeconds = 60
+
+	if err := st.talk(testGuardID); err != nil {
+		t.Fatalf("talk error = %v", err)
+	}
+
+	// updates show the client is still there
+	st.z.Advance(50 * time.Second)
+	if err := st.endEvent(testGuardID, 1, 10, clientPackets.EventEndModeUpdate, 4); err != nil {
+		t.Fatalf("event update error = %v", err)
+	}
+
+	st.z.Advance(50 * time.Second)
+	if st.player.Event == nil {
+		t.Fatal("event ended although the client answered")
+	}
+
+	st.sent = nil
+	st.z.Advance(20 * time.Second)
+
+	if st.player.Event != nil || st.player.Vars["guard"] != 0 {
+		t.Fatalf("event = %+v, vars = %v, want the event ended without finishing", st.player.Event, st.player.Vars)
+	}
+
+	if modes := releases(st.sent); len(modes) != 1 || modes[0] != serverPackets.EventReleaseStandard {
+		t.Fatalf("releases = %v, want a standard one", modes)
+	}
+}
@@ -0,0 +1,152 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/GoFFXI/GoFFXI/internal/config"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/experience"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// levelCap returns the highest level jobs may reach with the enabled expansions. The cap
+// stayed at 75 until each Abyssea expansion raised it by 5, and Seekers of Adoulin
+// arrived with the final cap of 99.
+func levelCap(cfg *config.Config) uint8 {
+	switch {
+	case cfg.SeekersOfAdoulinEnabled:
+		return experience.MaxLevel
+	case cfg.HeroesOfAbysseaEnabled:
+		return 90
+	case cfg.ScarsOfAbysseaEnabled:
+		return 85
+	case cfg.VisionsOfAbysseaEnabled:
+		return 80
+	default:
+		return experience.DefaultLevelCap
+	}
+}
+
+// playerProgress returns the main job of a player with its level and experience points.
+// It reports false when the jobs of the player are not loaded.
+func playerProgress(player *zone.Player) (uint8, experience.Progress, bool) {
+	if player.Character == nil || player.Character.Jobs == nil || player.Character.Exp == nil || player.Stats == nil {
+		return 0, experience.Progress{}, false
+	}
+
+	job := player.Stats.MainJob
+	progress := experience.Progress{
+		Level: player.Character.Jobs.Level(job),
+		Exp:   player.Character.Exp.Exp(job),
+	}
+
+	return job, progress, progress.Level > 0
+}
+
+// gainExp awards experience points to the main job of a player.
+func (s *InstanceWorker) gainExp(z *zone.Zone, player *zone.Player, points uint32) {
+	job, progress, ok := playerProgress(player)
+	if !ok || points == 0 {
+		return
+	}
+
+	sendPackets(z, player.ClientAddr, createExpMessagePacket(player, points))
+	s.setProgress(z, player, job, progress.Gain(points, levelCap(s.cfg)))
+}
+
+// loseExp takes experience points from the main job of a player, which can cost a level.
+func (s *InstanceWorker) loseExp(z *zone.Zone, player *zone.Player, points uint32) {
+	job, progress, ok := playerProgress(player)
+	if !ok || points == 0 {
+		return
+	}
+
+	packet := createExpMessagePacket(player, points)
+	packet.MessageNum = serverPackets.MessageLoseExp
+	sendPackets(z, player.ClientAddr, packet)
+
+	s.setProgress(z, player, job, progress.Lose(points))
+}
+
+// setJobLevel sets the level of the main job of a player, starting it from 0 experience points.
+func (s *InstanceWorker) setJobLevel(z *zone.Zone, player *zone.Player, level uint8) bool {
+	job, _, ok := playerProgress(player)
+	if !ok {
+		return false
+	}
+
+	s.setProgress(z, player, job, experience.Progress{Level: level})
+	return true
+}
+
+// setProgress stores the level and experience points of a job. A level change is
+// announced to the players around, applied to the stats and equipment of the player,
+// and sent to the client along with the new experience points.
+func (s *InstanceWorker) setProgress(z *zone.Zone, player *zone.Player, job uint8, progress experience.Progress) {
+	jobs := player.Character.Jobs
+	previousLevel := jobs.Level(job)
+
+	player.Character.Exp.SetExp(job, progress.Exp)
+	exp := *player.Character.Exp
+	s.queueSave("exp", player.CharacterID, func(ctx context.Context) error {
+		return s.DB().SaveCharacterExp(ctx, &exp)
+	})
+
+	if progress.Level == previousLevel {
+		sendPackets(z, player.ClientAddr, CreateCliStatusPacket(player))
+		return
+	}
+
+	jobs.SetLevel(job, progress.Level)
+	savedJobs := *jobs
+	s.queueSave("jobs", player.CharacterID, func(ctx context.Context) error {
+		_, err := s.DB().UpdateCharacterJobs(ctx, &savedJobs)
+		return err
+	})
+
+	message := serverPackets.MessageLevelUp
+	if progress.Level < previousLevel {
+		message = serverPackets.MessageLevelDown
+	}
+
+	sendNearby(z, player.Position, createLevelMessagePacket(player, progress.Level, message))
+
+	s.unequipUnusable(z, player)
+	s.refreshStats(player)
+
+	// gaining a level restores HP and MP
+	if progress.Level > previousLevel && player.Stats != nil {
+		player.Stats.HP = uint16(max(player.Derived.MaxHP, 0)) //nolint:gosec // the maximum HP fits the stored HP
+		player.Stats.MP = uint16(max(player.Derived.MaxMP, 0)) //nolint:gosec // the maximum MP fits the stored MP
+		player.StatsDirty = true
+	}
+
+	player.MarkChanged()
+
+	jobInfoPacket := CreateJobInfoPacket(jobs, player.Looks, player.Stats, player.Derived)
+	sendPackets(z, player.ClientAddr, jobInfoPacket, CreateCliStatusPacket(player), CreateGroupAttrPacket(player))
+}
+
+// createExpMessagePacket builds the "<player> gains <points> experience points." message.
+func createExpMessagePacket(player *zone.Player, points uint32) *serverPackets.BattleMessage2Packet {
+	return &serverPackets.BattleMessage2Packet{
+		UniqueNoCas: player.CharacterID,
+		UniqueNoTar: player.CharacterID,
+		ActIndexCas: player.ActIndex,
+		ActIndexTar: player.ActIndex,
+		Data:        points,
+		MessageNum:  serverPackets.MessageGainExp,
+	}
+}
+
+// createLevelMessagePacket builds the message announcing that a player reached a level.
+func createLevelMessagePacket(player *zone.Player, level uint8, message uint16) *serverPackets.BattleMessagePacket {
+	return &serverPackets.BattleMessagePacket{
+		UniqueNoCas: player.CharacterID,
+		UniqueNoTar: player.CharacterID,
+		Data:        uint32(level),
+		ActIndexCas: player.ActIndex,
+		ActIndexTar: player.ActIndex,
+		MessageNum:  message,
+	}
+}
@@ -0,0 +1,124 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/GoFFXI/GoFFXI/internal/config"
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+func newTestLevelingPlayer(t *testing.T) (*InstanceWorker, *zone.Zone, *zone.Player) {
+	t.Helper()
+
+	s := newTestWorker()
+	z, player := newTestPlayerZone(t)
+
+	stats := &database.CharacterStats{CharacterID: player.CharacterID, HP: 10, MainJob: 1}
+	player.Character = &database.Character{
+		ID:    player.CharacterID,
+		Jobs:  testJobs(),
+		Exp:   &database.CharacterExp{CharacterID: player.CharacterID, WAR: 5000},
+		Stats: stats,
+	}
+	player.Stats = stats
+	player.Looks = &database.CharacterLooks{Race: 1}
+	s.refreshStats(player)
+
+	return s, z, player
+}
+
+func TestLevelCap(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  config.Config
+		want uint8
+	}{
+		{"no Abyssea", config.Config{TreasuresOfAhtUrhganEnabled: true, WingsOfTheGoddessEnabled: true}, 75},
+		{"Visions of Abyssea", config.Config{VisionsOfAbysseaEnabled: true}, 80},
+		{"Heroes of Abyssea", config.Config{VisionsOfAbysseaEnabled: true, ScarsOfAbysseaEnabled: true, HeroesOfAbysseaEnabled: true}, 90},
+		{"Seekers of Adoulin", config.Config{SeekersOfAdoulinEnabled: true}, 99},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := levelCap(&tc.cfg); got != tc.want {
+				t.Fatalf("levelCap() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGainExpLevelsUp(t *testing.T) {
+	s, z, player := newTestLevelingPlayer(t)
+	maxHP := player.Derived.MaxHP
+
+	// WAR 30 needs 5800 points for level 31
+	s.gainExp(z, player, 1000)
+
+	if player.Character.Jobs.WAR != 31 || player.Character.Exp.WAR != 200 {
+		t.Fatalf("WAR = level %d with %d points, want level 31 with 200", player.Character.Jobs.WAR, player.Character.Exp.WAR)
+	}
+
+	if player.Derived.MaxHP <= maxHP || int32(player.Stats.HP) != player.Derived.MaxHP {
+		t.Fatalf("HP = %d/%d, want a full and higher maximum than %d", player.Stats.HP, player.Derived.MaxHP, maxHP)
+	}
+
+	// the experience points and the new level are persisted
+	if len(s.saves) != 2 {
+		t.Fatalf("queued saves = %d, want %d", len(s.saves), 2)
+	}
+
+	packet := CreateCliStatusPacket(player)
+	if packet.MainJobLevel != 31 || packet.ExpNow != 200 || packet.ExpNext != 5900 {
+		t.Fatalf("CliStatus = level %d, %d/%d points", packet.MainJobLevel, packet.ExpNow, packet.ExpNext)
+	}
+}
+
+func TestGainExpStopsAtTheCap(t *testing.T) {
+	s, z, player := newTestLevelingPlayer(t)
+	player.Character.Jobs.WAR = 75
+
+	s.gainExp(z, player, 50000)
+
+	if player.Character.Jobs.WAR != 75 || player.Character.Exp.WAR != 43999 {
+		t.Fatalf("WAR = level %d with %d points, want level 75 with 43999", player.Character.Jobs.WAR, player.Character.Exp.WAR)
+	}
+
+	if len(s.saves) != 1 {
+		t.Fatalf("queued saves = %d, want %d", len(s.saves), 1)
+	}
+}
+
+func TestLoseExpLevelsDown(t *testing.T) {
+	s, z, player := newTestLevelingPlayer(t)
+	maxHP := player.Derived.MaxHP
+
+	s.loseExp(z, player, 5500)
+
+	// WAR 29 needs 5700 points for level 30
+	if player.Character.Jobs.WAR != 29 || player.Character.Exp.WAR != 5200 {
+		t.Fatalf("WAR = level %d with %d points, want level 29 with 5200", player.Character.Jobs.WAR, player.Character.Exp.WAR)
+	}
+
+	if player.Derived.MaxHP >= maxHP || player.Stats.HP != 10 {
+		t.Fatalf("HP = %d/%d, want 10 and a lower maximum than %d", player.Stats.HP, player.Derived.MaxHP, maxHP)
+	}
+}
+
+func TestSetJobLevel(t *testing.T) {
+	s, z, player := newTestLevelingPlayer(t)
+
+	if !s.setJobLevel(z, player, 50) {
+		t.Fatalf("setJobLevel() = false, want true")
+	}
+
+	if player.Character.Jobs.WAR != 50 || player.Character.Exp.WAR != 0 {
+		t.Fatalf("WAR = level %d with %d points, want level 50 with 0", player.Character.Jobs.WAR, player.Character.Exp.WAR)
+	}
+
+	player.Character.Exp = nil
+	if s.setJobLevel(z, player, 10) {
+		t.Fatalf("setJobLevel() = true without experience points loaded")
+	}
+}
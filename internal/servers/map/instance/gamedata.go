@@ -0,0 +1,50 @@
+package instance
+
+import (
+	"github.com/nats-io/nats.go"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+)
+
+// SubjectGameDataReload is the NATS subject every instance reloads its game data on
+const SubjectGameDataReload = "map.gamedata.reload"
+
+// ProcessGameDataReload reloads the game data from disk. A reload that fails to load
+// or validate is logged and the instance keeps the data it had.
+func (s *InstanceWorker) ProcessGameDataReload(_ *nats.Msg) {
+	data, err := s.gameData.Reload()
+	if err != nil {
+		s.Logger().Error("could not reload game data, keeping the current data", "error", err)
+		return
+	}
+
+	s.logGameData(data)
+}
+
+// publishGameDataReload asks every instance to reload its game data.
+func (s *InstanceWorker) publishGameDataReload() error {
+	return s.NATS().Publish(SubjectGameDataReload, nil)
+}
+
+func (s *InstanceWorker) logGameData(data *gamedata.Data) {
+	for _, name := range data.Missing {
+		s.Logger().Warn("game data file not found, treating it as empty", "path", s.cfg.MapGameDataPath, "file", name)
+	}
+
+	s.Logger().Info("loaded game data",
+		"path", s.cfg.MapGameDataPath,
+		"version", data.Manifest.Version,
+		"items", len(data.Items),
+		"equipment", len(data.Equipment),
+		"itemMods", len(data.ItemMods),
+		"zones", len(data.Zones),
+		"zoneLines", len(data.ZoneLines),
+		"npcs", len(data.NPCs),
+		"mobGroups", len(data.MobGroups),
+		"spawnPoints", len(data.SpawnPoints),
+		"dropTables", len(data.DropTables),
+		"statusEffects", len(data.StatusEffects),
+		"spells", len(data.Spells),
+		"abilities", len(data.Abilities),
+	)
+}
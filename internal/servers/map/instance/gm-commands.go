@@ -0,0 +1,653 @@
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/experience"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// gmCommandPrefix starts a chat message that is a GM command rather than something to say
+const gmCommandPrefix = '!'
+
+// GM levels required to run commands; accounts at a level may run every command up to it
+const (
+	gmLevelHelper uint8 = 1
+	gmLevelGM     uint8 = 2
+	gmLevelSenior uint8 = 3
+	gmLevelAdmin  uint8 = 4
+)
+
+const (
+	// maxGMExpPoints is the most experience points !addexp awards at once
+	maxGMExpPoints = 100000
+
+	// maxGMItemQuantity is the largest stack !additem accepts
+	maxGMItemQuantity = 99
+
+	// defaultGMEffectSeconds is how long the status effects of !addeffect last by default
+	defaultGMEffectSeconds = 3600
+
+	// maxZoneID is the highest zone the client keeps track of
+	maxZoneID = serverPackets.EnterZonePacketSize*8 - 1
+)
+
+var (
+	ErrGMCommandUsage     = errors.New("invalid arguments")
+	ErrGMCommandForbidden = errors.New("insufficient GM level")
+	ErrUnknownGMCommand   = errors.New("unknown command")
+)
+
+// gmCommand is a command staff run by saying "!<name> <args>".
+type gmCommand struct {
+	name  string
+	level uint8
+	usage string
+	help  string
+	run   func(c *gmCommandContext, args []string) error
+}
+
+// gmCommandContext is what a command runs with. Commands run on the zone goroutine of
+// the GM; anything slow (database, other instances) is done with background.
+type gmCommandContext struct {
+	s      *InstanceWorker
+	zone   *zone.Zone
+	player *zone.Player
+}
+
+// reply sends a system message to the GM, from the zone goroutine.
+func (c *gmCommandContext) reply(format string, args ...any) {
+	c.zone.Send(c.player.ClientAddr, createSystemChatPacket(fmt.Sprintf(format, args...)))
+}
+
+// background runs fn off the zone loop and sends its result (or error) to the GM.
+func (c *gmCommandContext) background(command string, fn func(ctx context.Context) (string, error)) {
+	clientAddr := c.player.ClientAddr
+	characterID := c.player.CharacterID
+
+	go func() {
+		ctx, cancel := context.WithTimeout(c.s.ctx, playerRequestTimeout)
+		defer cancel()
+
+		text, err := fn(ctx)
+		if err != nil {
+			c.s.Logger().Warn("GM command failed", "characterID", characterID, "command", command, "error", err)
+			text = fmt.Sprintf("!%s failed: %v", command, err)
+		}
+
+		if err := c.s.sendPacket(clientAddr, createSystemChatPacket(text)); err != nil {
+			c.s.Logger().Error("failed to send GM command reply", "clientAddr", clientAddr, "error", err)
+		}
+	}()
+}
+
+// registerGMCommands builds the table of every GM command.
+func (s *InstanceWorker) registerGMCommands() {
+	commands := []gmCommand{
+		{name: "help", level: gmLevelHelper, usage: "!help [command]", help: "Lists the commands you can use, or explains one.", run: s.gmHelp},
+		{name: "goto", level: gmLevelGM, usage: "!goto <player>", help: "Warps you to a player, on any zone.", run: s.gmGoto},
+		{name: "zone", level: gmLevelGM, usage: "!zone <zone> [x y z]", help: "Warps you to a zone, at its entrance unless coordinates are given.", run: s.gmZone},
+		{name: "hide", level: gmLevelGM, usage: "!hide", help: "Toggles whether other players can see you.", run: s.gmHide},
+		{name: "kick", level: gmLevelGM, usage: "!kick <player>", help: "Disconnects a player.", run: s.gmKick},
+		{name: "additem", level: gmLevelSenior, usage: "!additem <item> [quantity]", help: "Adds an item to your inventory.", run: s.gmAddItem},
+		{name: "addexp", level: gmLevelSenior, usage: "!addexp <points>", help: "Awards experience points to your main job.", run: s.gmAddExp},
+		{name: "addeffect", level: gmLevelSenior, usage: "!addeffect <effect> [power] [seconds]", help: "Gives you a status effect (for an hour by default).", run: s.gmAddEffect},
+		{name: "deleffect", level: gmLevelSenior, usage: "!deleffect <effect|dispel|erase>", help: "Removes one of your status effects, or dispels or erases the latest one.", run: s.gmDelEffect},
+		{name: "setlevel", level: gmLevelSenior, usage: "!setlevel <level>", help: "Sets the level of your main job.", run: s.gmSetLevel},
+		{name: "ban", level: gmLevelAdmin, usage: "!ban <player> <duration|perm> [reason]", help: "Bans a player's account (e.g. 12h, 7d or perm) and kicks them.", run: s.gmBan},
+		{name: "reload", level: gmLevelAdmin, usage: "!reload", help: "Reloads the game data files on every instance.", run: s.gmReload},
+		{name: "reloadscripts", level: gmLevelAdmin, usage: "!reloadscripts", help: "Reloads the scripts of your zone.", run: s.gmReloadScripts},
+	}
+
+	s.gmCommands = make(map[string]gmCommand, len(commands))
+	for _, command := range commands {
+		s.gmCommands[command.name] = command
+	}
+}
+
+// runGMCommand parses and runs a GM command line, then records it in the audit log.
+func (s *InstanceWorker) runGMCommand(z *zone.Zone, player *zone.Player, line string) {
+	name, args := parseGMCommand(line)
+	c := &gmCommandContext{s: s, zone: z, player: player}
+
+	command, ok := s.gmCommands[name]
+	var err error
+	switch {
+	case !ok:
+		err = fmt.Errorf("%w !%s", ErrUnknownGMCommand, name)
+		c.reply("Unknown command !%s. Type !help for a list of commands.", name)
+	case player.GMLevel < command.level:
+		err = fmt.Errorf("%w for !%s", ErrGMCommandForbidden, name)
+		c.reply("You are not allowed to use !%s.", name)
+	default:
+		err = command.run(c, args)
+		if errors.Is(err, ErrGMCommandUsage) {
+			c.reply("Usage: %s", command.usage)
+		} else if err != nil {
+			c.reply("!%s failed: %v", name, err)
+		}
+	}
+
+	s.auditGMCommand(z, player, line, err)
+}
+
+// auditGMCommand logs a GM command and stores it for later review.
+func (s *InstanceWorker) auditGMCommand(z *zone.Zone, player *zone.Player, line string, err error) {
+	var accountID uint32
+	if player.Character != nil {
+		accountID = player.Character.AccountID
+	}
+
+	gmCommandLog := &database.GMCommandLog{
+		AccountID:     accountID,
+		CharacterID:   player.CharacterID,
+		CharacterName: player.Name,
+		GMLevel:       player.GMLevel,
+		ZoneID:        z.ID(),
+		Command:       truncate(line, 255),
+		CreatedAt:     time.Now(),
+	}
+
+	if err != nil {
+		gmCommandLog.Result = truncate(err.Error(), 255)
+	}
+
+	s.Logger().Info("GM command", "characterID", player.CharacterID, "gmLevel", player.GMLevel, "zoneID", z.ID(), "command", line, "error", err)
+
+	s.queueSave("GM command log", player.CharacterID, func(ctx context.Context) error {
+		return s.DB().CreateGMCommandLog(ctx, gmCommandLog)
+	})
+}
+
+func (s *InstanceWorker) gmHelp(c *gmCommandContext, args []string) error {
+	if len(args) > 1 {
+		return ErrGMCommandUsage
+	}
+
+	if len(args) == 1 {
+		command, ok := s.gmCommands[strings.ToLower(strings.TrimPrefix(args[0], string(gmCommandPrefix)))]
+		if !ok || c.player.GMLevel < command.level {
+			return fmt.Errorf("%w !%s", ErrUnknownGMCommand, args[0])
+		}
+
+		c.reply("%s - %s", command.usage, command.help)
+		return nil
+	}
+
+	for _, command := range availableGMCommands(s.gmCommands, c.player.GMLevel) {
+		c.reply("%s - %s", command.usage, command.help)
+	}
+
+	return nil
+}
+
+func (s *InstanceWorker) gmGoto(c *gmCommandContext, args []string) error {
+	if len(args) != 1 {
+		return ErrGMCommandUsage
+	}
+
+	target := args[0]
+	characterID := c.player.CharacterID
+	c.background("goto", func(_ context.Context) (string, error) {
+		data, err := s.requestPlayer(target, playerRequestLocate, nil)
+		if err != nil {
+			return "", err
+		}
+
+		var location playerLocation
+		if err := json.Unmarshal(data, &location); err != nil {
+			return "", fmt.Errorf("failed to unmarshal location: %w", err)
+		}
+
+		// the GM may have moved on in the meantime; only warp it if it is still where it asked from
+		return fmt.Sprintf("Warping to %s.", target), c.zone.Post(func(z *zone.Zone) {
+			if player := z.Player(characterID); player != nil {
+				s.changeZone(z, player, location.ZoneID, location.Position)
+			}
+		})
+	})
+
+	return nil
+}
+
+func (s *InstanceWorker) gmZone(c *gmCommandContext, args []string) error {
+	if len(args) != 1 && len(args) != 4 {
+		return ErrGMCommandUsage
+	}
+
+	zoneID, err := parseGMNumber[uint16](args[0])
+	if err != nil || zoneID > maxZoneID {
+		return ErrGMCommandUsage
+	}
+
+	var destination zone.Position
+	if len(args) == 1 {
+		entry, ok := s.gameData.Current().ZoneEntry(zoneID)
+		if !ok {
+			return fmt.Errorf("zone %d has no known entry point, give coordinates", zoneID)
+		}
+
+		destination = zone.Position{X: entry.ToX, Y: entry.ToY, Z: entry.ToZ, Rotation: entry.ToRotation}
+	} else {
+		coordinates := make([]float32, 3)
+		for i, arg := range args[1:] {
+			value, err := strconv.ParseFloat(arg, 32)
+			if err != nil {
+				return ErrGMCommandUsage
+			}
+
+			coordinates[i] = float32(value)
+		}
+
+		destination = zone.Position{X: coordinates[0], Y: coordinates[1], Z: coordinates[2]}
+		if !destination.IsFinite() {
+			return ErrGMCommandUsage
+		}
+	}
+
+	s.changeZone(c.zone, c.player, zoneID, destination)
+	return nil
+}
+
+func (s *InstanceWorker) gmHide(c *gmCommandContext, args []string) error {
+	if len(args) != 0 {
+		return ErrGMCommandUsage
+	}
+
+	c.player.Hidden = !c.player.Hidden
+	c.player.MarkChanged()
+
+	if c.player.Hidden {
+		c.reply("You are now hidden.")
+	} else {
+		c.reply("You are now visible.")
+	}
+
+	return nil
+}
+
+func (s *InstanceWorker) gmKick(c *gmCommandContext, args []string) error {
+	if len(args) != 1 {
+		return ErrGMCommandUsage
+	}
+
+	target := args[0]
+	gmLevel := c.player.GMLevel
+	c.background("kick", func(ctx context.Context) (string, error) {
+		if _, err := s.gmTargetCharacter(ctx, target, gmLevel, "kick"); err != nil {
+			return "", err
+		}
+
+		if _, err := s.requestPlayer(target, playerRequestKick, nil); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("Kicked %s.", target), nil
+	})
+
+	return nil
+}
+
+func (s *InstanceWorker) gmAddItem(c *gmCommandContext, args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return ErrGMCommandUsage
+	}
+
+	itemID, err := parseGMNumber[uint16](args[0])
+	if err != nil || itemID == inventory.GilItemID {
+		return ErrGMCommandUsage
+	}
+
+	quantity := uint32(1)
+	if len(args) == 2 {
+		if quantity, err = parseGMNumber[uint32](args[1]); err != nil || quantity == 0 || quantity > maxGMItemQuantity {
+			return ErrGMCommandUsage
+		}
+	}
+
+	if c.player.Inventory == nil {
+		return errors.New("inventory is not loaded")
+	}
+
+	item := inventory.Item{ID: itemID, Quantity: quantity}
+	changed, err := c.player.Inventory.Add(serverPackets.ContainerKindInventory, item, s.stackSize)
+	if err != nil {
+		return err
+	}
+
+	s.syncItems(c.zone, c.player, changed)
+	c.reply("Added %d of item %d.", quantity, itemID)
+
+	return nil
+}
+
+func (s *InstanceWorker) gmSetLevel(c *gmCommandContext, args []string) error {
+	if len(args) != 1 {
+		return ErrGMCommandUsage
+	}
+
+	level, err := parseGMNumber[uint8](args[0])
+	if err != nil || level == 0 || level > experience.MaxLevel {
+		return ErrGMCommandUsage
+	}
+
+	if !s.setJobLevel(c.zone, c.player, level) {
+		return errors.New("jobs are not loaded")
+	}
+
+	c.reply("Main job set to level %d.", level)
+	return nil
+}
+
+func (s *InstanceWorker) gmAddExp(c *gmCommandContext, args []string) error {
+	if len(args) != 1 {
+		return ErrGMCommandUsage
+	}
+
+	points, err := parseGMNumber[uint32](args[0])
+	if err != nil || points == 0 || points > maxGMExpPoints {
+		return ErrGMCommandUsage
+	}
+
+	if _, _, ok := playerProgress(c.player); !ok {
+		return errors.New("jobs are not loaded")
+	}
+
+	s.gainExp(c.zone, c.player, points)
+	return nil
+}
+
+func (s *InstanceWorker) gmAddEffect(c *gmCommandContext, args []string) error {
+	if len(args) < 1 || len(args) > 3 {
+		return ErrGMCommandUsage
+	}
+
+	id, err := parseGMNumber[uint16](args[0])
+	if err != nil {
+		return ErrGMCommandUsage
+	}
+
+	power, seconds := uint32(1), uint32(defaultGMEffectSeconds)
+	if len(args) > 1 {
+		if power, err = parseGMNumber[uint32](args[1]); err != nil || power > math.MaxInt32 {
+			return ErrGMCommandUsage
+		}
+	}
+
+	if len(args) > 2 {
+		if seconds, err = parseGMNumber[uint32](args[2]); err != nil {
+			return ErrGMCommandUsage
+		}
+	}
+
+	effect, ok := s.newEffect(id, int32(power), effectTickInterval, time.Duration(seconds)*time.Second, c.player.CharacterID)
+	if !ok {
+		return fmt.Errorf("no status effect %d", id)
+	}
+
+	if !s.addPlayerEffect(c.zone, c.player, effect) {
+		return fmt.Errorf("status effect %d did not overwrite your active effects", id)
+	}
+
+	return nil
+}
+
+func (s *InstanceWorker) gmDelEffect(c *gmCommandContext, args []string) error {
+	if len(args) != 1 {
+		return ErrGMCommandUsage
+	}
+
+	var lost []effects.Effect
+	switch strings.ToLower(args[0]) {
+	case "dispel", "erase":
+		flag := effects.FlagDispelable
+		if strings.EqualFold(args[0], "erase") {
+			flag = effects.FlagErasable
+		}
+
+		if effect, ok := c.player.Effects.Dispel(flag); ok {
+			lost = append(lost, effect)
+		}
+	default:
+		id, err := parseGMNumber[uint16](args[0])
+		if err != nil {
+			return ErrGMCommandUsage
+		}
+
+		lost = c.player.Effects.Remove(id)
+	}
+
+	if len(lost) == 0 {
+		return errors.New("no such status effect")
+	}
+
+	s.playerEffectsLost(c.zone, c.player, lost)
+	return nil
+}
+
+func (s *InstanceWorker) gmBan(c *gmCommandContext, args []string) error {
+	if len(args) < 2 {
+		return ErrGMCommandUsage
+	}
+
+	target := args[0]
+	duration, err := parseGMBanDuration(args[1])
+	if err != nil {
+		return ErrGMCommandUsage
+	}
+
+	reason := strings.Join(args[2:], " ")
+	if reason == "" {
+		reason = "banned by " + c.player.Name
+	}
+
+	gmLevel := c.player.GMLevel
+	c.background("ban", func(ctx context.Context) (string, error) {
+		character, err := s.gmTargetCharacter(ctx, target, gmLevel, "ban")
+		if err != nil {
+			return "", err
+		}
+
+		now := time.Now()
+		_, err = s.DB().CreateAccountBan(ctx, &database.AccountBan{
+			AccountID:    character.AccountID,
+			TimeBanned:   now,
+			TimeUnbanned: now.Add(duration),
+			Reason:       truncate(reason, 512),
+		})
+		if err != nil {
+			return "", err
+		}
+
+		// the ban stands whether or not the character is online
+		if _, err := s.requestPlayer(target, playerRequestKick, nil); err != nil && !errors.Is(err, ErrPlayerOffline) {
+			s.Logger().Warn("failed to kick banned character", "name", target, "error", err)
+		}
+
+		return fmt.Sprintf("Banned %s.", target), nil
+	})
+
+	return nil
+}
+
+func (s *InstanceWorker) gmReload(c *gmCommandContext, args []string) error {
+	if len(args) != 0 {
+		return ErrGMCommandUsage
+	}
+
+	c.background("reload", func(_ context.Context) (string, error) {
+		// check the files here first so the errors reach the GM rather than only the logs
+		data, err := gamedata.Load(s.cfg.MapGameDataPath)
+		if err != nil {
+			return "", err
+		}
+
+		if err = data.Validate(); err != nil {
+			return "", err
+		}
+
+		if err = s.publishGameDataReload(); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("Reloading game data version %s on every instance.", data.Manifest.Version), nil
+	})
+
+	return nil
+}
+
+func (s *InstanceWorker) gmReloadScripts(c *gmCommandContext, args []string) error {
+	if len(args) != 0 {
+		return ErrGMCommandUsage
+	}
+
+	z := c.zone
+	c.background("reloadscripts", func(ctx context.Context) (string, error) {
+		count, err := s.reloadZoneScripts(ctx, z)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("Reloaded %d scripts of zone %d.", count, z.ID()), nil
+	})
+
+	return nil
+}
+
+// playerLocation is the reply to a locate request.
+type playerLocation struct {
+	ZoneID   uint16
+	Position zone.Position
+}
+
+// receiveLocate tells where a player of this instance is.
+func (s *InstanceWorker) receiveLocate(z *zone.Zone, player *zone.Player, _ []byte) ([]byte, error) {
+	return json.Marshal(playerLocation{ZoneID: z.ID(), Position: player.Position})
+}
+
+// receiveKick disconnects a player of this instance.
+func (s *InstanceWorker) receiveKick(z *zone.Zone, player *zone.Player, _ []byte) ([]byte, error) {
+	s.Logger().Info("kicking character", "characterID", player.CharacterID)
+	s.leaveGame(z, player, serverPackets.LogoutStateLogout)
+
+	return nil, nil
+}
+
+// parseGMCommand splits a command line into the lowercase command name and its arguments.
+func parseGMCommand(line string) (string, []string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	return strings.ToLower(fields[0]), fields[1:]
+}
+
+// parseGMNumber parses an unsigned number argument that must fit in T.
+func parseGMNumber[T uint8 | uint16 | uint32](arg string) (T, error) {
+	var bits int
+	switch any(T(0)).(type) {
+	case uint8:
+		bits = 8
+	case uint16:
+		bits = 16
+	default:
+		bits = 32
+	}
+
+	value, err := strconv.ParseUint(arg, 10, bits)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrGMCommandUsage, err)
+	}
+
+	return T(value), nil
+}
+
+// permanentBan is how long a "perm" ban lasts
+const permanentBan = 100 * 365 * 24 * time.Hour
+
+// gmTargetCharacter looks up the character a GM command acts on, refusing characters
+// of accounts with a GM level at least that of the GM.
+func (s *InstanceWorker) gmTargetCharacter(ctx context.Context, name string, gmLevel uint8, action string) (database.Character, error) {
+	character, err := s.DB().GetCharacterByName(ctx, name)
+	if errors.Is(err, database.ErrNotFound) {
+		return database.Character{}, fmt.Errorf("no character named %s", name)
+	} else if err != nil {
+		return database.Character{}, err
+	}
+
+	account, err := s.DB().GetAccountByID(ctx, uint(character.AccountID))
+	if err != nil {
+		return database.Character{}, err
+	}
+
+	if account.GMLevel >= gmLevel {
+		return database.Character{}, fmt.Errorf("%w to %s %s", ErrGMCommandForbidden, action, name)
+	}
+
+	return character, nil
+}
+
+// parseGMBanDuration parses a ban duration: "perm", a number of days ("7d") or a Go duration ("12h").
+func parseGMBanDuration(arg string) (time.Duration, error) {
+	if strings.EqualFold(arg, "perm") {
+		return permanentBan, nil
+	}
+
+	if days, ok := strings.CutSuffix(arg, "d"); ok {
+		count, err := strconv.ParseUint(days, 10, 16)
+		if err != nil || count == 0 {
+			return 0, ErrGMCommandUsage
+		}
+
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(arg)
+	if err != nil || duration <= 0 {
+		return 0, ErrGMCommandUsage
+	}
+
+	return duration, nil
+}
+
+// availableGMCommands returns the commands a GM level may run, ordered by level then name.
+func availableGMCommands(commands map[string]gmCommand, gmLevel uint8) []gmCommand {
+	available := make([]gmCommand, 0, len(commands))
+	for _, command := range commands {
+		if command.level <= gmLevel {
+			available = append(available, command)
+		}
+	}
+
+	slices.SortFunc(available, func(a, b gmCommand) int {
+		if a.level != b.level {
+			return int(a.level) - int(b.level)
+		}
+
+		return strings.Compare(a.name, b.name)
+	})
+
+	return available
+}
+
+func truncate(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+
+	return text[:length]
+}
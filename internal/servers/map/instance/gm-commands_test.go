@@ -0,0 +1,114 @@
+package instance
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+)
+
+func TestParseGMCommand(t *testing.T) {
+	name, args := parseGMCommand("  ZONE 230  1.5 -2 3 ")
+	if name != "zone" || !slices.Equal(args, []string{"230", "1.5", "-2", "3"}) {
+		t.Fatalf("parseGMCommand() = %q, %q", name, args)
+	}
+
+	if name, args := parseGMCommand("   "); name != "" || args != nil {
+		t.Fatalf("parseGMCommand() = %q, %q, want empty", name, args)
+	}
+}
+
+func TestParseGMNumber(t *testing.T) {
+	if got, err := parseGMNumber[uint8]("75"); err != nil || got != 75 {
+		t.Fatalf("parseGMNumber(75) = %d, %v", got, err)
+	}
+
+	for _, arg := range []string{"256", "-1", "abc", ""} {
+		if _, err := parseGMNumber[uint8](arg); err == nil {
+			t.Fatalf("parseGMNumber(%q) succeeded, want an error", arg)
+		}
+	}
+}
+
+func TestParseGMBanDuration(t *testing.T) {
+	cases := []struct {
+		arg    string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"perm", permanentBan, true},
+		{"7d", 7 * 24 * time.Hour, true},
+		{"12h", 12 * time.Hour, true},
+		{"0d", 0, false},
+		{"-1h", 0, false},
+		{"forever", 0, false},
+	}
+
+	for _, tc := range cases {
+		got, err := parseGMBanDuration(tc.arg)
+		if got != tc.want || (err == nil) != tc.wantOK {
+			t.Fatalf("parseGMBanDuration(%q) = %v, %v, want %v", tc.arg, got, err, tc.want)
+		}
+	}
+}
+
+func TestGMCommandPermissions(t *testing.T) {
+	s := newTestWorker()
+	s.registerGMCommands()
+	z, player := newTestPlayerZone(t)
+
+	player.GMLevel = gmLevelHelper
+	s.runGMCommand(z, player, "hide")
+	if player.Hidden {
+		t.Fatalf("helper could run !hide")
+	}
+
+	player.GMLevel = gmLevelGM
+	s.runGMCommand(z, player, "hide")
+	if !player.Hidden {
+		t.Fatalf("GM could not run !hide")
+	}
+
+	// every command, allowed or not, is audited
+	if len(s.saves) != 2 {
+		t.Fatalf("queued saves = %d, want %d", len(s.saves), 2)
+	}
+}
+
+func TestAvailableGMCommands(t *testing.T) {
+	s := newTestWorker()
+	s.registerGMCommands()
+
+	var names []string
+	for _, command := range availableGMCommands(s.gmCommands, gmLevelGM) {
+		names = append(names, command.name)
+	}
+
+	want := []string{"help", "goto", "hide", "kick", "zone"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("availableGMCommands() = %v, want %v", names, want)
+	}
+
+	if got := len(availableGMCommands(s.gmCommands, gmLevelAdmin)); got != len(s.gmCommands) {
+		t.Fatalf("admin commands = %d, want %d", got, len(s.gmCommands))
+	}
+}
+
+func TestGMZoneEntry(t *testing.T) {
+	s := newTestWorker()
+	s.gameData = gamedata.NewStoreFromData(&gamedata.Data{
+		ZoneLines: map[uint32]gamedata.ZoneLine{1: {ID: 1, FromZone: 230, ToZone: 231, ToX: 10}},
+	})
+	z, player := newTestPlayerZone(t)
+	c := &gmCommandContext{s: s, zone: z, player: player}
+
+	// without coordinates, only zones with a known entry point can be warped to
+	if err := s.gmZone(c, []string{"232"}); err == nil || z.Player(player.CharacterID) == nil {
+		t.Fatalf("!zone 232 error = %v, want the GM kept in place", err)
+	}
+
+	if err := s.gmZone(c, []string{"231"}); err != nil || z.Player(player.CharacterID) != nil {
+		t.Fatalf("!zone 231 error = %v, want the GM warped", err)
+	}
+}
@@ -0,0 +1,43 @@
+package instance
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/config"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// newTestWorker returns a worker without NATS or a database; its saves stay queued.
+func newTestWorker() *InstanceWorker {
+	s := &InstanceWorker{
+		cfg:            &config.Config{MapLogoutSeconds: 30},
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		zones:          make(map[uint16]*zone.Zone),
+		characterZones: make(map[uint32]uint16),
+		saves:          make(chan pendingSave, saveQueueSize),
+		overflowed:     make(chan struct{}, 1),
+		gameData:       gamedata.NewStoreFromData(&gamedata.Data{}),
+	}
+	s.registerNPCHandlers()
+
+	return s
+}
+
+// newTestPlayerZone returns zone 230 on a virtual clock, with a player of character 1.
+func newTestPlayerZone(t *testing.T) (*zone.Zone, *zone.Player) {
+	t.Helper()
+
+	z := zone.New(230, zone.Options{
+		TickInterval: time.Second,
+		Clock:        zone.NewVirtualClock(time.Unix(0, 0)),
+	})
+
+	player := &zone.Player{CharacterID: 1, ClientAddr: "127.0.0.1:1000"}
+	z.AddPlayer(player)
+
+	return z, player
+}
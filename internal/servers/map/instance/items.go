@@ -0,0 +1,168 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+var ErrNotInMogHouse = errors.New("container can only be used in the mog house")
+
+// mogHouseContainer reports whether a container is kept in the mog house, and can only
+// be used there.
+func mogHouseContainer(container serverPackets.ContainerKind) bool {
+	switch container {
+	case serverPackets.ContainerKindMogSafe, serverPackets.ContainerKindStorage,
+		serverPackets.ContainerKindMogLocker, serverPackets.ContainerKindMogSafe2:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkContainers rejects the mog house containers outside of the player's mog house.
+func checkContainers(player *zone.Player, containers ...serverPackets.ContainerKind) error {
+	for _, container := range containers {
+		if mogHouseContainer(container) && !player.InMogHouse {
+			return fmt.Errorf("%w: container %d", ErrNotInMogHouse, container)
+		}
+	}
+
+	return nil
+}
+
+// loadInventory loads a character's container sizes and items.
+func (s *InstanceWorker) loadInventory(ctx context.Context, characterID uint32) (*inventory.Inventory, error) {
+	containers, err := s.DB().GetCharacterContainers(ctx, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load containers: %w", err)
+	}
+
+	sizes := inventory.DefaultSizes
+	for _, container := range containers {
+		if int(container.Container) < inventory.ContainerCount {
+			sizes[container.Container] = container.Size
+		}
+	}
+
+	items, err := s.DB().GetCharacterItems(ctx, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load items: %w", err)
+	}
+
+	inv := inventory.New(sizes)
+	for _, item := range items {
+		location := inventory.Location{Container: serverPackets.ContainerKind(item.Container), Slot: item.Slot}
+		if err := inv.Set(location, inventory.Item{ID: item.ItemID, Quantity: item.Quantity, Extra: item.Extra}); err != nil {
+			// keep the row; the container may only be locked for now
+			s.Logger().Warn("skipping item outside of its container", "characterID", characterID, "itemID", item.ItemID, "error", err)
+		}
+	}
+
+	return inv, nil
+}
+
+// stackSize returns how many of an item fit in one slot; unknown items do not stack.
+func (s *InstanceWorker) stackSize(itemID uint16) uint32 {
+	if item, ok := s.gameData.Current().Items[itemID]; ok {
+		return uint32(item.StackSize)
+	}
+
+	return 1
+}
+
+// syncItems sends the changed slots to the client and persists them.
+func (s *InstanceWorker) syncItems(z *zone.Zone, player *zone.Player, changed []inventory.Location) {
+	if len(changed) == 0 {
+		return
+	}
+
+	saved := make([]database.CharacterItem, 0, len(changed))
+	for _, location := range changed {
+		item, ok := player.Inventory.Get(location)
+		if ok {
+			z.Send(player.ClientAddr, createItemAttrPacket(player.Inventory, location, item))
+		} else {
+			z.Send(player.ClientAddr, &serverPackets.ItemNumPacket{Category: uint8(location.Container), ItemIndex: location.Slot})
+		}
+
+		// an item with no quantity is an empty slot to delete
+		saved = append(saved, database.CharacterItem{
+			CharacterID: player.CharacterID,
+			Container:   uint8(location.Container),
+			Slot:        location.Slot,
+			ItemID:      item.ID,
+			Quantity:    item.Quantity,
+			Extra:       item.Extra,
+		})
+	}
+
+	z.Send(player.ClientAddr, &serverPackets.ItemSamePacket{State: serverPackets.ItemSameStateDone})
+
+	s.queueSave("items", player.CharacterID, func(ctx context.Context) error {
+		for i := range saved {
+			var err error
+			if saved[i].Quantity == 0 {
+				err = s.DB().DeleteCharacterItem(ctx, saved[i].CharacterID, saved[i].Container, saved[i].Slot)
+			} else {
+				err = s.DB().SaveCharacterItem(ctx, &saved[i])
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// CreateItemMaxPacket tells the client how large each of its containers is.
+func CreateItemMaxPacket(inv *inventory.Inventory) *serverPackets.ItemMaxPacket {
+	packet := &serverPackets.ItemMaxPacket{}
+	for container, size := range inv.Sizes() {
+		// the hidden gil slot counts toward the size; locked containers stay at 0
+		if size > 0 {
+			packet.ItemNum[container] = size + 1
+			packet.ItemNum2[container] = uint16(size) + 1
+		}
+	}
+
+	return packet
+}
+
+// createItemPackets returns the packets sending every item of an inventory, followed
+// by the packet telling the client its containers are loaded.
+func createItemPackets(inv *inventory.Inventory) []serverPackets.ServerPacket {
+	locations := inv.Locations()
+
+	packets := make([]serverPackets.ServerPacket, 0, len(locations)+1)
+	for _, location := range locations {
+		item, _ := inv.Get(location)
+		packets = append(packets, createItemAttrPacket(inv, location, item))
+	}
+
+	return append(packets, &serverPackets.ItemSamePacket{State: serverPackets.ItemSameStateDone})
+}
+
+func createItemAttrPacket(inv *inventory.Inventory, location inventory.Location, item inventory.Item) *serverPackets.ItemAttrPacket {
+	packet := &serverPackets.ItemAttrPacket{
+		ItemNum:   item.Quantity,
+		ItemNo:    item.ID,
+		Category:  uint8(location.Container),
+		ItemIndex: location.Slot,
+	}
+
+	if inv.Locked(location) {
+		packet.LockFlg = itemLockEquipped
+	}
+
+	copy(packet.Attr[:], item.Extra)
+
+	return packet
+}
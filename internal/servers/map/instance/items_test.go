@@ -0,0 +1,65 @@
+package instance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+)
+
+func TestMogHouseContainers(t *testing.T) {
+	s := newTestWorker()
+	z, player := newTestPlayerZone(t)
+	pctx := &PacketContext{CharacterID: player.CharacterID, Zone: z}
+
+	player.Inventory = inventory.New(inventory.DefaultSizes)
+	safe := inventory.Location{Container: serverPackets.ContainerKindMogSafe, Slot: 1}
+	if err := player.Inventory.Set(safe, inventory.Item{ID: 4096, Quantity: 2}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	moveOut := &clientPackets.ItemMovePacket{ItemNum: 1, Category1: uint8(serverPackets.ContainerKindMogSafe), ItemIndex1: 1, Category2: uint8(serverPackets.ContainerKindInventory), ItemIndex2: inventory.AnySlot}
+	moveIn := &clientPackets.ItemMovePacket{ItemNum: 1, Category1: uint8(serverPackets.ContainerKindInventory), ItemIndex1: 1, Category2: uint8(serverPackets.ContainerKindMogSafe), ItemIndex2: inventory.AnySlot}
+	dump := &clientPackets.ItemDumpPacket{ItemNum: 1, Category: uint8(serverPackets.ContainerKindMogSafe), ItemIndex: 1}
+	sort := &clientPackets.ItemStackPacket{Category: uint8(serverPackets.ContainerKindMogSafe)}
+
+	// outside of the mog house, the safe can be neither taken from, filled, dropped from nor sorted
+	if err := s.handleItemMovePacket(pctx, moveOut); !errors.Is(err, ErrNotInMogHouse) {
+		t.Fatalf("move out of the safe error = %v, want ErrNotInMogHouse", err)
+	}
+
+	if err := s.handleItemDumpPacket(pctx, dump); !errors.Is(err, ErrNotInMogHouse) {
+		t.Fatalf("drop from the safe error = %v, want ErrNotInMogHouse", err)
+	}
+
+	if err := s.handleItemStackPacket(pctx, sort); !errors.Is(err, ErrNotInMogHouse) {
+		t.Fatalf("sort of the safe error = %v, want ErrNotInMogHouse", err)
+	}
+
+	if item, _ := player.Inventory.Get(safe); item.Quantity != 2 {
+		t.Fatalf("safe quantity = %d, want 2", item.Quantity)
+	}
+
+	// a city with a mog menu is not the mog house
+	s.gameData = gamedata.NewStoreFromData(&gamedata.Data{Zones: map[uint16]gamedata.Zone{230: {ID: 230, Misc: gamedata.ZoneMiscMogMenu}}})
+	if err := s.handleItemMovePacket(pctx, moveOut); !errors.Is(err, ErrNotInMogHouse) {
+		t.Fatalf("move out of the safe in a city error = %v, want ErrNotInMogHouse", err)
+	}
+
+	player.InMogHouse = true
+
+	if err := s.handleItemMovePacket(pctx, moveOut); err != nil {
+		t.Fatalf("move out of the safe in the mog house error = %v", err)
+	}
+
+	if err := s.handleItemMovePacket(pctx, moveIn); err != nil {
+		t.Fatalf("move into the safe in the mog house error = %v", err)
+	}
+
+	if err := s.handleItemDumpPacket(pctx, dump); err != nil {
+		t.Fatalf("drop from the safe in the mog house error = %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// subJobFlag is the bit of the unlocked job flags telling the client support jobs are unlocked
+const subJobFlag uint32 = 1
+
+var (
+	ErrJobsNotLoaded      = errors.New("jobs are not loaded")
+	ErrJobOutsideMogHouse = errors.New("jobs can only be changed in the mog house")
+	ErrJobLocked          = errors.New("job is not unlocked")
+	ErrSubJobLocked       = errors.New("support jobs are not unlocked")
+	ErrSameJob            = errors.New("main and support jobs must differ")
+)
+
+// jobFlags returns the unlocked job flags in the layout of the login and job info packets.
+func jobFlags(jobs *database.CharacterJobs) uint32 {
+	flags := jobs.Unlocked &^ subJobFlag
+	if jobs.SubJobUnlocked {
+		flags |= subJobFlag
+	}
+
+	return flags
+}
+
+// jobLevels returns the job levels in the layouts of the login and job info packets:
+// the first 16 jobs, then every job.
+func jobLevels(jobs *database.CharacterJobs) (levels [16]uint8, allLevels [24]uint8) {
+	all := jobs.Levels()
+	copy(levels[:], all)
+	copy(allLevels[:], all)
+
+	return levels, allLevels
+}
+
+// checkJobChange returns the jobs a character ends up with after asking to change them,
+// where 0 keeps the current job. Making the support job the main job swaps the two.
+func checkJobChange(jobs *database.CharacterJobs, mainJob, subJob, newMain, newSub uint8) (uint8, uint8, error) {
+	targetMain, targetSub := mainJob, subJob
+
+	if newMain != 0 {
+		if !jobs.IsUnlocked(newMain) {
+			return 0, 0, fmt.Errorf("%w: %d", ErrJobLocked, newMain)
+		}
+
+		targetMain = newMain
+	}
+
+	if newSub != 0 {
+		if !jobs.SubJobUnlocked {
+			return 0, 0, ErrSubJobLocked
+		}
+
+		if !jobs.IsUnlocked(newSub) {
+			return 0, 0, fmt.Errorf("%w: %d", ErrJobLocked, newSub)
+		}
+
+		targetSub = newSub
+	}
+
+	if !jobs.IsUnlocked(targetMain) {
+		return 0, 0, fmt.Errorf("%w: %d", ErrJobLocked, targetMain)
+	}
+
+	if targetSub != 0 && targetSub == targetMain {
+		if newSub != 0 {
+			return 0, 0, fmt.Errorf("%w: %d", ErrSameJob, targetMain)
+		}
+
+		targetSub = mainJob
+	}
+
+	return targetMain, targetSub, nil
+}
+
+// changeJob switches the jobs of a character, taking off the equipment the new jobs
+// cannot use and the status effects lost on job changes, then tells the client and
+// persists the change.
+func (s *InstanceWorker) changeJob(z *zone.Zone, player *zone.Player, mainJob, subJob uint8) {
+	player.Stats.MainJob = mainJob
+	player.Stats.SubJob = subJob
+
+	s.unequipUnusable(z, player)
+	if lost := player.Effects.RemoveFlagged(effects.FlagOnJobChange); len(lost) > 0 {
+		s.playerEffectsChanged(z, player)
+	}
+
+	s.refreshStats(player)
+	player.MarkChanged()
+
+	jobInfoPacket := CreateJobInfoPacket(player.Character.Jobs, player.Looks, player.Stats, player.Derived)
+	sendPackets(z, player.ClientAddr, jobInfoPacket, CreateCliStatusPacket(player), CreateGroupAttrPacket(player))
+
+	stats := *player.Stats
+	s.queueSave("jobs", player.CharacterID, func(ctx context.Context) error {
+		_, err := s.DB().UpdateCharacterStats(ctx, &stats)
+		return err
+	})
+}
+
+// CreateJobInfoPacket builds the packet telling the client its jobs and their levels.
+func CreateJobInfoPacket(jobs *database.CharacterJobs, looks *database.CharacterLooks, stats *database.CharacterStats, derived charstats.Stats) *serverPackets.JobInfoPacket {
+	packet := &serverPackets.JobInfoPacket{
+		BPBase: derived.Base,
+		BPAdj:  derived.Bonus,
+		HPMax:  derived.MaxHP,
+		MPMax:  derived.MaxMP,
+	}
+
+	if jobs != nil {
+		packet.GetJobFlag = jobFlags(jobs)
+		packet.JobLevel, packet.JobLevelEx = jobLevels(jobs)
+		packet.SubJobFlag = boolToUint8(jobs.SubJobUnlocked)
+	}
+
+	if looks != nil {
+		packet.RaceID = looks.Race
+	}
+
+	if stats != nil {
+		packet.MainJobID = stats.MainJob
+		packet.SubJobID = stats.SubJob
+	}
+
+	return packet
+}
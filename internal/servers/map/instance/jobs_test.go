@@ -0,0 +1,144 @@
+package instance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+)
+
+func testJobs() *database.CharacterJobs {
+	// the six starting jobs plus PLD, which was never leveled
+	return &database.CharacterJobs{WAR: 30, MNK: 1, WHM: 12, BLM: 1, RDM: 1, THF: 1, Unlocked: 0b1111_1110, SubJobUnlocked: true}
+}
+
+func TestCheckJobChange(t *testing.T) {
+	subJobsLocked := testJobs()
+	subJobsLocked.SubJobUnlocked = false
+
+	cases := []struct {
+		name              string
+		jobs              *database.CharacterJobs
+		newMain, newSub   uint8
+		wantMain, wantSub uint8
+		want              error
+	}{
+		{"change main", testJobs(), 3, 0, 3, 2, nil},
+		{"change both", testJobs(), 4, 1, 4, 1, nil},
+		{"support job becomes main", testJobs(), 2, 0, 2, 1, nil},
+		{"keep both", testJobs(), 0, 0, 1, 2, nil},
+		{"locked main", testJobs(), 8, 0, 0, 0, ErrJobLocked},
+		{"unknown job", testJobs(), 40, 0, 0, 0, ErrJobLocked},
+		{"locked support", testJobs(), 0, 9, 0, 0, ErrJobLocked},
+		{"support jobs locked", subJobsLocked, 0, 3, 0, 0, ErrSubJobLocked},
+		{"same jobs", testJobs(), 3, 3, 0, 0, ErrSameJob},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mainJob, subJob, err := checkJobChange(tc.jobs, 1, 2, tc.newMain, tc.newSub)
+			if !errors.Is(err, tc.want) || (tc.want == nil && err != nil) {
+				t.Fatalf("checkJobChange() error = %v, want %v", err, tc.want)
+			}
+
+			if mainJob != tc.wantMain || subJob != tc.wantSub {
+				t.Fatalf("checkJobChange() = %d/%d, want %d/%d", mainJob, subJob, tc.wantMain, tc.wantSub)
+			}
+		})
+	}
+}
+
+func TestCreateJobInfoPacket(t *testing.T) {
+	jobs := testJobs()
+	jobs.RUN = 5
+
+	packet := CreateJobInfoPacket(jobs, &database.CharacterLooks{Race: 2}, &database.CharacterStats{MainJob: 1, SubJob: 3}, charstats.Stats{MaxHP: 300, MaxMP: 20})
+	if packet.GetJobFlag != 0b1111_1111 || packet.SubJobFlag != 1 {
+		t.Fatalf("GetJobFlag = %b, SubJobFlag = %d", packet.GetJobFlag, packet.SubJobFlag)
+	}
+
+	if packet.JobLevel[1] != 30 || packet.JobLevel[3] != 12 || packet.JobLevelEx[22] != 5 {
+		t.Fatalf("JobLevel = %v, JobLevelEx = %v", packet.JobLevel, packet.JobLevelEx)
+	}
+
+	if packet.RaceID != 2 || packet.MainJobID != 1 || packet.SubJobID != 3 || packet.HPMax != 300 {
+		t.Fatalf("packet = %+v", packet)
+	}
+
+	data, err := packet.Serialize()
+	if err != nil || len(data) != serverPackets.PacketSizeJobInfo {
+		t.Fatalf("Serialize() = %d bytes, %v, want %d", len(data), err, serverPackets.PacketSizeJobInfo)
+	}
+}
+
+func TestJobChangeOnlyInMogHouse(t *testing.T) {
+	s := newTestWorker()
+	s.gameData = gamedata.NewStoreFromData(&gamedata.Data{Zones: map[uint16]gamedata.Zone{230: {ID: 230, Misc: gamedata.ZoneMiscMogMenu}}})
+	z, player := newTestPlayerZone(t)
+	pctx := &PacketContext{CharacterID: player.CharacterID, Zone: z}
+
+	stats := &database.CharacterStats{CharacterID: player.CharacterID, MainJob: 1}
+	player.Character = &database.Character{ID: player.CharacterID, Jobs: testJobs(), Stats: stats}
+	player.Stats = stats
+	player.Inventory = inventory.New(inventory.DefaultSizes)
+
+	// a city with a mog menu is not the mog house
+	packet := &clientPackets.MyRoomJobPacket{MainJobIndex: 3}
+	if err := s.handleMyRoomJobPacket(pctx, packet); !errors.Is(err, ErrJobOutsideMogHouse) {
+		t.Fatalf("job change in a city error = %v, want ErrJobOutsideMogHouse", err)
+	}
+
+	if player.Stats.MainJob != 1 {
+		t.Fatalf("main job = %d, want 1", player.Stats.MainJob)
+	}
+
+	player.InMogHouse = true
+	if err := s.handleMyRoomJobPacket(pctx, packet); err != nil {
+		t.Fatalf("job change in the mog house error = %v", err)
+	}
+
+	if player.Stats.MainJob != 3 {
+		t.Fatalf("main job = %d, want 3", player.Stats.MainJob)
+	}
+}
+
+func TestChangeJobUnequipsUnusableItems(t *testing.T) {
+	s := newTestWorker()
+	s.gameData = gamedata.NewStoreFromData(&gamedata.Data{Equipment: testEquipment()})
+	z, player := newTestPlayerZone(t)
+
+	stats := &database.CharacterStats{CharacterID: player.CharacterID, MainJob: 1}
+	player.Character = &database.Character{ID: player.CharacterID, Jobs: testJobs(), Stats: stats}
+	player.Stats = stats
+	player.Inventory = inventory.New(inventory.DefaultSizes)
+
+	location := inventory.Location{Container: serverPackets.ContainerKindInventory, Slot: 1}
+	if err := player.Inventory.Set(location, inventory.Item{ID: bronzeCap, Quantity: 1}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if err := player.Inventory.Equip(serverPackets.EquipKindHead, location); err != nil {
+		t.Fatalf("Equip() error = %v", err)
+	}
+
+	// WHM cannot wear the cap
+	s.changeJob(z, player, 3, 1)
+
+	if player.Stats.MainJob != 3 || player.Stats.SubJob != 1 {
+		t.Fatalf("jobs = %d/%d, want 3/1", player.Stats.MainJob, player.Stats.SubJob)
+	}
+
+	if _, ok := player.Inventory.Equipped(serverPackets.EquipKindHead); ok {
+		t.Fatalf("cap is still equipped")
+	}
+
+	// the unequipped slot and the new jobs are persisted
+	if len(s.saves) != 2 {
+		t.Fatalf("queued saves = %d, want %d", len(s.saves), 2)
+	}
+}
@@ -0,0 +1,94 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// maxKeyItemID is the highest key item the client keeps track of
+const maxKeyItemID = serverPackets.KeyItemTableCount*serverPackets.KeyItemsPerTable - 1
+
+// loadKeyItems loads the key items a character holds.
+func (s *InstanceWorker) loadKeyItems(ctx context.Context, characterID uint32) (map[uint16]bool, error) {
+	rows, err := s.DB().GetCharacterKeyItems(ctx, characterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key items: %w", err)
+	}
+
+	keyItems := make(map[uint16]bool, len(rows))
+	for _, row := range rows {
+		keyItems[row.KeyItemID] = true
+	}
+
+	return keyItems, nil
+}
+
+// addKeyItem gives a key item to a player, reporting false if they already had it
+// (or the client would not know it).
+func (s *InstanceWorker) addKeyItem(z *zone.Zone, player *zone.Player, keyItemID uint16) bool {
+	if keyItemID > maxKeyItemID || player.KeyItems[keyItemID] {
+		return false
+	}
+
+	if player.KeyItems == nil {
+		player.KeyItems = make(map[uint16]bool)
+	}
+
+	player.KeyItems[keyItemID] = true
+	sendPackets(z, player.ClientAddr, createKeyItemsPacket(player.KeyItems, keyItemID/serverPackets.KeyItemsPerTable))
+
+	characterID := player.CharacterID
+	s.queueSave("key item", characterID, func(ctx context.Context) error {
+		return s.DB().AddCharacterKeyItem(ctx, characterID, keyItemID)
+	})
+
+	return true
+}
+
+// delKeyItem takes a key item from a player, reporting false if they did not have it.
+func (s *InstanceWorker) delKeyItem(z *zone.Zone, player *zone.Player, keyItemID uint16) bool {
+	if !player.KeyItems[keyItemID] {
+		return false
+	}
+
+	delete(player.KeyItems, keyItemID)
+	sendPackets(z, player.ClientAddr, createKeyItemsPacket(player.KeyItems, keyItemID/serverPackets.KeyItemsPerTable))
+
+	characterID := player.CharacterID
+	s.queueSave("key item", characterID, func(ctx context.Context) error {
+		return s.DB().DeleteCharacterKeyItem(ctx, characterID, keyItemID)
+	})
+
+	return true
+}
+
+// createKeyItemPackets builds the key item list packets of every table.
+func createKeyItemPackets(keyItems map[uint16]bool) []serverPackets.ServerPacket {
+	packets := make([]serverPackets.ServerPacket, 0, serverPackets.KeyItemTableCount)
+	for table := range uint16(serverPackets.KeyItemTableCount) {
+		packets = append(packets, createKeyItemsPacket(keyItems, table))
+	}
+
+	return packets
+}
+
+// createKeyItemsPacket builds the key item list packet of a table. Key items are not
+// tracked as looked at, so the client is told every key item held was.
+func createKeyItemsPacket(keyItems map[uint16]bool, table uint16) *serverPackets.ScenarioItemPacket {
+	packet := &serverPackets.ScenarioItemPacket{TableIndex: table}
+
+	for keyItemID := range keyItems {
+		if keyItemID/serverPackets.KeyItemsPerTable != table {
+			continue
+		}
+
+		bit := keyItemID % serverPackets.KeyItemsPerTable
+		packet.GetItemFlag[bit/32] |= 1 << (bit % 32)
+		packet.LookItemFlag[bit/32] |= 1 << (bit % 32)
+	}
+
+	return packet
+}
@@ -0,0 +1,262 @@
+package instance
+
+import (
+	"math"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/combat"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/experience"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	// mobSightRange is how far mobs that detect by sight see, within mobSightHalfAngle
+	// of the direction they face (32 is 45 degrees)
+	mobSightRange     = 15.0
+	mobSightHalfAngle = 32
+
+	// mobHearingRange is how far mobs that detect by hearing notice players, in any direction
+	mobHearingRange = 8.0
+
+	// mobDetectHeight is the largest height difference mobs detect players across
+	mobDetectHeight = 8.0
+
+	// mobLinkRange is how close mobs of a group have to be to join each other's fights
+	mobLinkRange = 10.0
+
+	// mobMeleeRange is how close mobs get to their target to attack it
+	mobMeleeRange = 3.0
+
+	// mobPursuitRange is how far a target can get from a mob before the mob gives up
+	mobPursuitRange = 30.0
+
+	// mobLeashRange is how far mobs chase their target away from their spawn point
+	mobLeashRange = 60.0
+
+	// mobRoamRange is how far from their spawn point idle mobs roam
+	mobRoamRange = 10.0
+
+	// idle mobs wait between mobRoamMinWait and mobRoamMaxWait before roaming again
+	mobRoamMinWait = 10 * time.Second
+	mobRoamMaxWait = 30 * time.Second
+
+	// mobBaseEnmity is the enmity a character gets for being noticed by a mob
+	mobBaseEnmity = 1
+)
+
+// updateMobs runs the AI of every spawned mob of the zone; it is a zone system.
+func (s *InstanceWorker) updateMobs(z *zone.Zone, now time.Time) {
+	for _, mob := range z.Mobs() {
+		if mob.Spawned {
+			s.updateMob(z, mob, now)
+		}
+	}
+}
+
+func (s *InstanceWorker) updateMob(z *zone.Zone, mob *zone.Mob, now time.Time) {
+	step := mobStep(z, mob)
+
+	switch mob.State {
+	case zone.AIIdle:
+		if s.mobAggro(z, mob) {
+			return
+		}
+
+		if !now.Before(mob.NextRoamAt) {
+			mob.Destination = mobRoamDestination(z, mob)
+			mob.SetState(zone.AIRoaming, now)
+		}
+	case zone.AIRoaming:
+		if s.mobAggro(z, mob) {
+			return
+		}
+
+		var arrived bool
+		if mob.Position, arrived = mob.Position.MoveTowards(mob.Destination, step); arrived {
+			mob.SetState(zone.AIIdle, now)
+			mob.NextRoamAt = now.Add(mobRoamWait(z))
+		}
+	case zone.AIChasing, zone.AIAttacking:
+		s.updateEngagedMob(z, mob, now, step)
+	case zone.AIDisengaging:
+		var arrived bool
+		if mob.Position, arrived = mob.Position.MoveTowards(mob.Home, step); arrived {
+			mob.Position.Rotation = mob.Home.Rotation
+			mob.HP, mob.MP = mob.MaxHP, mob.MaxMP
+			mob.MarkChanged()
+			mob.SetState(zone.AIIdle, now)
+			mob.NextRoamAt = now.Add(mobRoamWait(z))
+		}
+	case zone.AIDespawning:
+		if now.Sub(mob.StateChangedAt) >= mobDespawnDelay {
+			s.despawnMob(z, mob)
+		}
+	}
+}
+
+// updateEngagedMob follows the character with the most enmity, disengaging once
+// nobody on the hate list can be fought anymore or the fight strayed too far.
+func (s *InstanceWorker) updateEngagedMob(z *zone.Zone, mob *zone.Mob, now time.Time, step float64) {
+	mob.Hate.Decay(z.TickInterval())
+
+	target := mobTarget(z, mob)
+	if target == nil ||
+		mob.Position.HorizontalDistance(mob.Home) > mobLeashRange ||
+		mob.Position.HorizontalDistance(target.Position) > mobPursuitRange {
+		s.disengageMob(z, mob)
+		return
+	}
+
+	// the claim passes on when whoever held it is out of the fight
+	if mob.ClaimedBy != target.CharacterID && !mob.Hate.Has(mob.ClaimedBy) {
+		mob.ClaimedBy = target.CharacterID
+		mob.MarkChanged()
+	}
+
+	if mob.Target != target.CharacterID {
+		mob.Target, mob.TargetIndex = target.CharacterID, target.ActIndex
+		mob.MarkChanged()
+	}
+
+	if distance := mob.Position.HorizontalDistance(target.Position); distance > mobMeleeRange {
+		// stop just inside melee range rather than on top of the target
+		mob.Position, _ = mob.Position.MoveTowards(target.Position, min(step, distance-mobMeleeRange+0.5))
+	}
+
+	state := zone.AIChasing
+	if mob.Position.HorizontalDistance(target.Position) <= mobMeleeRange {
+		mob.Position.Rotation = mob.Position.RotationTowards(target.Position)
+		state = zone.AIAttacking
+	}
+
+	if mob.State != state {
+		mob.SetState(state, now)
+	}
+}
+
+// mobTarget returns the character a mob should fight, dropping the characters on its
+// hate list that cannot be fought anymore (they left, died, ...).
+func mobTarget(z *zone.Zone, mob *zone.Mob) *zone.Player {
+	for {
+		characterID, ok := mob.Hate.Top()
+		if !ok {
+			return nil
+		}
+
+		if player := z.Player(characterID); player != nil && mobCanTarget(player) {
+			return player
+		}
+
+		mob.Hate.Remove(characterID)
+	}
+}
+
+// mobCanTarget reports whether mobs can notice and fight a player. Players in events
+// cannot act, so they cannot be fought either, and players in their mog house are not
+// out in the zone.
+func mobCanTarget(player *zone.Player) bool {
+	if player.Disconnected || player.Hidden || player.InMogHouse || player.Event != nil {
+		return false
+	}
+
+	return player.Stats == nil || player.Stats.HP > 0
+}
+
+// mobAggro looks for a player an aggressive mob detects, and engages the first one.
+func (s *InstanceWorker) mobAggro(z *zone.Zone, mob *zone.Mob) bool {
+	if !mob.Aggro {
+		return false
+	}
+
+	for _, player := range z.PlayersNear(mob.Position, mobSightRange) {
+		if mobDetects(mob, player) {
+			s.engageMob(z, mob, player)
+			return true
+		}
+	}
+
+	return false
+}
+
+// mobDetects reports whether a mob notices a player, by sight or hearing depending on its family.
+func mobDetects(mob *zone.Mob, player *zone.Player) bool {
+	if !mobCanTarget(player) || math.Abs(float64(player.Position.Y-mob.Position.Y)) > mobDetectHeight {
+		return false
+	}
+
+	// mobs leave alone the players they check as too weak against
+	if level, _ := playerLevels(player); experience.TooWeak(level, mob.Level) {
+		return false
+	}
+
+	distance := mob.Position.HorizontalDistance(player.Position)
+	if mob.Detects&gamedata.DetectSight != 0 && distance <= mobSightRange && mob.Position.IsFacing(player.Position, mobSightHalfAngle) {
+		return true
+	}
+
+	return mob.Detects&gamedata.DetectHearing != 0 && distance <= mobHearingRange
+}
+
+// engageMob makes a mob fight a player, claiming it for the player if it was not
+// claimed yet. Idle mobs of its group that link join in.
+func (s *InstanceWorker) engageMob(z *zone.Zone, mob *zone.Mob, player *zone.Player) {
+	wasEngaged := mob.State.Engaged()
+	mob.Hate.Add(player.CharacterID, mobBaseEnmity, 0)
+	claimMob(mob, player)
+
+	if wasEngaged {
+		return
+	}
+
+	mob.Target, mob.TargetIndex = player.CharacterID, player.ActIndex
+	mob.NextAttackAt = z.Now().Add(combat.AttackInterval(mob.Delay))
+	mob.SetState(zone.AIChasing, z.Now())
+	mob.MarkChanged()
+
+	for _, other := range z.Mobs() {
+		if other != mob && other.Spawned && other.Links && other.GroupID == mob.GroupID &&
+			(other.State == zone.AIIdle || other.State == zone.AIRoaming) &&
+			other.Position.HorizontalDistance(mob.Position) <= mobLinkRange {
+			s.engageMob(z, other, player)
+		}
+	}
+}
+
+// disengageMob ends the fight of a mob, which walks back to its spawn point.
+func (s *InstanceWorker) disengageMob(z *zone.Zone, mob *zone.Mob) {
+	mob.Hate.Clear()
+	mob.Target, mob.TargetIndex, mob.ClaimedBy = 0, 0, 0
+	mob.SetState(zone.AIDisengaging, z.Now())
+	mob.MarkChanged()
+}
+
+// claimMob gives the claim of an unclaimed mob to a player.
+func claimMob(mob *zone.Mob, player *zone.Player) {
+	if mob.ClaimedBy == 0 {
+		mob.ClaimedBy = player.CharacterID
+		mob.MarkChanged()
+	}
+}
+
+// mobStep returns how far a mob moves in a tick.
+func mobStep(z *zone.Zone, mob *zone.Mob) float64 {
+	return float64(mob.Speed) / 10 * z.TickInterval().Seconds()
+}
+
+// mobRoamDestination picks a random point within roaming range of a mob's spawn point.
+func mobRoamDestination(z *zone.Zone, mob *zone.Mob) zone.Position {
+	angle := z.Rand().Float64() * 2 * math.Pi
+	distance := z.Rand().Float64() * mobRoamRange
+
+	destination := mob.Home
+	destination.X += float32(math.Cos(angle) * distance)
+	destination.Z += float32(math.Sin(angle) * distance)
+
+	return destination
+}
+
+// mobRoamWait picks how long an idle mob waits before roaming.
+func mobRoamWait(z *zone.Zone) time.Duration {
+	return mobRoamMinWait + time.Duration(z.Rand().Int64N(int64(mobRoamMaxWait-mobRoamMinWait)))
+}
@@ -0,0 +1,147 @@
+package instance
+
+import (
+	"slices"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	// mobSpawnTypeNormal mobs spawn with their zone and respawn on a timer; the other
+	// spawn types (scripted, time of day, ...) are left to scripts
+	mobSpawnTypeNormal = 0
+
+	// mobDefaultSpeed is the movement speed of mobs (4 yalms per second)
+	mobDefaultSpeed = 40
+
+	// mobDespawnDelay is how long a dead mob stays before it disappears
+	mobDespawnDelay = 10 * time.Second
+)
+
+// spawnZoneMobs adds the mobs of the zone's spawn points and spawns those that spawn
+// with the zone.
+func (s *InstanceWorker) spawnZoneMobs(z *zone.Zone) {
+	data := s.gameData.Current()
+
+	mobIDs := make([]uint32, 0)
+	for mobID, point := range data.SpawnPoints {
+		if point.Zone() == z.ID() {
+			mobIDs = append(mobIDs, mobID)
+		}
+	}
+
+	// spawn in ID order, so the zone's random rolls are reproducible
+	slices.Sort(mobIDs)
+
+	for _, mobID := range mobIDs {
+		point := data.SpawnPoints[mobID]
+		if data.MobGroups[point.Group()].SpawnType != mobSpawnTypeNormal {
+			continue
+		}
+
+		mob := newSpawnPointMob(point)
+		z.AddMob(mob)
+		s.spawnMob(z, mob)
+	}
+}
+
+// newSpawnPointMob creates the (not yet spawned) mob of a spawn point.
+func newSpawnPointMob(point gamedata.SpawnPoint) *zone.Mob {
+	return &zone.Mob{
+		ID:      point.MobID,
+		Index:   gamedata.EntityIndex(point.MobID),
+		Name:    point.Name,
+		GroupID: point.GroupID,
+		Home:    zone.Position{X: point.X, Y: point.Y, Z: point.Z, Rotation: point.Rotation},
+	}
+}
+
+// spawnMob brings a mob to life at its spawn point. What the mob is made of is read
+// from the current game data, so reloaded data applies from the next spawn on.
+func (s *InstanceWorker) spawnMob(z *zone.Zone, mob *zone.Mob) {
+	data := s.gameData.Current()
+
+	point, ok := data.SpawnPoints[mob.ID]
+	if !ok {
+		z.Logger().Warn("not spawning mob without a spawn point", "mobID", mob.ID)
+		return
+	}
+
+	group := data.MobGroups[point.Group()]
+	pool := data.MobPools[group.PoolID]
+	family := data.MobFamilies[pool.FamilyID]
+
+	mob.Name = point.Name
+	mob.GroupID = point.GroupID
+	mob.Home = zone.Position{X: point.X, Y: point.Y, Z: point.Z, Rotation: point.Rotation}
+	mob.ModelID = pool.ModelID
+	mob.Aggro = pool.Aggro
+	mob.Links = pool.Links
+	mob.Detects = family.Detects
+	mob.Speed = mobDefaultSpeed
+	mob.Delay = pool.CombatDelay
+	if mob.Delay == 0 {
+		mob.Delay = mobDefaultDelay
+	}
+
+	mob.Level = group.MinLevel
+	if group.MaxLevel > group.MinLevel {
+		mob.Level += uint8(z.Rand().IntN(int(group.MaxLevel-group.MinLevel) + 1)) //nolint:gosec // bounded by the level range
+	}
+
+	mob.MaxHP = mobMaxHP(mob.Level, group.HP)
+	mob.MaxMP = int32(group.MP) //nolint:gosec // MP overrides are small
+	mob.HP, mob.MP = mob.MaxHP, mob.MaxMP
+
+	mob.Position = mob.Home
+	mob.Target, mob.TargetIndex, mob.ClaimedBy = 0, 0, 0
+	mob.Hate.Clear()
+	mob.TP = 0
+	mob.Spawned = true
+	mob.SetState(zone.AIIdle, z.Now())
+	mob.NextRoamAt = z.Now().Add(mobRoamWait(z))
+	mob.MarkChanged()
+}
+
+// despawnMob takes a mob out of the zone and schedules its respawn; mobs without a
+// respawn time stay gone until something spawns them again.
+func (s *InstanceWorker) despawnMob(z *zone.Zone, mob *zone.Mob) {
+	mob.Spawned = false
+	mob.Target, mob.TargetIndex, mob.ClaimedBy = 0, 0, 0
+	mob.Hate.Clear()
+	mob.SetState(zone.AIIdle, z.Now())
+
+	point, ok := s.gameData.Current().SpawnPoints[mob.ID]
+	if !ok {
+		return
+	}
+
+	respawn := s.gameData.Current().MobGroups[point.Group()].RespawnSeconds
+	if respawn == 0 {
+		return
+	}
+
+	z.After(time.Duration(respawn)*time.Second, func(z *zone.Zone) {
+		if !mob.Spawned {
+			s.spawnMob(z, mob)
+		}
+	})
+}
+
+// mobMaxHP returns the maximum HP of a mob: the group's override, or a rough curve
+// by level until per-family stats are loaded.
+func mobMaxHP(level uint8, override uint32) int32 {
+	if override > 0 {
+		return int32(min(override, 1<<30)) //nolint:gosec // clamped
+	}
+
+	l := int32(level)
+	return 30 + l*10 + l*l/2
+}
+
+// mobHPP returns the health percentage of a mob.
+func mobHPP(mob *zone.Mob) uint8 {
+	return percentOf(mob.HP, mob.MaxHP)
+}
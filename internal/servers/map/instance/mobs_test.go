@@ -0,0 +1,183 @@
+package instance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// testMobID is a mob of zone 230 with target index 0x010
+const testMobID = 0x010E6010
+
+func testMobData() *gamedata.Data {
+	return &gamedata.Data{
+		MobGroups: map[gamedata.MobGroupKey]gamedata.MobGroup{
+			{ZoneID: 230, GroupID: 1}: {GroupID: 1, ZoneID: 230, PoolID: 10, RespawnSeconds: 60, MinLevel: 3, MaxLevel: 5},
+			{ZoneID: 230, GroupID: 2}: {GroupID: 2, ZoneID: 230, PoolID: 10, SpawnType: 128, MinLevel: 3, MaxLevel: 3},
+		},
+		SpawnPoints: map[uint32]gamedata.SpawnPoint{
+			testMobID:     {MobID: testMobID, Name: "Wild_Rabbit", GroupID: 1},
+			testMobID + 1: {MobID: testMobID + 1, Name: "Wild_Rabbit", GroupID: 1, X: -5},
+			testMobID + 2: {MobID: testMobID + 2, Name: "Scripted_Rabbit", GroupID: 2},
+		},
+		MobPools: map[uint32]gamedata.MobPool{
+			10: {PoolID: 10, FamilyID: 1, ModelID: 0x0194, Aggro: true, Links: true},
+		},
+		MobFamilies: map[uint16]gamedata.MobFamily{
+			1: {FamilyID: 1, Detects: gamedata.DetectSight},
+		},
+	}
+}
+
+// newTestMobZone returns a zone with two linked, aggressive rabbits facing east from
+// around the origin, and a player far away from them.
+func newTestMobZone(t *testing.T) (*InstanceWorker, *zone.Zone, *zone.Player) {
+	t.Helper()
+
+	s := newTestWorker()
+	s.gameData = gamedata.NewStoreFromData(testMobData())
+
+	z, player := newTestPlayerZone(t)
+	player.Position = zone.Position{X: 200}
+
+	s.spawnZoneMobs(z)
+	z.AddSystem(s.updateMobs)
+
+	return s, z, player
+}
+
+func TestSpawnZoneMobs(t *testing.T) {
+	_, z, _ := newTestMobZone(t)
+
+	mob := z.Mob(testMobID)
+	if mob == nil || !mob.Spawned || mob.Index != 0x010 || mob.ModelID != 0x0194 {
+		t.Fatalf("mob = %+v, want a spawned mob with index 0x010 and model 0x194", mob)
+	}
+
+	if mob.Level < 3 || mob.Level > 5 || mob.HP != mob.MaxHP || mob.MaxHP != mobMaxHP(mob.Level, 0) {
+		t.Fatalf("mob level = %d, HP = %d/%d", mob.Level, mob.HP, mob.MaxHP)
+	}
+
+	// scripted spawns are left alone
+	if z.Mob(testMobID+2) != nil {
+		t.Fatalf("scripted mob was spawned with the zone")
+	}
+}
+
+func TestMobAggroBySight(t *testing.T) {
+	_, z, player := newTestMobZone(t)
+	mob := z.Mob(testMobID)
+
+	// behind the mob, which only detects by sight
+	player.Position = zone.Position{X: -12}
+	z.Advance(time.Second)
+	if mob.State.Engaged() {
+		t.Fatalf("mob aggroed a player behind it")
+	}
+
+	player.Position = zone.Position{X: 12}
+	z.Advance(time.Second)
+	if mob.State != zone.AIChasing || mob.Target != player.CharacterID || mob.ClaimedBy != player.CharacterID {
+		t.Fatalf("mob state = %v, target = %d, claimed by %d", mob.State, mob.Target, mob.ClaimedBy)
+	}
+
+	// the other rabbit of the group links
+	if linked := z.Mob(testMobID + 1); !linked.State.Engaged() || linked.Target != player.CharacterID {
+		t.Fatalf("linked mob state = %v, target = %d", linked.State, linked.Target)
+	}
+
+	// at 4 yalms per second the rabbit reaches melee range of a player 12 yalms away
+	z.Advance(3 * time.Second)
+	if mob.State != zone.AIAttacking || mob.Position.HorizontalDistance(player.Position) > mobMeleeRange {
+		t.Fatalf("mob state = %v at %.1f yalms, want attacking in melee range", mob.State, mob.Position.HorizontalDistance(player.Position))
+	}
+}
+
+func TestMobIgnoresTooWeakPlayers(t *testing.T) {
+	_, z, player := newTestMobZone(t)
+	// WAR 30 against a level 3 to 5 rabbit
+	player.Stats = &database.CharacterStats{CharacterID: player.CharacterID, HP: 100, MainJob: 1}
+	player.Character = &database.Character{ID: player.CharacterID, Jobs: testJobs(), Stats: player.Stats}
+
+	player.Position = zone.Position{X: 5}
+	z.Advance(time.Second)
+	if z.Mob(testMobID).State.Engaged() {
+		t.Fatalf("mob aggroed a player it checks as too weak against")
+	}
+}
+
+func TestMobDisengagesWhenTargetLeaves(t *testing.T) {
+	_, z, player := newTestMobZone(t)
+	mob := z.Mob(testMobID)
+
+	player.Position = zone.Position{X: 8}
+	z.Advance(3 * time.Second)
+	mob.HP = 1
+
+	z.RemovePlayer(player.CharacterID)
+	z.Advance(time.Second)
+	if mob.State != zone.AIDisengaging || mob.ClaimedBy != 0 || mob.Hate.Len() != 0 {
+		t.Fatalf("mob state = %v, claimed by %d, hate list of %d", mob.State, mob.ClaimedBy, mob.Hate.Len())
+	}
+
+	z.Advance(3 * time.Second)
+	if mob.State != zone.AIIdle || mob.Position.HorizontalDistance(mob.Home) != 0 || mob.HP != mob.MaxHP {
+		t.Fatalf("mob state = %v, %.1f yalms from home, HP %d/%d", mob.State, mob.Position.HorizontalDistance(mob.Home), mob.HP, mob.MaxHP)
+	}
+}
+
+func TestMobRoamsAroundHome(t *testing.T) {
+	_, z, _ := newTestMobZone(t)
+	mob := z.Mob(testMobID)
+
+	for range 120 {
+		z.Advance(time.Second)
+		if mob.Position.HorizontalDistance(mob.Home) > mobRoamRange {
+			t.Fatalf("mob roamed %.1f yalms from home", mob.Position.HorizontalDistance(mob.Home))
+		}
+	}
+
+	if mob.Position == mob.Home {
+		t.Fatalf("mob never left its spawn point")
+	}
+}
+
+func TestMobDespawnsAndRespawns(t *testing.T) {
+	_, z, _ := newTestMobZone(t)
+	mob := z.Mob(testMobID)
+
+	mob.HP = 0
+	mob.SetState(zone.AIDespawning, z.Now())
+	z.Advance(mobDespawnDelay)
+	if mob.Spawned {
+		t.Fatalf("dead mob still spawned after %v", mobDespawnDelay)
+	}
+
+	z.Advance(59 * time.Second)
+	if mob.Spawned {
+		t.Fatalf("mob respawned before its respawn time")
+	}
+
+	z.Advance(time.Second)
+	if !mob.Spawned || mob.HP != mob.MaxHP || mob.State != zone.AIIdle {
+		t.Fatalf("mob spawned = %v, HP = %d/%d, state = %v", mob.Spawned, mob.HP, mob.MaxHP, mob.State)
+	}
+}
+
+func TestCreateMobUpdatePacket(t *testing.T) {
+	mob := &zone.Mob{ID: testMobID, Index: 0x010, Name: "Wild_Rabbit", ModelID: 0x0194, HP: 25, MaxHP: 100, ClaimedBy: 1, TargetIndex: 0x400, State: zone.AIAttacking}
+
+	packet := CreateMobUpdatePacket(mob, zone.VisibilitySpawn)
+	if packet.Hpp != 25 || packet.ServerStatus != serverPackets.CharNPCStatusEngaged || packet.BtTargetID != 1 || packet.Flags0>>17 != 0x400 {
+		t.Fatalf("packet = %+v", packet)
+	}
+
+	data, err := packet.Serialize()
+	if err != nil || len(data) != serverPackets.PacketSizeCharNPC {
+		t.Fatalf("Serialize() = %d bytes, %v, want %d", len(data), err, serverPackets.PacketSizeCharNPC)
+	}
+}
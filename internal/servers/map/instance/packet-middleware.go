@@ -0,0 +1,113 @@
+package instance
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+)
+
+// RequireCharacter rejects packets from clients that have no authenticated character.
+// The router only attaches a character ID once the client's login was verified.
+func RequireCharacter() PacketMiddleware {
+	return func(next PacketHandler) PacketHandler {
+		return func(pctx *PacketContext, packet clientPackets.Packet) error {
+			if pctx.CharacterID == 0 {
+				return fmt.Errorf("%w: %s requires an authenticated character", ErrPacketRejected, pctx.Definition.Name)
+			}
+
+			return next(pctx, packet)
+		}
+	}
+}
+
+// RequireZone rejects packets from characters that have not entered a zone on this instance yet.
+func RequireZone() PacketMiddleware {
+	return func(next PacketHandler) PacketHandler {
+		return func(pctx *PacketContext, packet clientPackets.Packet) error {
+			if pctx.Zone == nil {
+				return fmt.Errorf("%w: %s requires the character to be in a zone", ErrPacketRejected, pctx.Definition.Name)
+			}
+
+			return next(pctx, packet)
+		}
+	}
+}
+
+// RateLimit rejects packets from clients sending more than perSecond packets per
+// second, allowing bursts of up to burst packets. A limit of 0 disables it.
+func RateLimit(perSecond, burst int) PacketMiddleware {
+	limiter := newRateLimiter(float64(perSecond), float64(max(burst, perSecond)))
+
+	return func(next PacketHandler) PacketHandler {
+		if perSecond <= 0 {
+			return next
+		}
+
+		return func(pctx *PacketContext, packet clientPackets.Packet) error {
+			if !limiter.allow(pctx.ClientAddr, time.Now()) {
+				return fmt.Errorf("%w: %s rate limited", ErrPacketRejected, pctx.ClientAddr)
+			}
+
+			return next(pctx, packet)
+		}
+	}
+}
+
+// rateLimiterIdleTimeout is how long a client's bucket is kept after its last packet
+const rateLimiterIdleTimeout = time.Minute
+
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// rateLimiter is a token bucket per client address.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+}
+
+func newRateLimiter(rate, burst float64) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   burst,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (l *rateLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// drop the buckets of clients that went quiet
+	if now.Sub(l.lastPrune) > rateLimiterIdleTimeout {
+		for k, bucket := range l.buckets {
+			if now.Sub(bucket.lastSeen) > rateLimiterIdleTimeout {
+				delete(l.buckets, k)
+			}
+		}
+
+		l.lastPrune = now
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, lastSeen: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = min(l.burst, bucket.tokens+now.Sub(bucket.lastSeen).Seconds()*l.rate)
+	bucket.lastSeen = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+	return true
+}
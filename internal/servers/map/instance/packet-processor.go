@@ -1,15 +1,38 @@
 package instance
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"sync"
+	"time"
 
-	"github.com/davecgh/go-spew/spew"
 	"github.com/nats-io/nats.go"
 
 	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
 	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
 )
 
+// registerPacketHandlers wires every supported client packet to its handler.
+func (s *InstanceWorker) registerPacketHandlers() {
+	s.packets.Use(RateLimit(s.Config().MapClientPacketRateLimit, s.Config().MapClientPacketBurst))
+
+	s.packets.Handle(clientPackets.PacketTypeLogin, Typed(s.handleLoginPacket), RequireCharacter())
+	s.packets.Handle(clientPackets.PacketTypePosition, Typed(s.handlePositionPacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeZoneLine, Typed(s.handleZoneLinePacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeLogout, Typed(s.handleLogoutPacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeChat, Typed(s.handleChatPacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeItemDump, Typed(s.handleItemDumpPacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeItemMove, Typed(s.handleItemMovePacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeItemStack, Typed(s.handleItemStackPacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeEquipSet, Typed(s.handleEquipSetPacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeMyRoomJob, Typed(s.handleMyRoomJobPacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeTell, Typed(s.handleTellPacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeAction, Typed(s.handleActionPacket), RequireCharacter(), RequireZone())
+	s.packets.Handle(clientPackets.PacketTypeEventEnd, Typed(s.handleEventEndPacket), RequireCharacter(), RequireZone())
+}
+
 func (s *InstanceWorker) ProcessPacket(msg *nats.Msg) {
 	s.Logger().Debug("received packet", "natsSubject", msg.Subject)
 
@@ -20,12 +43,65 @@ func (s *InstanceWorker) ProcessPacket(msg *nats.Msg) {
 		return
 	}
 
-	// begin checking packet type
-	switch routedPacket.Packet.Type {
-	case clientPackets.PacketTypeLogin:
-		s.processLoginPacket(routedPacket)
-	default:
-		s.Logger().Warn("received unhandled packet type", "packetType", routedPacket.Packet.Type)
-		spew.Dump(routedPacket)
+	// packets of characters that are in a zone are handled by that zone's loop
+	if z := s.zoneForCharacter(routedPacket.CharacterID); z != nil {
+		if err := z.Post(func(z *zone.Zone) { s.dispatchPacket(&routedPacket, z) }); err != nil {
+			s.Logger().Warn("dropping client packet", "packetType", routedPacket.Packet.Type, "clientAddr", routedPacket.ClientAddr, "error", err)
+		}
+
+		return
+	}
+
+	s.dispatchPacket(&routedPacket, nil)
+}
+
+func (s *InstanceWorker) dispatchPacket(routedPacket *mapPackets.RoutedPacket, z *zone.Zone) {
+	elapsed, err := s.packets.Dispatch(routedPacket, z)
+	switch {
+	case errors.Is(err, ErrNoPacketHandler):
+		s.Logger().Debug("received unhandled packet type", "packetType", routedPacket.Packet.Type, "clientAddr", routedPacket.ClientAddr, "preview", hexPreview(routedPacket.Packet.Data, 64))
+	case errors.Is(err, ErrPacketRejected):
+		s.Logger().Warn("rejected client packet", "packetType", routedPacket.Packet.Type, "clientAddr", routedPacket.ClientAddr, "error", err)
+	case err != nil:
+		s.Logger().Error("failed to handle client packet", "packetType", routedPacket.Packet.Type, "clientAddr", routedPacket.ClientAddr, "error", err)
+	case elapsed > slowPacketHandlerThreshold:
+		s.Logger().Warn("slow client packet handler", "packetType", routedPacket.Packet.Type, "clientAddr", routedPacket.ClientAddr, "elapsed", elapsed)
+	}
+}
+
+// ReportPacketMetrics periodically logs the per-handler packet metrics.
+func (s *InstanceWorker) ReportPacketMetrics(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if s.Config().MapPacketMetricsIntervalSeconds <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(s.Config().MapPacketMetricsIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, metrics := range s.packets.Metrics() {
+				var average time.Duration
+				if calls := metrics.Handled + metrics.Failed + metrics.Rejected; calls > 0 {
+					average = metrics.TotalTime / time.Duration(calls) //nolint:gosec // call counts never come close to overflowing
+				}
+
+				s.Logger().Info("packet handler metrics",
+					"packetType", metrics.Type,
+					"name", metrics.Name,
+					"handled", metrics.Handled,
+					"failed", metrics.Failed,
+					"rejected", metrics.Rejected,
+					"parseErrors", metrics.ParseErrors,
+					"averageTime", average,
+					"maxTime", metrics.MaxTime,
+				)
+			}
+		}
 	}
 }
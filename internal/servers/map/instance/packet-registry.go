@@ -0,0 +1,176 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// slowPacketHandlerThreshold is how long a handler may take before it is logged as slow
+const slowPacketHandlerThreshold = 50 * time.Millisecond
+
+var (
+	ErrNoPacketHandler  = errors.New("no handler registered for packet")
+	ErrPacketRejected   = errors.New("packet rejected")
+	ErrUnexpectedPacket = errors.New("unexpected packet type for handler")
+)
+
+// PacketContext describes the client packet being handled.
+type PacketContext struct {
+	ClientAddr  string
+	CharacterID uint32
+	Sequence    uint16
+	Definition  clientPackets.Definition
+
+	// Zone is the zone the character is in. Packets of characters that are in a zone
+	// are handled on that zone's goroutine; otherwise (e.g. login) Zone is nil.
+	Zone *zone.Zone
+}
+
+// PacketHandler handles a parsed client packet.
+type PacketHandler func(pctx *PacketContext, packet clientPackets.Packet) error
+
+// PacketMiddleware wraps a handler, e.g. to reject packets before they reach it.
+// Middleware rejecting a packet should return an error wrapping ErrPacketRejected.
+type PacketMiddleware func(next PacketHandler) PacketHandler
+
+// Typed adapts a handler for a concrete packet type to a PacketHandler.
+func Typed[T clientPackets.Packet](handler func(pctx *PacketContext, packet T) error) PacketHandler {
+	return func(pctx *PacketContext, packet clientPackets.Packet) error {
+		typed, ok := packet.(T)
+		if !ok {
+			return fmt.Errorf("%w: %T", ErrUnexpectedPacket, packet)
+		}
+
+		return handler(pctx, typed)
+	}
+}
+
+// PacketHandlerMetrics are the counters kept for a single packet type.
+type PacketHandlerMetrics struct {
+	Type        uint16
+	Name        string
+	Handled     uint64
+	Failed      uint64
+	Rejected    uint64
+	ParseErrors uint64
+	TotalTime   time.Duration
+	MaxTime     time.Duration
+}
+
+type registeredHandler struct {
+	handler PacketHandler
+	metrics PacketHandlerMetrics
+}
+
+// PacketRegistry dispatches client packets to the handler registered for their type.
+type PacketRegistry struct {
+	mu         sync.Mutex
+	handlers   map[uint16]*registeredHandler
+	middleware []PacketMiddleware
+}
+
+func NewPacketRegistry() *PacketRegistry {
+	return &PacketRegistry{
+		handlers: make(map[uint16]*registeredHandler),
+	}
+}
+
+// Use adds middleware that is applied to every handler registered afterwards.
+func (r *PacketRegistry) Use(middleware ...PacketMiddleware) {
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Handle registers the handler for a packet type. The packet type must have a
+// parser registered in the client packets package. Middleware is applied in order,
+// after the registry-wide middleware.
+func (r *PacketRegistry) Handle(packetType uint16, handler PacketHandler, middleware ...PacketMiddleware) {
+	definition, ok := clientPackets.Lookup(packetType)
+	if !ok {
+		panic(fmt.Sprintf("no parser registered for client packet 0x%03X", packetType))
+	}
+
+	if _, exists := r.handlers[packetType]; exists {
+		panic(fmt.Sprintf("handler for client packet 0x%03X registered twice", packetType))
+	}
+
+	chain := append(append([]PacketMiddleware{}, r.middleware...), middleware...)
+	for i := len(chain) - 1; i >= 0; i-- {
+		handler = chain[i](handler)
+	}
+
+	r.handlers[packetType] = &registeredHandler{
+		handler: handler,
+		metrics: PacketHandlerMetrics{Type: packetType, Name: definition.Name},
+	}
+}
+
+// Dispatch parses a routed client packet and runs its handler.
+func (r *PacketRegistry) Dispatch(routedPacket *mapPackets.RoutedPacket, z *zone.Zone) (time.Duration, error) {
+	registered, ok := r.handlers[routedPacket.Packet.Type]
+	if !ok {
+		return 0, fmt.Errorf("%w: 0x%03X", ErrNoPacketHandler, routedPacket.Packet.Type)
+	}
+
+	packet, definition, err := clientPackets.Parse(routedPacket.Packet.Data)
+	if err != nil {
+		r.mu.Lock()
+		registered.metrics.ParseErrors++
+		r.mu.Unlock()
+
+		return 0, err
+	}
+
+	pctx := &PacketContext{
+		ClientAddr:  routedPacket.ClientAddr,
+		CharacterID: routedPacket.CharacterID,
+		Sequence:    routedPacket.Packet.Sequence,
+		Definition:  definition,
+		Zone:        z,
+	}
+
+	start := time.Now()
+	err = registered.handler(pctx, packet)
+	elapsed := time.Since(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics := &registered.metrics
+	switch {
+	case errors.Is(err, ErrPacketRejected):
+		metrics.Rejected++
+	case err != nil:
+		metrics.Failed++
+	default:
+		metrics.Handled++
+	}
+
+	metrics.TotalTime += elapsed
+	metrics.MaxTime = max(metrics.MaxTime, elapsed)
+
+	return elapsed, err
+}
+
+// Metrics returns a snapshot of the per-handler metrics, ordered by packet type.
+func (r *PacketRegistry) Metrics() []PacketHandlerMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := make([]PacketHandlerMetrics, 0, len(r.handlers))
+	for _, registered := range r.handlers {
+		snapshot = append(snapshot, registered.metrics)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Type < snapshot[j].Type
+	})
+
+	return snapshot
+}
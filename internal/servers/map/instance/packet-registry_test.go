@@ -0,0 +1,85 @@
+package instance
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
+)
+
+func routedLoginPacket(characterID uint32) *mapPackets.RoutedPacket {
+	data := make([]byte, clientPackets.PacketSizeLogin)
+	binary.LittleEndian.PutUint16(data[0:], clientPackets.PacketTypeLogin|(clientPackets.PacketSizeLogin/4)<<9)
+
+	return &mapPackets.RoutedPacket{
+		ClientAddr:  "127.0.0.1:54230",
+		CharacterID: characterID,
+		Packet: mapPackets.BasicPacket{
+			Type: clientPackets.PacketTypeLogin,
+			Size: uint16(len(data)),
+			Data: data,
+		},
+	}
+}
+
+func TestPacketRegistryDispatch(t *testing.T) {
+	registry := NewPacketRegistry()
+
+	var handled *clientPackets.LoginPacket
+	registry.Handle(clientPackets.PacketTypeLogin, Typed(func(_ *PacketContext, packet *clientPackets.LoginPacket) error {
+		handled = packet
+		return nil
+	}), RequireCharacter())
+
+	if _, err := registry.Dispatch(routedLoginPacket(0), nil); !errors.Is(err, ErrPacketRejected) {
+		t.Fatalf("Dispatch() error = %v, want %v", err, ErrPacketRejected)
+	}
+
+	if handled != nil {
+		t.Fatal("Dispatch() ran the handler for a packet without a character")
+	}
+
+	if _, err := registry.Dispatch(routedLoginPacket(1), nil); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	if handled == nil {
+		t.Fatal("Dispatch() did not run the handler")
+	}
+
+	metrics := registry.Metrics()
+	if len(metrics) != 1 || metrics[0].Handled != 1 || metrics[0].Rejected != 1 {
+		t.Fatalf("Metrics() = %+v, want 1 handled and 1 rejected", metrics)
+	}
+}
+
+func TestPacketRegistryUnhandled(t *testing.T) {
+	registry := NewPacketRegistry()
+	if _, err := registry.Dispatch(routedLoginPacket(1), nil); !errors.Is(err, ErrNoPacketHandler) {
+		t.Fatalf("Dispatch() error = %v, want %v", err, ErrNoPacketHandler)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(2, 2)
+	now := time.Unix(0, 0)
+
+	if !limiter.allow("a", now) || !limiter.allow("a", now) {
+		t.Fatal("allow() rejected packets within the burst")
+	}
+
+	if limiter.allow("a", now) {
+		t.Fatal("allow() accepted a packet over the burst")
+	}
+
+	if !limiter.allow("b", now) {
+		t.Fatal("allow() shared buckets between clients")
+	}
+
+	if !limiter.allow("a", now.Add(500*time.Millisecond)) {
+		t.Fatal("allow() did not refill the bucket")
+	}
+}
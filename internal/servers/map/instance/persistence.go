@@ -0,0 +1,137 @@
+package instance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	saveQueueSize = 1024
+
+	// finalSaveTimeout bounds how long the queued saves may take once the instance is shutting down
+	finalSaveTimeout = 10 * time.Second
+)
+
+// pendingSave is a database write queued by a zone.
+type pendingSave struct {
+	description string
+	characterID uint32
+	save        func(ctx context.Context) error
+}
+
+// queueSave hands a write to the save goroutine, so zone loops never wait on the
+// database and writes for a character are applied in the order they were queued.
+// When the queue is full, saves wait in an overflow list instead of blocking the zone.
+func (s *InstanceWorker) queueSave(description string, characterID uint32, save func(ctx context.Context) error) {
+	pending := pendingSave{description: description, characterID: characterID, save: save}
+
+	s.overflowMu.Lock()
+	defer s.overflowMu.Unlock()
+
+	// saves only go through the queue while nothing waits in the overflow, so they
+	// are applied in order
+	if len(s.overflow) == 0 {
+		select {
+		case s.saves <- pending:
+			return
+		default:
+			s.Logger().Warn("save queue is full, the database is falling behind", "queued", cap(s.saves))
+		}
+	}
+
+	s.overflow = append(s.overflow, pending)
+
+	select {
+	case s.overflowed <- struct{}{}:
+	default:
+	}
+}
+
+// ProcessSaves applies queued saves until the context is cancelled. It then waits
+// for the zones to stop (they queue their final saves when stopping) and drains the queue.
+func (s *InstanceWorker) ProcessSaves(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.zonesWG.Wait()
+
+			finalCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finalSaveTimeout)
+			defer cancel()
+
+			s.drainSaves(finalCtx)
+			return
+		case pending := <-s.saves:
+			s.applySave(ctx, pending)
+		case <-s.overflowed:
+			s.drainSaves(ctx)
+		}
+	}
+}
+
+// drainSaves applies the queued saves, then those of the overflow, until both are
+// empty. No save enters the queue while the overflow holds any, so once the queue is
+// drained the overflow holds the next ones.
+func (s *InstanceWorker) drainSaves(ctx context.Context) {
+	for {
+		select {
+		case pending := <-s.saves:
+			s.applySave(ctx, pending)
+			continue
+		default:
+		}
+
+		s.overflowMu.Lock()
+		overflow := s.overflow
+		s.overflow = nil
+		s.overflowMu.Unlock()
+
+		if len(overflow) == 0 {
+			return
+		}
+
+		for _, pending := range overflow {
+			s.applySave(ctx, pending)
+		}
+	}
+}
+
+func (s *InstanceWorker) applySave(ctx context.Context, pending pendingSave) {
+	if err := pending.save(ctx); err != nil {
+		s.Logger().Error("failed to save character state", "what", pending.description, "characterID", pending.characterID, "error", err)
+	}
+}
+
+// savePlayerPosition queues a write of the player's position, if it changed.
+func (s *InstanceWorker) savePlayerPosition(z *zone.Zone, player *zone.Player) {
+	if !player.PositionDirty {
+		return
+	}
+
+	player.PositionDirty = false
+	zoneID := z.ID()
+	position := player.Position
+
+	s.queueSave("position", player.CharacterID, func(ctx context.Context) error {
+		return s.DB().UpdateCharacterPosition(ctx, player.CharacterID, zoneID, position.X, position.Y, position.Z, position.Rotation)
+	})
+}
+
+// savePlayer queues writes of everything about the player that changed since it was last persisted.
+func (s *InstanceWorker) savePlayer(z *zone.Zone, player *zone.Player) {
+	s.savePlayerPosition(z, player)
+	s.savePlayerStats(player)
+	s.savePlayerEffects(z, player, effects.FlagLogout)
+}
+
+// autosave persists the state of every player in the zone.
+func (s *InstanceWorker) autosave(z *zone.Zone) {
+	for _, player := range z.Players() {
+		s.savePlayer(z, player)
+	}
+}
@@ -0,0 +1,37 @@
+package instance
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestQueueSaveOverflow(t *testing.T) {
+	s := newTestWorker()
+	s.saves = make(chan pendingSave, 2)
+
+	// the queue is full after two saves; the zone goes on and the rest wait their turn
+	var applied []int
+	for i := range 5 {
+		s.queueSave("test", 1, func(context.Context) error {
+			applied = append(applied, i)
+			return nil
+		})
+	}
+
+	if len(s.saves) != 2 || len(s.overflow) != 3 || len(s.overflowed) != 1 {
+		t.Fatalf("queued = %d, overflow = %d, want 2 and 3", len(s.saves), len(s.overflow))
+	}
+
+	s.drainSaves(context.Background())
+
+	if want := []int{0, 1, 2, 3, 4}; !slices.Equal(applied, want) {
+		t.Fatalf("applied = %v, want %v", applied, want)
+	}
+
+	// with the overflow empty, saves go through the queue again
+	s.queueSave("test", 1, func(context.Context) error { return nil })
+	if len(s.saves) != 1 || len(s.overflow) != 0 {
+		t.Fatalf("queued = %d, overflow = %d, want 1 and 0", len(s.saves), len(s.overflow))
+	}
+}
@@ -0,0 +1,161 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	// playerSubjectPrefix is followed by the lowercase name of a character and the kind of
+	// request (e.g. map.player.shantotto.tell); the instance the character is on answers it
+	playerSubjectPrefix = "map.player."
+
+	// playerRequestTimeout bounds how long a request to another instance's player may take
+	playerRequestTimeout = 2 * time.Second
+
+	// playerOffline is the reply to requests for a character that is not in the world anymore
+	playerOffline = "offline"
+
+	// playerRequestErrorHeader carries the error a request failed with
+	playerRequestErrorHeader = "Player-Request-Error"
+)
+
+const (
+	playerRequestTell   = "tell"
+	playerRequestLocate = "locate"
+	playerRequestKick   = "kick"
+)
+
+var ErrPlayerOffline = errors.New("player is offline")
+
+// playerRequestHandler answers a request for a player, on the player's zone goroutine.
+type playerRequestHandler func(z *zone.Zone, player *zone.Player, data []byte) ([]byte, error)
+
+// playerRequestHandlers returns the handler of each kind of player request.
+func (s *InstanceWorker) playerRequestHandlers() map[string]playerRequestHandler {
+	return map[string]playerRequestHandler{
+		playerRequestTell:   s.receiveTell,
+		playerRequestLocate: s.receiveLocate,
+		playerRequestKick:   s.receiveKick,
+	}
+}
+
+// requestPlayer sends a request to the instance a character is on and returns its reply.
+func (s *InstanceWorker) requestPlayer(name, request string, data []byte) ([]byte, error) {
+	subject, ok := playerSubject(name, request)
+	if !ok {
+		return nil, ErrPlayerOffline
+	}
+
+	reply, err := s.NATS().Request(subject, data, playerRequestTimeout)
+	if errors.Is(err, nats.ErrNoResponders) || errors.Is(err, nats.ErrTimeout) {
+		return nil, ErrPlayerOffline
+	} else if err != nil {
+		return nil, err
+	}
+
+	if string(reply.Data) == playerOffline {
+		return nil, ErrPlayerOffline
+	}
+
+	if errMessage := reply.Header.Get(playerRequestErrorHeader); errMessage != "" {
+		return nil, errors.New(errMessage)
+	}
+
+	return reply.Data, nil
+}
+
+// subscribePlayer makes a player that entered a zone of this instance reachable by requests.
+func (s *InstanceWorker) subscribePlayer(player *zone.Player) {
+	subject, ok := playerSubject(player.Name, "*")
+	if !ok {
+		return
+	}
+
+	characterID := player.CharacterID
+	subscription, err := s.NATS().Subscribe(subject, func(msg *nats.Msg) {
+		s.answerPlayerRequest(characterID, msg)
+	})
+	if err != nil {
+		s.Logger().Error("failed to subscribe to player requests", "characterID", characterID, "error", err)
+		return
+	}
+
+	s.playerSubscriptionsMu.Lock()
+	defer s.playerSubscriptionsMu.Unlock()
+
+	if previous, ok := s.playerSubscriptions[characterID]; ok {
+		_ = previous.Unsubscribe()
+	}
+
+	s.playerSubscriptions[characterID] = subscription
+}
+
+// unsubscribePlayer stops routing requests to a player that left its zone.
+func (s *InstanceWorker) unsubscribePlayer(characterID uint32) {
+	s.playerSubscriptionsMu.Lock()
+	defer s.playerSubscriptionsMu.Unlock()
+
+	if subscription, ok := s.playerSubscriptions[characterID]; ok {
+		_ = subscription.Unsubscribe()
+		delete(s.playerSubscriptions, characterID)
+	}
+}
+
+// answerPlayerRequest runs the handler of a request on the player's zone and replies with its result.
+func (s *InstanceWorker) answerPlayerRequest(characterID uint32, msg *nats.Msg) {
+	request := msg.Subject[strings.LastIndexByte(msg.Subject, '.')+1:]
+	handler, ok := s.playerRequestHandlers()[request]
+	if !ok {
+		return
+	}
+
+	z := s.zoneForCharacter(characterID)
+	if z == nil {
+		_ = msg.Respond([]byte(playerOffline))
+		return
+	}
+
+	err := z.Post(func(z *zone.Zone) {
+		player := z.Player(characterID)
+		if player == nil || player.Disconnected {
+			_ = msg.Respond([]byte(playerOffline))
+			return
+		}
+
+		reply, err := handler(z, player, msg.Data)
+		if err != nil {
+			response := nats.NewMsg(msg.Reply)
+			response.Header.Set(playerRequestErrorHeader, err.Error())
+			_ = msg.RespondMsg(response)
+			return
+		}
+
+		_ = msg.Respond(reply)
+	})
+	if err != nil {
+		_ = msg.Respond([]byte(playerOffline))
+	}
+}
+
+// playerSubject returns the subject a request for a character is sent to. Character names
+// only contain letters; anything else cannot be a character (nor a safe subject).
+func playerSubject(name, request string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return "", false
+		}
+	}
+
+	return fmt.Sprintf("%s%s.%s", playerSubjectPrefix, strings.ToLower(name), request), true
+}
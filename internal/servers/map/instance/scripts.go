@@ -0,0 +1,254 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/scripting"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// zoneScriptModule is the module of a zone's own script, whose onInitialize is called
+// when the zone starts and onZoneIn when a character enters it
+const zoneScriptModule = "Zone"
+
+// scriptsDir returns the directory holding the scripts of a zone, named after the
+// zone; zones missing from the game data have no scripts.
+func (s *InstanceWorker) scriptsDir(zoneID uint16) (string, bool) {
+	zoneData, ok := s.gameData.Current().Zones[zoneID]
+	if !ok || zoneData.Name == "" {
+		return "", false
+	}
+
+	return filepath.Join(s.cfg.MapScriptsPath, "zones", zoneData.Name), true
+}
+
+// compileZoneScripts reads and compiles the scripts of a zone.
+func (s *InstanceWorker) compileZoneScripts(zoneID uint16) (*scripting.Scripts, error) {
+	dir, ok := s.scriptsDir(zoneID)
+	if !ok {
+		return &scripting.Scripts{}, nil
+	}
+
+	return scripting.Compile(dir)
+}
+
+// newZoneScripts creates the script runtime of a new zone and loads its scripts. A
+// zone whose scripts do not load runs without them.
+func (s *InstanceWorker) newZoneScripts(z *zone.Zone) *scripting.Runtime {
+	logger := z.Logger().With("component", "scripts")
+	runtime := scripting.New(scripting.Options{
+		Host:        &scriptHost{s: s, z: z},
+		Logger:      logger,
+		CallTimeout: time.Duration(s.Config().MapScriptTimeoutMilliseconds) * time.Millisecond,
+	})
+
+	scripts, err := s.compileZoneScripts(z.ID())
+	if err == nil {
+		err = runtime.Load(scripts)
+	}
+
+	if err != nil {
+		logger.Error("could not load the zone's scripts, running without them", "error", err)
+		return runtime
+	}
+
+	if scripts.Len() > 0 {
+		logger.Info("loaded zone scripts", "scripts", scripts.Len())
+	}
+
+	return runtime
+}
+
+// zoneScripts returns the script runtime of a zone this instance is simulating, or nil.
+func (s *InstanceWorker) zoneScripts(zoneID uint16) *scripting.Runtime {
+	s.zonesMu.Lock()
+	defer s.zonesMu.Unlock()
+
+	return s.scripts[zoneID]
+}
+
+// callScript calls a function of a zone's scripts, reporting whether the scripts
+// define it. Script errors are logged rather than returned: a broken script only
+// fails what it was doing.
+func (s *InstanceWorker) callScript(z *zone.Zone, module, function string, args ...any) bool {
+	runtime := s.zoneScripts(z.ID())
+	if runtime == nil {
+		return false
+	}
+
+	handled, err := runtime.Call(module, function, args...)
+	if err != nil {
+		z.Logger().Warn("script failed", "module", module, "function", function, "error", err)
+	}
+
+	return handled
+}
+
+// reloadZoneScripts compiles the scripts of a zone again and swaps them in on the
+// zone goroutine, returning the number of scripts loaded. The running scripts are
+// kept if the new ones fail to load.
+func (s *InstanceWorker) reloadZoneScripts(ctx context.Context, z *zone.Zone) (int, error) {
+	scripts, err := s.compileZoneScripts(z.ID())
+	if err != nil {
+		return 0, err
+	}
+
+	loaded := make(chan error, 1)
+	err = z.Post(func(z *zone.Zone) {
+		runtime := s.zoneScripts(z.ID())
+		if runtime == nil {
+			loaded <- fmt.Errorf("zone %d has no script runtime", z.ID())
+			return
+		}
+
+		loaded <- runtime.Load(scripts)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	select {
+	case err = <-loaded:
+		return scripts.Len(), err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// scriptHost is how the scripts of a zone act on it. It is only used from the zone
+// goroutine.
+type scriptHost struct {
+	s *InstanceWorker
+	z *zone.Zone
+}
+
+func (h *scriptHost) PlayerName(characterID uint32) (string, bool) {
+	player := h.z.Player(characterID)
+	if player == nil {
+		return "", false
+	}
+
+	return player.Name, true
+}
+
+func (h *scriptHost) SpawnMob(mobID uint32) bool {
+	mob := h.z.Mob(mobID)
+	if mob == nil {
+		// mobs that do not spawn with the zone are only added once something spawns them
+		point, ok := h.s.gameData.Current().SpawnPoints[mobID]
+		if !ok || point.Zone() != h.z.ID() {
+			return false
+		}
+
+		mob = newSpawnPointMob(point)
+		h.z.AddMob(mob)
+	}
+
+	if mob.Spawned {
+		return false
+	}
+
+	h.s.spawnMob(h.z, mob)
+
+	return mob.Spawned
+}
+
+func (h *scriptHost) DespawnMob(mobID uint32) bool {
+	mob := h.z.Mob(mobID)
+	if mob == nil || !mob.Spawned {
+		return false
+	}
+
+	h.s.despawnMob(h.z, mob)
+
+	return true
+}
+
+func (h *scriptHost) NPCName(npcID uint32) (string, bool) {
+	npc, ok := h.s.gameData.Current().NPCs[npcID]
+	if !ok || npc.Zone() != h.z.ID() {
+		return "", false
+	}
+
+	return npc.Name, true
+}
+
+func (h *scriptHost) StartEvent(characterID uint32, eventID uint16, params []uint32) bool {
+	player := h.z.Player(characterID)
+	if player == nil {
+		return false
+	}
+
+	return h.s.startEvent(h.z, player, eventID, params)
+}
+
+func (h *scriptHost) UpdateEvent(characterID uint32, params []uint32) bool {
+	player := h.z.Player(characterID)
+	return player != nil && updateEvent(h.z, player, params)
+}
+
+func (h *scriptHost) AddItem(characterID uint32, itemID uint16, quantity uint32) bool {
+	player := h.z.Player(characterID)
+	if player == nil || player.Inventory == nil || itemID == inventory.GilItemID {
+		return false
+	}
+
+	item := inventory.Item{ID: itemID, Quantity: quantity}
+	changed, err := player.Inventory.Add(serverPackets.ContainerKindInventory, item, h.s.stackSize)
+	if err != nil {
+		return false
+	}
+
+	h.s.syncItems(h.z, player, changed)
+
+	return true
+}
+
+func (h *scriptHost) HasKeyItem(characterID uint32, keyItemID uint16) bool {
+	player := h.z.Player(characterID)
+	return player != nil && player.KeyItems[keyItemID]
+}
+
+func (h *scriptHost) AddKeyItem(characterID uint32, keyItemID uint16) bool {
+	player := h.z.Player(characterID)
+	return player != nil && h.s.addKeyItem(h.z, player, keyItemID)
+}
+
+func (h *scriptHost) DelKeyItem(characterID uint32, keyItemID uint16) bool {
+	player := h.z.Player(characterID)
+	return player != nil && h.s.delKeyItem(h.z, player, keyItemID)
+}
+
+func (h *scriptHost) CharVar(characterID uint32, name string) int32 {
+	player := h.z.Player(characterID)
+	if player == nil {
+		return 0
+	}
+
+	return player.Vars[name]
+}
+
+func (h *scriptHost) SetCharVar(characterID uint32, name string, value int32) {
+	if player := h.z.Player(characterID); player != nil {
+		h.s.setCharacterVar(player, name, value)
+	}
+}
+
+func (h *scriptHost) Message(characterID uint32, text string) {
+	if player := h.z.Player(characterID); player != nil {
+		h.z.Send(player.ClientAddr, createSystemChatPacket(text))
+	}
+}
+
+func (h *scriptHost) After(d time.Duration, fn func()) uint64 {
+	return uint64(h.z.After(d, func(*zone.Zone) { fn() }))
+}
+
+func (h *scriptHost) Cancel(timerID uint64) {
+	h.z.Cancel(zone.TimerID(timerID))
+}
@@ -0,0 +1,220 @@
+package instance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/scripting"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// scriptTest is zone 230 ("Test_Zone") with a player and the mobs of testMobData, and
+// the NPCs of testNPCs, whose scripts are read from a temporary directory.
+type scriptTest struct {
+	s      *InstanceWorker
+	z      *zone.Zone
+	player *zone.Player
+	dir    string
+	sent   []serverPackets.ServerPacket
+}
+
+func newScriptTest(t *testing.T, files map[string]string) *scriptTest {
+	t.Helper()
+
+	data := testMobData()
+	data.Zones = map[uint16]gamedata.Zone{230: {ID: 230, Name: "Test_Zone"}}
+	data.NPCs = testNPCs()
+
+	st := &scriptTest{s: newTestWorker(), dir: t.TempDir()}
+	st.s.gameData = gamedata.NewStoreFromData(data)
+	st.s.cfg.MapScriptsPath = st.dir
+	st.s.scripts = make(map[uint16]*scripting.Runtime)
+
+	st.z = zone.New(230, zone.Options{
+		TickInterval: time.Second,
+		Clock:        zone.NewVirtualClock(time.Unix(0, 0)),
+		Sender: func(_ string, packet serverPackets.ServerPacket) error {
+			st.sent = append(st.sent, packet)
+			return nil
+		},
+		Visibility: buildVisibilityPacket,
+	})
+
+	st.player = &zone.Player{CharacterID: 1, ClientAddr: "127.0.0.1:1000", Name: "Tester", Inventory: inventory.New(inventory.DefaultSizes)}
+	st.z.AddPlayer(st.player)
+	st.s.spawnZoneNPCs(st.z)
+
+	st.write(t, files)
+	st.s.scripts[230] = st.s.newZoneScripts(st.z)
+	t.Cleanup(st.s.scripts[230].Close)
+
+	return st
+}
+
+// write writes script files of the zone.
+func (st *scriptTest) write(t *testing.T, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(st.dir, "zones", "Test_Zone", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func (st *scriptTest) zoneIn() bool {
+	return st.s.callScript(st.z, zoneScriptModule, "onZoneIn", scripting.Player{CharacterID: st.player.CharacterID})
+}
+
+func TestScriptAPI(t *testing.T) {
+	st := newScriptTest(t, map[string]string{
+		"Zone.lua": `
+			function onZoneIn(player)
+				player:addKeyItem(515)
+				player:setCharVar("quest", 2)
+				player:addItem(4096)
+				player:startEvent(5, 7)
+				SpawnMob(0x010E6012)
+				SetTimer(10, function() player:message("later") end)
+			end
+		`,
+	})
+
+	if !st.zoneIn() {
+		t.Fatalf("callScript() = false, want the zone script's onZoneIn called")
+	}
+
+	if !st.player.KeyItems[515] || st.player.Vars["quest"] != 2 {
+		t.Fatalf("key items = %v, vars = %v", st.player.KeyItems, st.player.Vars)
+	}
+
+	if item, ok := st.player.Inventory.Get(inventory.Location{Container: serverPackets.ContainerKindInventory, Slot: 1}); !ok || item.ID != 4096 || item.Quantity != 1 {
+		t.Fatalf("inventory slot 1 = %+v, %v, want item 4096", item, ok)
+	}
+
+	// scripted mobs are added to the zone when spawned
+	if mob := st.z.Mob(testMobID + 2); mob == nil || !mob.Spawned {
+		t.Fatalf("scripted mob = %+v, want it spawned", mob)
+	}
+
+	// the key item, the variable and the item are persisted
+	if len(st.s.saves) != 3 {
+		t.Fatalf("queued saves = %d, want 3", len(st.s.saves))
+	}
+
+	st.z.Step()
+
+	var keyItems *serverPackets.ScenarioItemPacket
+	var event *serverPackets.EventNumPacket
+	for _, packet := range st.sent {
+		switch p := packet.(type) {
+		case *serverPackets.ScenarioItemPacket:
+			keyItems = p
+		case *serverPackets.EventNumPacket:
+			event = p
+		}
+	}
+
+	if keyItems == nil || keyItems.TableIndex != 1 || keyItems.GetItemFlag[0] != 1<<3 {
+		t.Fatalf("key item packet = %+v, want key item 3 of table 1", keyItems)
+	}
+
+	if event == nil || event.EventPara != 5 || event.Num[0] != 7 || event.EventNum != 230 || event.UniqueNo != 1 {
+		t.Fatalf("event packet = %+v, want event 5 of zone 230 with param 7", event)
+	}
+
+	st.sent = nil
+	st.z.Advance(10 * time.Second)
+
+	if len(st.sent) != 1 {
+		t.Fatalf("packets after the script timer = %d, want its message", len(st.sent))
+	}
+}
+
+func TestScriptErrorsAreIsolated(t *testing.T) {
+	st := newScriptTest(t, map[string]string{
+		"Zone.lua": `
+			function onZoneIn(player)
+				player:setCharVar("before", 1)
+				error("broken")
+			end
+		`,
+	})
+
+	if !st.zoneIn() {
+		t.Fatalf("callScript() = false, want the failing function reported as defined")
+	}
+
+	// what the script did before failing stays done
+	if st.player.Vars["before"] != 1 {
+		t.Fatalf("vars = %v", st.player.Vars)
+	}
+
+	if st.s.callScript(st.z, "npcs/Nobody", "onTrigger") {
+		t.Fatalf("callScript() of a missing module = true")
+	}
+}
+
+func TestReloadZoneScripts(t *testing.T) {
+	st := newScriptTest(t, map[string]string{
+		"Zone.lua": `function onZoneIn(player) player:setCharVar("version", 1) end`,
+	})
+
+	reload := func() (int, error) {
+		var count int
+		var err error
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			count, err = st.s.reloadZoneScripts(context.Background(), st.z)
+		}()
+
+		// the scripts are swapped in on the zone goroutine
+		for {
+			select {
+			case <-done:
+				return count, err
+			case <-time.After(time.Millisecond):
+				st.z.Step()
+			}
+		}
+	}
+
+	// scripts that do not load leave the running ones in place
+	st.write(t, map[string]string{"Zone.lua": `error("broken")`})
+	if _, err := reload(); err == nil {
+		t.Fatalf("reloadZoneScripts() of a broken script succeeded")
+	}
+
+	st.zoneIn()
+	if st.player.Vars["version"] != 1 {
+		t.Fatalf("version = %d after a failed reload, want 1", st.player.Vars["version"])
+	}
+
+	st.write(t, map[string]string{
+		"Zone.lua":       `function onZoneIn(player) player:setCharVar("version", 2) end`,
+		"npcs/Guard.lua": `function onTrigger(player) end`,
+	})
+
+	count, err := reload()
+	if err != nil || count != 2 {
+		t.Fatalf("reloadZoneScripts() = %d, %v, want 2 scripts", count, err)
+	}
+
+	st.zoneIn()
+	if st.player.Vars["version"] != 2 {
+		t.Fatalf("version = %d after reloading, want 2", st.player.Vars["version"])
+	}
+}
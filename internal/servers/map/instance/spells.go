@@ -0,0 +1,155 @@
+package instance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/combat"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// castingMoveTolerance is how far casters can move (e.g. from their position updates
+// jittering) before their spell is interrupted
+const castingMoveTolerance = 0.5
+
+// startCasting starts casting a spell of a player at a target. Spells that cannot be
+// cast right now (out of range, not enough MP, ...) are refused with a message rather
+// than an error: the client cannot know all of it.
+func (s *InstanceWorker) startCasting(z *zone.Zone, player *zone.Player, spellID uint16, targetID uint32, targetIndex uint16) error {
+	if playerDefeated(player) {
+		return ErrDefeated
+	}
+
+	spell, ok := s.gameData.Current().Spells[spellID]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnknownSpell, spellID)
+	}
+
+	if !playerCanUse(player, spell.Level) {
+		return fmt.Errorf("%w: spell %d", ErrActionUnavailable, spellID)
+	}
+
+	now := z.Now()
+	target, message := spellCheck(z, player, spell, targetID, targetIndex)
+	switch {
+	case player.Casting != nil:
+		message = serverPackets.MessageUnableToCast
+	case !player.Recasts.Ready(zone.RecastSpell, spell.ID, now):
+		message = serverPackets.MessageWaitLonger
+	}
+
+	if message != 0 {
+		refuseAction(z, player, target, message, spell.ID)
+		return nil
+	}
+
+	player.Casting = &zone.Casting{
+		SpellID:     spell.ID,
+		TargetID:    targetID,
+		TargetIndex: targetIndex,
+		From:        player.Position,
+		CompletesAt: now.Add(time.Duration(spell.CastTime) * time.Millisecond),
+	}
+
+	result := serverPackets.BattleResult{Param: uint32(spell.ID), Message: serverPackets.MessageStartsCasting}
+	sendNearby(z, player.Position, createActionPacket(player, target, serverPackets.BattleCategoryMagicStart, serverPackets.BattleCastStart, 0, result))
+
+	return nil
+}
+
+// spellCheck resolves the target of a spell and checks the caster can cast it there,
+// returning the target and the message telling why it cannot, or 0.
+func spellCheck(z *zone.Zone, player *zone.Player, spell gamedata.Spell, targetID uint32, targetIndex uint16) (zone.Entity, uint16) {
+	target, ok := actionTarget(z, player, targetID, targetIndex, spell.ValidTargets)
+	if !ok {
+		return target, serverPackets.MessageCannotOnTarget
+	}
+
+	if message := actionReachMessage(player, target, spell.Yalms(), serverPackets.MessageTooFarToCast); message != 0 {
+		return target, message
+	}
+
+	if player.Stats == nil || player.Stats.MP < spell.MPCost {
+		return target, serverPackets.MessageNotEnoughMP
+	}
+
+	return target, 0
+}
+
+// updateCasting finishes the spells of the zone's players whose casting time is over,
+// and interrupts those of players who moved or were defeated; it is a zone system.
+func (s *InstanceWorker) updateCasting(z *zone.Zone, now time.Time) {
+	for _, player := range z.Players() {
+		casting := player.Casting
+		if casting == nil {
+			continue
+		}
+
+		switch {
+		case player.Disconnected || playerDefeated(player) || player.Position.HorizontalDistance(casting.From) > castingMoveTolerance:
+			interruptCasting(z, player)
+		case !now.Before(casting.CompletesAt):
+			s.finishCasting(z, player, casting, now)
+		}
+	}
+}
+
+// finishCasting casts the spell a player finished casting. The target may have moved
+// away or died meanwhile, so everything is checked again before the MP is spent.
+func (s *InstanceWorker) finishCasting(z *zone.Zone, player *zone.Player, casting *zone.Casting, now time.Time) {
+	spell, ok := s.gameData.Current().Spells[casting.SpellID]
+	if !ok {
+		// the spell was removed by a game data reload
+		interruptCasting(z, player)
+		return
+	}
+
+	target, message := spellCheck(z, player, spell, casting.TargetID, casting.TargetIndex)
+	if message != 0 {
+		interruptCasting(z, player)
+		refuseAction(z, player, target, message, spell.ID)
+		return
+	}
+
+	player.Casting = nil
+	player.Stats.MP -= spell.MPCost
+	player.StatsDirty = true
+	player.MarkChanged()
+	sendPackets(z, player.ClientAddr, CreateGroupAttrPacket(player))
+
+	player.Recasts.Start(zone.RecastSpell, spell.ID, time.Duration(spell.RecastTime)*time.Millisecond, now)
+
+	action := spellAction(spell)
+	s.actionEnmity(z, player, target, &action)
+	outcome := s.resolveAction(z, player, target, &action, s.spellHooks[spell.Name])
+
+	sendNearby(z, player.Position, createActionPacket(player, target, serverPackets.BattleCategoryMagicFinish, uint32(spell.ID), 0, outcome.result))
+	s.applyActionOutcome(z, player, target, outcome)
+}
+
+// interruptCasting stops the spell a player is casting, without spending its MP.
+func interruptCasting(z *zone.Zone, player *zone.Player) {
+	casting := player.Casting
+	if casting == nil {
+		return
+	}
+
+	player.Casting = nil
+
+	result := serverPackets.BattleResult{Param: uint32(casting.SpellID), Message: serverPackets.MessageIsInterrupted}
+	sendNearby(z, player.Position, createActionPacket(player, player, serverPackets.BattleCategoryMagicStart, serverPackets.BattleCastInterrupt, 0, result))
+}
+
+// interruptOnHit may interrupt the spell of a player hit by a mob.
+func interruptOnHit(z *zone.Zone, player *zone.Player, mob *zone.Mob) {
+	if player.Casting == nil {
+		return
+	}
+
+	level, _ := playerLevels(player)
+	if z.Rand().Float64() < combat.InterruptRate(level, mob.Level) {
+		interruptCasting(z, player)
+	}
+}
@@ -0,0 +1,201 @@
+package instance
+
+import (
+	"context"
+	"math"
+
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/experience"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// playerLevels returns the main job level and the effective support job level of a player.
+func playerLevels(player *zone.Player) (uint8, uint8) {
+	if player.Character == nil || player.Character.Jobs == nil || player.Stats == nil {
+		return 1, 0
+	}
+
+	mainLevel := max(player.Character.GetMainJobLevel(), 1)
+	if player.Stats.SubJob == 0 || !player.Character.Jobs.SubJobUnlocked {
+		return mainLevel, 0
+	}
+
+	return mainLevel, charstats.SubJobLevel(mainLevel, player.Character.Jobs.Level(player.Stats.SubJob))
+}
+
+// statsInput gathers what the stats of a player depend on.
+func (s *InstanceWorker) statsInput(player *zone.Player) charstats.Input {
+	in := charstats.Input{Mods: make(charstats.Modifiers)}
+	in.MainLevel, in.SubLevel = playerLevels(player)
+
+	if player.Looks != nil {
+		in.Race = player.Looks.Race
+	}
+
+	if player.Stats != nil {
+		in.MainJob = player.Stats.MainJob
+		if in.SubLevel > 0 {
+			in.SubJob = player.Stats.SubJob
+		}
+	}
+
+	if player.Inventory != nil {
+		in.Mods.Merge(equipmentMods(player.Inventory, s.gameData.Current().ItemMods))
+	}
+
+	in.Mods.Merge(player.EffectMods)
+	return in
+}
+
+// equipmentMods sums the modifiers of every equipped item.
+func equipmentMods(inv *inventory.Inventory, itemMods map[uint16][]gamedata.ItemMod) charstats.Modifiers {
+	mods := make(charstats.Modifiers)
+	for slot := serverPackets.EquipKind(0); slot < serverPackets.EquipKindEnd; slot++ {
+		location, ok := inv.Equipped(slot)
+		if !ok {
+			continue
+		}
+
+		item, _ := inv.Get(location)
+		for _, mod := range itemMods[item.ID] {
+			mods.Add(charstats.Mod(mod.ModID), int32(mod.Value))
+		}
+	}
+
+	return mods
+}
+
+// refreshStats recomputes the stats of a player, keeping its HP and MP within the new
+// maximums. It reports whether anything changed.
+func (s *InstanceWorker) refreshStats(player *zone.Player) bool {
+	derived := charstats.Calculate(s.statsInput(player))
+	if derived == player.Derived {
+		return false
+	}
+
+	player.Derived = derived
+	if player.Stats != nil {
+		hp := uint16(min(int32(player.Stats.HP), derived.MaxHP)) //nolint:gosec // bounded by the stored HP
+		mp := uint16(min(int32(player.Stats.MP), derived.MaxMP)) //nolint:gosec // bounded by the stored MP
+		if hp != player.Stats.HP || mp != player.Stats.MP {
+			player.Stats.HP, player.Stats.MP = hp, mp
+			player.StatsDirty = true
+		}
+	}
+
+	return true
+}
+
+// updateStats recomputes the stats of a player after something they depend on changed,
+// and tells the client and the players around when they did.
+func (s *InstanceWorker) updateStats(z *zone.Zone, player *zone.Player) {
+	if !s.refreshStats(player) {
+		return
+	}
+
+	player.MarkChanged()
+	sendPackets(z, player.ClientAddr, CreateCliStatusPacket(player), CreateGroupAttrPacket(player))
+}
+
+// savePlayerStats queues a write of the player's current HP and MP, if they changed.
+func (s *InstanceWorker) savePlayerStats(player *zone.Player) {
+	if !player.StatsDirty || player.Stats == nil {
+		return
+	}
+
+	player.StatsDirty = false
+	stats := *player.Stats
+
+	s.queueSave("stats", player.CharacterID, func(ctx context.Context) error {
+		_, err := s.DB().UpdateCharacterStats(ctx, &stats)
+		return err
+	})
+}
+
+// percentOf returns value as a percentage of maximum, rounded up so only 0 shows as 0.
+func percentOf(value, maximum int32) uint8 {
+	if maximum <= 0 || value <= 0 {
+		return 0
+	}
+
+	return uint8(min(math.Ceil(float64(value)*100/float64(maximum)), 100))
+}
+
+// playerHPP returns the health percentage of a player.
+func playerHPP(player *zone.Player) uint8 {
+	if player.Stats == nil {
+		return 100
+	}
+
+	return percentOf(int32(player.Stats.HP), player.Derived.MaxHP)
+}
+
+// CreateCliStatusPacket builds the packet telling the client its stats.
+func CreateCliStatusPacket(player *zone.Player) *serverPackets.CliStatusPacket {
+	mainLevel, subLevel := playerLevels(player)
+	packet := &serverPackets.CliStatusPacket{
+		HPMax:        player.Derived.MaxHP,
+		MPMax:        player.Derived.MaxMP,
+		MainJobLevel: mainLevel,
+		SubJobLevel:  subLevel,
+		BPBase:       player.Derived.Base,
+		BPAdj:        player.Derived.Bonus,
+		Atk:          int16(min(player.Derived.Attack, math.MaxInt16)),  //nolint:gosec // clamped
+		Def:          int16(min(player.Derived.Defense, math.MaxInt16)), //nolint:gosec // clamped
+	}
+
+	if player.Stats != nil {
+		packet.MainJobID = player.Stats.MainJob
+		packet.SubJobID = player.Stats.SubJob
+	}
+
+	if player.Character != nil {
+		packet.Nation = player.Character.Nation
+	}
+
+	if _, progress, ok := playerProgress(player); ok {
+		packet.ExpNow = progress.Exp
+		packet.ExpNext = experience.ToNextLevel(progress.Level)
+	}
+
+	return packet
+}
+
+// CreateGroupAttrPacket builds the packet telling the client its current HP and MP.
+func CreateGroupAttrPacket(player *zone.Player) *serverPackets.GroupAttrPacket {
+	mainLevel, subLevel := playerLevels(player)
+	packet := &serverPackets.GroupAttrPacket{
+		UniqueNo:     player.CharacterID,
+		ActIndex:     player.ActIndex,
+		Tp:           uint32(max(player.TP, 0)), //nolint:gosec // TP is never negative
+		Hpp:          playerHPP(player),
+		MainJobLevel: mainLevel,
+		SubJobLevel:  subLevel,
+	}
+
+	if player.Stats != nil {
+		packet.Hp = uint32(player.Stats.HP)
+		packet.Mp = uint32(player.Stats.MP)
+		packet.Mpp = percentOf(int32(player.Stats.MP), player.Derived.MaxMP)
+		packet.MainJobID = player.Stats.MainJob
+		packet.SubJobID = player.Stats.SubJob
+	}
+
+	return packet
+}
+
+// CreateServerStatusPacket builds the packet telling the client the state of its own
+// character (battle stance, defeated, ...).
+func CreateServerStatusPacket(player *zone.Player) *serverPackets.ServerStatusPacket {
+	packet := &serverPackets.ServerStatusPacket{
+		UniqueNo:     player.CharacterID,
+		Hpp:          playerHPP(player),
+		ServerStatus: playerServerStatus(player),
+	}
+
+	setStatusIcons(packet, player)
+	return packet
+}
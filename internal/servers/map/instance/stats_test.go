@@ -0,0 +1,89 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+)
+
+func TestPercentOf(t *testing.T) {
+	cases := []struct {
+		value, maximum int32
+		want           uint8
+	}{
+		{31, 31, 100},
+		{1, 1000, 1},
+		{0, 1000, 0},
+		{500, 1000, 50},
+		{40, 31, 100},
+		{10, 0, 0},
+	}
+
+	for _, tc := range cases {
+		if got := percentOf(tc.value, tc.maximum); got != tc.want {
+			t.Fatalf("percentOf(%d, %d) = %d, want %d", tc.value, tc.maximum, got, tc.want)
+		}
+	}
+}
+
+func TestRefreshStats(t *testing.T) {
+	s := newTestWorker()
+	s.gameData = gamedata.NewStoreFromData(&gamedata.Data{
+		ItemMods: map[uint16][]gamedata.ItemMod{
+			bronzeCap: {{ItemID: bronzeCap, ModID: uint16(charstats.ModHP), Value: 10}, {ItemID: bronzeCap, ModID: uint16(charstats.ModSTR), Value: 2}},
+		},
+	})
+	_, player := newTestPlayerZone(t)
+
+	// a new Hume warrior, stored with more HP than it can have
+	stats := &database.CharacterStats{CharacterID: player.CharacterID, HP: 50, MP: 50, MainJob: 1, SubJob: 2}
+	player.Character = &database.Character{ID: player.CharacterID, Jobs: testJobs(), Stats: stats}
+	player.Stats = stats
+	player.Looks = &database.CharacterLooks{Race: 1}
+	player.Inventory = inventory.New(inventory.DefaultSizes)
+
+	// WAR30/MNK15
+	if !s.refreshStats(player) {
+		t.Fatalf("refreshStats() = false, want true")
+	}
+
+	want := charstats.Calculate(charstats.Input{Race: 1, MainJob: 1, MainLevel: 30, SubJob: 2, SubLevel: 1, Mods: charstats.Modifiers{}})
+	if player.Derived != want {
+		t.Fatalf("Derived = %+v, want %+v", player.Derived, want)
+	}
+
+	if player.Stats.MP != 0 || !player.StatsDirty {
+		t.Fatalf("MP = %d, dirty = %v, want 0 and dirty", player.Stats.MP, player.StatsDirty)
+	}
+
+	location := inventory.Location{Container: serverPackets.ContainerKindInventory, Slot: 1}
+	_ = player.Inventory.Set(location, inventory.Item{ID: bronzeCap, Quantity: 1})
+	_ = player.Inventory.Equip(serverPackets.EquipKindHead, location)
+
+	if !s.refreshStats(player) {
+		t.Fatalf("refreshStats() = false after equipping, want true")
+	}
+
+	if player.Derived.MaxHP != want.MaxHP+10 || player.Derived.Bonus[charstats.STR] != 2 {
+		t.Fatalf("MaxHP = %d, STR bonus = %d", player.Derived.MaxHP, player.Derived.Bonus[charstats.STR])
+	}
+
+	if s.refreshStats(player) {
+		t.Fatalf("refreshStats() = true without changes, want false")
+	}
+
+	packet := CreateCliStatusPacket(player)
+	if packet.MainJobLevel != 30 || packet.SubJobLevel != 1 || packet.HPMax != player.Derived.MaxHP {
+		t.Fatalf("CliStatusPacket = %+v", packet)
+	}
+
+	for _, p := range []serverPackets.ServerPacket{packet, CreateGroupAttrPacket(player)} {
+		if data, err := p.Serialize(); err != nil || len(data) != int(p.Size()) {
+			t.Fatalf("Serialize() 0x%03X = %d bytes, %v, want %d", p.Type(), len(data), err, p.Size())
+		}
+	}
+}
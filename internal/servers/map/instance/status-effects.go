@@ -0,0 +1,311 @@
+package instance
+
+import (
+	"context"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/combat"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+const (
+	// effectTickInterval is the time between the ticks of status effects that tick (regen, poison, ...)
+	effectTickInterval = 3 * time.Second
+
+	// permanentIconTimestamp is the end time the client shows no timer for
+	permanentIconTimestamp = 0x7FFFFFFF
+)
+
+// newEffect builds a status effect from its game data, reporting whether the effect exists.
+func (s *InstanceWorker) newEffect(id uint16, power int32, tick, duration time.Duration, sourceID uint32) (effects.Effect, bool) {
+	data := s.gameData.Current()
+
+	definition, ok := data.StatusEffects[id]
+	if !ok {
+		return effects.Effect{}, false
+	}
+
+	mods := make(charstats.Modifiers)
+	for _, mod := range data.EffectMods[id] {
+		mods.Add(charstats.Mod(mod.ModID), int32(mod.Value))
+	}
+
+	return effects.Effect{
+		ID:         id,
+		Flags:      effects.Flag(definition.Flags),
+		Type:       definition.Type,
+		NegativeID: definition.NegativeID,
+		Overwrite:  effects.Overwrite(definition.Overwrite),
+		Power:      power,
+		Mods:       mods,
+		SourceID:   sourceID,
+		Tick:       tick,
+		Duration:   duration,
+	}, true
+}
+
+// updateEffects wears off and ticks the status effects of the players and spawned mobs
+// of the zone; it is a zone system.
+func (s *InstanceWorker) updateEffects(z *zone.Zone, now time.Time) {
+	for _, player := range z.Players() {
+		s.playerEffectsLost(z, player, player.Effects.Expire(now))
+		for _, effect := range player.Effects.Due(now) {
+			s.playerEffectTick(z, player, effect)
+		}
+	}
+
+	for _, mob := range z.Mobs() {
+		if !mob.Spawned {
+			continue
+		}
+
+		mobEffectsLost(z, mob, mob.Effects.Expire(now))
+		for _, effect := range mob.Effects.Due(now) {
+			s.mobEffectTick(z, mob, effect)
+		}
+	}
+}
+
+// addPlayerEffect applies a status effect to a player and tells the players around,
+// reporting whether it landed. The effects it overwrites are replaced silently.
+func (s *InstanceWorker) addPlayerEffect(z *zone.Zone, player *zone.Player, effect effects.Effect) bool {
+	if !s.applyPlayerEffect(z, player, effect) {
+		return false
+	}
+
+	sendNearby(z, player.Position, createEffectMessagePacket(player, effect.ID, serverPackets.MessageGainsEffect))
+	return true
+}
+
+// applyPlayerEffect applies a status effect to a player without a message, for the
+// actions whose result tells it; it reports whether the effect landed.
+func (s *InstanceWorker) applyPlayerEffect(z *zone.Zone, player *zone.Player, effect effects.Effect) bool {
+	if _, ok := player.Effects.Add(effect, z.Now()); !ok {
+		return false
+	}
+
+	s.playerEffectsChanged(z, player)
+	return true
+}
+
+// playerEffectsLost tells the players around that status effects of a player wore
+// off (or were dispelled), and updates what they gave.
+func (s *InstanceWorker) playerEffectsLost(z *zone.Zone, player *zone.Player, lost []effects.Effect) {
+	if len(lost) == 0 {
+		return
+	}
+
+	for _, effect := range lost {
+		sendNearby(z, player.Position, createEffectMessagePacket(player, effect.ID, serverPackets.MessageEffectWearsOff))
+	}
+
+	s.playerEffectsChanged(z, player)
+}
+
+// playerEffectsChanged applies the modifiers of the status effects of a player after
+// they changed, and sends the client its new icons.
+func (s *InstanceWorker) playerEffectsChanged(z *zone.Zone, player *zone.Player) {
+	player.EffectsDirty = true
+	player.EffectMods = player.Effects.Modifiers()
+
+	s.updateStats(z, player)
+	sendPackets(z, player.ClientAddr, CreateStatusIconsPacket(player, z.Now()), CreateServerStatusPacket(player))
+}
+
+// playerEffectTick gives (or takes) the HP, MP and TP of a status effect tick to a player.
+func (s *InstanceWorker) playerEffectTick(z *zone.Zone, player *zone.Player, effect *effects.Effect) {
+	if player.Stats == nil || playerDefeated(player) {
+		return
+	}
+
+	hp, mp, tp := effectTickAmounts(effect)
+	if hp == 0 && mp == 0 && tp == 0 {
+		return
+	}
+
+	player.Stats.HP = uint16(min(max(int32(player.Stats.HP)+hp, 0), player.Derived.MaxHP)) //nolint:gosec // clamped to the maximum HP
+	player.Stats.MP = uint16(min(max(int32(player.Stats.MP)+mp, 0), player.Derived.MaxMP)) //nolint:gosec // clamped to the maximum MP
+	player.TP = combat.AddTP(player.TP, tp)
+	player.StatsDirty = true
+	player.MarkChanged()
+	sendPackets(z, player.ClientAddr, CreateGroupAttrPacket(player))
+
+	if player.Stats.HP == 0 {
+		s.defeatPlayer(z, player, player)
+	}
+}
+
+// mobEffectsLost tells the players around that status effects of a mob wore off.
+func mobEffectsLost(z *zone.Zone, mob *zone.Mob, lost []effects.Effect) {
+	for _, effect := range lost {
+		sendNearby(z, mob.Position, createEffectMessagePacket(mob, effect.ID, serverPackets.MessageEffectWearsOff))
+	}
+}
+
+// mobEffectTick gives (or takes) the HP, MP and TP of a status effect tick to a mob.
+// A mob killed by an effect is defeated by whoever applied it, or else by its claimer.
+func (s *InstanceWorker) mobEffectTick(z *zone.Zone, mob *zone.Mob, effect *effects.Effect) {
+	if mob.HP <= 0 {
+		return
+	}
+
+	hp, mp, tp := effectTickAmounts(effect)
+	if hp == 0 && mp == 0 && tp == 0 {
+		return
+	}
+
+	mob.HP = min(max(mob.HP+hp, 0), mob.MaxHP)
+	mob.MP = min(max(mob.MP+mp, 0), mob.MaxMP)
+	mob.TP = combat.AddTP(mob.TP, tp)
+	mob.MarkChanged()
+
+	if mob.HP > 0 {
+		return
+	}
+
+	var killer zone.Entity = mob
+	if source := z.Player(effect.SourceID); source != nil {
+		killer = source
+	} else if claimer := z.Player(mob.ClaimedBy); claimer != nil {
+		killer = claimer
+	}
+
+	s.defeatMob(z, mob, killer)
+}
+
+// effectTickAmounts returns the HP, MP and TP a tick of a status effect gives (negative
+// when it takes some, e.g. poison).
+func effectTickAmounts(effect *effects.Effect) (int32, int32, int32) {
+	mods := effect.Modifiers()
+
+	return mods[charstats.ModRegen] - mods[charstats.ModRegenDown],
+		mods[charstats.ModRefresh] - mods[charstats.ModRefreshDown],
+		mods[charstats.ModRegain] - mods[charstats.ModRegainDown]
+}
+
+// restoreEffects reapplies the status effects a character kept when it last left a
+// zone. Effects that no longer exist in the game data are dropped.
+func (s *InstanceWorker) restoreEffects(player *zone.Player, saved []database.CharacterEffect, now time.Time) {
+	for _, row := range saved {
+		tick := time.Duration(row.Tick) * time.Millisecond
+		remaining := time.Duration(row.Remaining) * time.Millisecond
+
+		if effect, ok := s.newEffect(row.EffectID, row.Power, tick, remaining, row.SourceID); ok {
+			player.Effects.Add(effect, now)
+		}
+	}
+
+	player.EffectMods = player.Effects.Modifiers()
+}
+
+// savePlayerEffects queues a write of the status effects a player keeps, leaving out
+// the ones with the flag of how the player is leaving (FlagLogout, or FlagOnZone when
+// changing zones). Timed effects are written whenever there are any, so the remaining
+// durations stay current.
+func (s *InstanceWorker) savePlayerEffects(z *zone.Zone, player *zone.Player, lostFlag effects.Flag) {
+	if !player.EffectsDirty && player.Effects.Len() == 0 {
+		return
+	}
+
+	player.EffectsDirty = false
+	characterID := player.CharacterID
+	saved := savedEffects(player, lostFlag, z.Now())
+
+	s.queueSave("effects", characterID, func(ctx context.Context) error {
+		return s.DB().RunInTx(ctx, func(ctx context.Context, tx database.Tx) error {
+			if err := tx.DeleteCharacterEffects(ctx, characterID); err != nil {
+				return err
+			}
+
+			return tx.InsertCharacterEffects(ctx, saved)
+		})
+	})
+}
+
+// savedEffects returns the rows of the status effects a player keeps when leaving with
+// lostFlag. Effects about to wear off are left out.
+func savedEffects(player *zone.Player, lostFlag effects.Flag, now time.Time) []database.CharacterEffect {
+	saved := make([]database.CharacterEffect, 0, player.Effects.Len())
+	for _, effect := range player.Effects.All() {
+		remaining := effect.Remaining(now)
+		if effect.Has(lostFlag) || (effect.Duration != 0 && remaining < time.Millisecond) {
+			continue
+		}
+
+		saved = append(saved, database.CharacterEffect{
+			CharacterID: player.CharacterID,
+			Slot:        uint8(len(saved)), //nolint:gosec // bounded by effects.MaxEffects
+			EffectID:    effect.ID,
+			Power:       effect.Power,
+			SourceID:    effect.SourceID,
+			Tick:        uint32(effect.Tick.Milliseconds()), //nolint:gosec // ticks are short
+			Remaining:   uint32(remaining.Milliseconds()),   //nolint:gosec // durations are at most hours
+		})
+	}
+
+	return saved
+}
+
+// createEffectMessagePacket builds the message telling that an entity gained or lost a status effect.
+func createEffectMessagePacket(entity zone.Entity, effectID uint16, message uint16) *serverPackets.BattleMessagePacket {
+	packet := createCombatMessagePacket(entity, entity, message)
+	packet.Data = uint32(effectID)
+
+	return packet
+}
+
+// CreateStatusIconsPacket builds the packet telling the client the status effect icons
+// of its character and when they wear off. An effect's icon is its ID.
+func CreateStatusIconsPacket(player *zone.Player, now time.Time) *serverPackets.StatusIconsPacket {
+	packet := &serverPackets.StatusIconsPacket{
+		Kind:   serverPackets.MiscDataStatusIcons,
+		Length: serverPackets.PacketSizeStatusIcons,
+	}
+
+	for i := range packet.Icons {
+		packet.Icons[i] = serverPackets.StatusIconEmpty
+	}
+
+	for i, effect := range player.Effects.All() {
+		if i == len(packet.Icons) {
+			break
+		}
+
+		packet.Icons[i] = effect.ID
+		packet.Timestamps[i] = statusIconTimestamp(effect, now)
+	}
+
+	return packet
+}
+
+// statusIconTimestamp returns when a status effect wears off, in the 1/60ths of a
+// second since the Vana'diel epoch the client counts in (truncated to 32 bits, as it does).
+func statusIconTimestamp(effect *effects.Effect, now time.Time) uint32 {
+	if effect.Duration == 0 {
+		return permanentIconTimestamp
+	}
+
+	end := now.Add(effect.Remaining(now))
+	return uint32((end.Unix() - vanadielEpoch) * 60) //nolint:gosec // the client wraps around as well
+}
+
+// setStatusIcons fills the status effect icons of the server status packet: their low
+// bytes, then 2 more bits for each.
+func setStatusIcons(packet *serverPackets.ServerStatusPacket, player *zone.Player) {
+	for i := range packet.BufStatus {
+		packet.BufStatus[i] = uint8(serverPackets.StatusIconEmpty)
+	}
+
+	for i, effect := range player.Effects.All() {
+		if i == len(packet.BufStatus) {
+			break
+		}
+
+		packet.BufStatus[i] = uint8(effect.ID) //nolint:gosec // the low byte of the icon
+		packet.BufStatusHigh |= uint64(effect.ID>>8&0x03) << (2 * i)
+	}
+}
@@ -0,0 +1,179 @@
+package instance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// the status effects of the tests
+const (
+	testPoison  = 3
+	testProtect = 40
+	testRegen   = 42
+	testSignet  = 253
+)
+
+// newEffectTest is a combat test whose game data has a few status effects.
+func newEffectTest(t *testing.T) *combatTest {
+	t.Helper()
+
+	ct := newCombatTest(t)
+	ct.z.AddSystem(ct.s.updateEffects)
+
+	data := ct.s.gameData.Current()
+	data.StatusEffects = map[uint16]gamedata.StatusEffect{
+		testPoison:  {ID: testPoison, Name: "poison", Flags: uint32(effects.FlagErasable | effects.FlagDeath), Overwrite: uint8(effects.OverwriteHigher)},
+		testProtect: {ID: testProtect, Name: "protect", Flags: uint32(effects.FlagDispelable | effects.FlagDeath)},
+		testRegen:   {ID: testRegen, Name: "regen", Flags: uint32(effects.FlagDispelable | effects.FlagDeath)},
+		testSignet:  {ID: testSignet, Name: "signet", Flags: uint32(effects.FlagOnZone)},
+	}
+	data.EffectMods = map[uint16][]gamedata.StatusEffectMod{
+		testPoison:  {{EffectID: testPoison, ModID: uint16(charstats.ModRegenDown), Value: 1}},
+		testProtect: {{EffectID: testProtect, ModID: uint16(charstats.ModDEF), Value: 1}},
+		testRegen:   {{EffectID: testRegen, ModID: uint16(charstats.ModRegen), Value: 1}},
+	}
+
+	return ct
+}
+
+func (ct *combatTest) addEffect(t *testing.T, id uint16, power int32, duration time.Duration) {
+	t.Helper()
+
+	effect, ok := ct.s.newEffect(id, power, effectTickInterval, duration, 0)
+	if !ok || !ct.s.addPlayerEffect(ct.z, ct.player, effect) {
+		t.Fatalf("addPlayerEffect(%d) did not land", id)
+	}
+}
+
+// icons returns the status icons last sent to the client, clearing the recorded packets.
+func (ct *combatTest) icons() *serverPackets.StatusIconsPacket {
+	var icons *serverPackets.StatusIconsPacket
+	for _, packet := range ct.sent {
+		if p, ok := packet.(*serverPackets.StatusIconsPacket); ok {
+			icons = p
+		}
+	}
+
+	ct.sent = nil
+	return icons
+}
+
+func TestStatusEffectMods(t *testing.T) {
+	ct := newEffectTest(t)
+	defense := ct.player.Derived.Defense
+
+	ct.addEffect(t, testProtect, 20, time.Minute)
+	if got := ct.player.Derived.Defense; got != defense+20 {
+		t.Fatalf("Defense = %d, want %d", got, defense+20)
+	}
+
+	ct.z.Step()
+	icons := ct.icons()
+	if icons == nil || icons.Icons[0] != testProtect || icons.Icons[1] != serverPackets.StatusIconEmpty || icons.Timestamps[0] == 0 {
+		t.Fatalf("status icons = %+v, want protect", icons)
+	}
+
+	status := CreateServerStatusPacket(ct.player)
+	if status.BufStatus[0] != testProtect || status.BufStatus[1] != 0xFF {
+		t.Fatalf("BufStatus = %v, want protect", status.BufStatus)
+	}
+
+	// a weaker protect does not overwrite it, and it wears off after a minute
+	weaker, _ := ct.s.newEffect(testProtect, 10, 0, time.Hour, 0)
+	if ct.s.addPlayerEffect(ct.z, ct.player, weaker) {
+		t.Fatalf("a weaker protect landed")
+	}
+
+	ct.z.Advance(time.Minute)
+	if got := ct.messages(); len(got) != 1 || got[0] != serverPackets.MessageEffectWearsOff {
+		t.Fatalf("messages = %v, want protect wearing off", got)
+	}
+
+	if ct.player.Effects.Len() != 0 || ct.player.Derived.Defense != defense {
+		t.Fatalf("effects = %d, Defense = %d, want none and %d", ct.player.Effects.Len(), ct.player.Derived.Defense, defense)
+	}
+}
+
+func TestStatusEffectTicks(t *testing.T) {
+	ct := newEffectTest(t)
+	ct.player.Stats.HP = 10
+
+	ct.addEffect(t, testRegen, 5, 10*time.Second)
+	ct.z.Advance(time.Minute)
+	if ct.player.Stats.HP != 25 {
+		t.Fatalf("HP = %d, want 25 after 3 regen ticks", ct.player.Stats.HP)
+	}
+
+	// poison kills, and the effects lost on death go with the character
+	ct.addEffect(t, testSignet, 1, 0)
+	ct.addEffect(t, testPoison, 10, time.Minute)
+	ct.z.Advance(9 * time.Second)
+	if !playerDefeated(ct.player) {
+		t.Fatalf("HP = %d, want the player defeated", ct.player.Stats.HP)
+	}
+
+	if ct.player.Effects.Len() != 1 || ct.player.Effects.Get(testSignet) == nil {
+		t.Fatalf("effects = %+v, want signet only", ct.player.Effects.All())
+	}
+}
+
+func TestMobStatusEffects(t *testing.T) {
+	ct := newEffectTest(t)
+	if err := ct.engage(); err != nil {
+		t.Fatalf("engage error = %v", err)
+	}
+
+	poison, _ := ct.s.newEffect(testPoison, ct.mob.HP, effectTickInterval, time.Minute, ct.player.CharacterID)
+	if _, ok := ct.mob.Effects.Add(poison, ct.z.Now()); !ok {
+		t.Fatalf("poison did not land on the mob")
+	}
+
+	ct.z.Advance(3 * time.Second)
+	if ct.mob.HP != 0 || ct.mob.State != zone.AIDespawning || ct.mob.Effects.Len() != 0 {
+		t.Fatalf("mob HP = %d, state = %v, effects = %d, want it defeated", ct.mob.HP, ct.mob.State, ct.mob.Effects.Len())
+	}
+
+	if ct.player.Character.Exp.WAR <= 5000 {
+		t.Fatalf("exp = %d, want the poisoner to earn experience", ct.player.Character.Exp.WAR)
+	}
+}
+
+func TestSavedEffects(t *testing.T) {
+	ct := newEffectTest(t)
+
+	ct.addEffect(t, testProtect, 20, time.Minute)
+	ct.addEffect(t, testSignet, 1, 0)
+	ct.z.Advance(10 * time.Second)
+
+	want := []database.CharacterEffect{
+		{CharacterID: 1, EffectID: testProtect, Power: 20, Tick: 3000, Remaining: 50000},
+		{CharacterID: 1, Slot: 1, EffectID: testSignet, Power: 1, Tick: 3000},
+	}
+
+	saved := savedEffects(ct.player, effects.FlagLogout, ct.z.Now())
+	if len(saved) != 2 || saved[0] != want[0] || saved[1] != want[1] {
+		t.Fatalf("savedEffects() = %+v, want %+v", saved, want)
+	}
+
+	// signet is lost when changing zones
+	if saved := savedEffects(ct.player, effects.FlagOnZone, ct.z.Now()); len(saved) != 1 || saved[0] != want[0] {
+		t.Fatalf("savedEffects(zoning) = %+v, want protect only", saved)
+	}
+
+	// restoring them starts the timers again from what remained
+	restored := newCombatTest(t)
+	restored.s.gameData = ct.s.gameData
+	restored.s.restoreEffects(restored.player, saved, restored.z.Now())
+
+	protect := restored.player.Effects.Get(testProtect)
+	if protect == nil || protect.Remaining(restored.z.Now()) != 50*time.Second || restored.player.EffectMods[charstats.ModDEF] != 20 {
+		t.Fatalf("restored effects = %+v, mods = %v", restored.player.Effects.All(), restored.player.EffectMods)
+	}
+}
@@ -0,0 +1,116 @@
+package instance
+
+import (
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// npcEntityIDBase is the lowest entity ID of NPCs and mobs; lower IDs are characters
+const npcEntityIDBase = 0x01000000
+
+// buildVisibilityPacket builds the 0x00D (characters) or 0x00E (mobs and NPCs) packet telling
+// viewer about another entity.
+func buildVisibilityPacket(_ *zone.Player, entityID uint32, entityIndex uint16, entity zone.Entity, flags serverPackets.CharUpdateSendFlags) serverPackets.ServerPacket {
+	if flags&serverPackets.CharUpdateFlagDespawn != 0 && entityID >= npcEntityIDBase {
+		return &serverPackets.CharNPCPacket{
+			UniqueNo: entityID,
+			ActIndex: entityIndex,
+			SendFlg:  flags,
+		}
+	}
+
+	if flags&serverPackets.CharUpdateFlagDespawn != 0 {
+		return &serverPackets.CharUpdatePacket{
+			UniqueID:  entityID,
+			ActIndex:  entityIndex,
+			SendFlags: flags,
+		}
+	}
+
+	switch e := entity.(type) {
+	case *zone.Player:
+		return CreatePlayerUpdatePacket(e, flags)
+	case *zone.Mob:
+		return CreateMobUpdatePacket(e, flags)
+	case *zone.NPC:
+		return CreateNPCUpdatePacket(e, flags)
+	default:
+		return nil
+	}
+}
+
+// CreatePlayerUpdatePacket builds a 0x00D packet describing a player as it currently is in the zone.
+func CreatePlayerUpdatePacket(player *zone.Player, flags serverPackets.CharUpdateSendFlags) *serverPackets.CharUpdatePacket {
+	packet := CreateCharacterUpdatePacket(player.Character, player.Looks, playerHPP(player))
+	packet.UniqueID = player.CharacterID
+	packet.ActIndex = player.ActIndex
+	packet.SendFlags = flags
+	packet.PosX = player.Position.X
+	packet.PosZ = player.Position.Y
+	packet.PosY = player.Position.Z
+	packet.Direction = int8(player.Position.Rotation) //nolint:gosec // the packet stores the rotation byte as a signed value
+	packet.ServerStatus = playerServerStatus(player)
+
+	return packet
+}
+
+// playerServerStatus returns the status other clients show a player in.
+func playerServerStatus(player *zone.Player) uint8 {
+	switch {
+	case playerDefeated(player):
+		return serverPackets.ServerStatusDead
+	case player.BattleTarget != 0:
+		return serverPackets.ServerStatusEngaged
+	default:
+		return serverPackets.ServerStatusNormal
+	}
+}
+
+// CreateMobUpdatePacket builds a 0x00E packet describing a mob as it currently is in the zone.
+func CreateMobUpdatePacket(mob *zone.Mob, flags serverPackets.CharUpdateSendFlags) *serverPackets.CharNPCPacket {
+	packet := &serverPackets.CharNPCPacket{
+		UniqueNo:   mob.ID,
+		ActIndex:   mob.Index,
+		SendFlg:    flags,
+		Dir:        mob.Position.Rotation,
+		PosX:       mob.Position.X,
+		PosZ:       mob.Position.Y,
+		PosY:       mob.Position.Z,
+		Flags0:     uint32(mob.TargetIndex) << 17,
+		Speed:      mob.Speed,
+		SpeedBase:  mob.Speed,
+		Hpp:        mobHPP(mob),
+		BtTargetID: mob.ClaimedBy,
+		ModelID:    mob.ModelID,
+	}
+
+	switch {
+	case mob.State == zone.AIDespawning:
+		packet.ServerStatus = serverPackets.CharNPCStatusDead
+	case mob.State.Engaged():
+		packet.ServerStatus = serverPackets.CharNPCStatusEngaged
+	}
+
+	copy(packet.Name[:], mob.Name)
+	return packet
+}
+
+// CreateNPCUpdatePacket builds a 0x00E packet describing an NPC of the zone.
+func CreateNPCUpdatePacket(npc *zone.NPC, flags serverPackets.CharUpdateSendFlags) *serverPackets.CharNPCPacket {
+	packet := &serverPackets.CharNPCPacket{
+		UniqueNo:     npc.ID,
+		ActIndex:     npc.Index,
+		SendFlg:      flags,
+		Dir:          npc.Position.Rotation,
+		PosX:         npc.Position.X,
+		PosZ:         npc.Position.Y,
+		PosY:         npc.Position.Z,
+		Hpp:          100,
+		ServerStatus: npc.Animation,
+		Flags1:       npc.Flags,
+		ModelID:      npc.ModelID,
+	}
+
+	copy(packet.Name[:], npc.Name)
+	return packet
+}
@@ -16,8 +16,11 @@ import (
 
 	"github.com/GoFFXI/GoFFXI/internal/config"
 	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/gamedata"
 	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
 	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/scripting"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
 )
 
 type InstanceWorker struct {
@@ -27,17 +30,62 @@ type InstanceWorker struct {
 	logger        *slog.Logger
 	ctx           context.Context
 	subscriptions []*nats.Subscription
+	packets       *PacketRegistry
+	gameData      *gamedata.Store
+	chatRoutes    map[uint8]chatRoute
+	gmCommands    map[string]gmCommand
+	spellHooks    map[string]actionHook
+	abilityHooks  map[string]actionHook
+	npcHandlers   map[string]npcHandler
+
+	playerSubscriptionsMu sync.Mutex
+	playerSubscriptions   map[uint32]*nats.Subscription
+
+	zonesMu        sync.Mutex
+	zones          map[uint16]*zone.Zone
+	scripts        map[uint16]*scripting.Runtime
+	characterZones map[uint32]uint16
+	zonesWG        sync.WaitGroup
+
+	saves      chan pendingSave
+	overflowMu sync.Mutex
+	overflow   []pendingSave
+	overflowed chan struct{}
 }
 
 func NewInstanceWorker(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*InstanceWorker, error) {
 	var err error
 
 	srv := InstanceWorker{
-		cfg:    cfg,
-		logger: logger,
-		ctx:    ctx,
+		cfg:     cfg,
+		logger:  logger,
+		ctx:     ctx,
+		packets: NewPacketRegistry(),
+
+		zones:          make(map[uint16]*zone.Zone),
+		scripts:        make(map[uint16]*scripting.Runtime),
+		characterZones: make(map[uint32]uint16),
+
+		playerSubscriptions: make(map[uint32]*nats.Subscription),
+
+		saves:      make(chan pendingSave, saveQueueSize),
+		overflowed: make(chan struct{}, 1),
+	}
+
+	srv.registerPacketHandlers()
+	srv.registerChatRoutes()
+	srv.registerGMCommands()
+	srv.registerActionHooks()
+	srv.registerNPCHandlers()
+
+	// load the game data; files that are missing are empty and disable what needs them
+	srv.gameData, err = gamedata.NewStore(cfg.MapGameDataPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load game data: %w", err)
 	}
 
+	srv.logGameData(srv.gameData.Current())
+
 	// initialize NATS connection
 	if err = srv.CreateNATSConnection(); err != nil {
 		return nil, fmt.Errorf("could not create NATS connection: %w", err)
@@ -67,18 +115,37 @@ func (s *InstanceWorker) NATS() *nats.Conn {
 	return s.natsConn
 }
 
-func (s *InstanceWorker) StartProcessingPackets() {
-	var subject string
+// StartProcessingPackets subscribes to the packets of the zones this instance simulates
+// and blocks until the context is cancelled, after which it waits for the zone loops to stop.
+func (s *InstanceWorker) StartProcessingPackets(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for _, subject := range s.zoneSubjects() {
+		s.subscribe(subject, s.ProcessPacket)
+		s.subscribe(subject+".disconnect", s.ProcessDisconnect)
+	}
+
+	s.subscribe(SubjectYell, s.ProcessYell)
+	s.subscribe(SubjectGameDataReload, s.ProcessGameDataReload)
+
+	<-ctx.Done()
 
-	// create a subscription to the instance subject
-	if s.Config().MapInstanceID == 0 {
-		subject = "map.instance.*"
-	} else {
-		subject = fmt.Sprintf("map.instance.%d", s.cfg.MapInstanceID)
+	for _, subscription := range s.subscriptions {
+		_ = subscription.Unsubscribe()
 	}
 
+	s.playerSubscriptionsMu.Lock()
+	for _, subscription := range s.playerSubscriptions {
+		_ = subscription.Unsubscribe()
+	}
+	s.playerSubscriptionsMu.Unlock()
+
+	s.zonesWG.Wait()
+}
+
+func (s *InstanceWorker) subscribe(subject string, handler nats.MsgHandler) {
 	s.Logger().Info("subscribing to NATS subject", "subject", subject)
-	newSubscription, err := s.NATS().Subscribe(subject, s.ProcessPacket)
+	newSubscription, err := s.NATS().Subscribe(subject, handler)
 	if err != nil {
 		s.Logger().Error("could not subscribe to NATS subject", "subject", subject, "error", err)
 		return
@@ -87,6 +154,20 @@ func (s *InstanceWorker) StartProcessingPackets() {
 	s.subscriptions = append(s.subscriptions, newSubscription)
 }
 
+// zoneSubjects returns the NATS subjects the router publishes the packets of this instance's zones to.
+func (s *InstanceWorker) zoneSubjects() []string {
+	if len(s.Config().MapInstanceZones) == 0 {
+		return []string{"map.zone.*"}
+	}
+
+	subjects := make([]string, 0, len(s.Config().MapInstanceZones))
+	for _, zoneID := range s.Config().MapInstanceZones {
+		subjects = append(subjects, fmt.Sprintf("map.zone.%d", zoneID))
+	}
+
+	return subjects
+}
+
 func (s *InstanceWorker) WaitForShutdown(cancelCtx context.CancelFunc, wg *sync.WaitGroup) error {
 	// setup signal handling
 	signalChannel := make(chan os.Signal, 1)
@@ -140,6 +221,11 @@ func (s *InstanceWorker) sendPacket(clientAddr string, packet serverPackets.Serv
 		},
 	}
 
+	if leave, ok := packet.(*leavePacket); ok {
+		routedPacket.ZoneID = leave.zoneID
+		routedPacket.Disconnect = leave.disconnect
+	}
+
 	subject := fmt.Sprintf("map.router.%s.send", clientAddr)
 	return s.NATS().Publish(subject, routedPacket.ToJSON())
 }
@@ -0,0 +1,131 @@
+package instance
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// leavePacket is the logout packet sent to a client leaving its zone. The destination
+// zone (or whether the client leaves the game) is not part of the packet; it tells the
+// router what to do with the client's session once the packet has been delivered.
+type leavePacket struct {
+	*serverPackets.LogoutPacket
+
+	zoneID     uint16
+	disconnect bool
+}
+
+// disconnectPacket ends the session of a client that cannot go on playing.
+func disconnectPacket() *leavePacket {
+	return &leavePacket{
+		LogoutPacket: &serverPackets.LogoutPacket{LogoutState: serverPackets.LogoutStateShutdown},
+		disconnect:   true,
+	}
+}
+
+// zoneChangeReply returns what to send a client once its zone change was saved: the
+// zone change itself, or a disconnect if it could not be saved.
+func zoneChangeReply(packet *leavePacket, err error) *leavePacket {
+	if err != nil {
+		return disconnectPacket()
+	}
+
+	return packet
+}
+
+// changeZone moves a player out of its zone and tells the client to load the destination
+// zone. The client then logs in again, and the instance simulating the destination takes over.
+func (s *InstanceWorker) changeZone(z *zone.Zone, player *zone.Player, toZoneID uint16, destination zone.Position) {
+	fromZoneID := z.ID()
+	characterID := player.CharacterID
+
+	s.Logger().Info("character changing zones", "characterID", characterID, "from", fromZoneID, "to", toZoneID)
+	s.leaveZone(z, player, toZoneID, func(ctx context.Context) error {
+		return s.DB().UpdateCharacterZone(ctx, characterID, fromZoneID, toZoneID, destination.X, destination.Y, destination.Z, destination.Rotation)
+	})
+}
+
+// setMogHouse moves a player into or out of its mog house. The mog house is loaded
+// like a zone of its own, so the client zones back into the same zone, and the login
+// that follows puts it in (or takes it out of) the mog house.
+func (s *InstanceWorker) setMogHouse(z *zone.Zone, player *zone.Player, inMogHouse bool) {
+	characterID := player.CharacterID
+
+	s.Logger().Info("character changing mog house", "characterID", characterID, "zoneID", z.ID(), "inMogHouse", inMogHouse)
+	s.leaveZone(z, player, z.ID(), func(ctx context.Context) error {
+		return s.DB().UpdateCharacterMogHouse(ctx, characterID, inMogHouse)
+	})
+}
+
+// leaveZone removes a player from its zone, and once save persisted where the character
+// goes, tells the client to load the zone it is going to.
+func (s *InstanceWorker) leaveZone(z *zone.Zone, player *zone.Player, toZoneID uint16, save func(ctx context.Context) error) {
+	s.cancelLogout(z, player, "changed zones")
+
+	// the login in the destination zone loads the character's HP, MP and effects back
+	s.savePlayerStats(player)
+	s.savePlayerEffects(z, player, effects.FlagOnZone)
+	z.RemovePlayer(player.CharacterID)
+	s.releaseCharacter(player.CharacterID)
+
+	characterID := player.CharacterID
+	clientAddr := player.ClientAddr
+	packet := &leavePacket{
+		LogoutPacket: &serverPackets.LogoutPacket{
+			LogoutState: serverPackets.LogoutStateZoneChange,
+			IP:          ipToUint32(s.Config().MapServerIP),
+			Port:        s.Config().MapServerPort,
+		},
+		zoneID: toZoneID,
+	}
+
+	// the client is only told to zone once the destination is persisted, so the login
+	// that follows (possibly on another instance) loads the new zone. The character has
+	// left this zone already, so if the destination cannot be saved the client is
+	// disconnected, and logs back in where the database has it.
+	s.queueSave("zone change", characterID, func(ctx context.Context) error {
+		err := save(ctx)
+		if sendErr := s.sendPacket(clientAddr, zoneChangeReply(packet, err)); sendErr != nil {
+			s.Logger().Error("failed to send zone change packet", "characterID", characterID, "error", sendErr)
+		}
+
+		return err
+	})
+}
+
+// markZoneVisited sets the zone's bit in a visited zones bitmap, reporting whether it was not set yet.
+func markZoneVisited(visited []byte, zoneID uint16) ([]byte, bool) {
+	index := int(zoneID / 8)
+	if index >= serverPackets.EnterZonePacketSize {
+		return visited, false
+	}
+
+	if len(visited) <= index {
+		visited = append(visited, make([]byte, index+1-len(visited))...)
+	}
+
+	bit := byte(1) << (zoneID % 8)
+	if visited[index]&bit != 0 {
+		return visited, false
+	}
+
+	visited[index] |= bit
+	return visited, true
+}
+
+// ipToUint32 converts an IPv4 address to the value of a packet's IP field. Packets are
+// serialized little-endian, so the address is read that way for its bytes to go out in
+// network order.
+func ipToUint32(ipStr string) uint32 {
+	ip := net.ParseIP(ipStr).To4()
+	if ip == nil {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint32(ip)
+}
@@ -0,0 +1,65 @@
+package instance
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+func TestZoneChangeIP(t *testing.T) {
+	packet := &serverPackets.LogoutPacket{
+		LogoutState: serverPackets.LogoutStateZoneChange,
+		IP:          ipToUint32("192.168.1.20"),
+		Port:        54230,
+	}
+
+	data, err := packet.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+
+	// the address follows the logout state, in network order
+	if want := []byte{192, 168, 1, 20}; !bytes.Equal(data[4:8], want) {
+		t.Fatalf("serialized IP = %v, want %v", data[4:8], want)
+	}
+
+	if ipToUint32("not an address") != 0 {
+		t.Fatalf("ipToUint32() of an invalid address is not 0")
+	}
+}
+
+func TestZoneChangeSavesStats(t *testing.T) {
+	s := newTestWorker()
+	z, player := newTestPlayerZone(t)
+
+	player.Stats = &database.CharacterStats{CharacterID: 1, HP: 12, MP: 3}
+	player.StatsDirty = true
+	s.changeZone(z, player, 231, zone.Position{})
+
+	// the HP and MP, then the zone change
+	if player.StatsDirty || len(s.saves) != 2 {
+		t.Fatalf("queued saves = %d, stats dirty = %v, want the stats saved", len(s.saves), player.StatsDirty)
+	}
+
+	if save := <-s.saves; save.description != "stats" {
+		t.Fatalf("first save = %q, want the stats", save.description)
+	}
+}
+
+func TestZoneChangeReply(t *testing.T) {
+	packet := &leavePacket{LogoutPacket: &serverPackets.LogoutPacket{LogoutState: serverPackets.LogoutStateZoneChange}, zoneID: 231}
+
+	if got := zoneChangeReply(packet, nil); got != packet {
+		t.Fatalf("zoneChangeReply() = %+v, want the zone change", got)
+	}
+
+	// a destination that was not saved must not be loaded
+	got := zoneChangeReply(packet, errors.New("database is down"))
+	if !got.disconnect || got.zoneID != 0 || got.LogoutState != serverPackets.LogoutStateShutdown {
+		t.Fatalf("zoneChangeReply() after a failed save = %+v, want a disconnect", got)
+	}
+}
@@ -0,0 +1,126 @@
+package instance
+
+import (
+	"time"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/zone"
+)
+
+// getOrStartZone returns the running loop of a zone, starting it if this instance
+// is not simulating the zone yet.
+func (s *InstanceWorker) getOrStartZone(zoneID uint16) *zone.Zone {
+	s.zonesMu.Lock()
+	defer s.zonesMu.Unlock()
+
+	if z, ok := s.zones[zoneID]; ok {
+		return z
+	}
+
+	z := zone.New(zoneID, zone.Options{
+		TickInterval:   time.Duration(s.Config().MapZoneTickMilliseconds) * time.Millisecond,
+		EventQueueSize: s.Config().MapZoneEventQueueSize,
+		Sender:         s.sendPacket,
+		Logger:         s.Logger().With("component", "zone"),
+
+		ViewDistance:     s.Config().MapViewDistance,
+		VisibilityBudget: s.Config().MapVisibilityUpdatesPerTick,
+		Visibility:       buildVisibilityPacket,
+	})
+
+	s.setupZone(z)
+
+	s.zones[zoneID] = z
+	s.scripts[zoneID] = s.newZoneScripts(z)
+	s.zonesWG.Add(1)
+	go func() {
+		defer s.zonesWG.Done()
+		z.Run(s.ctx)
+	}()
+
+	return z
+}
+
+// setupZone registers the instance's systems and timers on a new zone.
+func (s *InstanceWorker) setupZone(z *zone.Zone) {
+	if interval := s.Config().MapAutosaveIntervalSeconds; interval > 0 {
+		z.Every(time.Duration(interval)*time.Second, s.autosave)
+	}
+
+	// persist everyone still in the zone when the instance shuts down
+	z.OnStop(s.autosave)
+
+	s.spawnZoneMobs(z)
+	s.spawnZoneNPCs(z)
+	z.AddSystem(s.updateMobs)
+	z.AddSystem(s.updateCombat)
+	z.AddSystem(s.updateCasting)
+	z.AddSystem(s.updateEffects)
+
+	// the scripts start from the zone goroutine, once the zone runs
+	if err := z.Post(func(z *zone.Zone) { s.callScript(z, zoneScriptModule, "onInitialize") }); err != nil {
+		z.Logger().Error("could not initialize the zone's scripts", "error", err)
+	}
+
+	z.OnStop(func(z *zone.Zone) {
+		if runtime := s.zoneScripts(z.ID()); runtime != nil {
+			runtime.Close()
+		}
+	})
+}
+
+// zoneForCharacter returns the zone a character is currently in, if any.
+func (s *InstanceWorker) zoneForCharacter(characterID uint32) *zone.Zone {
+	s.zonesMu.Lock()
+	defer s.zonesMu.Unlock()
+
+	zoneID, ok := s.characterZones[characterID]
+	if !ok {
+		return nil
+	}
+
+	return s.zones[zoneID]
+}
+
+// placeCharacter records that a character's packets are to be handled by the given zone.
+func (s *InstanceWorker) placeCharacter(characterID uint32, zoneID uint16) *zone.Zone {
+	z := s.getOrStartZone(zoneID)
+
+	s.zonesMu.Lock()
+	defer s.zonesMu.Unlock()
+
+	s.characterZones[characterID] = zoneID
+
+	return z
+}
+
+// runningZones returns the zones this instance is simulating.
+func (s *InstanceWorker) runningZones() []*zone.Zone {
+	s.zonesMu.Lock()
+	defer s.zonesMu.Unlock()
+
+	zones := make([]*zone.Zone, 0, len(s.zones))
+	for _, z := range s.zones {
+		zones = append(zones, z)
+	}
+
+	return zones
+}
+
+// releaseCharacter forgets the zone of a character that left it.
+func (s *InstanceWorker) releaseCharacter(characterID uint32) {
+	s.unsubscribePlayer(characterID)
+
+	s.zonesMu.Lock()
+	defer s.zonesMu.Unlock()
+
+	delete(s.characterZones, characterID)
+}
+
+// sendPackets queues packets for a client on the zone outbox, so they are flushed
+// in order with everything else the zone sends during the tick.
+func sendPackets(z *zone.Zone, clientAddr string, packets ...serverPackets.ServerPacket) {
+	for _, packet := range packets {
+		z.Send(clientAddr, packet)
+	}
+}
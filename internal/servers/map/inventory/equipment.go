@@ -0,0 +1,86 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+)
+
+var (
+	ErrItemLocked       = errors.New("item is equipped")
+	ErrInvalidEquipSlot = errors.New("invalid equipment slot")
+	ErrNotEquippable    = errors.New("items cannot be equipped from this container")
+)
+
+// Equippable reports whether items can be equipped straight from a container.
+func Equippable(container serverPackets.ContainerKind) bool {
+	switch container {
+	case serverPackets.ContainerKindInventory,
+		serverPackets.ContainerKindMogWardrobe,
+		serverPackets.ContainerKindMogWardrobe2,
+		serverPackets.ContainerKindMogWardrobe3,
+		serverPackets.ContainerKindMogWardrobe4,
+		serverPackets.ContainerKindMogWardrobe5,
+		serverPackets.ContainerKindMogWardrobe6,
+		serverPackets.ContainerKindMogWardrobe7,
+		serverPackets.ContainerKindMogWardrobe8:
+		return true
+	default:
+		return false
+	}
+}
+
+// Equip equips the item at a location in an equipment slot, replacing whatever was
+// equipped there. Equipped items are locked: they cannot be moved, dropped or merged.
+func (inv *Inventory) Equip(slot serverPackets.EquipKind, location Location) error {
+	if slot >= serverPackets.EquipKindEnd {
+		return fmt.Errorf("%w: %d", ErrInvalidEquipSlot, slot)
+	}
+
+	if !Equippable(location.Container) {
+		return fmt.Errorf("%w: container %d", ErrNotEquippable, location.Container)
+	}
+
+	if _, ok := inv.Get(location); !ok || !inv.valid(location) {
+		return fmt.Errorf("%w: container %d slot %d", ErrEmptySlot, location.Container, location.Slot)
+	}
+
+	if equippedIn, ok := inv.EquippedIn(location); ok && equippedIn != slot {
+		return fmt.Errorf("%w in slot %d", ErrItemLocked, equippedIn)
+	}
+
+	inv.equipment[slot] = location
+	return nil
+}
+
+// Unequip empties an equipment slot, returning the location of the item that was equipped.
+func (inv *Inventory) Unequip(slot serverPackets.EquipKind) (Location, bool) {
+	location, ok := inv.equipment[slot]
+	delete(inv.equipment, slot)
+
+	return location, ok
+}
+
+// Equipped returns the location of the item equipped in a slot.
+func (inv *Inventory) Equipped(slot serverPackets.EquipKind) (Location, bool) {
+	location, ok := inv.equipment[slot]
+	return location, ok
+}
+
+// EquippedIn returns the equipment slot the item at a location is equipped in.
+func (inv *Inventory) EquippedIn(location Location) (serverPackets.EquipKind, bool) {
+	for slot, equipped := range inv.equipment {
+		if equipped == location {
+			return slot, true
+		}
+	}
+
+	return 0, false
+}
+
+// Locked reports whether the item at a location is equipped.
+func (inv *Inventory) Locked(location Location) bool {
+	_, ok := inv.EquippedIn(location)
+	return ok
+}
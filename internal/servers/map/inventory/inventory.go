@@ -0,0 +1,368 @@
+// Package inventory models the item containers of a character (inventory, mog safe,
+// wardrobes, ...) and the rules for moving items between them.
+//
+// An Inventory is owned by the zone goroutine of its character, like the rest of the
+// player state. Every operation returns the slots it changed so the caller can send
+// the matching item packets and persist them.
+package inventory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+)
+
+const (
+	// ContainerCount is the number of containers a character has
+	ContainerCount = int(serverPackets.ContainerKindRecycleBin) + 1
+
+	// MaxContainerSize is the most slots a container can have (slot 0 is reserved for gil)
+	MaxContainerSize = 80
+
+	// AnySlot is the slot the client asks for when an item can go to any free slot
+	AnySlot uint8 = 0x52
+
+	// GilItemID is the item in slot 0 of the inventory that holds the character's gil
+	GilItemID uint16 = 0xFFFF
+)
+
+var (
+	ErrInvalidLocation = errors.New("invalid container or slot")
+	ErrContainerLocked = errors.New("container is locked")
+	ErrEmptySlot       = errors.New("slot is empty")
+	ErrInvalidQuantity = errors.New("invalid quantity")
+	ErrContainerFull   = errors.New("container is full")
+	ErrSlotOccupied    = errors.New("slot is occupied")
+	ErrNotMovable      = errors.New("items cannot be moved there")
+)
+
+// DefaultSizes are the container sizes of a new character; locked containers are 0.
+//
+//nolint:gochecknoglobals // static table
+var DefaultSizes = [ContainerCount]uint8{
+	serverPackets.ContainerKindInventory:   30,
+	serverPackets.ContainerKindMogSafe:     50,
+	serverPackets.ContainerKindTempItems:   MaxContainerSize,
+	serverPackets.ContainerKindMogWardrobe: MaxContainerSize,
+	serverPackets.ContainerKindRecycleBin:  10,
+}
+
+// Item is the content of a container slot.
+type Item struct {
+	ID       uint16
+	Quantity uint32
+	Extra    []byte
+}
+
+// Location identifies a slot.
+type Location struct {
+	Container serverPackets.ContainerKind
+	Slot      uint8
+}
+
+// StackSizer returns how many of an item fit in one slot.
+type StackSizer func(itemID uint16) uint32
+
+// Inventory holds every container of a character.
+type Inventory struct {
+	sizes [ContainerCount]uint8
+	items [ContainerCount]map[uint8]Item
+
+	// equipment holds the location of the item equipped in each equipment slot
+	equipment map[serverPackets.EquipKind]Location
+}
+
+// New returns an empty inventory with the given container sizes.
+func New(sizes [ContainerCount]uint8) *Inventory {
+	inventory := &Inventory{equipment: make(map[serverPackets.EquipKind]Location)}
+	for i, size := range sizes {
+		inventory.sizes[i] = min(size, MaxContainerSize)
+		inventory.items[i] = make(map[uint8]Item)
+	}
+
+	return inventory
+}
+
+// Size returns the number of usable slots of a container (0 if it is locked).
+func (inv *Inventory) Size(container serverPackets.ContainerKind) uint8 {
+	if int(container) >= ContainerCount {
+		return 0
+	}
+
+	return inv.sizes[container]
+}
+
+// Sizes returns the size of every container.
+func (inv *Inventory) Sizes() [ContainerCount]uint8 {
+	return inv.sizes
+}
+
+// Get returns the item in a slot.
+func (inv *Inventory) Get(location Location) (Item, bool) {
+	if int(location.Container) >= ContainerCount {
+		return Item{}, false
+	}
+
+	item, ok := inv.items[location.Container][location.Slot]
+	return item, ok
+}
+
+// Set places an item in a slot when loading an inventory, replacing what was there.
+// Slots outside of the container are rejected, except for the gil slot of the inventory.
+func (inv *Inventory) Set(location Location, item Item) error {
+	if !inv.valid(location) && location != gilLocation() {
+		return fmt.Errorf("%w: container %d slot %d", ErrInvalidLocation, location.Container, location.Slot)
+	}
+
+	// items without extra data are stored with an empty (or zeroed) blob; normalize it so they stack
+	if !slices.ContainsFunc(item.Extra, func(b byte) bool { return b != 0 }) {
+		item.Extra = nil
+	}
+
+	inv.items[location.Container][location.Slot] = item
+	return nil
+}
+
+// Gil returns the character's gil.
+func (inv *Inventory) Gil() uint32 {
+	return inv.items[serverPackets.ContainerKindInventory][0].Quantity
+}
+
+// Locations returns the occupied slots of every container, ordered by container then slot.
+func (inv *Inventory) Locations() []Location {
+	var locations []Location
+	for container := range inv.items {
+		slots := make([]uint8, 0, len(inv.items[container]))
+		for slot := range inv.items[container] {
+			slots = append(slots, slot)
+		}
+
+		sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+		for _, slot := range slots {
+			locations = append(locations, Location{Container: serverPackets.ContainerKind(container), Slot: slot})
+		}
+	}
+
+	return locations
+}
+
+// FreeSlot returns the first empty slot of a container.
+func (inv *Inventory) FreeSlot(container serverPackets.ContainerKind) (uint8, bool) {
+	for slot := uint8(1); slot <= inv.Size(container); slot++ {
+		if _, ok := inv.items[container][slot]; !ok {
+			return slot, true
+		}
+	}
+
+	return 0, false
+}
+
+// Move moves quantity items from a slot to another container and slot (or AnySlot),
+// splitting the stack if only part of it is moved, or merging into a stack of the
+// same item at the destination.
+func (inv *Inventory) Move(from Location, to Location, quantity uint32, stackSize StackSizer) ([]Location, error) {
+	item, err := inv.take(from, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	if !inv.valid(Location{Container: to.Container, Slot: 1}) {
+		return nil, fmt.Errorf("%w: container %d", ErrContainerLocked, to.Container)
+	}
+
+	if !movableInto(to.Container) && to.Container != from.Container {
+		return nil, fmt.Errorf("%w: container %d", ErrNotMovable, to.Container)
+	}
+
+	if to.Slot == AnySlot {
+		slot, ok := inv.FreeSlot(to.Container)
+		if !ok {
+			return nil, fmt.Errorf("%w: container %d", ErrContainerFull, to.Container)
+		}
+
+		to.Slot = slot
+	}
+
+	if !inv.valid(to) || to == from {
+		return nil, fmt.Errorf("%w: container %d slot %d", ErrInvalidLocation, to.Container, to.Slot)
+	}
+
+	target, occupied := inv.items[to.Container][to.Slot]
+	if occupied {
+		if inv.Locked(to) {
+			return nil, fmt.Errorf("%w: container %d slot %d", ErrItemLocked, to.Container, to.Slot)
+		}
+
+		if !stacksWith(target, item) || target.Quantity+quantity > stackSize(item.ID) {
+			return nil, fmt.Errorf("%w: container %d slot %d", ErrSlotOccupied, to.Container, to.Slot)
+		}
+
+		target.Quantity += quantity
+	} else {
+		target = Item{ID: item.ID, Quantity: quantity, Extra: item.Extra}
+	}
+
+	inv.remove(from, quantity)
+	inv.items[to.Container][to.Slot] = target
+
+	return []Location{from, to}, nil
+}
+
+// Add puts quantity of an item into a container, topping up the existing stacks of
+// the item first. Nothing is added unless everything fits.
+func (inv *Inventory) Add(container serverPackets.ContainerKind, item Item, stackSize StackSizer) ([]Location, error) {
+	if inv.Size(container) == 0 {
+		return nil, fmt.Errorf("%w: container %d", ErrContainerLocked, container)
+	}
+
+	if item.Quantity == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidQuantity, item.Quantity)
+	}
+
+	// work out where everything goes before changing anything
+	remaining := item.Quantity
+	placed := make(map[uint8]uint32)
+	for slot := uint8(1); slot <= inv.Size(container) && remaining > 0; slot++ {
+		existing, ok := inv.items[container][slot]
+		if ok && (!stacksWith(existing, item) || inv.Locked(Location{Container: container, Slot: slot})) {
+			continue
+		}
+
+		added := min(remaining, stackSize(item.ID)-min(existing.Quantity, stackSize(item.ID)))
+		if added > 0 {
+			placed[slot] = added
+			remaining -= added
+		}
+	}
+
+	if remaining > 0 {
+		return nil, fmt.Errorf("%w: container %d", ErrContainerFull, container)
+	}
+
+	var changed []Location
+	for slot := uint8(1); slot <= inv.Size(container); slot++ {
+		added, ok := placed[slot]
+		if !ok {
+			continue
+		}
+
+		existing, ok := inv.items[container][slot]
+		if !ok {
+			existing = Item{ID: item.ID, Extra: item.Extra}
+		}
+
+		existing.Quantity += added
+		inv.items[container][slot] = existing
+		changed = append(changed, Location{Container: container, Slot: slot})
+	}
+
+	return changed, nil
+}
+
+// Drop throws away quantity items from a slot.
+func (inv *Inventory) Drop(from Location, quantity uint32) ([]Location, error) {
+	if _, err := inv.take(from, quantity); err != nil {
+		return nil, err
+	}
+
+	inv.remove(from, quantity)
+	return []Location{from}, nil
+}
+
+// Sort merges the partial stacks of a container: later stacks of an item are moved
+// into the earliest ones until they are full.
+func (inv *Inventory) Sort(container serverPackets.ContainerKind, stackSize StackSizer) []Location {
+	var changed []Location
+	for slot := uint8(1); slot <= inv.Size(container); slot++ {
+		item, ok := inv.items[container][slot]
+		if !ok || inv.Locked(Location{Container: container, Slot: slot}) {
+			continue
+		}
+
+		for other := slot + 1; other <= inv.Size(container) && item.Quantity < stackSize(item.ID); other++ {
+			source, ok := inv.items[container][other]
+			if !ok || !stacksWith(item, source) || inv.Locked(Location{Container: container, Slot: other}) {
+				continue
+			}
+
+			moved := min(source.Quantity, stackSize(item.ID)-item.Quantity)
+			item.Quantity += moved
+			inv.items[container][slot] = item
+			inv.remove(Location{Container: container, Slot: other}, moved)
+
+			changed = append(changed, Location{Container: container, Slot: slot}, Location{Container: container, Slot: other})
+		}
+	}
+
+	return dedupe(changed)
+}
+
+// take checks that a slot holds at least quantity items and returns them.
+func (inv *Inventory) take(from Location, quantity uint32) (Item, error) {
+	if !inv.valid(from) {
+		return Item{}, fmt.Errorf("%w: container %d slot %d", ErrInvalidLocation, from.Container, from.Slot)
+	}
+
+	item, ok := inv.items[from.Container][from.Slot]
+	if !ok {
+		return Item{}, fmt.Errorf("%w: container %d slot %d", ErrEmptySlot, from.Container, from.Slot)
+	}
+
+	if quantity == 0 || quantity > item.Quantity {
+		return Item{}, fmt.Errorf("%w: %d of %d", ErrInvalidQuantity, quantity, item.Quantity)
+	}
+
+	if inv.Locked(from) {
+		return Item{}, fmt.Errorf("%w: container %d slot %d", ErrItemLocked, from.Container, from.Slot)
+	}
+
+	return item, nil
+}
+
+func (inv *Inventory) remove(from Location, quantity uint32) {
+	item := inv.items[from.Container][from.Slot]
+	if item.Quantity <= quantity {
+		delete(inv.items[from.Container], from.Slot)
+		return
+	}
+
+	item.Quantity -= quantity
+	inv.items[from.Container][from.Slot] = item
+}
+
+// valid reports whether a location is a usable slot of an unlocked container.
+func (inv *Inventory) valid(location Location) bool {
+	return location.Slot >= 1 && location.Slot <= inv.Size(location.Container)
+}
+
+func gilLocation() Location {
+	return Location{Container: serverPackets.ContainerKindInventory, Slot: 0}
+}
+
+// movableInto reports whether players may put items into a container themselves;
+// temporary items and the recycle bin are only filled by the game.
+func movableInto(container serverPackets.ContainerKind) bool {
+	return container != serverPackets.ContainerKindTempItems && container != serverPackets.ContainerKindRecycleBin
+}
+
+// stacksWith reports whether two items can share a slot.
+func stacksWith(a, b Item) bool {
+	return a.ID == b.ID && bytes.Equal(a.Extra, b.Extra)
+}
+
+func dedupe(locations []Location) []Location {
+	seen := make(map[Location]bool, len(locations))
+	unique := locations[:0]
+	for _, location := range locations {
+		if !seen[location] {
+			seen[location] = true
+			unique = append(unique, location)
+		}
+	}
+
+	return unique
+}
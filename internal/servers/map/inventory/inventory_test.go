@@ -0,0 +1,201 @@
+package inventory
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+)
+
+const crystal uint16 = 4096
+
+func stackOf12(uint16) uint32 {
+	return 12
+}
+
+func inventorySlot(slot uint8) Location {
+	return Location{Container: serverPackets.ContainerKindInventory, Slot: slot}
+}
+
+func newTestInventory(t *testing.T, items map[uint8]Item) *Inventory {
+	t.Helper()
+
+	inv := New(DefaultSizes)
+	for slot, item := range items {
+		if err := inv.Set(inventorySlot(slot), item); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+
+	return inv
+}
+
+func TestMove(t *testing.T) {
+	safe := Location{Container: serverPackets.ContainerKindMogSafe, Slot: AnySlot}
+
+	t.Run("whole stack to any slot", func(t *testing.T) {
+		inv := newTestInventory(t, map[uint8]Item{3: {ID: crystal, Quantity: 5}})
+
+		changed, err := inv.Move(inventorySlot(3), safe, 5, stackOf12)
+		if err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+
+		moved := Location{Container: serverPackets.ContainerKindMogSafe, Slot: 1}
+		if !slices.Equal(changed, []Location{inventorySlot(3), moved}) {
+			t.Fatalf("Move() changed = %v", changed)
+		}
+
+		if _, ok := inv.Get(inventorySlot(3)); ok {
+			t.Fatalf("source slot is not empty")
+		}
+
+		if item, _ := inv.Get(moved); item.Quantity != 5 {
+			t.Fatalf("moved quantity = %d, want 5", item.Quantity)
+		}
+	})
+
+	t.Run("split and merge", func(t *testing.T) {
+		inv := newTestInventory(t, map[uint8]Item{1: {ID: crystal, Quantity: 10}, 2: {ID: crystal, Quantity: 1}})
+
+		if _, err := inv.Move(inventorySlot(1), inventorySlot(2), 4, stackOf12); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+
+		from, _ := inv.Get(inventorySlot(1))
+		to, _ := inv.Get(inventorySlot(2))
+		if from.Quantity != 6 || to.Quantity != 5 {
+			t.Fatalf("quantities = %d, %d, want 6, 5", from.Quantity, to.Quantity)
+		}
+	})
+
+	cases := []struct {
+		name     string
+		to       Location
+		quantity uint32
+		want     error
+	}{
+		{"more than held", inventorySlot(AnySlot), 11, ErrInvalidQuantity},
+		{"nothing", inventorySlot(AnySlot), 0, ErrInvalidQuantity},
+		{"locked container", Location{Container: serverPackets.ContainerKindStorage, Slot: AnySlot}, 1, ErrContainerLocked},
+		{"temporary items", Location{Container: serverPackets.ContainerKindTempItems, Slot: AnySlot}, 1, ErrNotMovable},
+		{"other item", inventorySlot(2), 1, ErrSlotOccupied},
+		{"outside of the container", inventorySlot(31), 1, ErrInvalidLocation},
+		{"onto itself", inventorySlot(1), 1, ErrInvalidLocation},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inv := newTestInventory(t, map[uint8]Item{1: {ID: crystal, Quantity: 10}, 2: {ID: crystal + 1, Quantity: 1}})
+
+			if _, err := inv.Move(inventorySlot(1), tc.to, tc.quantity, stackOf12); !errors.Is(err, tc.want) {
+				t.Fatalf("Move() error = %v, want %v", err, tc.want)
+			}
+
+			if item, _ := inv.Get(inventorySlot(1)); item.Quantity != 10 {
+				t.Fatalf("failed move changed the source to %d", item.Quantity)
+			}
+		})
+	}
+}
+
+func TestDrop(t *testing.T) {
+	inv := newTestInventory(t, map[uint8]Item{0: {ID: GilItemID, Quantity: 100}, 1: {ID: crystal, Quantity: 3}})
+
+	if _, err := inv.Drop(inventorySlot(0), 1); !errors.Is(err, ErrInvalidLocation) {
+		t.Fatalf("Drop(gil) error = %v, want %v", err, ErrInvalidLocation)
+	}
+
+	if _, err := inv.Drop(inventorySlot(2), 1); !errors.Is(err, ErrEmptySlot) {
+		t.Fatalf("Drop(empty) error = %v, want %v", err, ErrEmptySlot)
+	}
+
+	if _, err := inv.Drop(inventorySlot(1), 3); err != nil {
+		t.Fatalf("Drop() error = %v", err)
+	}
+
+	if _, ok := inv.Get(inventorySlot(1)); ok || inv.Gil() != 100 {
+		t.Fatalf("Drop() left slot 1 filled or changed gil to %d", inv.Gil())
+	}
+}
+
+func TestSort(t *testing.T) {
+	inv := newTestInventory(t, map[uint8]Item{
+		1: {ID: crystal, Quantity: 8},
+		2: {ID: crystal + 1, Quantity: 1},
+		3: {ID: crystal, Quantity: 7},
+		4: {ID: crystal, Quantity: 2, Extra: []byte{1}},
+		5: {ID: crystal, Quantity: 1, Extra: make([]byte, 24)},
+	})
+
+	changed := inv.Sort(serverPackets.ContainerKindInventory, stackOf12)
+	if !slices.Equal(changed, []Location{inventorySlot(1), inventorySlot(3), inventorySlot(5)}) {
+		t.Fatalf("Sort() changed = %v", changed)
+	}
+
+	first, _ := inv.Get(inventorySlot(1))
+	rest, _ := inv.Get(inventorySlot(3))
+	if first.Quantity != 12 || rest.Quantity != 4 {
+		t.Fatalf("quantities = %d, %d, want 12, 4", first.Quantity, rest.Quantity)
+	}
+
+	// a zeroed extra blob is no extra data
+	if _, ok := inv.Get(inventorySlot(5)); ok {
+		t.Fatalf("slot 5 was not merged")
+	}
+
+	// items with different extra data never stack
+	if item, _ := inv.Get(inventorySlot(4)); item.Quantity != 2 {
+		t.Fatalf("signed item quantity = %d, want 2", item.Quantity)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	inv := newTestInventory(t, map[uint8]Item{1: {ID: crystal + 1, Quantity: 1}, 2: {ID: crystal, Quantity: 10}})
+
+	changed, err := inv.Add(serverPackets.ContainerKindInventory, Item{ID: crystal, Quantity: 15}, stackOf12)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if !slices.Equal(changed, []Location{inventorySlot(2), inventorySlot(3), inventorySlot(4)}) {
+		t.Fatalf("Add() changed = %v", changed)
+	}
+
+	if item, _ := inv.Get(inventorySlot(4)); item.Quantity != 1 {
+		t.Fatalf("last stack quantity = %d, want 1", item.Quantity)
+	}
+
+	if _, err := inv.Add(serverPackets.ContainerKindInventory, Item{ID: crystal, Quantity: 12 * 30}, stackOf12); !errors.Is(err, ErrContainerFull) {
+		t.Fatalf("Add() error = %v, want %v", err, ErrContainerFull)
+	}
+
+	if item, _ := inv.Get(inventorySlot(4)); item.Quantity != 1 {
+		t.Fatalf("failed Add() changed the inventory")
+	}
+}
+
+func TestEquippedItemsAreLocked(t *testing.T) {
+	inv := newTestInventory(t, map[uint8]Item{1: {ID: crystal, Quantity: 1}})
+
+	if err := inv.Equip(serverPackets.EquipKindAmmo, inventorySlot(1)); err != nil {
+		t.Fatalf("Equip() error = %v", err)
+	}
+
+	if err := inv.Equip(serverPackets.EquipKindHead, inventorySlot(1)); !errors.Is(err, ErrItemLocked) {
+		t.Fatalf("Equip() in a second slot error = %v, want %v", err, ErrItemLocked)
+	}
+
+	if _, err := inv.Drop(inventorySlot(1), 1); !errors.Is(err, ErrItemLocked) {
+		t.Fatalf("Drop() error = %v, want %v", err, ErrItemLocked)
+	}
+
+	if _, err := inv.Move(inventorySlot(1), inventorySlot(AnySlot), 1, stackOf12); !errors.Is(err, ErrItemLocked) {
+		t.Fatalf("Move() error = %v, want %v", err, ErrItemLocked)
+	}
+
+	if location, ok := inv.Unequip(serverPackets.EquipKindAmmo); !ok || location != inventorySlot(1) || inv.Locked(location) {
+		t.Fatalf("Unequip() = %v, %v", location, ok)
+	}
+}
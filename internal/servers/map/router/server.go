@@ -16,6 +16,7 @@ import (
 	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
 	clientPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/client"
 	"github.com/GoFFXI/GoFFXI/internal/servers/base/udp"
+	"github.com/GoFFXI/GoFFXI/internal/tools/blowfish"
 	"github.com/GoFFXI/GoFFXI/internal/tools/zlib"
 )
 
@@ -140,12 +141,19 @@ func (s *MapRouterServer) HandleIncomingPacket(ctx context.Context, length int,
 		return
 	}
 
+	session.touch(time.Now())
 	session.lastClientPacketID = clientSequence
 	s.updateServerAckFromClient(session, clientAck, clientAddrStr)
 	copy(session.lastClientHeader[:], data[:mapPackets.HeaderSize])
 
+	// a client that changed zones logs in again with a plaintext login packet
 	if packetType == clientPackets.PacketTypeLogin {
-		return
+		if loginPacket, err := clientPackets.ParseLoginPacket(data); err == nil {
+			loginPayload := make([]byte, packetSize)
+			copy(loginPayload, data[payloadStart:payloadEnd])
+			s.sessionZoned(session, loginPacket, head.Sync, loginPayload)
+			return
+		}
 	}
 
 	if err := s.processEncryptedPacket(session, data[:]); err != nil {
@@ -192,22 +200,55 @@ func (s *MapRouterServer) sessionCreated(ctx context.Context, clientAddr *net.UD
 		return
 	}
 
+	// the session must not be expired and must be used from the IP address that authenticated it
+	if err = accountSession.Verify(clientAddr.IP.String(), time.Now()); err != nil {
+		s.Logger().Warn("rejecting account session", "clientAddr", clientAddr.String(), "characterID", loginPacket.UniqueNo, "accountID", accountSession.AccountID, "error", err)
+		return
+	}
+
+	// the character's zone decides which instance handles its packets
+	character, err := s.DB().GetCharacterByID(ctx, loginPacket.UniqueNo)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			s.Logger().Warn("character not found for session", "characterID", loginPacket.UniqueNo)
+		} else {
+			s.Logger().Error("failed to load character for session", "characterID", loginPacket.UniqueNo, "error", err)
+		}
+		return
+	}
+
 	session, err := NewSession(clientAddr, accountSession.SessionKey, s)
 	if err != nil {
 		s.Logger().Error("failed to create session", "clientAddr", clientAddr.String(), "error", err)
 		return
 	}
 
-	character, err := s.DB().GetCharacterByID(ctx, loginPacket.UniqueNo)
-	if err == nil {
-		session.character = &character
-	} else if !errors.Is(err, database.ErrNotFound) {
-		s.Logger().Warn("failed to load character for session", "characterID", loginPacket.UniqueNo, "error", err)
-	}
+	session.character = &character
+	session.zoneID = character.PosZone
 
 	s.setSession(clientAddr.String(), session)
 }
 
+// sessionZoned handles the login packet a client sends once it loaded the zone it was sent to.
+func (s *MapRouterServer) sessionZoned(session *Session, loginPacket *clientPackets.LoginPacket, sequence uint16, payload []byte) {
+	clientAddr := session.clientAddr.String()
+
+	if session.character == nil || session.character.ID != loginPacket.UniqueNo {
+		s.Logger().Warn("ignoring login packet for another character", "clientAddr", clientAddr, "characterID", loginPacket.UniqueNo)
+		return
+	}
+
+	if !session.zoned() {
+		s.Logger().Debug("ignoring repeated login packet", "clientAddr", clientAddr, "characterID", loginPacket.UniqueNo)
+		return
+	}
+
+	s.Logger().Info("client changed zones", "clientAddr", clientAddr, "characterID", loginPacket.UniqueNo, "zoneID", session.currentZone())
+	if err := s.forwardPacketToInstance(session, clientPackets.PacketTypeLogin, sequence, payload); err != nil {
+		s.Logger().Error("failed to forward login packet", "clientAddr", clientAddr, "error", err)
+	}
+}
+
 func (s *MapRouterServer) forwardPacketToInstance(session *Session, packetType uint16, sequence uint16, payload []byte) error {
 	routedPacket := mapPackets.RoutedPacket{
 		ClientAddr: session.clientAddr.String(),
@@ -225,7 +266,7 @@ func (s *MapRouterServer) forwardPacketToInstance(session *Session, packetType u
 
 	s.Logger().Debug("forwarding packet to instance", "clientAddr", session.clientAddr.String(), "packetType", packetType, "sequence", sequence, "payloadBytes", len(payload))
 
-	subject := fmt.Sprintf("map.instance.%d", s.Config().MapInstanceID)
+	subject := fmt.Sprintf("map.zone.%d", session.currentZone())
 	return s.NATS().Publish(subject, routedPacket.ToJSON())
 }
 
@@ -251,15 +292,15 @@ func (s *MapRouterServer) DeliverPacketsToClients(ctx context.Context, wg *sync.
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			s.flushPendingPackets()
+			s.flushPendingPackets(ctx)
 		}
 	}
 }
 
-func (s *MapRouterServer) flushPendingPackets() {
+func (s *MapRouterServer) flushPendingPackets(ctx context.Context) {
 	sessions := s.snapshotSessions()
 	for _, session := range sessions {
-		s.flushSessionQueue(session)
+		s.flushSessionQueue(ctx, session)
 	}
 }
 
@@ -273,7 +314,7 @@ func (s *MapRouterServer) snapshotSessions() []*Session {
 	return snapshot
 }
 
-func (s *MapRouterServer) flushSessionQueue(session *Session) {
+func (s *MapRouterServer) flushSessionQueue(ctx context.Context, session *Session) {
 	addr := session.clientAddr.String()
 
 	for {
@@ -289,6 +330,26 @@ func (s *MapRouterServer) flushSessionQueue(session *Session) {
 			s.requeuePackets(addr, pending)
 			return
 		}
+
+		for _, packet := range pending {
+			// the client has been told to leave the game
+			if packet.Disconnect {
+				s.endSession(ctx, session, "logout")
+				return
+			}
+
+			// the client switches keys once it was told to change zones
+			if packet.ZoneID == 0 {
+				continue
+			}
+
+			if err := session.changeZone(packet.ZoneID); err != nil {
+				s.Logger().Error("failed to rotate session key", "clientAddr", addr, "error", err)
+				continue
+			}
+
+			s.Logger().Info("client sent to another zone", "clientAddr", addr, "zoneID", packet.ZoneID)
+		}
 	}
 }
 
@@ -428,8 +489,8 @@ func (s *MapRouterServer) buildNetworkPacket(session *Session, payload []byte, s
 	s.Logger().Debug("building network packet", "clientAddr", session.clientAddr.String(), "serverPacketID", serverPacketID, "clientPacketID", session.lastClientPacketID, "bitCount", bitCount, "md5", hex.EncodeToString(hash[:]), "chunkPreview", hexPreview(chunk, 64))
 	s.Logger().Debug("udp header preview", "clientAddr", session.clientAddr.String(), "header", hex.EncodeToString(packet[:mapPackets.HeaderSize]), "bodyPreview", hexPreview(packet[mapPackets.HeaderSize:], 64))
 
-	if session.currentBlowfish != nil {
-		session.currentBlowfish.EncryptPacket(packet, int(mapPackets.HeaderSize))
+	if current, _ := session.keys(); current != nil {
+		current.EncryptPacket(packet, int(mapPackets.HeaderSize))
 	}
 
 	return packet, nil
@@ -440,28 +501,19 @@ func (s *MapRouterServer) processEncryptedPacket(session *Session, data []byte)
 		return fmt.Errorf("packet too small after header")
 	}
 
-	decoded := make([]byte, len(data))
-	copy(decoded, data)
-
 	// Keep the latest client header so we can mirror its fields back to the client.
 	copy(session.lastClientHeader[:], data[:mapPackets.HeaderSize])
 	session.lastClientPacketID = binary.LittleEndian.Uint16(data[0:2])
 
-	if session.currentBlowfish != nil {
-		session.currentBlowfish.DecryptPacket(decoded, int(mapPackets.HeaderSize))
-	}
-
-	payloadStart := int(mapPackets.HeaderSize)
-	payloadEnd := len(decoded) - mapPackets.MD5ChecksumSize
-	if payloadEnd <= payloadStart+4 {
-		return fmt.Errorf("encrypted payload too small")
+	// packets the client sent before it learnt it is changing zones still use the previous key
+	current, previous := session.keys()
+	payload, err := decryptPacket(current, data)
+	if err != nil && previous != nil {
+		payload, err = decryptPacket(previous, data)
 	}
 
-	payload := decoded[payloadStart:payloadEnd]
-	checksum := decoded[payloadEnd:]
-	expected := md5.Sum(payload)
-	if !bytes.Equal(expected[:], checksum) {
-		return fmt.Errorf("md5 mismatch")
+	if err != nil {
+		return err
 	}
 
 	bitCount := binary.LittleEndian.Uint32(payload[len(payload)-4:])
@@ -480,28 +532,54 @@ func (s *MapRouterServer) processEncryptedPacket(session *Session, data []byte)
 	return nil
 }
 
+// decryptPacket decrypts a client packet with the given key and returns its compressed
+// payload (with the trailing bit count) once the checksum matches.
+func decryptPacket(key *blowfish.Blowfish, data []byte) ([]byte, error) {
+	decoded := make([]byte, len(data))
+	copy(decoded, data)
+
+	if key != nil {
+		key.DecryptPacket(decoded, int(mapPackets.HeaderSize))
+	}
+
+	payloadStart := int(mapPackets.HeaderSize)
+	payloadEnd := len(decoded) - mapPackets.MD5ChecksumSize
+	if payloadEnd <= payloadStart+4 {
+		return nil, fmt.Errorf("encrypted payload too small")
+	}
+
+	payload := decoded[payloadStart:payloadEnd]
+	checksum := decoded[payloadEnd:]
+	expected := md5.Sum(payload)
+	if !bytes.Equal(expected[:], checksum) {
+		return nil, fmt.Errorf("md5 mismatch")
+	}
+
+	return payload, nil
+}
+
 func (s *MapRouterServer) dispatchSubPackets(session *Session, data []byte) int {
 	offset := 0
 	processed := 0
 	for offset+4 <= len(data) {
-		header := binary.LittleEndian.Uint16(data[offset:])
-		packetType := header & 0x1FF
-		sizeUnits := header >> 9
-		packetSize := int(sizeUnits) * 2
-		if packetSize == 0 || offset+packetSize > len(data) {
-			return processed
+		head := mapPackets.PacketHeader{
+			ID:   binary.LittleEndian.Uint16(data[offset:]),
+			Sync: binary.LittleEndian.Uint16(data[offset+2:]),
 		}
 
-		sequence := binary.LittleEndian.Uint16(data[offset+2:])
-		payloadSize := packetSize - 4
-		if payloadSize < 0 {
+		// the sub-packet size is given in 4 byte units and includes the header
+		packetType := head.GetPacketID()
+		packetSize := int(head.GetPacketSize()) * 4
+		if packetSize < 4 || offset+packetSize > len(data) {
 			return processed
 		}
 
-		payload := make([]byte, payloadSize)
-		copy(payload, data[offset+4:offset+packetSize])
+		// forward the whole sub-packet (header included), the same way the login packet is forwarded
+		payload := make([]byte, packetSize)
+		copy(payload, data[offset:offset+packetSize])
 
-		s.Logger().Debug("dispatching sub-packet", "clientAddr", session.clientAddr.String(), "packetType", packetType, "sequence", sequence, "payloadBytes", payloadSize)
+		sequence := head.Sync
+		s.Logger().Debug("dispatching sub-packet", "clientAddr", session.clientAddr.String(), "packetType", packetType, "sequence", sequence, "payloadBytes", packetSize)
 		if err := s.forwardPacketToInstance(session, packetType, sequence, payload); err != nil {
 			s.Logger().Error("failed to forward decompressed packet", "clientAddr", session.clientAddr.String(), "packetType", packetType, "error", err)
 		}
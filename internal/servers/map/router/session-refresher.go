@@ -0,0 +1,36 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RefreshSessions keeps the account sessions of connected characters from expiring while
+// they are in-game. Sessions of characters that are no longer routed here are left alone,
+// so the lobby-auth sweeper removes them once their TTL runs out.
+func (s *MapRouterServer) RefreshSessions(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ttl := time.Duration(s.Config().SessionKeyTTLSeconds) * time.Second
+	ticker := time.NewTicker(max(ttl/2, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			characterIDs := make([]uint32, 0)
+			for _, session := range s.snapshotSessions() {
+				if session.character != nil {
+					characterIDs = append(characterIDs, session.character.ID)
+				}
+			}
+
+			if err := s.DB().TouchAccountSessionsByCharacterIDs(ctx, characterIDs, time.Now().Add(ttl)); err != nil {
+				s.Logger().Error("failed to refresh account sessions", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	mapPackets "github.com/GoFFXI/GoFFXI/internal/packets/map"
+)
+
+// sessionTimeoutCheckInterval is how often sessions are checked for clients that stopped responding
+const sessionTimeoutCheckInterval = 5 * time.Second
+
+// DisconnectIdleSessions ends the sessions of clients that stopped sending packets and
+// tells the instance simulating their zone, so the character can leave the world.
+func (s *MapRouterServer) DisconnectIdleSessions(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	timeout := time.Duration(s.Config().MapClientTimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(sessionTimeoutCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, session := range s.snapshotSessions() {
+				if now.Sub(session.idleSince()) < timeout {
+					continue
+				}
+
+				s.endSession(ctx, session, "timeout")
+				if session.character == nil {
+					continue
+				}
+
+				disconnect := mapPackets.ClientDisconnect{
+					ClientAddr:  session.clientAddr.String(),
+					CharacterID: session.character.ID,
+				}
+
+				subject := fmt.Sprintf("map.zone.%d.disconnect", session.currentZone())
+				if err := s.NATS().Publish(subject, disconnect.ToJSON()); err != nil {
+					s.Logger().Error("failed to publish client disconnect", "clientAddr", disconnect.ClientAddr, "error", err)
+				}
+			}
+		}
+	}
+}
+
+// endSession forgets a client: its session, queued packets and account session are removed,
+// so the character has to go through the lobby again.
+func (s *MapRouterServer) endSession(ctx context.Context, session *Session, reason string) {
+	addr := session.clientAddr.String()
+
+	s.sessionsMu.Lock()
+	if s.sessions[addr] == session {
+		delete(s.sessions, addr)
+	}
+	s.sessionsMu.Unlock()
+
+	session.Close()
+
+	s.packetsMu.Lock()
+	delete(s.packetsToSend, addr)
+	s.packetsMu.Unlock()
+
+	if err := s.DB().DeleteAccountSessionBySessionKey(ctx, session.sessionKey[:]); err != nil {
+		s.Logger().Error("failed to delete account session", "clientAddr", addr, "error", err)
+	}
+
+	s.Logger().Info("session ended", "clientAddr", addr, "reason", reason)
+}
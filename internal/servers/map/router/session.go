@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/GoFFXI/GoFFXI/internal/database"
@@ -15,11 +17,18 @@ import (
 type Session struct {
 	clientAddr *net.UDPAddr
 	character  *database.Character
-	lastUpdate time.Time
+
+	// lastUpdate is when the client last sent a packet (Unix nanoseconds)
+	lastUpdate atomic.Int64
+
+	// zoneID is the zone the client's packets are routed to
+	zoneID uint16
 
 	lastClientPacketID uint16
 	lastServerPacketID uint16
 
+	// blowfishMu guards the keys and the zone, which change when the client changes zones
+	blowfishMu       sync.Mutex
 	sessionKey       [blowfish.KeySize]byte
 	currentBlowfish  *blowfish.Blowfish
 	previousBlowfish *blowfish.Blowfish
@@ -45,13 +54,14 @@ func NewSession(clientAddr *net.UDPAddr, sessionKey []byte, server *MapRouterSer
 	session := &Session{
 		clientAddr:      clientAddr,
 		character:       nil,
-		lastUpdate:      time.Now(),
 		sessionKey:      keyCopy,
 		currentBlowfish: bFish,
 		server:          server,
 		subscriptions:   []*nats.Subscription{},
 	}
 
+	session.touch(time.Now())
+
 	// setup the NATS subscriptions for the session
 	subject := fmt.Sprintf("map.router.%s.send", clientAddr.String())
 	if err := session.addNATSSubscription(subject, session.processNATSSendRequest); err != nil {
@@ -100,9 +110,13 @@ func (s *Session) processNATSSendRequest(msg *nats.Msg) {
 }
 
 func (s *Session) IncrementBlowfish() error {
-	// Save the current key as previous
-	s.previousBlowfish = s.currentBlowfish
+	s.blowfishMu.Lock()
+	defer s.blowfishMu.Unlock()
 
+	return s.incrementBlowfish()
+}
+
+func (s *Session) incrementBlowfish() error {
 	// Create new Blowfish with incremented key
 	newBF := &blowfish.Blowfish{
 		Key:    s.currentBlowfish.Key,
@@ -114,10 +128,67 @@ func (s *Session) IncrementBlowfish() error {
 		return err
 	}
 
+	// Save the current key as previous, packets the client sent before zoning still use it
+	s.previousBlowfish = s.currentBlowfish
 	s.currentBlowfish = newBF
 	return nil
 }
 
+// changeZone rotates the session key and routes the client's packets to a new zone.
+// It is called once the packet telling the client to change zones has been sent.
+func (s *Session) changeZone(zoneID uint16) error {
+	s.blowfishMu.Lock()
+	defer s.blowfishMu.Unlock()
+
+	if err := s.incrementBlowfish(); err != nil {
+		return err
+	}
+
+	s.zoneID = zoneID
+	return nil
+}
+
+// zoned accepts the rotated key once the client logged into its new zone. It reports
+// whether the session was changing zones at all.
+func (s *Session) zoned() bool {
+	s.blowfishMu.Lock()
+	defer s.blowfishMu.Unlock()
+
+	if s.currentBlowfish.Status != blowfish.BlowfishPendingZone {
+		return false
+	}
+
+	s.currentBlowfish.Status = blowfish.BlowfishAccepted
+	s.previousBlowfish = nil
+	return true
+}
+
+// currentZone returns the zone the client's packets are routed to.
+func (s *Session) currentZone() uint16 {
+	s.blowfishMu.Lock()
+	defer s.blowfishMu.Unlock()
+
+	return s.zoneID
+}
+
+// keys returns the current and previous (possibly nil) session keys.
+func (s *Session) keys() (*blowfish.Blowfish, *blowfish.Blowfish) {
+	s.blowfishMu.Lock()
+	defer s.blowfishMu.Unlock()
+
+	return s.currentBlowfish, s.previousBlowfish
+}
+
+// touch records that the client sent a packet.
+func (s *Session) touch(now time.Time) {
+	s.lastUpdate.Store(now.UnixNano())
+}
+
+// idleSince returns when the client last sent a packet.
+func (s *Session) idleSince() time.Time {
+	return time.Unix(0, s.lastUpdate.Load())
+}
+
 func (s *Session) Close() {
 	for _, sub := range s.subscriptions {
 		_ = sub.Unsubscribe()
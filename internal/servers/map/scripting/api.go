@@ -0,0 +1,360 @@
+package scripting
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// names of the metatables of the userdata scripts see characters and NPCs as
+	playerTypeName = "Player"
+	npcTypeName    = "NPC"
+
+	// maxEventParams is the number of parameters an event starts with
+	maxEventParams = 8
+
+	// maxCharVarName is the longest name of a character variable (the database column's size)
+	maxCharVarName = 64
+)
+
+// sandboxedGlobals are the functions of the base library scripts are not given: they
+// reach files, other modules, the environments of other functions or the collector.
+//
+//nolint:gochecknoglobals // static list
+var sandboxedGlobals = []string{
+	"collectgarbage", "dofile", "getfenv", "load", "loadfile", "loadstring",
+	"module", "newproxy", "require", "setfenv", "_printregs",
+}
+
+// openLibs opens the libraries scripts may use: the base functions (without those
+// listed in sandboxedGlobals), table, string and math.
+func (s *state) openLibs() {
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		s.lua.Push(s.lua.NewFunction(lib.open))
+		s.lua.Push(lua.LString(lib.name))
+		s.lua.Call(1, 0)
+	}
+
+	for _, name := range sandboxedGlobals {
+		s.lua.SetGlobal(name, lua.LNil)
+	}
+
+	// string.dump hands out bytecode, which is not checked when it is loaded back
+	if stringLib, ok := s.lua.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		stringLib.RawSetString("dump", lua.LNil)
+	}
+
+	s.lua.SetGlobal("print", s.lua.NewFunction(s.print))
+}
+
+// openAPI registers the functions scripts act on the zone with.
+func (s *state) openAPI() {
+	s.lua.SetGlobal("SpawnMob", s.lua.NewFunction(s.spawnMob))
+	s.lua.SetGlobal("DespawnMob", s.lua.NewFunction(s.despawnMob))
+	s.lua.SetGlobal("SetTimer", s.lua.NewFunction(s.setTimer))
+	s.lua.SetGlobal("CancelTimer", s.lua.NewFunction(s.cancelTimer))
+
+	meta := s.lua.NewTypeMetatable(playerTypeName)
+	meta.RawSetString("__index", s.lua.SetFuncs(s.lua.NewTable(), map[string]lua.LGFunction{
+		"getID":       s.playerID,
+		"getName":     s.playerName,
+		"startEvent":  s.startEvent,
+		"updateEvent": s.updateEvent,
+		"addItem":     s.addItem,
+		"hasKeyItem":  s.hasKeyItem,
+		"addKeyItem":  s.addKeyItem,
+		"delKeyItem":  s.delKeyItem,
+		"getCharVar":  s.getCharVar,
+		"setCharVar":  s.setCharVar,
+		"message":     s.message,
+	}))
+
+	meta = s.lua.NewTypeMetatable(npcTypeName)
+	meta.RawSetString("__index", s.lua.SetFuncs(s.lua.NewTable(), map[string]lua.LGFunction{
+		"getID":   s.npcID,
+		"getName": s.npcName,
+	}))
+}
+
+// player returns the userdata scripts see a character as.
+func (s *state) player(characterID uint32) *lua.LUserData {
+	ud := s.lua.NewUserData()
+	ud.Value = Player{CharacterID: characterID}
+	s.lua.SetMetatable(ud, s.lua.GetTypeMetatable(playerTypeName))
+
+	return ud
+}
+
+// npc returns the userdata scripts see an NPC as.
+func (s *state) npc(npcID uint32) *lua.LUserData {
+	ud := s.lua.NewUserData()
+	ud.Value = NPC{ID: npcID}
+	s.lua.SetMetatable(ud, s.lua.GetTypeMetatable(npcTypeName))
+
+	return ud
+}
+
+// checkPlayer returns the character of the Player argument n.
+func checkPlayer(ls *lua.LState, n int) uint32 {
+	if player, ok := ls.CheckUserData(n).Value.(Player); ok {
+		return player.CharacterID
+	}
+
+	ls.ArgError(n, "Player expected")
+
+	return 0
+}
+
+// checkNPC returns the NPC argument n.
+func checkNPC(ls *lua.LState, n int) uint32 {
+	if npc, ok := ls.CheckUserData(n).Value.(NPC); ok {
+		return npc.ID
+	}
+
+	ls.ArgError(n, "NPC expected")
+
+	return 0
+}
+
+// checkEventParams returns the event parameter arguments from n on.
+func checkEventParams(ls *lua.LState, n int) []uint32 {
+	if ls.GetTop()-n+1 > maxEventParams {
+		ls.ArgError(n+maxEventParams, "too many event parameters")
+	}
+
+	params := make([]uint32, 0, maxEventParams)
+	for i := n; i <= ls.GetTop(); i++ {
+		// parameters are sent as-is; negative values wrap the way the client reads them
+		params = append(params, uint32(ls.CheckInt64(i))) //nolint:gosec // see above
+	}
+
+	return params
+}
+
+// checkID returns the integer argument n, which has to fit in T.
+func checkID[T uint16 | uint32 | uint64](ls *lua.LState, n int) T {
+	v := ls.CheckInt64(n)
+	if v < 0 || uint64(v) > uint64(^T(0)) {
+		ls.ArgError(n, "out of range")
+	}
+
+	return T(v) //nolint:gosec // checked above
+}
+
+// print logs its arguments, separated by spaces.
+func (s *state) print(ls *lua.LState) int {
+	parts := make([]string, 0, ls.GetTop())
+	for i := 1; i <= ls.GetTop(); i++ {
+		parts = append(parts, ls.ToStringMeta(ls.Get(i)).String())
+	}
+
+	s.r.logger.Info("script: " + strings.Join(parts, " "))
+
+	return 0
+}
+
+// SpawnMob(mobID) -> bool
+func (s *state) spawnMob(ls *lua.LState) int {
+	mobID := checkID[uint32](ls, 1)
+	ls.Push(lua.LBool(s.r.host.SpawnMob(mobID)))
+
+	return 1
+}
+
+// DespawnMob(mobID) -> bool
+func (s *state) despawnMob(ls *lua.LState) int {
+	mobID := checkID[uint32](ls, 1)
+	ls.Push(lua.LBool(s.r.host.DespawnMob(mobID)))
+
+	return 1
+}
+
+// SetTimer(seconds, fn) -> timerID; fn is called once the seconds have passed.
+func (s *state) setTimer(ls *lua.LState) int {
+	seconds := ls.CheckNumber(1)
+	fn := ls.CheckFunction(2)
+	if seconds < 0 || math.IsNaN(float64(seconds)) || math.IsInf(float64(seconds), 0) {
+		ls.ArgError(1, "invalid delay")
+	}
+
+	var timerID uint64
+	timerID = s.r.host.After(time.Duration(float64(seconds)*float64(time.Second)), func() {
+		delete(s.timers, timerID)
+
+		if err := s.call(fn); err != nil {
+			s.r.logger.Warn("script timer failed", "error", err)
+		}
+	})
+	s.timers[timerID] = struct{}{}
+
+	ls.Push(lua.LNumber(timerID))
+
+	return 1
+}
+
+// CancelTimer(timerID); only the timers of the scripts can be cancelled.
+func (s *state) cancelTimer(ls *lua.LState) int {
+	timerID := checkID[uint64](ls, 1)
+	if _, ok := s.timers[timerID]; ok {
+		delete(s.timers, timerID)
+		s.r.host.Cancel(timerID)
+	}
+
+	return 0
+}
+
+// player:getID() -> characterID
+func (s *state) playerID(ls *lua.LState) int {
+	ls.Push(lua.LNumber(checkPlayer(ls, 1)))
+	return 1
+}
+
+// player:getName() -> name, or nil once the character left the zone
+func (s *state) playerName(ls *lua.LState) int {
+	name, ok := s.r.host.PlayerName(checkPlayer(ls, 1))
+	if !ok {
+		ls.Push(lua.LNil)
+		return 1
+	}
+
+	ls.Push(lua.LString(name))
+
+	return 1
+}
+
+// npc:getID() -> npcID
+func (s *state) npcID(ls *lua.LState) int {
+	ls.Push(lua.LNumber(checkNPC(ls, 1)))
+	return 1
+}
+
+// npc:getName() -> name
+func (s *state) npcName(ls *lua.LState) int {
+	name, ok := s.r.host.NPCName(checkNPC(ls, 1))
+	if !ok {
+		ls.Push(lua.LNil)
+		return 1
+	}
+
+	ls.Push(lua.LString(name))
+
+	return 1
+}
+
+// player:startEvent(eventID, params...) -> bool; cutscenes are events too.
+func (s *state) startEvent(ls *lua.LState) int {
+	characterID := checkPlayer(ls, 1)
+	eventID := checkID[uint16](ls, 2)
+	params := checkEventParams(ls, 3)
+	ls.Push(lua.LBool(s.r.host.StartEvent(characterID, eventID, params)))
+
+	return 1
+}
+
+// player:updateEvent(params...) -> bool; sends new parameters to the running event.
+func (s *state) updateEvent(ls *lua.LState) int {
+	characterID := checkPlayer(ls, 1)
+	params := checkEventParams(ls, 2)
+	ls.Push(lua.LBool(s.r.host.UpdateEvent(characterID, params)))
+
+	return 1
+}
+
+// player:addItem(itemID, [quantity]) -> bool
+func (s *state) addItem(ls *lua.LState) int {
+	characterID := checkPlayer(ls, 1)
+	itemID := checkID[uint16](ls, 2)
+
+	quantity := uint32(1)
+	if ls.GetTop() >= 3 {
+		quantity = checkID[uint32](ls, 3)
+	}
+
+	if quantity == 0 {
+		ls.ArgError(3, "out of range")
+	}
+
+	ls.Push(lua.LBool(s.r.host.AddItem(characterID, itemID, quantity)))
+
+	return 1
+}
+
+// player:hasKeyItem(keyItemID) -> bool
+func (s *state) hasKeyItem(ls *lua.LState) int {
+	characterID := checkPlayer(ls, 1)
+	keyItemID := checkID[uint16](ls, 2)
+	ls.Push(lua.LBool(s.r.host.HasKeyItem(characterID, keyItemID)))
+
+	return 1
+}
+
+// player:addKeyItem(keyItemID) -> bool
+func (s *state) addKeyItem(ls *lua.LState) int {
+	characterID := checkPlayer(ls, 1)
+	keyItemID := checkID[uint16](ls, 2)
+	ls.Push(lua.LBool(s.r.host.AddKeyItem(characterID, keyItemID)))
+
+	return 1
+}
+
+// player:delKeyItem(keyItemID) -> bool
+func (s *state) delKeyItem(ls *lua.LState) int {
+	characterID := checkPlayer(ls, 1)
+	keyItemID := checkID[uint16](ls, 2)
+	ls.Push(lua.LBool(s.r.host.DelKeyItem(characterID, keyItemID)))
+
+	return 1
+}
+
+// player:getCharVar(name) -> value
+func (s *state) getCharVar(ls *lua.LState) int {
+	characterID := checkPlayer(ls, 1)
+	name := checkCharVarName(ls, 2)
+	ls.Push(lua.LNumber(s.r.host.CharVar(characterID, name)))
+
+	return 1
+}
+
+// player:setCharVar(name, value); setting a variable to 0 deletes it.
+func (s *state) setCharVar(ls *lua.LState) int {
+	characterID := checkPlayer(ls, 1)
+	name := checkCharVarName(ls, 2)
+
+	value := ls.CheckInt64(3)
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		ls.ArgError(3, "out of range")
+	}
+
+	s.r.host.SetCharVar(characterID, name, int32(value)) //nolint:gosec // checked above
+
+	return 0
+}
+
+// player:message(text)
+func (s *state) message(ls *lua.LState) int {
+	characterID := checkPlayer(ls, 1)
+	s.r.host.Message(characterID, ls.CheckString(2))
+
+	return 0
+}
+
+// checkCharVarName returns the character variable name argument n.
+func checkCharVarName(ls *lua.LState, n int) string {
+	name := ls.CheckString(n)
+	if name == "" || len(name) > maxCharVarName {
+		ls.ArgError(n, "invalid variable name")
+	}
+
+	return name
+}
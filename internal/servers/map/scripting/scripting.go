@@ -0,0 +1,302 @@
+// Package scripting runs the Lua scripts of a zone, which NPCs, quests and zone events
+// are written in. Scripts run sandboxed: they cannot reach files, the OS or other
+// modules, every call into them has a time limit, and they only act on the world
+// through the Host of their zone. A broken script fails its own call and nothing else.
+package scripting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	// DefaultCallTimeout is how long a call into the scripts may run by default
+	DefaultCallTimeout = 100 * time.Millisecond
+
+	// limits of a zone's Lua state; going over them fails the call
+	callStackSize   = 200
+	registrySize    = 8 * 1024
+	registryMaxSize = 256 * 1024
+)
+
+var (
+	ErrScriptFailed    = errors.New("script failed")
+	ErrInvalidArgument = errors.New("invalid script argument")
+)
+
+// Host is what scripts act on; the map instance implements it for each zone. It is
+// only called from the zone goroutine, while a script runs. Methods acting on a
+// character report false (or zero) when the character is not in the zone.
+type Host interface {
+	// PlayerName returns the name of a character in the zone.
+	PlayerName(characterID uint32) (string, bool)
+
+	// SpawnMob spawns a mob of the zone that is not spawned.
+	SpawnMob(mobID uint32) bool
+
+	// DespawnMob takes a spawned mob of the zone out of it.
+	DespawnMob(mobID uint32) bool
+
+	// NPCName returns the name of an NPC of the zone.
+	NPCName(npcID uint32) (string, bool)
+
+	// StartEvent starts an event (a dialog or cutscene) for a character, with the NPC
+	// it is talking to or, for cutscenes, itself. It reports false if the character is
+	// in an event already.
+	StartEvent(characterID uint32, eventID uint16, params []uint32) bool
+
+	// UpdateEvent sends new parameters to the event a character is in.
+	UpdateEvent(characterID uint32, params []uint32) bool
+
+	// AddItem adds items to the inventory of a character, reporting false if they do not fit.
+	AddItem(characterID uint32, itemID uint16, quantity uint32) bool
+
+	HasKeyItem(characterID uint32, keyItemID uint16) bool
+
+	// AddKeyItem gives a key item to a character, reporting false if it already had it.
+	AddKeyItem(characterID uint32, keyItemID uint16) bool
+
+	// DelKeyItem takes a key item from a character, reporting false if it did not have it.
+	DelKeyItem(characterID uint32, keyItemID uint16) bool
+
+	// CharVar returns a variable of a character; variables never set are 0.
+	CharVar(characterID uint32, name string) int32
+
+	SetCharVar(characterID uint32, name string, value int32)
+
+	// Message sends a system message to a character.
+	Message(characterID uint32, text string)
+
+	// After runs fn on the zone goroutine once d has passed.
+	After(d time.Duration, fn func()) uint64
+
+	// Cancel stops a timer started by After.
+	Cancel(timerID uint64)
+}
+
+// Options configure a runtime.
+type Options struct {
+	Host   Host
+	Logger *slog.Logger
+
+	// CallTimeout is how long a call into the scripts may run (DefaultCallTimeout if 0)
+	CallTimeout time.Duration
+}
+
+// Player is a character passed to a script function.
+type Player struct {
+	CharacterID uint32
+}
+
+// NPC is an NPC passed to a script function.
+type NPC struct {
+	ID uint32
+}
+
+// Runtime is the Lua state the scripts of a zone run in. It is not safe for
+// concurrent use: like the rest of the zone, it is only used from its goroutine.
+type Runtime struct {
+	host        Host
+	logger      *slog.Logger
+	callTimeout time.Duration
+
+	current *state
+}
+
+// state is a Lua state with the modules loaded into it and the timers they started.
+type state struct {
+	r       *Runtime
+	lua     *lua.LState
+	modules map[string]*lua.LTable
+	timers  map[uint64]struct{}
+}
+
+// New creates a runtime without scripts.
+func New(options Options) *Runtime {
+	r := &Runtime{
+		host:        options.Host,
+		logger:      options.Logger,
+		callTimeout: options.CallTimeout,
+	}
+
+	if r.logger == nil {
+		r.logger = slog.Default()
+	}
+
+	if r.callTimeout <= 0 {
+		r.callTimeout = DefaultCallTimeout
+	}
+
+	r.current = r.newState()
+
+	return r
+}
+
+// Load replaces the running scripts. The new scripts are run in a state of their own
+// first: if one of them fails, the running scripts are kept. Otherwise the timers the
+// previous scripts started are cancelled.
+func (r *Runtime) Load(scripts *Scripts) error {
+	next := r.newState()
+
+	modules := make([]string, 0, len(scripts.modules))
+	for module := range scripts.modules {
+		modules = append(modules, module)
+	}
+
+	// load in a fixed order, for what the scripts do as they load
+	slices.Sort(modules)
+
+	for _, module := range modules {
+		env := next.lua.NewTable()
+		meta := next.lua.NewTable()
+		meta.RawSetString("__index", next.lua.G.Global)
+		next.lua.SetMetatable(env, meta)
+
+		chunk := next.lua.NewFunctionFromProto(scripts.modules[module])
+		chunk.Env = env
+
+		if err := next.call(chunk); err != nil {
+			next.close()
+			return fmt.Errorf("%w: loading %s: %w", ErrScriptFailed, module, err)
+		}
+
+		next.modules[module] = env
+	}
+
+	r.current.close()
+	r.current = next
+
+	return nil
+}
+
+// Close cancels the timers of the scripts and releases their state.
+func (r *Runtime) Close() {
+	r.current.close()
+	r.current = r.newState()
+}
+
+// Has reports whether a module defines a function.
+func (r *Runtime) Has(module, function string) bool {
+	_, ok := r.function(module, function)
+	return ok
+}
+
+// Call calls a function of a module, reporting false if the module does not define
+// it. Arguments are Player and NPC values, integers, strings and booleans. Errors of the
+// script are returned; the runtime stays usable.
+func (r *Runtime) Call(module, function string, args ...any) (bool, error) {
+	fn, ok := r.function(module, function)
+	if !ok {
+		return false, nil
+	}
+
+	values := make([]lua.LValue, 0, len(args))
+	for _, arg := range args {
+		value, err := r.current.value(arg)
+		if err != nil {
+			return true, fmt.Errorf("%s.%s: %w", module, function, err)
+		}
+
+		values = append(values, value)
+	}
+
+	if err := r.current.call(fn, values...); err != nil {
+		return true, fmt.Errorf("%w: %s.%s: %w", ErrScriptFailed, module, function, err)
+	}
+
+	return true, nil
+}
+
+func (r *Runtime) function(module, function string) (*lua.LFunction, bool) {
+	env, ok := r.current.modules[module]
+	if !ok {
+		return nil, false
+	}
+
+	fn, ok := env.RawGetString(function).(*lua.LFunction)
+	return fn, ok
+}
+
+// newState creates a sandboxed Lua state with the scripting API.
+func (r *Runtime) newState() *state {
+	s := &state{
+		r: r,
+		lua: lua.NewState(lua.Options{
+			SkipOpenLibs:    true,
+			CallStackSize:   callStackSize,
+			RegistrySize:    registrySize,
+			RegistryMaxSize: registryMaxSize,
+		}),
+		modules: make(map[string]*lua.LTable),
+		timers:  make(map[uint64]struct{}),
+	}
+
+	s.openLibs()
+	s.openAPI()
+
+	return s
+}
+
+// call calls a Lua function in protected mode, within the call timeout. Calls made
+// while a script runs (a script's API call leading to another script) share the
+// timeout of the outer call.
+func (s *state) call(fn *lua.LFunction, args ...lua.LValue) (err error) {
+	defer func() {
+		// the API is in Go: make sure nothing in it takes the zone down
+		if rcv := recover(); rcv != nil {
+			err = fmt.Errorf("panic: %v", rcv)
+		}
+	}()
+
+	if s.lua.Context() == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), s.r.callTimeout)
+		defer cancel()
+
+		s.lua.SetContext(ctx)
+		defer s.lua.RemoveContext()
+	}
+
+	return s.lua.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, args...)
+}
+
+// value converts a Go argument to Lua.
+func (s *state) value(arg any) (lua.LValue, error) {
+	switch v := arg.(type) {
+	case Player:
+		return s.player(v.CharacterID), nil
+	case NPC:
+		return s.npc(v.ID), nil
+	case bool:
+		return lua.LBool(v), nil
+	case string:
+		return lua.LString(v), nil
+	case int:
+		return lua.LNumber(v), nil
+	case int32:
+		return lua.LNumber(v), nil
+	case uint8:
+		return lua.LNumber(v), nil
+	case uint16:
+		return lua.LNumber(v), nil
+	case uint32:
+		return lua.LNumber(v), nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrInvalidArgument, arg)
+	}
+}
+
+// close cancels the timers the state's scripts started and releases the state.
+func (s *state) close() {
+	for timerID := range s.timers {
+		s.r.host.Cancel(timerID)
+	}
+
+	clear(s.timers)
+	s.lua.Close()
+}
@@ -0,0 +1,364 @@
+package scripting
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// fakeHost records what scripts do; it has a single character, 1 ("Tester").
+type fakeHost struct {
+	events   []uint16
+	params   []uint32
+	items    map[uint16]uint32
+	keyItems map[uint16]bool
+	vars     map[string]int32
+	messages []string
+	spawned  []uint32
+
+	nextTimer uint64
+	timers    map[uint64]func()
+}
+
+func newFakeHost() *fakeHost {
+	return &fakeHost{
+		items:    make(map[uint16]uint32),
+		keyItems: make(map[uint16]bool),
+		vars:     make(map[string]int32),
+		timers:   make(map[uint64]func()),
+	}
+}
+
+func (h *fakeHost) PlayerName(characterID uint32) (string, bool) {
+	return "Tester", characterID == 1
+}
+
+func (h *fakeHost) SpawnMob(mobID uint32) bool {
+	h.spawned = append(h.spawned, mobID)
+	return true
+}
+
+func (h *fakeHost) DespawnMob(uint32) bool {
+	panic("despawn exploded")
+}
+
+func (h *fakeHost) NPCName(npcID uint32) (string, bool) {
+	return "Guard", npcID == 100
+}
+
+func (h *fakeHost) UpdateEvent(_ uint32, params []uint32) bool {
+	h.params = params
+	return len(h.events) > 0
+}
+
+func (h *fakeHost) StartEvent(_ uint32, eventID uint16, params []uint32) bool {
+	h.events = append(h.events, eventID)
+	h.params = params
+
+	return true
+}
+
+func (h *fakeHost) AddItem(_ uint32, itemID uint16, quantity uint32) bool {
+	h.items[itemID] += quantity
+	return true
+}
+
+func (h *fakeHost) HasKeyItem(_ uint32, keyItemID uint16) bool {
+	return h.keyItems[keyItemID]
+}
+
+func (h *fakeHost) AddKeyItem(_ uint32, keyItemID uint16) bool {
+	if h.keyItems[keyItemID] {
+		return false
+	}
+
+	h.keyItems[keyItemID] = true
+
+	return true
+}
+
+func (h *fakeHost) DelKeyItem(_ uint32, keyItemID uint16) bool {
+	had := h.keyItems[keyItemID]
+	delete(h.keyItems, keyItemID)
+
+	return had
+}
+
+func (h *fakeHost) CharVar(_ uint32, name string) int32 {
+	return h.vars[name]
+}
+
+func (h *fakeHost) SetCharVar(_ uint32, name string, value int32) {
+	h.vars[name] = value
+}
+
+func (h *fakeHost) Message(_ uint32, text string) {
+	h.messages = append(h.messages, text)
+}
+
+func (h *fakeHost) After(_ time.Duration, fn func()) uint64 {
+	h.nextTimer++
+	h.timers[h.nextTimer] = fn
+
+	return h.nextTimer
+}
+
+func (h *fakeHost) Cancel(timerID uint64) {
+	delete(h.timers, timerID)
+}
+
+// fire runs the pending timers.
+func (h *fakeHost) fire() {
+	timers := h.timers
+	h.timers = make(map[uint64]func())
+
+	for _, fn := range timers {
+		fn()
+	}
+}
+
+// newTestRuntime loads the given modules into a runtime of a fake host.
+func newTestRuntime(t *testing.T, modules map[string]string) (*Runtime, *fakeHost) {
+	t.Helper()
+
+	host := newFakeHost()
+	r := New(Options{Host: host, CallTimeout: 50 * time.Millisecond})
+	t.Cleanup(r.Close)
+
+	if err := r.Load(testScripts(t, modules)); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	return r, host
+}
+
+func testScripts(t *testing.T, modules map[string]string) *Scripts {
+	t.Helper()
+
+	scripts := &Scripts{modules: make(map[string]*lua.FunctionProto)}
+	for module, source := range modules {
+		if err := scripts.add(module, source); err != nil {
+			t.Fatalf("add(%s) error = %v", module, err)
+		}
+	}
+
+	return scripts
+}
+
+func TestCall(t *testing.T) {
+	r, host := newTestRuntime(t, map[string]string{
+		"npcs/Guard": `
+			function onTrigger(player, npc, count)
+				if player:hasKeyItem(5) then
+					player:startEvent(100, 1, -1)
+					return
+				end
+
+				player:addKeyItem(5)
+				player:addItem(4096, count)
+				player:setCharVar("visits", player:getCharVar("visits") + 1)
+				player:message("Hello, " .. player:getName() .. ". I am " .. npc:getName() .. ".")
+				SpawnMob(17187111)
+			end
+
+			function onEventUpdate(player, eventID, option, npc)
+				player:updateEvent(eventID + option, npc:getID())
+			end
+		`,
+	})
+
+	for range 2 {
+		handled, err := r.Call("npcs/Guard", "onTrigger", Player{CharacterID: 1}, NPC{ID: 100}, 3)
+		if !handled || err != nil {
+			t.Fatalf("Call() = %v, %v, want handled without error", handled, err)
+		}
+	}
+
+	if host.items[4096] != 3 || host.vars["visits"] != 1 || !host.keyItems[5] || len(host.spawned) != 1 {
+		t.Fatalf("host after first call = %+v", host)
+	}
+
+	if len(host.messages) != 1 || host.messages[0] != "Hello, Tester. I am Guard." {
+		t.Fatalf("messages = %q", host.messages)
+	}
+
+	if len(host.events) != 1 || host.events[0] != 100 || len(host.params) != 2 || host.params[1] != 0xFFFFFFFF {
+		t.Fatalf("events = %v, params = %v, want event 100 with params 1, -1", host.events, host.params)
+	}
+
+	if _, err := r.Call("npcs/Guard", "onEventUpdate", Player{CharacterID: 1}, uint16(100), uint32(2), NPC{ID: 100}); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	if len(host.params) != 2 || host.params[0] != 102 || host.params[1] != 100 {
+		t.Fatalf("update params = %v, want 102, 100", host.params)
+	}
+
+	if handled, err := r.Call("npcs/Guard", "onEventFinish", Player{CharacterID: 1}); handled || err != nil {
+		t.Fatalf("Call() of an undefined function = %v, %v, want not handled", handled, err)
+	}
+
+	if handled, _ := r.Call("npcs/Nobody", "onTrigger"); handled {
+		t.Fatalf("Call() of an unknown module handled")
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	r, _ := newTestRuntime(t, map[string]string{
+		"Zone": `
+			function check()
+				return io == nil and os == nil and require == nil and dofile == nil
+					and loadstring == nil and setfenv == nil and string.dump == nil
+					and debug == nil and package == nil
+			end
+
+			function run()
+				if not check() then
+					error("sandbox escaped")
+				end
+			end
+		`,
+	})
+
+	if _, err := r.Call("Zone", "run"); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+}
+
+func TestModulesAreIsolated(t *testing.T) {
+	r, _ := newTestRuntime(t, map[string]string{
+		"a": `value = 1; function get() if value ~= 1 then error("overwritten") end end`,
+		"b": `value = 2`,
+	})
+
+	if _, err := r.Call("a", "get"); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+}
+
+func TestScriptErrors(t *testing.T) {
+	r, host := newTestRuntime(t, map[string]string{
+		"Zone": `
+			function fails() error("broken quest") end
+			function loops() while true do end end
+			function recurses() return 1 + recurses() end
+			function panics() DespawnMob(1) end
+			function badArgument(player) player:addItem(70000) end
+			function works(player) player:message("still here") end
+		`,
+	})
+
+	for _, function := range []string{"fails", "loops", "recurses", "panics", "badArgument"} {
+		handled, err := r.Call("Zone", function, Player{CharacterID: 1})
+		if !handled || !errors.Is(err, ErrScriptFailed) {
+			t.Fatalf("Call(%s) = %v, %v, want ErrScriptFailed", function, handled, err)
+		}
+	}
+
+	// the runtime keeps working after its scripts failed
+	if _, err := r.Call("Zone", "works", Player{CharacterID: 1}); err != nil || len(host.messages) != 1 {
+		t.Fatalf("Call() after failures = %v, messages %q", err, host.messages)
+	}
+
+	if _, err := r.Call("Zone", "works", struct{}{}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Call() with an unsupported argument error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestTimers(t *testing.T) {
+	r, host := newTestRuntime(t, map[string]string{
+		"Zone": `
+			function start(player)
+				SetTimer(5, function() player:message("fired") end)
+				local cancelled = SetTimer(5, function() player:message("cancelled") end)
+				CancelTimer(cancelled)
+				SetTimer(1, function() error("timer failed") end)
+			end
+		`,
+	})
+
+	if _, err := r.Call("Zone", "start", Player{CharacterID: 1}); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	host.fire()
+
+	if len(host.messages) != 1 || host.messages[0] != "fired" {
+		t.Fatalf("messages = %q, want only the timer that was not cancelled", host.messages)
+	}
+}
+
+func TestReload(t *testing.T) {
+	r, host := newTestRuntime(t, map[string]string{
+		"Zone": `
+			SetTimer(60, function() end)
+			function greet(player) player:message("v1") end
+		`,
+	})
+
+	// scripts failing to load leave the running ones in place
+	err := r.Load(testScripts(t, map[string]string{"Zone": `error("broken")`}))
+	if !errors.Is(err, ErrScriptFailed) {
+		t.Fatalf("Load() of a broken script error = %v, want ErrScriptFailed", err)
+	}
+
+	if len(host.timers) != 1 {
+		t.Fatalf("timers after a failed reload = %d, want 1", len(host.timers))
+	}
+
+	if err = r.Load(testScripts(t, map[string]string{"Zone": `function greet(player) player:message("v2") end`})); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(host.timers) != 0 {
+		t.Fatalf("timers after a reload = %d, want those of the previous scripts cancelled", len(host.timers))
+	}
+
+	if _, err = r.Call("Zone", "greet", Player{CharacterID: 1}); err != nil || len(host.messages) != 1 || host.messages[0] != "v2" {
+		t.Fatalf("Call() after reload = %v, messages %q", err, host.messages)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "npcs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"Zone.lua":       "function onInitialize() end",
+		"npcs/Guard.lua": "function onTrigger(player) end",
+		"README.md":      "not a script",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scripts, err := Compile(dir)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	if scripts.Len() != 2 || scripts.modules["npcs/Guard"] == nil {
+		t.Fatalf("Compile() modules = %v, want Zone and npcs/Guard", scripts.modules)
+	}
+
+	if scripts, err = Compile(filepath.Join(dir, "missing")); err != nil || scripts.Len() != 0 {
+		t.Fatalf("Compile() of a missing directory = %v, %v, want no scripts", scripts, err)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, "Broken.lua"), []byte("function ("), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Compile(dir); err == nil || !errors.Is(err, ErrInvalidScript) || !strings.Contains(err.Error(), "Broken.lua") {
+		t.Fatalf("Compile() of a broken script error = %v, want ErrInvalidScript naming the file", err)
+	}
+}
@@ -0,0 +1,81 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// scriptExtension is the extension of the script files
+const scriptExtension = ".lua"
+
+var ErrInvalidScript = errors.New("invalid script")
+
+// Scripts are the compiled scripts of a zone, keyed by module: the path of the file
+// in the zone's script directory without its extension ("Zone", "npcs/Rycharde").
+// Compiling is done off the zone goroutine; the scripts only run once loaded into a
+// Runtime.
+type Scripts struct {
+	modules map[string]*lua.FunctionProto
+}
+
+// Compile reads and compiles the scripts of a directory and its subdirectories. A
+// directory that does not exist holds no scripts.
+func Compile(dir string) (*Scripts, error) {
+	scripts := &Scripts{modules: make(map[string]*lua.FunctionProto)}
+
+	fsys := os.DirFS(dir)
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || path.Ext(name) != scriptExtension {
+			return nil
+		}
+
+		source, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		return scripts.add(strings.TrimSuffix(name, scriptExtension), string(source))
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return scripts, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+
+	return scripts, nil
+}
+
+// add compiles the source of a module.
+func (s *Scripts) add(module, source string) error {
+	chunk, err := parse.Parse(strings.NewReader(source), module+scriptExtension)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidScript, err)
+	}
+
+	proto, err := lua.Compile(chunk, module+scriptExtension)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidScript, err)
+	}
+
+	s.modules[module] = proto
+
+	return nil
+}
+
+// Len returns the number of modules.
+func (s *Scripts) Len() int {
+	return len(s.modules)
+}
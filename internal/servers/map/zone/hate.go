@@ -0,0 +1,99 @@
+package zone
+
+import (
+	"time"
+)
+
+const (
+	// MaxEnmity caps both kinds of enmity a character can have on a mob
+	MaxEnmity = 30000
+
+	// VolatileEnmityDecay is how much volatile enmity wears off per second
+	VolatileEnmityDecay = 60
+)
+
+type hateEntry struct {
+	cumulative int32
+	volatile   int32
+
+	// order keeps ties on the character that got on the list first
+	order uint64
+}
+
+// HateList holds the enmity characters have on a mob. Cumulative enmity stays until
+// the fight ends, volatile enmity wears off over time; the mob fights the character
+// with the most of both. The zero value is an empty list.
+type HateList struct {
+	entries   map[uint32]*hateEntry
+	nextOrder uint64
+}
+
+// Add adds enmity for a character, putting it on the list if it was not.
+func (h *HateList) Add(characterID uint32, cumulative, volatile int32) {
+	if h.entries == nil {
+		h.entries = make(map[uint32]*hateEntry)
+	}
+
+	entry, ok := h.entries[characterID]
+	if !ok {
+		entry = &hateEntry{order: h.nextOrder}
+		h.nextOrder++
+		h.entries[characterID] = entry
+	}
+
+	entry.cumulative = min(max(entry.cumulative+cumulative, 0), MaxEnmity)
+	entry.volatile = min(max(entry.volatile+volatile, 0), MaxEnmity)
+}
+
+// Remove takes a character off the list.
+func (h *HateList) Remove(characterID uint32) {
+	delete(h.entries, characterID)
+}
+
+// Clear empties the list.
+func (h *HateList) Clear() {
+	h.entries = nil
+}
+
+// Has reports whether a character is on the list.
+func (h *HateList) Has(characterID uint32) bool {
+	_, ok := h.entries[characterID]
+	return ok
+}
+
+// Len returns the number of characters on the list.
+func (h *HateList) Len() int {
+	return len(h.entries)
+}
+
+// Enmity returns the total enmity of a character.
+func (h *HateList) Enmity(characterID uint32) int32 {
+	entry, ok := h.entries[characterID]
+	if !ok {
+		return 0
+	}
+
+	return entry.cumulative + entry.volatile
+}
+
+// Decay wears off the volatile enmity of everyone on the list.
+func (h *HateList) Decay(elapsed time.Duration) {
+	decay := int32(elapsed.Seconds() * VolatileEnmityDecay)
+	for _, entry := range h.entries {
+		entry.volatile = max(entry.volatile-decay, 0)
+	}
+}
+
+// Top returns the character with the most enmity. Ties go to whoever got on the list first.
+func (h *HateList) Top() (uint32, bool) {
+	var top uint32
+	var best *hateEntry
+	for characterID, entry := range h.entries {
+		if best == nil || entry.cumulative+entry.volatile > best.cumulative+best.volatile ||
+			(entry.cumulative+entry.volatile == best.cumulative+best.volatile && entry.order < best.order) {
+			top, best = characterID, entry
+		}
+	}
+
+	return top, best != nil
+}
@@ -0,0 +1,49 @@
+package zone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHateList(t *testing.T) {
+	var hate HateList
+	if _, ok := hate.Top(); ok {
+		t.Fatalf("Top() of an empty list reported a character")
+	}
+
+	hate.Add(1, 100, 0)
+	hate.Add(2, 50, 50)
+	if top, _ := hate.Top(); top != 1 {
+		t.Fatalf("Top() = %d, want the first of two characters with equal enmity", top)
+	}
+
+	hate.Add(2, 0, 100)
+	if top, _ := hate.Top(); top != 2 || hate.Enmity(2) != 200 {
+		t.Fatalf("Top() = %d with enmity %d, want 2 with 200", top, hate.Enmity(2))
+	}
+
+	// volatile enmity wears off, cumulative enmity stays
+	hate.Decay(2 * time.Second)
+	if hate.Enmity(2) != 80 {
+		t.Fatalf("Enmity(2) after decay = %d, want 80", hate.Enmity(2))
+	}
+
+	if top, _ := hate.Top(); top != 1 {
+		t.Fatalf("Top() after decay = %d, want 1", top)
+	}
+
+	hate.Add(1, 2*MaxEnmity, 0)
+	if hate.Enmity(1) != MaxEnmity {
+		t.Fatalf("Enmity(1) = %d, want it capped at %d", hate.Enmity(1), MaxEnmity)
+	}
+
+	hate.Remove(1)
+	if hate.Has(1) || hate.Len() != 1 {
+		t.Fatalf("Has(1) = %v, Len() = %d after Remove", hate.Has(1), hate.Len())
+	}
+
+	hate.Clear()
+	if hate.Len() != 0 {
+		t.Fatalf("Len() after Clear = %d", hate.Len())
+	}
+}
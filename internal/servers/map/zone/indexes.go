@@ -0,0 +1,50 @@
+package zone
+
+const (
+	// players use target indexes 0x400-0x6FF, below that are the zone's NPCs and mobs
+	firstPlayerIndex uint16 = 0x0400
+	lastPlayerIndex  uint16 = 0x06FF
+)
+
+// indexAllocator hands out target indexes, reusing released ones lowest first.
+type indexAllocator struct {
+	next     uint16
+	last     uint16
+	released []uint16
+}
+
+func newIndexAllocator(first, last uint16) *indexAllocator {
+	return &indexAllocator{next: first, last: last}
+}
+
+// allocate returns a free index, or 0 if the range is exhausted.
+func (a *indexAllocator) allocate() uint16 {
+	if len(a.released) > 0 {
+		lowest := 0
+		for i, index := range a.released {
+			if index < a.released[lowest] {
+				lowest = i
+			}
+		}
+
+		index := a.released[lowest]
+		a.released = append(a.released[:lowest], a.released[lowest+1:]...)
+
+		return index
+	}
+
+	if a.next > a.last {
+		return 0
+	}
+
+	index := a.next
+	a.next++
+
+	return index
+}
+
+func (a *indexAllocator) release(index uint16) {
+	if index != 0 {
+		a.released = append(a.released, index)
+	}
+}
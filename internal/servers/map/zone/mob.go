@@ -0,0 +1,184 @@
+package zone
+
+import (
+	"sort"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+)
+
+// AIState is what a mob is currently doing.
+type AIState uint8
+
+const (
+	// AIIdle mobs stand still, watching for players to aggro
+	AIIdle AIState = iota
+
+	// AIRoaming mobs wander around their spawn point, watching for players to aggro
+	AIRoaming
+
+	// AIChasing mobs run after their target until it is in melee range
+	AIChasing
+
+	// AIAttacking mobs are in melee range of their target
+	AIAttacking
+
+	// AIDisengaging mobs lost their target and return to their spawn point
+	AIDisengaging
+
+	// AIDespawning mobs are dead and about to disappear
+	AIDespawning
+)
+
+func (s AIState) String() string {
+	switch s {
+	case AIIdle:
+		return "idle"
+	case AIRoaming:
+		return "roaming"
+	case AIChasing:
+		return "chasing"
+	case AIAttacking:
+		return "attacking"
+	case AIDisengaging:
+		return "disengaging"
+	case AIDespawning:
+		return "despawning"
+	default:
+		return "unknown"
+	}
+}
+
+// Engaged reports whether mobs in the state are fighting.
+func (s AIState) Engaged() bool {
+	return s == AIChasing || s == AIAttacking
+}
+
+// Mob is the in-memory state of a monster of the zone. Mobs stay in the zone for as
+// long as it runs; players only see them while they are spawned.
+type Mob struct {
+	// ID is the mob's entity ID; it encodes the zone and the target index
+	ID      uint32
+	Index   uint16
+	Name    string
+	GroupID uint32
+
+	// ModelID is the model the client draws the mob with
+	ModelID uint16
+
+	Level uint8
+	HP    int32
+	MaxHP int32
+	MP    int32
+	MaxMP int32
+
+	// Aggro mobs attack the players they detect (see the gamedata Detect flags);
+	// Links mobs join the fights of mobs of their group
+	Aggro   bool
+	Links   bool
+	Detects uint16
+
+	// Speed is the movement speed sent to clients; mobs move Speed/10 yalms per second
+	Speed uint8
+
+	// Home is where the mob spawns and returns to after a fight
+	Home     Position
+	Position Position
+
+	Spawned bool
+	State   AIState
+
+	// StateChangedAt is the zone time the mob entered its current state
+	StateChangedAt time.Time
+
+	// Destination is where a roaming mob is heading
+	Destination Position
+
+	// NextRoamAt is when an idle mob starts roaming again
+	NextRoamAt time.Time
+
+	// Target and TargetIndex identify the character the mob is fighting, or are 0
+	Target      uint32
+	TargetIndex uint16
+
+	// ClaimedBy is the character that claimed the mob, or 0
+	ClaimedBy uint32
+
+	Hate HateList
+
+	// Delay is the time between the mob's attack rounds, in 1/60ths of a second
+	Delay uint16
+
+	// NextAttackAt is when an engaged mob attacks next
+	NextAttackAt time.Time
+
+	// TP is the tactical points the mob built up in combat
+	TP int32
+
+	// Effects are the mob's active status effects
+	Effects effects.List
+
+	// Revision is bumped whenever something players see (other than the position) changes
+	Revision uint32
+}
+
+func (m *Mob) EntityID() uint32 {
+	return m.ID
+}
+
+func (m *Mob) EntityIndex() uint16 {
+	return m.Index
+}
+
+func (m *Mob) EntityPosition() Position {
+	return m.Position
+}
+
+func (m *Mob) EntityRevision() uint32 {
+	return m.Revision
+}
+
+// MarkChanged signals that players need to be sent the mob's new appearance or status.
+func (m *Mob) MarkChanged() {
+	m.Revision++
+}
+
+// SetState switches the mob to another AI state.
+func (m *Mob) SetState(state AIState, now time.Time) {
+	if state.Engaged() != m.State.Engaged() || state == AIDespawning {
+		m.MarkChanged()
+	}
+
+	m.State = state
+	m.StateChangedAt = now
+}
+
+// AddMob adds (or replaces) a mob in the zone. Mobs keep the target index encoded in their ID.
+func (z *Zone) AddMob(mob *Mob) {
+	z.mobs[mob.ID] = mob
+	z.mobsByIndex[mob.Index] = mob
+}
+
+// Mob returns a mob of the zone, or nil.
+func (z *Zone) Mob(mobID uint32) *Mob {
+	return z.mobs[mobID]
+}
+
+// MobByIndex returns the mob with a target index, or nil.
+func (z *Zone) MobByIndex(index uint16) *Mob {
+	return z.mobsByIndex[index]
+}
+
+// Mobs returns every mob of the zone, spawned or not, ordered by ID.
+func (z *Zone) Mobs() []*Mob {
+	mobs := make([]*Mob, 0, len(z.mobs))
+	for _, mob := range z.mobs {
+		mobs = append(mobs, mob)
+	}
+
+	sort.Slice(mobs, func(i, j int) bool {
+		return mobs[i].ID < mobs[j].ID
+	})
+
+	return mobs
+}
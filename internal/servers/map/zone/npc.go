@@ -0,0 +1,65 @@
+package zone
+
+import "sort"
+
+// NPC is a static entity of the zone, such as a guard, a shop keeper or a door. NPCs
+// stay spawned for as long as the zone runs; what they do when talked to is up to
+// their handler.
+type NPC struct {
+	// ID is the NPC's entity ID; it encodes the zone and the target index
+	ID    uint32
+	Index uint16
+	Name  string
+
+	// ModelID is the model the client draws the NPC with
+	ModelID uint16
+
+	Position Position
+
+	// Flags and Animation are sent as-is in the NPC's entity updates
+	Flags     uint32
+	Animation uint8
+
+	// Revision is bumped whenever something players see (other than the position) changes
+	Revision uint32
+}
+
+func (n *NPC) EntityID() uint32 {
+	return n.ID
+}
+
+func (n *NPC) EntityIndex() uint16 {
+	return n.Index
+}
+
+func (n *NPC) EntityPosition() Position {
+	return n.Position
+}
+
+func (n *NPC) EntityRevision() uint32 {
+	return n.Revision
+}
+
+// AddNPC adds (or replaces) an NPC in the zone. NPCs keep the target index encoded in their ID.
+func (z *Zone) AddNPC(npc *NPC) {
+	z.npcs[npc.ID] = npc
+}
+
+// NPC returns an NPC of the zone, or nil.
+func (z *Zone) NPC(npcID uint32) *NPC {
+	return z.npcs[npcID]
+}
+
+// NPCs returns every NPC of the zone, ordered by ID.
+func (z *Zone) NPCs() []*NPC {
+	npcs := make([]*NPC, 0, len(z.npcs))
+	for _, npc := range z.npcs {
+		npcs = append(npcs, npc)
+	}
+
+	sort.Slice(npcs, func(i, j int) bool {
+		return npcs[i].ID < npcs[j].ID
+	})
+
+	return npcs
+}
@@ -0,0 +1,52 @@
+package zone
+
+import (
+	"math"
+)
+
+// RotationTowards returns the rotation (0-255, a full turn) that faces target from p.
+func (p Position) RotationTowards(target Position) uint8 {
+	angle := math.Atan2(float64(target.Z-p.Z), float64(target.X-p.X))
+
+	// the client's rotation grows clockwise seen from above, starting from +X
+	return uint8(int(math.Round(-angle*128/math.Pi)) & 0xFF) //nolint:gosec // masked to a byte
+}
+
+// IsFacing reports whether target is within halfAngle (in rotation units, 64 being a
+// quarter turn) of the direction p faces.
+func (p Position) IsFacing(target Position, halfAngle uint8) bool {
+	diff := int(int8(p.RotationTowards(target) - p.Rotation)) //nolint:gosec // wraps around on purpose
+	if diff < 0 {
+		diff = -diff
+	}
+
+	return diff <= int(halfAngle)
+}
+
+// MoveTowards returns the position reached by walking up to distance yalms straight
+// towards target on the ground plane, facing it, and whether target was reached.
+// The height is left alone; there is no navigation mesh to follow.
+func (p Position) MoveTowards(target Position, distance float64) (Position, bool) {
+	remaining := p.HorizontalDistance(target)
+	if remaining <= distance {
+		moved := p
+		moved.X, moved.Z = target.X, target.Z
+		if remaining > 0 {
+			moved.Rotation = p.RotationTowards(target)
+		}
+
+		return moved, true
+	}
+
+	if distance <= 0 {
+		return p, false
+	}
+
+	ratio := distance / remaining
+	moved := p
+	moved.X += float32(float64(target.X-p.X) * ratio)
+	moved.Z += float32(float64(target.Z-p.Z) * ratio)
+	moved.Rotation = p.RotationTowards(target)
+
+	return moved, false
+}
@@ -0,0 +1,52 @@
+package zone
+
+import (
+	"testing"
+)
+
+func TestRotationTowards(t *testing.T) {
+	origin := Position{}
+	cases := []struct {
+		target Position
+		want   uint8
+	}{
+		{Position{X: 1}, 0},
+		{Position{Z: -1}, 64},
+		{Position{X: -1}, 128},
+		{Position{Z: 1}, 192},
+	}
+
+	for _, tc := range cases {
+		if got := origin.RotationTowards(tc.target); got != tc.want {
+			t.Fatalf("RotationTowards(%+v) = %d, want %d", tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestIsFacing(t *testing.T) {
+	facingEast := Position{Rotation: 0}
+	if !facingEast.IsFacing(Position{X: 10, Z: 5}, 32) {
+		t.Fatalf("IsFacing() = false for a target slightly off ahead")
+	}
+
+	if facingEast.IsFacing(Position{X: -10}, 32) || facingEast.IsFacing(Position{Z: 10}, 32) {
+		t.Fatalf("IsFacing() = true for a target behind or to the side")
+	}
+
+	// the cone wraps around rotation 0
+	if !(Position{Rotation: 250}).IsFacing(Position{X: 10}, 32) {
+		t.Fatalf("IsFacing() = false across the wrap-around")
+	}
+}
+
+func TestMoveTowards(t *testing.T) {
+	moved, arrived := Position{Y: 3}.MoveTowards(Position{X: 10, Y: 7}, 4)
+	if arrived || moved != (Position{X: 4, Y: 3}) {
+		t.Fatalf("MoveTowards() = %+v, %v, want 4 yalms along X at the same height", moved, arrived)
+	}
+
+	moved, arrived = moved.MoveTowards(Position{X: 6, Z: 0}, 4)
+	if !arrived || moved.X != 6 {
+		t.Fatalf("MoveTowards() = %+v, %v, want the target reached", moved, arrived)
+	}
+}
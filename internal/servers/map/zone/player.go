@@ -0,0 +1,245 @@
+package zone
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/GoFFXI/GoFFXI/internal/database"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/charstats"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/effects"
+	"github.com/GoFFXI/GoFFXI/internal/servers/map/inventory"
+)
+
+// Position is a point in a zone. Y is the vertical axis.
+type Position struct {
+	X float32
+	Y float32
+	Z float32
+
+	// Rotation is the facing direction (0-255, a full turn)
+	Rotation uint8
+}
+
+// HorizontalDistance returns the distance between two positions on the ground plane, in yalms.
+func (p Position) HorizontalDistance(other Position) float64 {
+	dx := float64(p.X - other.X)
+	dz := float64(p.Z - other.Z)
+
+	return math.Sqrt(dx*dx + dz*dz)
+}
+
+// IsFinite reports whether all coordinates are real numbers.
+func (p Position) IsFinite() bool {
+	for _, v := range []float32{p.X, p.Y, p.Z} {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Casting is a spell being cast.
+type Casting struct {
+	SpellID     uint16
+	TargetID    uint32
+	TargetIndex uint16
+
+	// From is where the caster stood when casting started; moving away interrupts the spell
+	From Position
+
+	// CompletesAt is the zone time the spell goes off at
+	CompletesAt time.Time
+}
+
+// PlayerEvent is an event (a dialog or cutscene) a player is in. Not to be confused
+// with the Event functions run by the zone loop.
+type PlayerEvent struct {
+	// ID is the event's ID in the zone's event table
+	ID uint16
+
+	// NPCID and NPCIndex are the entity the event is with: an NPC, or the player itself for cutscenes
+	NPCID    uint32
+	NPCIndex uint16
+
+	// Timeout ends the event if the client stops answering, or is 0
+	Timeout TimerID
+}
+
+// Player is the in-memory state of a character in the zone.
+type Player struct {
+	CharacterID uint32
+	ClientAddr  string
+	Name        string
+
+	// the records loaded when the character entered the zone
+	Character *database.Character
+	Looks     *database.CharacterLooks
+	Stats     *database.CharacterStats
+
+	// Inventory holds the character's item containers
+	Inventory *inventory.Inventory
+
+	// Derived are the stats computed from the character's race, jobs, equipment and effects
+	Derived charstats.Stats
+
+	// Effects are the character's active status effects
+	Effects effects.List
+
+	// EffectMods are the modifiers of the character's active status effects
+	EffectMods charstats.Modifiers
+
+	// EffectsDirty is set when the status effects changed since they were last persisted
+	EffectsDirty bool
+
+	// StatsDirty is set when the current HP or MP changed since they were last persisted
+	StatsDirty bool
+
+	// ActIndex is the player's target index in the zone, assigned by AddPlayer
+	ActIndex uint16
+
+	Position   Position
+	FaceTarget uint16
+
+	// Revision is bumped whenever something other players see (other than the position) changes
+	Revision uint32
+
+	// MovedAt is the zone time of the last accepted position update
+	MovedAt time.Time
+
+	// PositionDirty is set when the position changed since it was last persisted
+	PositionDirty bool
+
+	// LogoutTimer is the timer of a pending /logout or /shutdown, or 0
+	LogoutTimer TimerID
+
+	// BattleTarget is the ID of the mob the player is engaged with, or 0
+	BattleTarget uint32
+
+	// NextAttackAt is when an engaged player attacks next
+	NextAttackAt time.Time
+
+	// TP is the tactical points the player built up in combat
+	TP int32
+
+	// Casting is the spell the player is casting, or nil
+	Casting *Casting
+
+	// Recasts holds when the player's spells and abilities can be used again
+	Recasts Recasts
+
+	// Event is the event the player is in, or nil; players cannot move or act during events
+	Event *PlayerEvent
+
+	// TalkingTo is the NPC whose handler runs for the player, or 0; the events it starts are with that NPC
+	TalkingTo uint32
+
+	// KeyItems are the key items the character holds
+	KeyItems map[uint16]bool
+
+	// Vars are the variables scripts keep on the character; those not set are 0
+	Vars map[string]int32
+
+	// GMLevel is the GM level of the character's account (0 for regular players)
+	GMLevel uint8
+
+	// Hidden players are not seen by anyone else
+	Hidden bool
+
+	// InMogHouse is set while the player is in its mog house, away from everyone else in the zone
+	InMogHouse bool
+
+	// Disconnected is set once the client stopped responding; the character lingers until it is removed
+	Disconnected bool
+
+	// seen holds the entities this player's client currently has spawned
+	seen map[uint32]seenEntity
+}
+
+func (p *Player) EntityID() uint32 {
+	return p.CharacterID
+}
+
+func (p *Player) EntityIndex() uint16 {
+	return p.ActIndex
+}
+
+func (p *Player) EntityPosition() Position {
+	return p.Position
+}
+
+func (p *Player) EntityRevision() uint32 {
+	return p.Revision
+}
+
+// MarkChanged signals that other players need to be sent the player's new appearance or status.
+func (p *Player) MarkChanged() {
+	p.Revision++
+}
+
+// MoveTo applies a position update.
+func (p *Player) MoveTo(position Position, now time.Time) {
+	if position != p.Position {
+		p.PositionDirty = true
+	}
+
+	p.Position = position
+	p.MovedAt = now
+}
+
+// AddPlayer adds (or replaces) a player in the zone and assigns its target index.
+func (z *Zone) AddPlayer(player *Player) {
+	if existing, ok := z.players[player.CharacterID]; ok {
+		player.ActIndex = existing.ActIndex
+	} else {
+		player.ActIndex = z.indexes.allocate()
+	}
+
+	player.seen = make(map[uint32]seenEntity)
+	z.players[player.CharacterID] = player
+}
+
+// RemovePlayer removes a player from the zone, returning it if it was present.
+func (z *Zone) RemovePlayer(characterID uint32) *Player {
+	player, ok := z.players[characterID]
+	if !ok {
+		return nil
+	}
+
+	delete(z.players, characterID)
+	z.indexes.release(player.ActIndex)
+
+	return player
+}
+
+// Player returns a player in the zone, or nil.
+func (z *Zone) Player(characterID uint32) *Player {
+	return z.players[characterID]
+}
+
+// Players returns every player in the zone, ordered by character ID.
+func (z *Zone) Players() []*Player {
+	players := make([]*Player, 0, len(z.players))
+	for _, player := range z.players {
+		players = append(players, player)
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].CharacterID < players[j].CharacterID
+	})
+
+	return players
+}
+
+// PlayersNear returns the players within distance yalms of a position, ordered by character ID.
+func (z *Zone) PlayersNear(position Position, distance float64) []*Player {
+	players := make([]*Player, 0)
+	for _, player := range z.Players() {
+		if player.Position.HorizontalDistance(position) <= distance {
+			players = append(players, player)
+		}
+	}
+
+	return players
+}
@@ -0,0 +1,67 @@
+package zone
+
+import (
+	"time"
+)
+
+// RecastKind tells spell timers from ability timers, which are numbered separately.
+type RecastKind uint8
+
+const (
+	// RecastSpell timers are keyed by spell ID
+	RecastSpell RecastKind = iota
+
+	// RecastAbility timers are keyed by recast ID, which abilities may share
+	RecastAbility
+)
+
+type recastKey struct {
+	kind RecastKind
+	id   uint16
+}
+
+// Recasts holds when the spells and abilities a character used can be used again.
+// The zero value has no timer running.
+type Recasts struct {
+	readyAt map[recastKey]time.Time
+}
+
+// Start starts the timer of a spell or ability, which is ready again after duration.
+func (r *Recasts) Start(kind RecastKind, id uint16, duration time.Duration, now time.Time) {
+	if duration <= 0 {
+		return
+	}
+
+	if r.readyAt == nil {
+		r.readyAt = make(map[recastKey]time.Time)
+	}
+
+	r.readyAt[recastKey{kind, id}] = now.Add(duration)
+}
+
+// Remaining returns how long until a spell or ability is ready again, or 0 when it is.
+func (r *Recasts) Remaining(kind RecastKind, id uint16, now time.Time) time.Duration {
+	key := recastKey{kind, id}
+
+	readyAt, ok := r.readyAt[key]
+	if !ok {
+		return 0
+	}
+
+	if !now.Before(readyAt) {
+		delete(r.readyAt, key)
+		return 0
+	}
+
+	return readyAt.Sub(now)
+}
+
+// Ready reports whether a spell or ability can be used again.
+func (r *Recasts) Ready(kind RecastKind, id uint16, now time.Time) bool {
+	return r.Remaining(kind, id, now) == 0
+}
+
+// Clear stops every timer.
+func (r *Recasts) Clear() {
+	r.readyAt = nil
+}
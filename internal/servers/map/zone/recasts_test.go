@@ -0,0 +1,32 @@
+package zone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecasts(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	var recasts Recasts
+	if !recasts.Ready(RecastSpell, 1, now) {
+		t.Fatalf("Ready() = false without any timer")
+	}
+
+	recasts.Start(RecastSpell, 1, 5*time.Second, now)
+	recasts.Start(RecastAbility, 1, time.Minute, now)
+
+	if got := recasts.Remaining(RecastSpell, 1, now.Add(2*time.Second)); got != 3*time.Second {
+		t.Fatalf("Remaining() = %v, want 3s", got)
+	}
+
+	// the spell and the ability of the same ID have timers of their own
+	if !recasts.Ready(RecastSpell, 1, now.Add(5*time.Second)) || recasts.Ready(RecastAbility, 1, now.Add(5*time.Second)) {
+		t.Fatalf("Ready() after 5s, want the spell ready and the ability not")
+	}
+
+	recasts.Clear()
+	if !recasts.Ready(RecastAbility, 1, now) {
+		t.Fatalf("Ready() = false after Clear()")
+	}
+}
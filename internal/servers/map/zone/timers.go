@@ -0,0 +1,112 @@
+package zone
+
+import (
+	"container/heap"
+	"time"
+)
+
+// TimerID identifies a scheduled timer so it can be cancelled.
+type TimerID uint64
+
+type timer struct {
+	id       TimerID
+	at       time.Time
+	interval time.Duration
+	fn       func(z *Zone)
+	index    int
+}
+
+// timerQueue is a min-heap of timers ordered by due time, then by the order they were scheduled in.
+type timerQueue []*timer
+
+func (q timerQueue) Len() int {
+	return len(q)
+}
+
+func (q timerQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].id < q[j].id
+	}
+
+	return q[i].at.Before(q[j].at)
+}
+
+func (q timerQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *timerQueue) Push(x any) {
+	t := x.(*timer) //nolint:forcetypeassert // only timers are pushed
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+
+func (q *timerQueue) Pop() any {
+	old := *q
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*q = old[:n-1]
+
+	return t
+}
+
+// After runs fn on the zone goroutine once d has passed.
+func (z *Zone) After(d time.Duration, fn func(z *Zone)) TimerID {
+	return z.schedule(d, 0, fn)
+}
+
+// Every runs fn on the zone goroutine every interval, starting one interval from now.
+func (z *Zone) Every(interval time.Duration, fn func(z *Zone)) TimerID {
+	return z.schedule(interval, max(interval, z.tickInterval), fn)
+}
+
+// Cancel stops a timer. Cancelling a timer that already fired is a no-op.
+func (z *Zone) Cancel(id TimerID) {
+	t, ok := z.timersByID[id]
+	if !ok {
+		return
+	}
+
+	heap.Remove(&z.timers, t.index)
+	delete(z.timersByID, id)
+}
+
+func (z *Zone) schedule(d, interval time.Duration, fn func(z *Zone)) TimerID {
+	z.nextTimerID++
+	t := &timer{
+		id:       z.nextTimerID,
+		at:       z.clock.Now().Add(d),
+		interval: interval,
+		fn:       fn,
+	}
+
+	heap.Push(&z.timers, t)
+	z.timersByID[t.id] = t
+
+	return t.id
+}
+
+// runTimers fires every timer that is due, rescheduling repeating ones.
+func (z *Zone) runTimers(now time.Time) {
+	for z.timers.Len() > 0 && !z.timers[0].at.After(now) {
+		t := z.timers[0]
+		if t.interval > 0 {
+			// a repeating timer fires at most once per tick, even if the zone fell behind
+			t.at = t.at.Add(t.interval)
+			if !t.at.After(now) {
+				t.at = now.Add(t.interval)
+			}
+
+			heap.Fix(&z.timers, 0)
+		} else {
+			heap.Pop(&z.timers)
+			delete(z.timersByID, t.id)
+		}
+
+		z.safely("timer", func() { t.fn(z) })
+	}
+}
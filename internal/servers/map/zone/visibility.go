@@ -0,0 +1,201 @@
+package zone
+
+import (
+	"math"
+	"sort"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+)
+
+const (
+	DefaultViewDistance     = 50.0
+	DefaultVisibilityBudget = 10
+)
+
+const (
+	// VisibilitySpawn is sent the first time a player sees an entity
+	VisibilitySpawn = serverPackets.CharUpdateFlagPosition | serverPackets.CharUpdateFlagClaimStatus |
+		serverPackets.CharUpdateFlagGeneral | serverPackets.CharUpdateFlagName | serverPackets.CharUpdateFlagModel
+
+	// VisibilityMove is sent when a visible entity moved
+	VisibilityMove = serverPackets.CharUpdateFlagPosition
+
+	// VisibilityChange is sent when a visible entity's appearance or status changed
+	VisibilityChange = serverPackets.CharUpdateFlagGeneral | serverPackets.CharUpdateFlagModel
+
+	// VisibilityDespawn is sent when an entity leaves a player's view
+	VisibilityDespawn = serverPackets.CharUpdateFlagDespawn
+)
+
+// Entity is anything a player can see in the zone.
+type Entity interface {
+	EntityID() uint32
+	EntityIndex() uint16
+	EntityPosition() Position
+
+	// EntityRevision changes whenever the entity's appearance or status changes
+	EntityRevision() uint32
+}
+
+// VisibilityBuilder builds the packet telling viewer about entity, using the given update flags.
+// entity is nil (and only its ID and index are known) when it is being despawned.
+type VisibilityBuilder func(viewer *Player, entityID uint32, entityIndex uint16, entity Entity, flags serverPackets.CharUpdateSendFlags) serverPackets.ServerPacket
+
+// seenEntity is what a player's client was last told about an entity.
+type seenEntity struct {
+	index    uint16
+	position Position
+	revision uint32
+}
+
+type cell struct {
+	x, z int32
+}
+
+type visibilityUpdate struct {
+	entityID uint32
+	index    uint16
+	entity   Entity
+	flags    serverPackets.CharUpdateSendFlags
+	distance float64
+}
+
+// entities returns everything that can be seen in the zone.
+func (z *Zone) entities() []Entity {
+	entities := make([]Entity, 0, len(z.players)+len(z.mobs)+len(z.npcs))
+	for _, player := range z.Players() {
+		if !player.Hidden && !player.InMogHouse {
+			entities = append(entities, player)
+		}
+	}
+
+	for _, mob := range z.Mobs() {
+		if mob.Spawned {
+			entities = append(entities, mob)
+		}
+	}
+
+	for _, npc := range z.NPCs() {
+		entities = append(entities, npc)
+	}
+
+	return entities
+}
+
+func (z *Zone) cellOf(position Position) cell {
+	return cell{
+		x: int32(math.Floor(float64(position.X) / z.viewDistance)),
+		z: int32(math.Floor(float64(position.Z) / z.viewDistance)),
+	}
+}
+
+// updateVisibility works out which entities every player can see and sends the
+// spawns, updates and despawns needed to bring their clients up to date. Each
+// player gets at most visibilityBudget packets per tick; despawns go first, then
+// the nearest entities. Whatever does not fit is picked up on the next tick.
+func (z *Zone) updateVisibility() {
+	// bucket the entities into cells as large as the view distance, so only the
+	// 3x3 cells around a player need to be checked
+	grid := make(map[cell][]Entity)
+	for _, entity := range z.entities() {
+		c := z.cellOf(entity.EntityPosition())
+		grid[c] = append(grid[c], entity)
+	}
+
+	for _, viewer := range z.Players() {
+		// the mog house is a room of its own; nothing of the zone is seen from it
+		if viewer.InMogHouse {
+			continue
+		}
+
+		z.updatePlayerVisibility(viewer, grid)
+	}
+}
+
+func (z *Zone) updatePlayerVisibility(viewer *Player, grid map[cell][]Entity) {
+	origin := z.cellOf(viewer.Position)
+	inRange := make(map[uint32]bool)
+
+	var despawns, spawns, updates []visibilityUpdate
+	for dx := int32(-1); dx <= 1; dx++ {
+		for dz := int32(-1); dz <= 1; dz++ {
+			for _, entity := range grid[cell{x: origin.x + dx, z: origin.z + dz}] {
+				if entity.EntityID() == viewer.CharacterID {
+					continue
+				}
+
+				distance := viewer.Position.HorizontalDistance(entity.EntityPosition())
+				if distance > z.viewDistance {
+					continue
+				}
+
+				inRange[entity.EntityID()] = true
+				update := visibilityUpdate{entityID: entity.EntityID(), index: entity.EntityIndex(), entity: entity, distance: distance}
+
+				seen, ok := viewer.seen[entity.EntityID()]
+				switch {
+				case !ok:
+					update.flags = VisibilitySpawn
+					spawns = append(spawns, update)
+				case seen.revision != entity.EntityRevision():
+					update.flags = VisibilityChange | VisibilityMove
+					updates = append(updates, update)
+				case seen.position != entity.EntityPosition():
+					update.flags = VisibilityMove
+					updates = append(updates, update)
+				}
+			}
+		}
+	}
+
+	for entityID, seen := range viewer.seen {
+		if !inRange[entityID] {
+			despawns = append(despawns, visibilityUpdate{entityID: entityID, index: seen.index, flags: VisibilityDespawn})
+		}
+	}
+
+	sort.Slice(despawns, func(i, j int) bool { return despawns[i].entityID < despawns[j].entityID })
+	byDistance := func(updates []visibilityUpdate) {
+		sort.Slice(updates, func(i, j int) bool {
+			if updates[i].distance == updates[j].distance {
+				return updates[i].entityID < updates[j].entityID
+			}
+
+			return updates[i].distance < updates[j].distance
+		})
+	}
+	byDistance(spawns)
+	byDistance(updates)
+
+	budget := z.visibilityBudget
+	for _, batch := range [][]visibilityUpdate{despawns, spawns, updates} {
+		for _, update := range batch {
+			if budget == 0 {
+				return
+			}
+
+			packet := z.visibility(viewer, update.entityID, update.index, update.entity, update.flags)
+			if packet != nil {
+				z.Send(viewer.ClientAddr, packet)
+			}
+
+			if update.flags == VisibilityDespawn {
+				delete(viewer.seen, update.entityID)
+			} else {
+				viewer.seen[update.entityID] = seenEntity{
+					index:    update.index,
+					position: update.entity.EntityPosition(),
+					revision: update.entity.EntityRevision(),
+				}
+			}
+
+			budget--
+		}
+	}
+}
+
+// Sees reports whether the viewer's client currently has the entity spawned.
+func (p *Player) Sees(entityID uint32) bool {
+	_, ok := p.seen[entityID]
+	return ok
+}
@@ -0,0 +1,186 @@
+package zone
+
+import (
+	"testing"
+	"time"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+)
+
+type sentUpdate struct {
+	viewer   uint32
+	entityID uint32
+	flags    serverPackets.CharUpdateSendFlags
+}
+
+func newVisibilityZone(budget int, sent *[]sentUpdate) *Zone {
+	return New(100, Options{
+		TickInterval:     100 * time.Millisecond,
+		Clock:            NewVirtualClock(time.Unix(0, 0)),
+		ViewDistance:     50,
+		VisibilityBudget: budget,
+		Visibility: func(viewer *Player, entityID uint32, _ uint16, _ Entity, flags serverPackets.CharUpdateSendFlags) serverPackets.ServerPacket {
+			*sent = append(*sent, sentUpdate{viewer: viewer.CharacterID, entityID: entityID, flags: flags})
+			return nil
+		},
+	})
+}
+
+func TestVisibilitySpawnMoveDespawn(t *testing.T) {
+	var sent []sentUpdate
+	z := newVisibilityZone(10, &sent)
+
+	a := &Player{CharacterID: 1}
+	b := &Player{CharacterID: 2, Position: Position{X: 30}}
+	z.AddPlayer(a)
+	z.AddPlayer(b)
+
+	if a.ActIndex != firstPlayerIndex || b.ActIndex != firstPlayerIndex+1 {
+		t.Fatalf("ActIndex = %#x, %#x, want %#x, %#x", a.ActIndex, b.ActIndex, firstPlayerIndex, firstPlayerIndex+1)
+	}
+
+	z.Step()
+	if len(sent) != 2 || sent[0] != (sentUpdate{1, 2, VisibilitySpawn}) || sent[1] != (sentUpdate{2, 1, VisibilitySpawn}) {
+		t.Fatalf("spawns = %v", sent)
+	}
+
+	// nothing changed, nothing to send
+	sent = nil
+	z.Step()
+	if len(sent) != 0 {
+		t.Fatalf("updates without changes = %v", sent)
+	}
+
+	b.MoveTo(Position{X: 40}, z.Now())
+	z.Step()
+	if len(sent) != 1 || sent[0] != (sentUpdate{1, 2, VisibilityMove}) {
+		t.Fatalf("move = %v", sent)
+	}
+
+	// out of range of each other, across a cell boundary
+	sent = nil
+	b.MoveTo(Position{X: 120}, z.Now())
+	z.Step()
+	if len(sent) != 2 || sent[0].flags != VisibilityDespawn || sent[1].flags != VisibilityDespawn {
+		t.Fatalf("despawns = %v", sent)
+	}
+
+	if a.Sees(2) || b.Sees(1) {
+		t.Fatal("players still see each other after despawning")
+	}
+}
+
+func TestVisibilityDespawnsRemovedPlayers(t *testing.T) {
+	var sent []sentUpdate
+	z := newVisibilityZone(10, &sent)
+
+	z.AddPlayer(&Player{CharacterID: 1})
+	z.AddPlayer(&Player{CharacterID: 2})
+	z.Step()
+
+	sent = nil
+	z.RemovePlayer(2)
+	z.Step()
+	if len(sent) != 1 || sent[0] != (sentUpdate{1, 2, VisibilityDespawn}) {
+		t.Fatalf("despawns = %v", sent)
+	}
+
+	// the index is reused by the next player
+	c := &Player{CharacterID: 3}
+	z.AddPlayer(c)
+	if c.ActIndex != firstPlayerIndex+1 {
+		t.Fatalf("ActIndex = %#x, want %#x", c.ActIndex, firstPlayerIndex+1)
+	}
+}
+
+func TestVisibilityBudget(t *testing.T) {
+	var sent []sentUpdate
+	z := newVisibilityZone(2, &sent)
+
+	viewer := &Player{CharacterID: 1}
+	z.AddPlayer(viewer)
+	for id := uint32(2); id <= 6; id++ {
+		z.AddPlayer(&Player{CharacterID: id, Position: Position{X: float32(id)}})
+	}
+
+	z.Step()
+
+	spawnedForViewer := 0
+	for _, update := range sent {
+		if update.viewer == viewer.CharacterID {
+			spawnedForViewer++
+		}
+	}
+
+	if spawnedForViewer != 2 || !viewer.Sees(2) || !viewer.Sees(3) || viewer.Sees(4) {
+		t.Fatalf("spawned %d entities for the viewer, want the 2 nearest", spawnedForViewer)
+	}
+
+	// the rest follow on later ticks
+	z.Step()
+	z.Step()
+	for id := uint32(2); id <= 6; id++ {
+		if !viewer.Sees(id) {
+			t.Fatalf("viewer does not see entity %d after three ticks", id)
+		}
+	}
+}
+
+func TestVisibilityMobs(t *testing.T) {
+	var sent []sentUpdate
+	z := newVisibilityZone(10, &sent)
+
+	z.AddPlayer(&Player{CharacterID: 1})
+	mob := &Mob{ID: 0x010E6001, Index: 1, Position: Position{X: 10}}
+	z.AddMob(mob)
+
+	// mobs that are not spawned are not seen
+	z.Step()
+	if len(sent) != 0 {
+		t.Fatalf("updates for an unspawned mob = %v", sent)
+	}
+
+	mob.Spawned = true
+	z.Step()
+	if len(sent) != 1 || sent[0] != (sentUpdate{1, mob.ID, VisibilitySpawn}) {
+		t.Fatalf("spawn = %v", sent)
+	}
+
+	if z.MobByIndex(1) != mob || z.Mob(mob.ID) != mob {
+		t.Fatalf("mob lookups did not find the mob")
+	}
+
+	sent = nil
+	mob.Spawned = false
+	z.Step()
+	if len(sent) != 1 || sent[0] != (sentUpdate{1, mob.ID, VisibilityDespawn}) {
+		t.Fatalf("despawn = %v", sent)
+	}
+}
+
+func TestVisibilityNPCs(t *testing.T) {
+	var sent []sentUpdate
+	z := newVisibilityZone(10, &sent)
+
+	player := &Player{CharacterID: 1}
+	z.AddPlayer(player)
+	npc := &NPC{ID: 0x01064001, Index: 1, Position: Position{X: 10}}
+	z.AddNPC(npc)
+
+	z.Step()
+	if len(sent) != 1 || sent[0] != (sentUpdate{1, npc.ID, VisibilitySpawn}) {
+		t.Fatalf("spawn = %v", sent)
+	}
+
+	if z.NPC(npc.ID) != npc {
+		t.Fatalf("NPC lookup did not find the NPC")
+	}
+
+	// NPCs out of view are despawned, like any other entity
+	sent = nil
+	player.Position = Position{X: 100}
+	z.Step()
+	if len(sent) != 1 || sent[0] != (sentUpdate{1, npc.ID, VisibilityDespawn}) {
+		t.Fatalf("despawn = %v", sent)
+	}
+}
@@ -0,0 +1,307 @@
+// Package zone implements the authoritative game loop of a single zone.
+//
+// Each zone owns its state and is only ever touched from its own goroutine.
+// Everything else talks to it by posting events, which are applied at the start
+// of the next tick, followed by due timers, the registered tick systems and
+// finally the packets queued for clients during the tick.
+package zone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+)
+
+const (
+	DefaultTickInterval   = 400 * time.Millisecond
+	DefaultEventQueueSize = 1024
+)
+
+var ErrEventQueueFull = errors.New("zone event queue is full")
+
+// Event is a unit of work applied to the zone on its own goroutine.
+type Event func(z *Zone)
+
+// System is called once per tick, after events and timers have been processed.
+type System func(z *Zone, now time.Time)
+
+// Sender delivers the packets queued for a client during a tick.
+type Sender func(clientAddr string, packet serverPackets.ServerPacket) error
+
+// Options configure a zone. Zero values fall back to the defaults.
+type Options struct {
+	TickInterval   time.Duration
+	EventQueueSize int
+	Clock          Clock
+	Sender         Sender
+	Logger         *slog.Logger
+
+	// Rand is the zone's source of randomness (mob levels, roaming, ...); tests pass a seeded one
+	Rand *rand.Rand
+
+	// ViewDistance is how far, in yalms, players see other entities
+	ViewDistance float64
+
+	// VisibilityBudget is the number of spawn/update/despawn packets sent to a player per tick
+	VisibilityBudget int
+
+	// Visibility builds the packets sent when entities enter, move in or leave a player's view.
+	// Without it, visibility is not tracked.
+	Visibility VisibilityBuilder
+}
+
+type outgoingPacket struct {
+	clientAddr string
+	packet     serverPackets.ServerPacket
+}
+
+// Zone is the simulation of a single zone.
+type Zone struct {
+	id           uint16
+	tickInterval time.Duration
+	clock        Clock
+	sender       Sender
+	logger       *slog.Logger
+	rand         *rand.Rand
+
+	events    chan Event
+	systems   []System
+	stopHooks []Event
+	outbox    []outgoingPacket
+	tick      uint64
+
+	players map[uint32]*Player
+	indexes *indexAllocator
+
+	mobs        map[uint32]*Mob
+	mobsByIndex map[uint16]*Mob
+
+	npcs map[uint32]*NPC
+
+	viewDistance     float64
+	visibilityBudget int
+	visibility       VisibilityBuilder
+
+	timers      timerQueue
+	timersByID  map[TimerID]*timer
+	nextTimerID TimerID
+}
+
+func New(id uint16, options Options) *Zone {
+	if options.TickInterval <= 0 {
+		options.TickInterval = DefaultTickInterval
+	}
+
+	if options.EventQueueSize <= 0 {
+		options.EventQueueSize = DefaultEventQueueSize
+	}
+
+	if options.Clock == nil {
+		options.Clock = RealClock{}
+	}
+
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+
+	if options.Rand == nil {
+		//nolint:gosec // game randomness, not security sensitive
+		options.Rand = rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(id)))
+	}
+
+	if options.ViewDistance <= 0 {
+		options.ViewDistance = DefaultViewDistance
+	}
+
+	if options.VisibilityBudget <= 0 {
+		options.VisibilityBudget = DefaultVisibilityBudget
+	}
+
+	return &Zone{
+		id:           id,
+		tickInterval: options.TickInterval,
+		clock:        options.Clock,
+		sender:       options.Sender,
+		logger:       options.Logger.With("zoneID", id),
+		rand:         options.Rand,
+		events:       make(chan Event, options.EventQueueSize),
+		timersByID:   make(map[TimerID]*timer),
+		players:      make(map[uint32]*Player),
+		indexes:      newIndexAllocator(firstPlayerIndex, lastPlayerIndex),
+		mobs:         make(map[uint32]*Mob),
+		mobsByIndex:  make(map[uint16]*Mob),
+		npcs:         make(map[uint32]*NPC),
+
+		viewDistance:     options.ViewDistance,
+		visibilityBudget: options.VisibilityBudget,
+		visibility:       options.Visibility,
+	}
+}
+
+// ID returns the zone ID.
+func (z *Zone) ID() uint16 {
+	return z.id
+}
+
+// Now returns the zone's current time.
+func (z *Zone) Now() time.Time {
+	return z.clock.Now()
+}
+
+// Tick returns the number of ticks the zone has run.
+func (z *Zone) Tick() uint64 {
+	return z.tick
+}
+
+// TickInterval returns the time between two ticks.
+func (z *Zone) TickInterval() time.Duration {
+	return z.tickInterval
+}
+
+// Rand returns the zone's source of randomness. Like the rest of the zone, it may only
+// be used from the zone goroutine.
+func (z *Zone) Rand() *rand.Rand {
+	return z.rand
+}
+
+// Logger returns the zone's logger.
+func (z *Zone) Logger() *slog.Logger {
+	return z.logger
+}
+
+// Post queues an event for the next tick. It is safe to call from any goroutine
+// and never blocks; ErrEventQueueFull is returned if the zone is falling behind.
+func (z *Zone) Post(event Event) error {
+	select {
+	case z.events <- event:
+		return nil
+	default:
+		return fmt.Errorf("%w: zone %d", ErrEventQueueFull, z.id)
+	}
+}
+
+// AddSystem registers a function that runs every tick. Systems run in the order they were added.
+// Must be called before the zone starts running, or from the zone goroutine.
+func (z *Zone) AddSystem(system System) {
+	z.systems = append(z.systems, system)
+}
+
+// OnStop registers a function that runs on the zone goroutine when the zone stops,
+// after the final tick (e.g. to persist the players still in the zone).
+func (z *Zone) OnStop(hook Event) {
+	z.stopHooks = append(z.stopHooks, hook)
+}
+
+// Send queues a packet for a client; queued packets are flushed at the end of the tick.
+func (z *Zone) Send(clientAddr string, packet serverPackets.ServerPacket) {
+	z.outbox = append(z.outbox, outgoingPacket{clientAddr: clientAddr, packet: packet})
+}
+
+// Run ticks the zone at its tick interval until the context is cancelled.
+func (z *Zone) Run(ctx context.Context) {
+	z.logger.Info("starting zone loop", "tickInterval", z.tickInterval)
+
+	ticker := time.NewTicker(z.tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// apply whatever is still queued before stopping
+			z.Step()
+
+			for _, hook := range z.stopHooks {
+				z.safely("stop hook", func() { hook(z) })
+			}
+
+			z.flush()
+			z.logger.Info("stopped zone loop", "ticks", z.tick)
+			return
+		case <-ticker.C:
+			start := time.Now()
+			z.Step()
+
+			if elapsed := time.Since(start); elapsed > z.tickInterval {
+				z.logger.Warn("zone tick overran its interval", "tick", z.tick, "elapsed", elapsed)
+			}
+		}
+	}
+}
+
+// Advance moves a virtual clock forward by d one tick at a time, running a tick
+// after each step. It is meant for tests and panics if the zone uses another clock.
+func (z *Zone) Advance(d time.Duration) {
+	clock, ok := z.clock.(*VirtualClock)
+	if !ok {
+		panic("zone: Advance requires a VirtualClock")
+	}
+
+	for ; d >= z.tickInterval; d -= z.tickInterval {
+		clock.Advance(z.tickInterval)
+		z.Step()
+	}
+
+	if d > 0 {
+		clock.Advance(d)
+	}
+}
+
+// Step runs a single tick: queued events, due timers, systems, visibility, then the outbox flush.
+func (z *Zone) Step() {
+	z.tick++
+	now := z.clock.Now()
+
+	// only apply the events queued before the tick started, so events posting
+	// further events cannot keep the zone in the same tick forever
+	for pending := len(z.events); pending > 0; pending-- {
+		event := <-z.events
+		z.safely("event", func() { event(z) })
+	}
+
+	z.runTimers(now)
+
+	for _, system := range z.systems {
+		z.safely("system", func() { system(z, now) })
+	}
+
+	if z.visibility != nil {
+		z.safely("visibility", z.updateVisibility)
+	}
+
+	z.flush()
+}
+
+func (z *Zone) flush() {
+	if len(z.outbox) == 0 {
+		return
+	}
+
+	outbox := z.outbox
+	z.outbox = nil
+
+	if z.sender == nil {
+		return
+	}
+
+	for _, outgoing := range outbox {
+		if err := z.sender(outgoing.clientAddr, outgoing.packet); err != nil {
+			z.logger.Warn("failed to send packet", "clientAddr", outgoing.clientAddr, "packetType", outgoing.packet.Type(), "error", err)
+		}
+	}
+}
+
+// safely runs fn, logging instead of crashing the zone if it panics.
+func (z *Zone) safely(kind string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			z.logger.Error("recovered from panic in zone "+kind, "tick", z.tick, "panic", r)
+		}
+	}()
+
+	fn()
+}
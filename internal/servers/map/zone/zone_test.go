@@ -0,0 +1,111 @@
+package zone
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	serverPackets "github.com/GoFFXI/GoFFXI/internal/packets/map/server"
+)
+
+func newTestZone(sender Sender) *Zone {
+	return New(100, Options{
+		TickInterval:   100 * time.Millisecond,
+		EventQueueSize: 4,
+		Clock:          NewVirtualClock(time.Unix(0, 0)),
+		Sender:         sender,
+	})
+}
+
+func TestZoneRunsEventsTimersAndSystemsInOrder(t *testing.T) {
+	z := newTestZone(nil)
+
+	var order []string
+	z.AddSystem(func(_ *Zone, _ time.Time) {
+		order = append(order, "system")
+	})
+
+	z.After(150*time.Millisecond, func(_ *Zone) {
+		order = append(order, "timer")
+	})
+
+	if err := z.Post(func(_ *Zone) { order = append(order, "event") }); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+
+	z.Advance(200 * time.Millisecond)
+
+	want := []string{"event", "system", "timer", "system"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+
+	if z.Tick() != 2 {
+		t.Fatalf("Tick() = %d, want %d", z.Tick(), 2)
+	}
+}
+
+func TestZoneRepeatingTimer(t *testing.T) {
+	z := newTestZone(nil)
+
+	fired := 0
+	id := z.Every(200*time.Millisecond, func(_ *Zone) {
+		fired++
+	})
+
+	z.Advance(time.Second)
+	if fired != 5 {
+		t.Fatalf("fired = %d, want %d", fired, 5)
+	}
+
+	z.Cancel(id)
+	z.Advance(time.Second)
+	if fired != 5 {
+		t.Fatalf("fired after Cancel() = %d, want %d", fired, 5)
+	}
+}
+
+func TestZonePostWhenFull(t *testing.T) {
+	z := newTestZone(nil)
+	for range 4 {
+		if err := z.Post(func(_ *Zone) {}); err != nil {
+			t.Fatalf("Post() error = %v", err)
+		}
+	}
+
+	if err := z.Post(func(_ *Zone) {}); !errors.Is(err, ErrEventQueueFull) {
+		t.Fatalf("Post() error = %v, want %v", err, ErrEventQueueFull)
+	}
+}
+
+func TestZoneFlushesOutboxOncePerTick(t *testing.T) {
+	var sent []string
+	z := newTestZone(func(clientAddr string, _ serverPackets.ServerPacket) error {
+		sent = append(sent, clientAddr)
+		return nil
+	})
+
+	z.AddSystem(func(z *Zone, _ time.Time) {
+		z.Send("a", &serverPackets.EnterZonePacket{})
+		z.Send("b", &serverPackets.EnterZonePacket{})
+	})
+
+	z.Step()
+	if !slices.Equal(sent, []string{"a", "b"}) {
+		t.Fatalf("sent = %v, want %v", sent, []string{"a", "b"})
+	}
+}
+
+func TestZoneRecoversFromPanics(t *testing.T) {
+	z := newTestZone(nil)
+
+	ran := false
+	_ = z.Post(func(_ *Zone) { panic("boom") })
+	_ = z.Post(func(_ *Zone) { ran = true })
+	z.Step()
+
+	if !ran {
+		t.Fatal("Step() stopped processing events after a panic")
+	}
+}
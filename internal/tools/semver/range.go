@@ -0,0 +1,231 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type operator string
+
+const (
+	opEqual          operator = "="
+	opGreater        operator = ">"
+	opGreaterOrEqual operator = ">="
+	opLess           operator = "<"
+	opLessOrEqual    operator = "<="
+	opCaret          operator = "^"
+	opTilde          operator = "~"
+)
+
+// operators are ordered so that the two character operators are matched first
+//
+//nolint:gochecknoglobals // static lookup table
+var operators = []operator{opGreaterOrEqual, opLessOrEqual, opGreater, opLess, opEqual, opCaret, opTilde}
+
+type comparator struct {
+	op      operator
+	version Version
+}
+
+func (c comparator) matches(v Version) bool {
+	cmp := v.Compare(c.version)
+
+	switch c.op {
+	case opGreater:
+		return cmp > 0
+	case opGreaterOrEqual:
+		return cmp >= 0
+	case opLess:
+		return cmp < 0
+	case opLessOrEqual:
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// Range is a set of version constraints.
+//
+// Comparators separated by whitespace must all match, and alternatives are
+// separated by "||". The supported comparators are:
+//
+//	1.2.3, =1.2.3   exactly 1.2.3
+//	1.2, 1.2.x      >=1.2.0 <1.3.0
+//	>1.2.3, >=1.2.3, <1.2.3, <=1.2.3
+//	~1.2.3          >=1.2.3 <1.3.0
+//	^1.2.3          >=1.2.3 <2.0.0 (^0.2.3 is >=0.2.3 <0.3.0)
+//	*               any version
+type Range struct {
+	raw  string
+	sets [][]comparator
+}
+
+// ParseRange parses a range expression.
+func ParseRange(s string) (Range, error) {
+	raw := strings.TrimSpace(s)
+	if raw == "" {
+		return Range{}, fmt.Errorf("%w: empty range", ErrInvalidRange)
+	}
+
+	r := Range{raw: raw}
+	for _, alternative := range strings.Split(raw, "||") {
+		set, err := parseComparatorSet(alternative)
+		if err != nil {
+			return Range{}, fmt.Errorf("%w %q: %w", ErrInvalidRange, raw, err)
+		}
+
+		r.sets = append(r.sets, set)
+	}
+
+	return r, nil
+}
+
+// MustParseRange is like ParseRange but panics if the range is invalid.
+func MustParseRange(s string) Range {
+	r, err := ParseRange(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Contains reports whether v satisfies the range.
+func (r Range) Contains(v Version) bool {
+	for _, set := range r.sets {
+		matched := true
+		for _, c := range set {
+			if !c.matches(v) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Range) String() string {
+	return r.raw
+}
+
+func parseComparatorSet(s string) ([]comparator, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, errors.New("empty alternative")
+	}
+
+	set := make([]comparator, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		term := fields[i]
+
+		// allow whitespace between the operator and the version (">= 2.0.0")
+		if isOperator(term) && i+1 < len(fields) {
+			i++
+			term += fields[i]
+		}
+
+		comparators, err := parseTerm(term)
+		if err != nil {
+			return nil, err
+		}
+
+		set = append(set, comparators...)
+	}
+
+	return set, nil
+}
+
+func isOperator(s string) bool {
+	for _, op := range operators {
+		if s == string(op) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTerm expands a single (possibly partial) comparator into plain comparators.
+func parseTerm(term string) ([]comparator, error) {
+	op := opEqual
+	for _, candidate := range operators {
+		if strings.HasPrefix(term, string(candidate)) {
+			op = candidate
+			term = term[len(candidate):]
+			break
+		}
+	}
+
+	v, components, err := parsePartial(term)
+	if err != nil {
+		return nil, err
+	}
+
+	if components == 0 {
+		if op != opEqual {
+			return nil, fmt.Errorf("operator %q cannot be used with a wildcard", op)
+		}
+
+		// a bare wildcard matches everything
+		return []comparator{}, nil
+	}
+
+	switch op {
+	case opGreaterOrEqual, opLess:
+		return []comparator{{op: op, version: v}}, nil
+	case opGreater:
+		if components == 3 {
+			return []comparator{{op: opGreater, version: v}}, nil
+		}
+
+		return []comparator{{op: opGreaterOrEqual, version: bump(v, components)}}, nil
+	case opLessOrEqual:
+		if components == 3 {
+			return []comparator{{op: opLessOrEqual, version: v}}, nil
+		}
+
+		return []comparator{{op: opLess, version: bump(v, components)}}, nil
+	case opTilde:
+		return between(v, bump(v, min(components, 2))), nil
+	case opCaret:
+		switch {
+		case v.Major > 0 || components == 1:
+			return between(v, bump(v, 1)), nil
+		case v.Minor > 0 || components == 2:
+			return between(v, bump(v, 2)), nil
+		default:
+			return between(v, bump(v, 3)), nil
+		}
+	default:
+		if components == 3 {
+			return []comparator{{op: opEqual, version: v}}, nil
+		}
+
+		return between(v, bump(v, components)), nil
+	}
+}
+
+func between(lower, upper Version) []comparator {
+	return []comparator{
+		{op: opGreaterOrEqual, version: lower},
+		{op: opLess, version: upper},
+	}
+}
+
+// bump increments the given component (1 = major, 2 = minor, 3 = patch) and zeroes the rest.
+func bump(v Version, component int) Version {
+	switch component {
+	case 1:
+		return New(v.Major+1, 0, 0)
+	case 2:
+		return New(v.Major, v.Minor+1, 0)
+	default:
+		return New(v.Major, v.Minor, v.Patch+1)
+	}
+}
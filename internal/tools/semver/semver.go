@@ -0,0 +1,138 @@
+// Package semver implements the subset of semantic versioning needed to match
+// client (xiloader) versions against ranges such as ">=2.0.0 <3.0.0" or "^2.1".
+// Pre-release and build metadata are not supported.
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidVersion = errors.New("invalid version")
+	ErrInvalidRange   = errors.New("invalid version range")
+)
+
+// Version is a MAJOR.MINOR.PATCH version number.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// New returns the version major.minor.patch.
+func New(major, minor, patch int) Version {
+	return Version{Major: major, Minor: minor, Patch: patch}
+}
+
+// Parse parses a full MAJOR.MINOR.PATCH version. A leading "v" is accepted.
+func Parse(s string) (Version, error) {
+	v, components, err := parsePartial(s)
+	if err != nil {
+		return Version{}, err
+	}
+
+	if components != 3 {
+		return Version{}, fmt.Errorf("%w: %q must have major, minor and patch components", ErrInvalidVersion, s)
+	}
+
+	return v, nil
+}
+
+// ParsePadded parses a version that may be missing its minor or patch component,
+// which are then 0 ("2.0" is 2.0.0). Wildcards are not accepted.
+func ParsePadded(s string) (Version, error) {
+	if strings.ContainsAny(s, "xX*") {
+		return Version{}, fmt.Errorf("%w: %q is not a version", ErrInvalidVersion, s)
+	}
+
+	v, _, err := parsePartial(s)
+	if err != nil {
+		return Version{}, err
+	}
+
+	return v, nil
+}
+
+// MustParse is like Parse but panics if the version is invalid.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is lower than, equal to or higher than other.
+func (v Version) Compare(other Version) int {
+	switch {
+	case v.Major != other.Major:
+		return compareInt(v.Major, other.Major)
+	case v.Minor != other.Minor:
+		return compareInt(v.Minor, other.Minor)
+	default:
+		return compareInt(v.Patch, other.Patch)
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// parsePartial parses a version that may be missing trailing components or use
+// wildcards ("2", "2.1", "2.1.x", "*"). It returns the number of components that
+// were actually specified.
+func parsePartial(s string) (Version, int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if s == "" {
+		return Version{}, 0, fmt.Errorf("%w: empty version", ErrInvalidVersion)
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return Version{}, 0, fmt.Errorf("%w: %q has too many components", ErrInvalidVersion, s)
+	}
+
+	var numbers [3]int
+	components := 0
+	for i, part := range parts {
+		if isWildcard(part) {
+			// everything after a wildcard has to be a wildcard as well
+			for _, rest := range parts[i+1:] {
+				if !isWildcard(rest) {
+					return Version{}, 0, fmt.Errorf("%w: %q has a number after a wildcard", ErrInvalidVersion, s)
+				}
+			}
+
+			break
+		}
+
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 || (len(part) > 1 && part[0] == '0') {
+			return Version{}, 0, fmt.Errorf("%w: %q is not a valid component of %q", ErrInvalidVersion, part, s)
+		}
+
+		numbers[i] = number
+		components++
+	}
+
+	return New(numbers[0], numbers[1], numbers[2]), components, nil
+}
+
+func isWildcard(part string) bool {
+	return part == "x" || part == "X" || part == "*"
+}
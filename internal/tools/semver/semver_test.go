@@ -0,0 +1,127 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("valid versions", func(t *testing.T) {
+		cases := map[string]Version{
+			"2.0.0":   New(2, 0, 0),
+			"v1.12.3": New(1, 12, 3),
+			" 0.0.1 ": New(0, 0, 1),
+		}
+
+		for input, want := range cases {
+			got, err := Parse(input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", input, err)
+			}
+
+			if got != want {
+				t.Fatalf("Parse(%q) = %v, want %v", input, got, want)
+			}
+		}
+	})
+
+	t.Run("invalid versions", func(t *testing.T) {
+		for _, input := range []string{"", "2", "2.0", "2.0.0.0", "2.x.0", "a.b.c", "2.-1.0", "01.0.0", "2.0.x"} {
+			if _, err := Parse(input); !errors.Is(err, ErrInvalidVersion) {
+				t.Fatalf("Parse(%q) error = %v, want %v", input, err, ErrInvalidVersion)
+			}
+		}
+	})
+}
+
+func TestParsePadded(t *testing.T) {
+	cases := map[string]Version{
+		"2":     New(2, 0, 0),
+		"2.1":   New(2, 1, 0),
+		"2.1.3": New(2, 1, 3),
+	}
+
+	for input, want := range cases {
+		if got, err := ParsePadded(input); err != nil || got != want {
+			t.Fatalf("ParsePadded(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "*", "2.x", "2.0.0.0", "a.b"} {
+		if _, err := ParsePadded(input); !errors.Is(err, ErrInvalidVersion) {
+			t.Fatalf("ParsePadded(%q) error = %v, want %v", input, err, ErrInvalidVersion)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "2.0.0", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"1.0.9", "1.0.10", -1},
+	}
+
+	for _, tc := range cases {
+		if got := MustParse(tc.a).Compare(MustParse(tc.b)); got != tc.want {
+			t.Fatalf("%s.Compare(%s) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	cases := []struct {
+		rng     string
+		matches []string
+		rejects []string
+	}{
+		{"2.0.0", []string{"2.0.0"}, []string{"2.0.1", "1.9.9"}},
+		{"=2.0.0", []string{"2.0.0"}, []string{"2.0.1"}},
+		{"2.1", []string{"2.1.0", "2.1.9"}, []string{"2.0.9", "2.2.0"}},
+		{"2.x", []string{"2.0.0", "2.9.9"}, []string{"1.9.9", "3.0.0"}},
+		{"*", []string{"0.0.0", "9.9.9"}, nil},
+		{">=2.0.0", []string{"2.0.0", "3.0.0"}, []string{"1.9.9"}},
+		{">= 2.0.0 < 3", []string{"2.0.0", "2.9.9"}, []string{"1.9.9", "3.0.0"}},
+		{">2.0.0", []string{"2.0.1"}, []string{"2.0.0"}},
+		{">2.0", []string{"2.1.0"}, []string{"2.0.9"}},
+		{"<=2.1", []string{"2.1.9"}, []string{"2.2.0"}},
+		{"~2.1.3", []string{"2.1.3", "2.1.9"}, []string{"2.1.2", "2.2.0"}},
+		{"~2", []string{"2.0.0", "2.9.0"}, []string{"3.0.0"}},
+		{"^2.1.3", []string{"2.1.3", "2.9.0"}, []string{"2.1.2", "3.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"1.2.3 || >=2.0.0 <2.1.0", []string{"1.2.3", "2.0.5"}, []string{"1.2.4", "2.1.0"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.rng, func(t *testing.T) {
+			r, err := ParseRange(tc.rng)
+			if err != nil {
+				t.Fatalf("ParseRange(%q) error = %v", tc.rng, err)
+			}
+
+			for _, v := range tc.matches {
+				if !r.Contains(MustParse(v)) {
+					t.Fatalf("Contains(%s) = false, want true", v)
+				}
+			}
+
+			for _, v := range tc.rejects {
+				if r.Contains(MustParse(v)) {
+					t.Fatalf("Contains(%s) = true, want false", v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	for _, input := range []string{"", "   ", ">=", ">=abc", "1.0.0 ||", ">*", "2.x.1"} {
+		if _, err := ParseRange(input); !errors.Is(err, ErrInvalidRange) {
+			t.Fatalf("ParseRange(%q) error = %v, want %v", input, err, ErrInvalidRange)
+		}
+	}
+}